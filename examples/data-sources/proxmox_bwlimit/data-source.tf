@@ -0,0 +1,7 @@
+data "proxmox_bwlimit" "datacenter" {}
+
+provider "proxmox" {
+  alias            = "heavy"
+  pm_api_url       = var.pm_api_url
+  pm_bwlimit_clone = data.proxmox_bwlimit.datacenter.clone > 0 ? data.proxmox_bwlimit.datacenter.clone / 2 : 51200
+}
@@ -0,0 +1,15 @@
+data "proxmox_guest_configs" "prod" {
+  pool = "prod"
+  type = "qemu"
+}
+
+locals {
+  guests_without_agent = [
+    for guest in jsondecode(data.proxmox_guest_configs.prod.json) : guest.name
+    if !startswith(lookup(guest.config, "agent", "0"), "1")
+  ]
+}
+
+output "guests_without_agent" {
+  value = local.guests_without_agent
+}
@@ -0,0 +1,13 @@
+data "proxmox_inventory" "prod" {
+  pool   = "prod"
+  domain = "prod.example.com"
+}
+
+resource "local_file" "inventory" {
+  filename = "inventory.yaml"
+  content  = data.proxmox_inventory.prod.yaml
+}
+
+output "hosts" {
+  value = data.proxmox_inventory.prod.hosts
+}
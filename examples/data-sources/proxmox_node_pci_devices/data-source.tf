@@ -0,0 +1,18 @@
+data "proxmox_node_pci_devices" "t4" {
+  node      = "pve1"
+  vendor_id = "10de"
+  device_id = "1eb8"
+}
+
+resource "proxmox_vm_qemu" "inference" {
+  name        = "inference"
+  target_node = "pve1"
+  clone       = "debian-template"
+
+  lifecycle {
+    precondition {
+      condition     = data.proxmox_node_pci_devices.t4.iommu_enabled && alltrue(data.proxmox_node_pci_devices.t4.devices[*].isolated)
+      error_message = "The GPU of pve1 can't be passed through on its own."
+    }
+  }
+}
@@ -0,0 +1,8 @@
+data "proxmox_pci_mapping" "free_vfs" {
+  node       = "pve1"
+  capability = "virtual_function"
+}
+
+output "vf" {
+  value = data.proxmox_pci_mapping.free_vfs.ids[0]
+}
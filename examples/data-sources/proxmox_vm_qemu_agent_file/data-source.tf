@@ -0,0 +1,4 @@
+data "proxmox_vm_qemu_agent_file" "machine_id" {
+  vmid = proxmox_vm_qemu.web.vmid
+  path = "/etc/machine-id"
+}
@@ -0,0 +1,7 @@
+provider "proxmox" {
+  pm_api_url = "https://proxmox-server01.example.com:8006/api2/json"
+
+  # or use the environment variables PM_API_TOKEN_ID and PM_API_TOKEN_SECRET
+  pm_api_token_id     = "terraform-user@pve!mytoken"
+  pm_api_token_secret = "afcd8f45-acc1-4d0f-bb12-a70b0777ec11"
+}
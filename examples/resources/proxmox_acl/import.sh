@@ -0,0 +1 @@
+terraform import proxmox_acl.operators_vm acl/PVEVMUser/vms/100
@@ -0,0 +1,12 @@
+resource "proxmox_acl" "operators_vm" {
+  path   = "/vms/${proxmox_vm_qemu.web.vmid}"
+  roleid = "PVEVMUser"
+  groups = [proxmox_group.operators.groupid]
+}
+
+resource "proxmox_acl" "ci_pool" {
+  path      = "/pool/${proxmox_pool.ci.poolid}"
+  roleid    = proxmox_role.terraform.roleid
+  tokens    = ["ci@pve!deploy"]
+  propagate = true
+}
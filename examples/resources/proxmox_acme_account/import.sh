@@ -0,0 +1 @@
+terraform import proxmox_acme_account.letsencrypt account/letsencrypt
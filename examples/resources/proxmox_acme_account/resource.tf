@@ -0,0 +1,13 @@
+resource "proxmox_acme_account" "letsencrypt" {
+  name       = "letsencrypt"
+  contact    = "ops@example.com"
+  accept_tos = true
+}
+
+resource "proxmox_acme_plugin" "cloudflare" {
+  plugin = "cloudflare"
+  api    = "cf"
+  data = {
+    CF_Token = var.cloudflare_token
+  }
+}
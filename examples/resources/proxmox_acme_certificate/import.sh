@@ -0,0 +1 @@
+terraform import proxmox_acme_certificate.pve1 acme/pve1
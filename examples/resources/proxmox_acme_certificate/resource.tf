@@ -0,0 +1,29 @@
+resource "proxmox_acme_account" "letsencrypt" {
+  name       = "letsencrypt"
+  contact    = "ops@example.com"
+  accept_tos = true
+}
+
+resource "proxmox_acme_plugin" "cloudflare" {
+  plugin = "cloudflare"
+  api    = "cf"
+  data = {
+    CF_Token = var.cloudflare_token
+  }
+}
+
+resource "proxmox_acme_certificate" "pve1" {
+  node    = "pve1"
+  account = proxmox_acme_account.letsencrypt.name
+
+  domain {
+    domain = "pve1.example.com"
+    plugin = proxmox_acme_plugin.cloudflare.plugin
+  }
+
+  renew_before_days = 30
+}
+
+output "pve1_certificate_expiry" {
+  value = proxmox_acme_certificate.pve1.not_after
+}
@@ -0,0 +1 @@
+terraform import proxmox_acme_plugin.cloudflare plugins/cloudflare
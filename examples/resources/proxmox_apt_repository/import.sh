@@ -0,0 +1 @@
+terraform import proxmox_apt_repository.no_subscription pve1/no-subscription
@@ -0,0 +1,10 @@
+resource "proxmox_apt_repository" "enterprise" {
+  node    = "pve1"
+  handle  = "enterprise"
+  enabled = false
+}
+
+resource "proxmox_apt_repository" "no_subscription" {
+  node   = "pve1"
+  handle = "no-subscription"
+}
@@ -0,0 +1 @@
+terraform import proxmox_backup_job.nightly backup/nightly
@@ -0,0 +1,16 @@
+resource "proxmox_backup_job" "nightly" {
+  job_id   = "nightly"
+  schedule = "mon..sat 02:00"
+  pool     = "prod"
+  storage  = "pbs"
+  mode     = "snapshot"
+
+  prune_backups {
+    keep_daily   = 7
+    keep_weekly  = 4
+    keep_monthly = 6
+  }
+
+  mailto            = ["ops@example.com"]
+  mail_notification = "failure"
+}
@@ -0,0 +1 @@
+terraform import proxmox_bridge.guests pve1/vmbr1
@@ -0,0 +1,19 @@
+resource "proxmox_bridge" "guests" {
+  node       = "pve1"
+  name       = "vmbr1"
+  ports      = ["eno2"]
+  vlan_aware = true
+  comment    = "guest networks"
+}
+
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = proxmox_bridge.guests.node
+  clone       = "debian-11-cloudinit"
+
+  network {
+    model  = "virtio"
+    bridge = proxmox_bridge.guests.name
+    tag    = 20
+  }
+}
@@ -0,0 +1 @@
+terraform import proxmox_ceph_mon.pve1 pve1/pve1
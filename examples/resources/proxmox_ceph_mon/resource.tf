@@ -0,0 +1,3 @@
+resource "proxmox_ceph_mon" "pve1" {
+  node = "pve1"
+}
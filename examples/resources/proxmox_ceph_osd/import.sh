@@ -0,0 +1 @@
+terraform import proxmox_ceph_osd.pve1_sdb pve1/3
@@ -0,0 +1,6 @@
+resource "proxmox_ceph_osd" "pve1_sdb" {
+  node      = "pve1"
+  device    = "/dev/sdb"
+  db_device = "/dev/nvme0n1"
+  db_size   = 60
+}
@@ -0,0 +1 @@
+terraform import proxmox_ceph_pool.vms pve1/vms
@@ -0,0 +1,6 @@
+resource "proxmox_ceph_pool" "vms" {
+  node     = "pve1"
+  name     = "vms"
+  size     = 3
+  min_size = 2
+}
@@ -0,0 +1 @@
+terraform import proxmox_cephfs.cephfs pve1/cephfs
@@ -0,0 +1,5 @@
+resource "proxmox_cephfs" "cephfs" {
+  node        = "pve1"
+  mds_nodes   = ["pve1", "pve2"]
+  add_storage = true
+}
@@ -0,0 +1 @@
+terraform import proxmox_cloud_init_disk.web pve1/local:iso/web-cidata.iso
@@ -0,0 +1,20 @@
+resource "proxmox_cloud_init_disk" "web" {
+  node     = "pve1"
+  storage  = "local"
+  filename = "web-cidata.iso"
+
+  user_data = <<-EOT
+    #cloud-config
+    hostname: web
+    packages:
+      - nginx
+  EOT
+
+  network_config = <<-EOT
+    version: 2
+    ethernets:
+      eth0:
+        addresses: [10.0.0.10/24]
+        gateway4: 10.0.0.1
+  EOT
+}
@@ -0,0 +1,7 @@
+resource "proxmox_cluster_firewall_options" "cluster" {
+  enabled    = true
+  policy_in  = "DROP"
+  policy_out = "ACCEPT"
+
+  depends_on = [proxmox_cluster_firewall_rules.cluster]
+}
@@ -0,0 +1 @@
+terraform import proxmox_cluster_firewall_rules.cluster cluster
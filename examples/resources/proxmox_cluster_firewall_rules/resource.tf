@@ -0,0 +1,15 @@
+resource "proxmox_cluster_firewall_rules" "cluster" {
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    source    = "+${proxmox_firewall_ipset.admins.name}"
+    proto     = "tcp"
+    dport     = "22,8006"
+    comment   = "Administration"
+  }
+
+  rule {
+    direction = "group"
+    action    = proxmox_firewall_security_group.monitoring.group
+  }
+}
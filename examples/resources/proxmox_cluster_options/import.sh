@@ -0,0 +1 @@
+terraform import proxmox_cluster_options.options cluster
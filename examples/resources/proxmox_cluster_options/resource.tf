@@ -0,0 +1,19 @@
+resource "proxmox_cluster_options" "options" {
+  keyboard          = "en-us"
+  console           = "xtermjs"
+  email_from        = "pve@example.com"
+  max_workers       = 4
+  migration_type    = "insecure"
+  migration_network = "10.1.0.0/24"
+
+  tag_style {
+    shape    = "circle"
+    ordering = "alphabetical"
+    color_map = {
+      prod = "FF0000:FFFFFF"
+      test = "00FF00"
+    }
+  }
+
+  registered_tags = ["prod", "test"]
+}
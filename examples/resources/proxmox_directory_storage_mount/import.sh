@@ -0,0 +1 @@
+terraform import proxmox_directory_storage_mount.ssd pve1/ssd
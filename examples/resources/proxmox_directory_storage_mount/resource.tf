@@ -0,0 +1,7 @@
+resource "proxmox_directory_storage_mount" "ssd" {
+  node        = "pve1"
+  name        = "ssd"
+  device      = "/dev/sdb"
+  filesystem  = "xfs"
+  add_storage = true
+}
@@ -0,0 +1 @@
+terraform import proxmox_file.installer pve1/local:iso/custom-installer.iso
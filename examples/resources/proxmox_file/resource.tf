@@ -0,0 +1,23 @@
+resource "proxmox_file" "installer" {
+  node               = "pve1"
+  storage            = "local"
+  content_type       = "iso"
+  source             = "${path.module}/images/custom-installer.iso"
+  checksum           = "ae6d563d2444665316901fe7091059ac34b8f67ba30f9159f7cef7d2fdc5bf8a"
+  checksum_algorithm = "sha256"
+}
+
+resource "proxmox_file" "template" {
+  node         = "pve1"
+  storage      = "local"
+  content_type = "vztmpl"
+  source       = "${path.module}/templates/base-image_1.0_amd64.tar.zst"
+}
+
+resource "proxmox_file" "network_config" {
+  node         = "pve1"
+  storage      = "local"
+  content_type = "snippets"
+  filename     = "web-network.yaml"
+  content      = file("${path.module}/cloud-init/network.yaml")
+}
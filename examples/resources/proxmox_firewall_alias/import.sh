@@ -0,0 +1 @@
+terraform import proxmox_firewall_alias.office aliases/office
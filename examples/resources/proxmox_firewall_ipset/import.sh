@@ -0,0 +1 @@
+terraform import proxmox_firewall_ipset.admins ipset/admins
@@ -0,0 +1,29 @@
+resource "proxmox_firewall_ipset" "admins" {
+  name    = "admins"
+  comment = "Hosts allowed to administrate the guests"
+
+  entry {
+    cidr = proxmox_firewall_alias.office.name
+  }
+
+  entry {
+    cidr    = "198.51.100.0/24"
+    comment = "VPN"
+  }
+
+  entry {
+    cidr    = "198.51.100.13"
+    nomatch = true
+  }
+}
+
+resource "proxmox_firewall_rules" "web" {
+  vmid = proxmox_vm_qemu.web.vmid
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    macro     = "SSH"
+    source    = "+${proxmox_firewall_ipset.admins.name}"
+  }
+}
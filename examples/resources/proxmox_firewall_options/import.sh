@@ -0,0 +1 @@
+terraform import proxmox_firewall_options.web firewall/100
@@ -0,0 +1,8 @@
+resource "proxmox_firewall_options" "web" {
+  vmid         = proxmox_vm_qemu.web.vmid
+  enabled      = true
+  ipfilter     = true
+  policy_in    = "DROP"
+  policy_out   = "ACCEPT"
+  log_level_in = "info"
+}
@@ -0,0 +1 @@
+terraform import proxmox_firewall_rules.web firewall/100
@@ -0,0 +1,25 @@
+resource "proxmox_firewall_rules" "web" {
+  vmid = proxmox_vm_qemu.web.vmid
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    macro     = "HTTPS"
+    comment   = "Public web server"
+  }
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    source    = "10.0.0.0/24"
+    proto     = "tcp"
+    dport     = "22"
+    log       = "info"
+  }
+
+  rule {
+    direction = "in"
+    action    = "DROP"
+    log       = "warning"
+  }
+}
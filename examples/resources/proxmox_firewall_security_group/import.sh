@@ -0,0 +1 @@
+terraform import proxmox_firewall_security_group.webserver groups/webserver
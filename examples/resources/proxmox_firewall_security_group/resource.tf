@@ -0,0 +1,25 @@
+resource "proxmox_firewall_security_group" "webserver" {
+  group   = "webserver"
+  comment = "Public web servers"
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    macro     = "HTTP"
+  }
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    macro     = "HTTPS"
+  }
+}
+
+resource "proxmox_firewall_rules" "web" {
+  vmid = proxmox_vm_qemu.web.vmid
+
+  rule {
+    direction = "group"
+    action    = proxmox_firewall_security_group.webserver.group
+  }
+}
@@ -0,0 +1 @@
+terraform import proxmox_group.operators groups/operators
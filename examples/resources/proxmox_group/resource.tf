@@ -0,0 +1,5 @@
+resource "proxmox_group" "operators" {
+  groupid = "operators"
+  comment = "Managed by Terraform"
+  members = [proxmox_user.terraform.userid]
+}
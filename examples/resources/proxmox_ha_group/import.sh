@@ -0,0 +1 @@
+terraform import proxmox_ha_group.prefer_pve1 groups/prefer-pve1
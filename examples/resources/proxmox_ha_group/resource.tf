@@ -0,0 +1,14 @@
+resource "proxmox_ha_group" "prefer_pve1" {
+  group = "prefer-pve1"
+  nodes = {
+    pve1 = 2
+    pve2 = 1
+    pve3 = 1
+  }
+  no_failback = true
+}
+
+resource "proxmox_ha_resource" "db" {
+  vmid  = 100
+  group = proxmox_ha_group.prefer_pve1.group
+}
@@ -0,0 +1 @@
+terraform import proxmox_ha_resource.db resources/vm:100
@@ -0,0 +1,17 @@
+resource "proxmox_vm_qemu" "db" {
+  name        = "db"
+  target_node = "pve1"
+  clone       = "debian-template"
+
+  lifecycle {
+    ignore_changes = [hastate]
+  }
+}
+
+resource "proxmox_ha_resource" "db" {
+  vmid         = proxmox_vm_qemu.db.vmid
+  state        = "started"
+  max_restart  = 2
+  max_relocate = 1
+  comment      = "Database"
+}
@@ -0,0 +1 @@
+terraform import proxmox_iso.debian pve1/local:iso/debian-11.0.0-amd64-netinst.iso
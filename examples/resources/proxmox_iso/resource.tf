@@ -0,0 +1,19 @@
+resource "proxmox_iso" "debian" {
+  node               = "pve1"
+  storage            = "local"
+  url                = "https://cdimage.debian.org/cdimage/archive/11.0.0/amd64/iso-cd/debian-11.0.0-amd64-netinst.iso"
+  checksum           = "ae6d563d2444665316901fe7091059ac34b8f67ba30f9159f7cef7d2fdc5bf8a"
+  checksum_algorithm = "sha256"
+}
+
+resource "proxmox_iso" "custom" {
+  node    = "pve1"
+  storage = "local"
+  source  = "${path.module}/images/custom-installer.iso"
+}
+
+resource "proxmox_vm_qemu" "installer" {
+  name        = "installer"
+  target_node = "pve1"
+  iso         = proxmox_iso.debian.volid
+}
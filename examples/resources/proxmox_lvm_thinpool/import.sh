@@ -0,0 +1 @@
+terraform import proxmox_lvm_thinpool.fast pve1/fast
@@ -0,0 +1,6 @@
+resource "proxmox_lvm_thinpool" "fast" {
+  node        = "pve1"
+  name        = "fast"
+  device      = "/dev/nvme1n1"
+  add_storage = true
+}
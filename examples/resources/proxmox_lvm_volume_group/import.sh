@@ -0,0 +1 @@
+terraform import proxmox_lvm_volume_group.bulk pve1/bulk
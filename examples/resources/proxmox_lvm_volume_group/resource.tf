@@ -0,0 +1,6 @@
+resource "proxmox_lvm_volume_group" "bulk" {
+  node        = "pve1"
+  name        = "bulk"
+  device      = "/dev/sdc"
+  add_storage = true
+}
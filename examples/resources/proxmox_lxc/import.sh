@@ -0,0 +1 @@
+terraform import proxmox_lxc.basic pve/lxc/100
@@ -0,0 +1,8 @@
+resource "proxmox_lxc_disk" "data" {
+  container = proxmox_lxc.basic.id
+  slot      = 0
+  storage   = "local-lvm"
+  mp        = "/mnt/data"
+  size      = "8G"
+  backup    = true
+}
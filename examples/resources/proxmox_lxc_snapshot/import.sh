@@ -0,0 +1 @@
+terraform import proxmox_lxc_snapshot.clean 101/clean
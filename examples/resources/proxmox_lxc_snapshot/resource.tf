@@ -0,0 +1,23 @@
+resource "proxmox_lxc" "ci_runner" {
+  target_node = "pve"
+  vmid        = 101
+  hostname    = "ci-runner"
+  ostemplate  = "local:vztmpl/debian-12-standard_12.2-1_amd64.tar.zst"
+  start       = true
+}
+
+variable "reset_runner" {
+  description = "Change to roll the runner back to its clean state"
+  default     = "1"
+}
+
+resource "proxmox_lxc_snapshot" "clean" {
+  vmid        = proxmox_lxc.ci_runner.vmid
+  name        = "clean"
+  description = "Runner before its first job"
+
+  rollback_triggers = {
+    reset = var.reset_runner
+  }
+  start_after_rollback = true
+}
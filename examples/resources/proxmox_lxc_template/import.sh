@@ -0,0 +1 @@
+terraform import proxmox_lxc_template.debian pve1/local:vztmpl/debian-12-standard_12.2-1_amd64.tar.zst
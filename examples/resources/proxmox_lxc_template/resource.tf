@@ -0,0 +1,11 @@
+resource "proxmox_lxc_template" "debian" {
+  node     = "pve1"
+  storage  = "local"
+  template = "debian-12-standard"
+}
+
+resource "proxmox_lxc" "web" {
+  target_node = "pve1"
+  hostname    = "web"
+  ostemplate  = proxmox_lxc_template.debian.volid
+}
@@ -0,0 +1 @@
+terraform import proxmox_mapping_dir.share dir/share
@@ -0,0 +1,13 @@
+resource "proxmox_mapping_dir" "share" {
+  name = "share"
+
+  directory {
+    node = "pve1"
+    path = "/srv/share"
+  }
+
+  directory {
+    node = "pve2"
+    path = "/srv/share"
+  }
+}
@@ -0,0 +1 @@
+terraform import proxmox_mapping_pci.gpu pci/gpu
@@ -0,0 +1,18 @@
+resource "proxmox_mapping_pci" "gpu" {
+  name        = "gpu"
+  description = "RTX 3090 of the render nodes"
+
+  device {
+    node        = "pve1"
+    path        = "0000:01:00"
+    device_id   = "10de:2204"
+    iommu_group = 12
+  }
+
+  device {
+    node        = "pve2"
+    path        = "0000:41:00"
+    device_id   = "10de:2204"
+    iommu_group = 30
+  }
+}
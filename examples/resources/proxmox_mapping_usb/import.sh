@@ -0,0 +1 @@
+terraform import proxmox_mapping_usb.license_dongle usb/license-dongle
@@ -0,0 +1,13 @@
+resource "proxmox_mapping_usb" "license_dongle" {
+  name = "license-dongle"
+
+  device {
+    node      = "pve1"
+    device_id = "0529:0001"
+  }
+
+  device {
+    node      = "pve2"
+    device_id = "0529:0001"
+  }
+}
@@ -0,0 +1 @@
+terraform import proxmox_metrics_server.influxdb metrics/influxdb
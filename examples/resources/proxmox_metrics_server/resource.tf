@@ -0,0 +1,10 @@
+resource "proxmox_metrics_server" "influxdb" {
+  name         = "influxdb"
+  type         = "influxdb"
+  server       = "influxdb.example.com"
+  port         = 8086
+  protocol     = "https"
+  organization = "ops"
+  bucket       = "proxmox"
+  token        = var.influxdb_token
+}
@@ -0,0 +1,28 @@
+resource "proxmox_network_bond" "uplink" {
+  node        = "pve1"
+  name        = "bond0"
+  slaves      = ["eno1", "eno2"]
+  mode        = "802.3ad"
+  hash_policy = "layer3+4"
+  mtu         = 9000
+}
+
+resource "proxmox_bridge" "guests" {
+  node       = proxmox_network_bond.uplink.node
+  name       = "vmbr1"
+  ports      = [proxmox_network_bond.uplink.name]
+  vlan_aware = true
+  mtu        = 9000
+}
+
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = proxmox_bridge.guests.node
+  clone       = "debian-11-cloudinit"
+
+  network {
+    model  = "virtio"
+    bridge = proxmox_bridge.guests.name
+    tag    = 20
+  }
+}
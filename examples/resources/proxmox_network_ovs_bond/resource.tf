@@ -0,0 +1,8 @@
+resource "proxmox_network_ovs_bond" "uplink" {
+  node        = "pve1"
+  name        = "bond0"
+  bridge      = "vmbr1"
+  slaves      = ["eno1", "eno2"]
+  mode        = "lacp-balance-tcp"
+  ovs_options = "other_config:lacp-time=fast"
+}
@@ -0,0 +1,23 @@
+resource "proxmox_network_ovs_bridge" "guests" {
+  node        = "pve1"
+  name        = "vmbr1"
+  ovs_options = "other_config:rstp-enable=true"
+}
+
+resource "proxmox_network_ovs_bond" "uplink" {
+  node        = proxmox_network_ovs_bridge.guests.node
+  name        = "bond0"
+  bridge      = proxmox_network_ovs_bridge.guests.name
+  slaves      = ["eno1", "eno2"]
+  mode        = "lacp-balance-tcp"
+  ovs_options = "other_config:lacp-time=fast"
+}
+
+resource "proxmox_network_ovs_int_port" "management" {
+  node    = proxmox_network_ovs_bridge.guests.node
+  name    = "mgmt"
+  bridge  = proxmox_network_ovs_bridge.guests.name
+  tag     = 10
+  cidr    = "10.0.10.2/24"
+  gateway = "10.0.10.1"
+}
@@ -0,0 +1,8 @@
+resource "proxmox_network_ovs_int_port" "management" {
+  node    = "pve1"
+  name    = "mgmt"
+  bridge  = "vmbr1"
+  tag     = 10
+  cidr    = "10.0.10.2/24"
+  gateway = "10.0.10.1"
+}
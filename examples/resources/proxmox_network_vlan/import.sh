@@ -0,0 +1 @@
+terraform import proxmox_network_vlan.management pve1/bond0.10
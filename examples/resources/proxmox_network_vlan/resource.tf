@@ -0,0 +1,15 @@
+resource "proxmox_network_vlan" "management" {
+  node    = "pve1"
+  name    = "bond0.10"
+  cidr    = "10.10.0.11/24"
+  gateway = "10.10.0.1"
+  comment = "management"
+}
+
+resource "proxmox_network_vlan" "storage" {
+  node       = "pve1"
+  name       = "vlan20"
+  raw_device = "bond0"
+  cidr       = "10.20.0.11/24"
+  mtu        = 9000
+}
@@ -0,0 +1 @@
+terraform import proxmox_node_certificate.pve1 certificate/pve1
@@ -0,0 +1,5 @@
+resource "proxmox_node_certificate" "pve1" {
+  node        = "pve1"
+  certificate = "${file("pve1.example.com.crt")}${file("intermediate-ca.crt")}"
+  private_key = file("pve1.example.com.key")
+}
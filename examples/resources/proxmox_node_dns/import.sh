@@ -0,0 +1 @@
+terraform import proxmox_node_dns.pve1 dns/pve1
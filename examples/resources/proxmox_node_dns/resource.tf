@@ -0,0 +1,6 @@
+resource "proxmox_node_dns" "pve1" {
+  node   = "pve1"
+  search = "example.com"
+  dns1   = "10.0.0.53"
+  dns2   = "10.0.1.53"
+}
@@ -0,0 +1 @@
+terraform import proxmox_node_firewall_options.pve1 nodes/pve1
@@ -0,0 +1,7 @@
+resource "proxmox_node_firewall_options" "pve1" {
+  node                = "pve1"
+  tcpflags            = true
+  log_level_in        = "info"
+  protection_synflood = true
+  nf_conntrack_max    = 524288
+}
@@ -0,0 +1 @@
+terraform import proxmox_node_firewall_rules.pve1 nodes/pve1
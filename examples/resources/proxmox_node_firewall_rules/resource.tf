@@ -0,0 +1,12 @@
+resource "proxmox_node_firewall_rules" "pve1" {
+  node = "pve1"
+
+  rule {
+    direction = "in"
+    action    = "ACCEPT"
+    source    = "10.0.5.0/24"
+    proto     = "tcp"
+    dport     = "9100"
+    comment   = "Node exporter"
+  }
+}
@@ -0,0 +1 @@
+terraform import proxmox_node_hosts_entry.build1 pve1/10.0.0.50
@@ -0,0 +1,5 @@
+resource "proxmox_node_hosts_entry" "build1" {
+  node      = "pve1"
+  address   = "10.0.0.50"
+  hostnames = ["build1.example.com", "build1"]
+}
@@ -0,0 +1,14 @@
+resource "proxmox_node_reboot" "pve1" {
+  node         = "pve1"
+  trigger      = var.maintenance_window
+  migrate_back = true
+}
+
+resource "proxmox_node_reboot" "pve2" {
+  node          = "pve2"
+  trigger       = var.maintenance_window
+  drain_targets = ["pve1"]
+  migrate_back  = true
+
+  depends_on = [proxmox_node_reboot.pve1]
+}
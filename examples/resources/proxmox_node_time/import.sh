@@ -0,0 +1 @@
+terraform import proxmox_node_time.pve1 time/pve1
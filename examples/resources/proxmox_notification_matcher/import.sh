@@ -0,0 +1 @@
+terraform import proxmox_notification_matcher.backup_failures matchers/backup-failures
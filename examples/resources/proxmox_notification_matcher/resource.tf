@@ -0,0 +1,6 @@
+resource "proxmox_notification_matcher" "backup_failures" {
+  name           = "backup-failures"
+  targets        = [proxmox_notification_target.mail.name]
+  match_severity = ["error"]
+  match_field    = ["exact:type=vzdump"]
+}
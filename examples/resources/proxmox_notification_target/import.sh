@@ -0,0 +1 @@
+terraform import proxmox_notification_target.mail smtp/ops-mail
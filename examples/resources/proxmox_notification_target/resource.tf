@@ -0,0 +1,10 @@
+resource "proxmox_notification_target" "mail" {
+  name         = "ops-mail"
+  type         = "smtp"
+  server       = "mail.example.com"
+  mode         = "starttls"
+  username     = "pve@example.com"
+  password     = var.smtp_password
+  from_address = "pve@example.com"
+  mailto       = ["ops@example.com"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_pool.tenant pools/tenant-a
@@ -0,0 +1,4 @@
+resource "proxmox_pool" "tenant" {
+  poolid  = "tenant-a"
+  comment = "The guests of tenant A"
+}
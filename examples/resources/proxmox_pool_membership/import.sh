@@ -0,0 +1 @@
+terraform import proxmox_pool_membership.web_vm vm/100/web
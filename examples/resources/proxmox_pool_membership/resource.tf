@@ -0,0 +1,13 @@
+resource "proxmox_pool" "web" {
+  poolid = "web"
+}
+
+resource "proxmox_pool_membership" "web_vm" {
+  poolid = proxmox_pool.web.poolid
+  vmid   = 100
+}
+
+resource "proxmox_pool_membership" "web_storage" {
+  poolid  = proxmox_pool.web.poolid
+  storage = "local-lvm"
+}
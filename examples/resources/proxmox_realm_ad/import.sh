@@ -0,0 +1 @@
+terraform import proxmox_realm_ad.example domains/example
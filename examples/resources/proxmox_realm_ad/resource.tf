@@ -0,0 +1,18 @@
+resource "proxmox_realm_ad" "example" {
+  realm    = "example"
+  comment  = "Company domain"
+  domain   = "example.com"
+  server1  = "dc1.example.com"
+  server2  = "dc2.example.com"
+  mode     = "ldaps"
+  verify   = true
+  bind_dn  = "CN=proxmox,OU=Services,DC=example,DC=com"
+  password = var.ad_bind_password
+
+  group_dn        = "OU=Groups,DC=example,DC=com"
+  sync_attributes = "email=mail,firstname=givenName,lastname=sn"
+
+  sync_scope           = "both"
+  sync_remove_vanished = ["acl", "entry"]
+  sync_on_apply        = true
+}
@@ -0,0 +1 @@
+terraform import proxmox_realm_ldap.example domains/example
@@ -0,0 +1,19 @@
+resource "proxmox_realm_ldap" "example" {
+  realm     = "example"
+  comment   = "Company directory"
+  server1   = "ldap1.example.com"
+  server2   = "ldap2.example.com"
+  mode      = "ldaps"
+  verify    = true
+  base_dn   = "ou=people,dc=example,dc=com"
+  bind_dn   = "cn=proxmox,ou=services,dc=example,dc=com"
+  password  = var.ldap_bind_password
+  user_attr = "uid"
+
+  group_dn        = "ou=groups,dc=example,dc=com"
+  group_name_attr = "cn"
+  sync_attributes = "email=mail,firstname=givenName,lastname=sn"
+
+  sync_scope           = "both"
+  sync_remove_vanished = ["acl", "entry"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_realm_openid.sso domains/sso
@@ -0,0 +1,9 @@
+resource "proxmox_realm_openid" "sso" {
+  realm          = "sso"
+  comment        = "Company single sign-on"
+  issuer_url     = "https://login.example.com/realms/main"
+  client_id      = "proxmox"
+  client_key     = var.oidc_client_secret
+  username_claim = "email"
+  autocreate     = true
+}
@@ -0,0 +1,16 @@
+resource "proxmox_remote_migration" "db" {
+  vmid                = 100
+  target_host         = "pve1.dc2.example.com"
+  target_fingerprint  = var.dc2_fingerprint
+  target_token_id     = "root@pam!migration"
+  target_token_secret = var.dc2_token_secret
+  target_vmid         = 2100
+  target_storage = {
+    "*" = "local-lvm"
+  }
+  target_bridge = {
+    "*"   = "vmbr0"
+    vmbr1 = "vmbr10"
+  }
+  delete_source = true
+}
@@ -0,0 +1 @@
+terraform import proxmox_replication_job.db replication/100-0
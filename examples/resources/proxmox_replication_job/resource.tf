@@ -0,0 +1,18 @@
+resource "proxmox_vm_qemu" "db" {
+  name        = "db"
+  target_node = "pve1"
+  clone       = "debian-12"
+
+  disk {
+    type    = "scsi"
+    storage = "local-zfs"
+    size    = "32G"
+  }
+}
+
+resource "proxmox_replication_job" "db" {
+  vmid     = proxmox_vm_qemu.db.vmid
+  target   = "pve2"
+  schedule = "*/5"
+  rate     = 50
+}
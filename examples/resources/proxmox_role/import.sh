@@ -0,0 +1 @@
+terraform import proxmox_role.terraform roles/TerraformProv
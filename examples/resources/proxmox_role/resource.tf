@@ -0,0 +1,11 @@
+resource "proxmox_role" "terraform" {
+  roleid     = "TerraformProv"
+  privileges = [
+    "Datastore.AllocateSpace",
+    "Datastore.Audit",
+    "VM.Allocate",
+    "VM.Audit",
+    "VM.Config.Disk",
+    "VM.PowerMgmt",
+  ]
+}
@@ -0,0 +1 @@
+terraform import proxmox_sdn_controller.evpnctl controllers/evpnctl
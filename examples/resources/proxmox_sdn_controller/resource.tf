@@ -0,0 +1,15 @@
+resource "proxmox_sdn_controller" "evpnctl" {
+  controller = "evpnctl"
+  type       = "evpn"
+  asn        = 65000
+  peers      = ["10.0.0.1", "10.0.0.2", "10.0.0.3"]
+}
+
+resource "proxmox_sdn_controller" "uplink" {
+  controller = "bgppve1"
+  type       = "bgp"
+  node       = "pve1"
+  asn        = 65000
+  peers      = ["192.168.0.1"]
+  ebgp       = true
+}
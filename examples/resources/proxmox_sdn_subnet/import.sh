@@ -0,0 +1 @@
+terraform import proxmox_sdn_subnet.web web/office-10.0.10.0-24
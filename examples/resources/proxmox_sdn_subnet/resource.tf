@@ -0,0 +1,11 @@
+resource "proxmox_sdn_subnet" "web" {
+  vnet    = proxmox_sdn_vnet.web.vnet
+  cidr    = "10.0.10.0/24"
+  gateway = "10.0.10.1"
+  snat    = true
+
+  dhcp_range {
+    start_address = "10.0.10.100"
+    end_address   = "10.0.10.199"
+  }
+}
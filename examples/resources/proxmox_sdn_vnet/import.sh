@@ -0,0 +1 @@
+terraform import proxmox_sdn_vnet.web vnets/web
@@ -0,0 +1,6 @@
+resource "proxmox_sdn_vnet" "web" {
+  vnet  = "web"
+  zone  = proxmox_sdn_zone.office.zone
+  tag   = 100
+  alias = "Web servers"
+}
@@ -0,0 +1 @@
+terraform import proxmox_sdn_zone.office zones/office
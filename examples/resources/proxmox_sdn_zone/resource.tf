@@ -0,0 +1,21 @@
+resource "proxmox_sdn_zone" "office" {
+  zone   = "office"
+  type   = "vlan"
+  bridge = "vmbr0"
+}
+
+resource "proxmox_sdn_zone" "tenants" {
+  zone  = "tenants"
+  type  = "vxlan"
+  peers = ["10.0.0.1", "10.0.0.2", "10.0.0.3"]
+  mtu   = 1450
+}
+
+resource "proxmox_sdn_zone" "routed" {
+  zone       = "routed"
+  type       = "evpn"
+  controller = "evpnctl"
+  vrf_vxlan  = 10000
+  exit_nodes = ["pve1"]
+  mtu        = 1450
+}
@@ -0,0 +1 @@
+terraform import proxmox_snapshot.provisioned 100/provisioned
@@ -0,0 +1,11 @@
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = "pve"
+  clone       = "debian-12"
+}
+
+resource "proxmox_snapshot" "provisioned" {
+  vmid        = proxmox_vm_qemu.web.vmid
+  name        = "provisioned"
+  description = "State right after provisioning"
+}
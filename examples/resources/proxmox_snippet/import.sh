@@ -0,0 +1 @@
+terraform import proxmox_snippet.user_data pve1/local:snippets/web-user-data.yaml
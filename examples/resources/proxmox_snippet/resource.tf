@@ -0,0 +1,31 @@
+resource "proxmox_snippet" "user_data" {
+  node     = "pve1"
+  storage  = "local"
+  filename = "web-user-data.yaml"
+  content  = <<-EOT
+    #cloud-config
+    package_upgrade: true
+    packages:
+      - nginx
+  EOT
+}
+
+resource "proxmox_snippet" "hookscript" {
+  node    = "pve1"
+  storage = "local"
+  source  = "${path.module}/hooks/notify.sh"
+}
+
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = "pve1"
+  clone       = "debian-11-cloudinit"
+  cicustom    = "user=${proxmox_snippet.user_data.volid}"
+}
+
+resource "proxmox_lxc" "worker" {
+  target_node = "pve1"
+  hostname    = "worker"
+  ostemplate  = "local:vztmpl/debian-11-standard_11.0-1_amd64.tar.gz"
+  hookscript  = proxmox_snippet.hookscript.volid
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_cephfs.shared storage/cephfs
@@ -0,0 +1,5 @@
+resource "proxmox_storage_cephfs" "shared" {
+  storage = "cephfs"
+  fs_name = "cephfs"
+  content = ["iso", "vztmpl", "snippets"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_cifs.isos storage/isos
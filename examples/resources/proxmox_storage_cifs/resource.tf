@@ -0,0 +1,10 @@
+resource "proxmox_storage_cifs" "isos" {
+  storage  = "isos"
+  server   = "fileserver.example.com"
+  share    = "proxmox"
+  subdir   = "/isos"
+  username = "proxmox"
+  password = var.smb_password
+  domain   = "EXAMPLE"
+  content  = ["iso", "vztmpl"]
+}
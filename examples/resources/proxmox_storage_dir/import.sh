@@ -0,0 +1 @@
+terraform import proxmox_storage_dir.images storage/images
@@ -0,0 +1,6 @@
+resource "proxmox_storage_dir" "images" {
+  storage = "images"
+  path    = "/srv/images"
+  content = ["images", "rootdir"]
+  nodes   = ["pve1", "pve2"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_iscsi.san storage/san
@@ -0,0 +1,15 @@
+resource "proxmox_storage_iscsi" "san" {
+  storage   = "san"
+  portal    = "10.0.10.5"
+  target    = "iqn.2003-01.org.linux-iscsi.san.x8664:sn.0123456789ab"
+  content   = ["none"]
+  scan_node = "pve1"
+}
+
+resource "proxmox_storage_lvm" "san_lun0" {
+  storage = "san-lun0"
+  vgname  = "san-lun0"
+  base    = proxmox_storage_iscsi.san.luns[0].volid
+  shared  = true
+  content = ["images", "rootdir"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_lvm.san storage/san
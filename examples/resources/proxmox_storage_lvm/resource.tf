@@ -0,0 +1,15 @@
+resource "proxmox_storage_lvm" "san" {
+  storage = "san"
+  vgname  = "san"
+  shared  = true
+  content = ["images"]
+}
+
+# a volume group created on a LUN of an iSCSI SAN
+resource "proxmox_storage_lvm" "san_lun0" {
+  storage = "san-lun0"
+  vgname  = "san-lun0"
+  base    = proxmox_storage_iscsi.san.luns[0].volid
+  shared  = true
+  content = ["images", "rootdir"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_lvmthin.local_thin storage/local-thin
@@ -0,0 +1,7 @@
+resource "proxmox_storage_lvmthin" "local_thin" {
+  storage  = "local-thin"
+  vgname   = "pve"
+  thinpool = "data"
+  content  = ["images", "rootdir"]
+  nodes    = ["pve1"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_nfs.backups storage/backups
@@ -0,0 +1,7 @@
+resource "proxmox_storage_nfs" "backups" {
+  storage = "backups"
+  server  = "nas.example.com"
+  export  = "/volume1/proxmox"
+  options = "vers=4.2"
+  content = ["backup", "iso", "vztmpl"]
+}
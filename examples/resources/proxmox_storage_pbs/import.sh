@@ -0,0 +1 @@
+terraform import proxmox_storage_pbs.backup storage/pbs
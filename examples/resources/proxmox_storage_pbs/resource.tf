@@ -0,0 +1,19 @@
+resource "proxmox_storage_pbs" "backup" {
+  storage     = "pbs"
+  server      = "pbs.example.com"
+  datastore   = "store1"
+  namespace   = "cluster1"
+  username    = "pve@pbs!cluster1"
+  password    = var.pbs_token_secret
+  fingerprint = "ab:cd:ef:01:23:45:67:89:ab:cd:ef:01:23:45:67:89:ab:cd:ef:01:23:45:67:89:ab:cd:ef:01:23:45:67:89"
+  content     = ["backup"]
+
+  encryption_key = file("pbs-encryption-key.json")
+
+  prune_backups {
+    keep_last    = 3
+    keep_daily   = 7
+    keep_weekly  = 4
+    keep_monthly = 6
+  }
+}
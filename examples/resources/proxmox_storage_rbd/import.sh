@@ -0,0 +1 @@
+terraform import proxmox_storage_rbd.vms storage/ceph-vms
@@ -0,0 +1,17 @@
+# the Ceph cluster of the Proxmox nodes
+resource "proxmox_storage_rbd" "vms" {
+  storage = "ceph-vms"
+  pool    = "vms"
+  content = ["images", "rootdir"]
+  krbd    = true
+}
+
+# an external Ceph cluster
+resource "proxmox_storage_rbd" "external" {
+  storage  = "ceph-external"
+  pool     = "proxmox"
+  monhost  = "10.0.0.1 10.0.0.2 10.0.0.3"
+  username = "proxmox"
+  keyring  = file("ceph.client.proxmox.keyring")
+  content  = ["images"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_retention.nightly_isos pve1/local/iso
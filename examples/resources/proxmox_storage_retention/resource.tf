@@ -0,0 +1,8 @@
+resource "proxmox_storage_retention" "nightly_isos" {
+  node      = "pve1"
+  storage   = "local"
+  content   = "iso"
+  filter    = "^nightly-.*\\.iso$"
+  keep_last = 3
+  max_age   = "720h"
+}
@@ -0,0 +1 @@
+terraform import proxmox_storage_zfspool.tank storage/tank
@@ -0,0 +1,8 @@
+resource "proxmox_storage_zfspool" "tank" {
+  storage   = "tank"
+  pool      = "tank/guests"
+  blocksize = "16k"
+  sparse    = true
+  content   = ["images", "rootdir"]
+  nodes     = ["pve1", "pve2"]
+}
@@ -0,0 +1 @@
+terraform import proxmox_user.terraform users/terraform-prov@pve
@@ -0,0 +1,7 @@
+resource "proxmox_user" "terraform" {
+  userid    = "terraform-prov@pve"
+  comment   = "Managed by Terraform"
+  email     = "ops@example.com"
+  groups    = ["operators"]
+  password  = var.terraform_user_password
+}
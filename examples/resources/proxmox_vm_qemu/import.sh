@@ -0,0 +1 @@
+terraform import proxmox_vm_qemu.web pve1/qemu/100
@@ -0,0 +1,23 @@
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = "pve1"
+  clone       = "debian-11-cloudinit"
+  agent       = 1
+
+  cores  = 2
+  memory = 2048
+
+  disk {
+    type    = "scsi"
+    storage = "local-lvm"
+    size    = "20G"
+  }
+
+  network {
+    model  = "virtio"
+    bridge = "vmbr0"
+  }
+
+  os_type   = "cloud-init"
+  ipconfig0 = "ip=dhcp"
+}
@@ -0,0 +1,6 @@
+resource "proxmox_vm_qemu_agent_file" "motd" {
+  vmid        = proxmox_vm_qemu.web.vmid
+  path        = "/etc/motd"
+  content     = "Managed by Terraform\n"
+  permissions = "0644"
+}
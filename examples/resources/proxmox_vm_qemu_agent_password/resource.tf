@@ -0,0 +1,6 @@
+resource "proxmox_vm_qemu_agent_password" "admin" {
+  vmid     = proxmox_vm_qemu.web.vmid
+  username = "admin"
+  password = var.admin_password
+  trigger  = var.rotation
+}
@@ -0,0 +1 @@
+terraform import proxmox_vm_qemu_template.debian12 pve1/qemu/9000
@@ -0,0 +1,17 @@
+resource "proxmox_vm_qemu_template" "debian12" {
+  target_node       = "pve1"
+  vmid              = 9000
+  name              = "debian-12-cloud"
+  agent             = 1
+  disk_storage      = "local-lvm"
+  import_from       = "local:import/debian-12-genericcloud-amd64.qcow2"
+  disk_size         = "10G"
+  cloudinit_storage = "local-lvm"
+  serial_console    = true
+}
+
+resource "proxmox_vm_qemu" "web" {
+  name        = "web"
+  target_node = proxmox_vm_qemu_template.debian12.target_node
+  clone       = proxmox_vm_qemu_template.debian12.name
+}
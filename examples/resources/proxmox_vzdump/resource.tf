@@ -0,0 +1,17 @@
+resource "proxmox_vzdump" "before_upgrade" {
+  vmid    = 100
+  storage = "pbs"
+  notes   = "before upgrade to ${var.app_version}"
+
+  triggers = {
+    app_version = var.app_version
+  }
+}
+
+resource "proxmox_vm_qemu" "database" {
+  vmid = 100
+  # ...
+  ciuser = "app-${var.app_version}"
+
+  depends_on = [proxmox_vzdump.before_upgrade]
+}
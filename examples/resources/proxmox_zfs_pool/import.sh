@@ -0,0 +1 @@
+terraform import proxmox_zfs_pool.tank pve1/tank
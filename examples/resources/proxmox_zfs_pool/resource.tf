@@ -0,0 +1,8 @@
+resource "proxmox_zfs_pool" "tank" {
+  node        = "pve1"
+  name        = "tank"
+  devices     = ["/dev/sdb", "/dev/sdc"]
+  raid_level  = "mirror"
+  compression = "lz4"
+  add_storage = true
+}
@@ -0,0 +1,448 @@
+package proxmox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// The proxmox-api-go client only covers guests, pools and a handful of other endpoints.
+// The helpers in this file talk to the rest of the API through a plain pxapi.Session that
+// shares the provider credentials. All of them return the content of the "data" key of the
+// API response.
+
+// encode every component of an API path so that ids like "user@pve" or "/vms/100" survive
+func apiPath(components ...string) string {
+	escaped := make([]string, len(components))
+	for i, component := range components {
+		escaped[i] = url.PathEscape(component)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// proxmox reports the reason of a failed call in the body, the session only gives us the status line
+func apiResponseError(resp *http.Response, err error) error {
+	if resp == nil || resp.Body == nil {
+		return err
+	}
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil || len(body) == 0 {
+		return err
+	}
+	return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(body)))
+}
+
+func apiResponseData(resp *http.Response, err error) (interface{}, error) {
+	if err != nil {
+		return nil, apiResponseError(resp, err)
+	}
+	jbody, err := pxapi.ResponseJSON(resp)
+	if err != nil {
+		return nil, err
+	}
+	return jbody["data"], nil
+}
+
+func apiGet(session *pxapi.Session, path string) (interface{}, error) {
+	return apiResponseData(session.Get(path, nil, nil))
+}
+
+func apiGetWithParams(session *pxapi.Session, path string, params map[string]interface{}) (interface{}, error) {
+	values := pxapi.ParamsToValues(params)
+	return apiResponseData(session.Get(path, &values, nil))
+}
+
+func apiGetMap(session *pxapi.Session, path string) (map[string]interface{}, error) {
+	data, err := apiGet(session, path)
+	if err != nil {
+		return nil, err
+	}
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected response from %s: %v", path, data)
+	}
+	return dataMap, nil
+}
+
+// The request body of params. Array parameters of proxmox, e.g. the dhcp-range of SDN
+// subnets, are passed as []string and sent once for every value.
+func apiParamsBody(params map[string]interface{}, allowedEmpty []string) []byte {
+	single := map[string]interface{}{}
+	lists := map[string][]string{}
+	for key, value := range params {
+		if list, ok := value.([]string); ok {
+			lists[key] = list
+		} else {
+			single[key] = value
+		}
+	}
+	values := pxapi.ParamsToValuesWithEmpty(single, allowedEmpty)
+	for key, list := range lists {
+		for _, value := range list {
+			values.Add(key, value)
+		}
+	}
+	return []byte(values.Encode())
+}
+
+// allowedEmpty is used as with apiPut.
+func apiPost(session *pxapi.Session, path string, params map[string]interface{}, allowedEmpty ...string) (interface{}, error) {
+	reqbody := apiParamsBody(params, allowedEmpty)
+	return apiResponseData(session.Post(path, nil, nil, &reqbody))
+}
+
+// allowedEmpty lists the parameters which are sent even though their value is empty,
+// this is how proxmox is told to clear an attribute.
+func apiPut(session *pxapi.Session, path string, params map[string]interface{}, allowedEmpty ...string) (interface{}, error) {
+	reqbody := apiParamsBody(params, allowedEmpty)
+	return apiResponseData(session.Put(path, nil, nil, &reqbody))
+}
+
+func apiDelete(session *pxapi.Session, path string) (interface{}, error) {
+	return apiResponseData(session.Delete(path, nil, nil))
+}
+
+// POST to an endpoint which starts a task and wait for the task to finish
+func apiPostTask(session *pxapi.Session, client *pxapi.Client, path string, params map[string]interface{}) (string, error) {
+	upid, err := apiPost(session, path, params)
+	if err != nil {
+		return "", err
+	}
+	return apiWaitForTask(session, client, upid)
+}
+
+// The entry of volid in the content listing of its storage on node, nil when neither the
+// volume nor its storage exist.
+func apiGetVolume(session *pxapi.Session, node string, volid string, content string) (map[string]interface{}, error) {
+	storage := strings.SplitN(volid, ":", 2)[0]
+	data, err := apiGetWithParams(session, apiPath("nodes", node, "storage", storage, "content"), map[string]interface{}{
+		"content": content,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok && apiString(entry["volid"]) == volid {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func apiDeleteVolume(session *pxapi.Session, client *pxapi.Client, node string, volid string) error {
+	storage := strings.SplitN(volid, ":", 2)[0]
+	upid, err := apiDelete(session, apiPath("nodes", node, "storage", storage, "content", volid))
+	if err != nil {
+		return err
+	}
+	// newer versions of proxmox delete the file in a task
+	if _, err = apiWaitForTask(session, client, upid); err != nil {
+		return err
+	}
+	volume, err := apiGetVolume(session, node, volid, "")
+	if err != nil {
+		return err
+	}
+	if volume != nil {
+		return fmt.Errorf("Volume %s is still on the storage after it was deleted", volid)
+	}
+	return nil
+}
+
+// The volumes owned by the guest vmid on the active storages of node for guest disks.
+func apiGetGuestVolumes(session *pxapi.Session, node string, vmid int) ([]string, error) {
+	storages, err := apiGetWithParams(session, apiPath("nodes", node, "storage"), map[string]interface{}{
+		"content": "images,rootdir",
+		"enabled": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	volids := []string{}
+	storageList, _ := storages.([]interface{})
+	for _, item := range storageList {
+		storage, _ := item.(map[string]interface{})
+		if !apiBool(storage["active"]) {
+			continue
+		}
+		content, err := apiGetWithParams(session, apiPath("nodes", node, "storage", apiString(storage["storage"]), "content"), map[string]interface{}{
+			"vmid": vmid,
+		})
+		if err != nil {
+			return nil, err
+		}
+		contentList, _ := content.([]interface{})
+		for _, volume := range contentList {
+			volume, _ := volume.(map[string]interface{})
+			volids = append(volids, apiString(volume["volid"]))
+		}
+	}
+	sort.Strings(volids)
+	return volids, nil
+}
+
+// how often apiWaitForTask polls the status and the log of a task
+var apiTaskPollInterval = time.Duration(pxapi.TaskStatusCheckInterval) * time.Second
+
+// number of the last lines of the task log added to the error of a failed task
+const apiTaskLogTail = 20
+
+// Like Client.WaitForCompletion, but the lines of the task log are written to the debug log
+// while waiting, and the last lines are added to the error when the task fails, so e.g. the
+// error of vzdump or qemu-img shows up instead of only the exit status. upid is the data
+// of the response which started the task, some endpoints start no task on older versions.
+func apiWaitForTask(session *pxapi.Session, client *pxapi.Client, upid interface{}) (string, error) {
+	task := apiString(upid)
+	if task == "" {
+		return "", nil
+	}
+	parts := strings.Split(task, ":")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("Unexpected task id %s", task)
+	}
+	node := parts[1]
+
+	logger, _ := CreateSubLogger("task_log")
+	tail := []string{}
+	start := 0
+	readLog := func() {
+		data, err := apiGetWithParams(session, apiPath("nodes", node, "tasks", task, "log"), map[string]interface{}{
+			"start": start,
+			"limit": 500,
+		})
+		if err != nil {
+			return
+		}
+		lines, _ := data.([]interface{})
+		for _, item := range lines {
+			line, _ := item.(map[string]interface{})
+			// the log of a running task ends with "no content" until the task writes a line
+			if apiInt(line["n"]) <= start || apiString(line["t"]) == "no content" {
+				continue
+			}
+			start = apiInt(line["n"])
+			logger.Debug().Str("upid", task).Msg(apiString(line["t"]))
+			tail = append(tail, apiString(line["t"]))
+			if len(tail) > apiTaskLogTail {
+				tail = tail[1:]
+			}
+		}
+	}
+
+	for started := time.Now(); time.Since(started) < time.Duration(client.TaskTimeout)*time.Second; time.Sleep(apiTaskPollInterval) {
+		status, err := apiGetMap(session, apiPath("nodes", node, "tasks", task, "status"))
+		if err != nil {
+			return "", err
+		}
+		readLog()
+		if apiString(status["status"]) != "stopped" {
+			continue
+		}
+		exitStatus := apiString(status["exitstatus"])
+		if exitStatus != "OK" {
+			return exitStatus, fmt.Errorf("%s, last lines of the task log:\n%s", exitStatus, strings.Join(tail, "\n"))
+		}
+		return exitStatus, nil
+	}
+	return "", fmt.Errorf("Wait timeout for: %s", task)
+}
+
+// The debug flag of pxapi is global, it is turned off by the first of the calls of
+// apiWithoutDebug running at the same time and restored by the last one.
+var (
+	apiDebugMutex   sync.Mutex
+	apiDebugCalls   int
+	apiDebugRestore bool
+)
+
+// Just like Session.Login, keep secrets sent in the request body out of the debug log.
+func apiWithoutDebug(call func() (interface{}, error)) (interface{}, error) {
+	apiDebugMutex.Lock()
+	if apiDebugCalls == 0 {
+		apiDebugRestore = *pxapi.Debug
+		*pxapi.Debug = false
+	}
+	apiDebugCalls++
+	apiDebugMutex.Unlock()
+
+	defer func() {
+		apiDebugMutex.Lock()
+		defer apiDebugMutex.Unlock()
+		apiDebugCalls--
+		if apiDebugCalls == 0 {
+			*pxapi.Debug = apiDebugRestore
+		}
+	}()
+	return call()
+}
+
+// proxmox answers 403 when the credentials lack a privilege on the path
+func apiPermissionDenied(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "403")
+}
+
+// Lists the guests (qemu and lxc) visible to the credentials, like /cluster/resources?type=vm does.
+// Credentials with privileges on pools only may be denied the cluster wide listing, the guests are
+// then collected from the members of the pools instead.
+func apiGetGuests(session *pxapi.Session) ([]map[string]interface{}, error) {
+	data, err := apiGetWithParams(session, "/cluster/resources", map[string]interface{}{"type": "vm"})
+	if apiPermissionDenied(err) {
+		logger, _ := CreateSubLogger("api_guests")
+		logger.Debug().Msgf("Listing the guests of the cluster was denied, falling back to the members of the pools: %v", err)
+		return apiGetPoolGuests(session)
+	}
+	if err != nil {
+		return nil, err
+	}
+	guests := []map[string]interface{}{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if guest, ok := item.(map[string]interface{}); ok {
+			guests = append(guests, guest)
+		}
+	}
+	return guests, nil
+}
+
+// Like Client.CheckVmRef, looks up the node and type of a guest, but through apiGetGuests.
+func apiGuestVmRef(session *pxapi.Session, vmid int) (*pxapi.VmRef, error) {
+	guests, err := apiGetGuests(session)
+	if err != nil {
+		return nil, err
+	}
+	for _, guest := range guests {
+		if apiInt(guest["vmid"]) == vmid {
+			vmr := pxapi.NewVmRef(vmid)
+			vmr.SetNode(apiString(guest["node"]))
+			vmr.SetVmType(apiString(guest["type"]))
+			vmr.SetPool(apiString(guest["pool"]))
+			return vmr, nil
+		}
+	}
+	return nil, fmt.Errorf("Vm '%d' not found", vmid)
+}
+
+func apiGetPoolGuests(session *pxapi.Session) ([]map[string]interface{}, error) {
+	pools, err := apiGet(session, "/pools")
+	if err != nil {
+		return nil, err
+	}
+	guests := []map[string]interface{}{}
+	seen := map[int]bool{}
+	poolList, _ := pools.([]interface{})
+	for _, item := range poolList {
+		pool, _ := item.(map[string]interface{})
+		poolID := apiString(pool["poolid"])
+		config, err := apiGetMap(session, apiPath("pools", poolID))
+		if apiPermissionDenied(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		members, _ := config["members"].([]interface{})
+		for _, item := range members {
+			member, ok := item.(map[string]interface{})
+			if !ok || (member["type"] != "qemu" && member["type"] != "lxc") || seen[apiInt(member["vmid"])] {
+				continue
+			}
+			seen[apiInt(member["vmid"])] = true
+			// members lack the pool they are listed in
+			member["pool"] = poolID
+			guests = append(guests, member)
+		}
+	}
+	return guests, nil
+}
+
+// Values in API responses are loosely typed: numbers might come back as strings and
+// booleans as 0/1. These helpers normalise them into the types terraform expects.
+
+func apiString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func apiInt(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
+	default:
+		return 0
+	}
+}
+
+func apiBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int:
+		return v != 0
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// proxmox sends lists either as json arrays or as a single delimited string
+func apiStringList(value interface{}, separators string) []string {
+	list := []string{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			list = append(list, apiString(item))
+		}
+	case string:
+		for _, item := range strings.FieldsFunc(v, func(r rune) bool {
+			return strings.ContainsRune(separators, r)
+		}) {
+			list = append(list, strings.TrimSpace(item))
+		}
+	}
+	return list
+}
+
+// proxmox sends property strings like type=secure,network=10.0.0.0/24 either decoded as an
+// object or as the string, implicitFirstKey is the key of a leading value without a key
+func apiPropertyString(value interface{}, implicitFirstKey string) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case string:
+		if v == "" {
+			return map[string]interface{}{}
+		}
+		return pxapi.ParsePMConf(v, implicitFirstKey)
+	default:
+		return map[string]interface{}{}
+	}
+}
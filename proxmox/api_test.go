@@ -0,0 +1,253 @@
+package proxmox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestApiString(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		output string
+	}{
+		{name: "nil", input: nil, output: ""},
+		{name: "string", input: "comment", output: "comment"},
+		{name: "integral float", input: float64(1700000000), output: "1700000000"},
+		{name: "fractional float", input: 0.5, output: "0.5"},
+		{name: "bool", input: true, output: "true"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := apiString(test.input); result != test.output {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestApiInt(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		output int
+	}{
+		{name: "nil", input: nil, output: 0},
+		{name: "float", input: float64(42), output: 42},
+		{name: "int", input: 7, output: 7},
+		{name: "numeric string", input: "1024", output: 1024},
+		{name: "invalid string", input: "abc", output: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := apiInt(test.input); result != test.output {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestApiBool(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		output bool
+	}{
+		{name: "nil", input: nil, output: false},
+		{name: "bool", input: true, output: true},
+		{name: "float one", input: float64(1), output: true},
+		{name: "float zero", input: float64(0), output: false},
+		{name: "int one", input: 1, output: true},
+		{name: "string one", input: "1", output: true},
+		{name: "string zero", input: "0", output: false},
+		{name: "invalid string", input: "yes", output: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := apiBool(test.input); result != test.output {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestApiStringList(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      interface{}
+		separators string
+		output     []string
+	}{
+		{name: "nil", input: nil, separators: ",", output: []string{}},
+		{name: "empty string", input: "", separators: ",", output: []string{}},
+		{name: "json array", input: []interface{}{"admins", "operators"}, separators: ",", output: []string{"admins", "operators"}},
+		{name: "delimited string", input: "admins,operators", separators: ",", output: []string{"admins", "operators"}},
+		{name: "multiple separators", input: "a; b,c", separators: ",;", output: []string{"a", "b", "c"}},
+		{name: "empty items", input: "a,,b,", separators: ",", output: []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := apiStringList(test.input, test.separators); !reflect.DeepEqual(result, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestApiParamsBody(t *testing.T) {
+	body := apiParamsBody(map[string]interface{}{
+		"subnet":     "10.0.0.0/24",
+		"snat":       true,
+		"gateway":    "",
+		"dhcp-range": []string{"start-address=10.0.0.100,end-address=10.0.0.199", "start-address=10.0.0.200,end-address=10.0.0.249"},
+	}, []string{"gateway"})
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	expected := url.Values{
+		"subnet":     {"10.0.0.0/24"},
+		"snat":       {"1"},
+		"gateway":    {""},
+		"dhcp-range": {"start-address=10.0.0.100,end-address=10.0.0.199", "start-address=10.0.0.200,end-address=10.0.0.249"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, values)
+	}
+}
+
+func TestApiGetGuestsPoolFallback(t *testing.T) {
+	responses := map[string]string{
+		"/pools":             `{"data":[{"poolid":"web"},{"poolid":"db"},{"poolid":"hidden"}]}`,
+		"/pools/web":         `{"data":{"members":[{"id":"qemu/100","vmid":100,"node":"pve1","type":"qemu"},{"id":"storage/pve1/local","type":"storage"}]}}`,
+		"/pools/db":          `{"data":{"members":[{"id":"lxc/200","vmid":200,"node":"pve2","type":"lxc"},{"id":"qemu/100","vmid":100,"node":"pve1","type":"qemu"}]}}`,
+		"/cluster/resources": "",
+		"/pools/hidden":      "",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok || body == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	session, err := pxapi.NewSession(server.URL, server.Client(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	guests, err := apiGetGuests(session)
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	result := []string{}
+	for _, guest := range guests {
+		result = append(result, apiString(guest["id"])+"@"+apiString(guest["pool"]))
+	}
+	sort.Strings(result)
+	expected := []string{"lxc/200@db", "qemu/100@web"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, result)
+	}
+}
+
+func TestApiWaitForTask(t *testing.T) {
+	upid := "UPID:pve1:00001234:00005678:61000000:vzdump:100:root@pam:"
+	lines := []string{
+		"INFO: starting new backup job: vzdump 100",
+		"INFO: creating vzdump archive",
+		"ERROR: Backup of VM 100 failed - no space left on device",
+	}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/nodes/pve1/tasks/" + upid + "/status":
+			polls++
+			if polls < 3 {
+				w.Write([]byte(`{"data":{"status":"running"}}`))
+			} else {
+				w.Write([]byte(`{"data":{"status":"stopped","exitstatus":"job errors"}}`))
+			}
+		case "/nodes/pve1/tasks/" + upid + "/log":
+			// one more line of the log every poll
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			entries := []map[string]interface{}{}
+			if start < polls && start < len(lines) {
+				entries = append(entries, map[string]interface{}{"n": start + 1, "t": lines[start]})
+			} else {
+				entries = append(entries, map[string]interface{}{"n": start + 1, "t": "no content"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": entries})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	apiTaskPollInterval = time.Millisecond
+	defer func() { apiTaskPollInterval = time.Duration(pxapi.TaskStatusCheckInterval) * time.Second }()
+
+	session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+	client, _ := pxapi.NewClient(server.URL, server.Client(), nil, 10)
+	exitStatus, err := apiWaitForTask(session, client, upid)
+	if exitStatus != "job errors" {
+		t.Errorf("expected exit status `job errors`, got `%s`", exitStatus)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "job errors") || !strings.Contains(err.Error(), lines[0]) || !strings.Contains(err.Error(), lines[2]) {
+		t.Errorf("expected the error to end with the task log, got `%+v`", err)
+	}
+
+	if exitStatus, err = apiWaitForTask(session, client, nil); exitStatus != "" || err != nil {
+		t.Errorf("expected no task to wait for, got `%s` and `%+v`", exitStatus, err)
+	}
+}
+
+func TestApiWithoutDebugConcurrent(t *testing.T) {
+	debug := *pxapi.Debug
+	defer func() { *pxapi.Debug = debug }()
+	*pxapi.Debug = true
+
+	const calls = 8
+	var started, done sync.WaitGroup
+	started.Add(calls)
+	done.Add(calls)
+	for i := 0; i < calls; i++ {
+		go func() {
+			defer done.Done()
+			apiWithoutDebug(func() (interface{}, error) {
+				// all of the calls overlap, none of them sees debug turned on
+				started.Done()
+				started.Wait()
+				if *pxapi.Debug {
+					t.Error("expected debug to be off during the call")
+				}
+				return nil, nil
+			})
+		}()
+	}
+	done.Wait()
+	if !*pxapi.Debug {
+		t.Error("expected debug to be restored after the calls")
+	}
+}
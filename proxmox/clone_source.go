@@ -0,0 +1,49 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The source of a clone can be pinned with source_digest, the digest proxmox computes over the
+// configuration of the VM. It changes with every change of the configuration of the template,
+// so a template changed since the digest was taken is noticed before it is cloned. The source
+// and its digest are recorded in the clone_source_* attributes, which tell which version of a
+// template a VM was built from.
+
+var sourceDigestMismatchActions = []string{"error", "warn"}
+
+// reads the digest of the configuration of a VM
+func cloneSourceDigest(session *pxapi.Session, sourceVmr *pxapi.VmRef) (string, error) {
+	config, err := apiGetMap(session, apiPath("nodes", sourceVmr.Node(), "qemu", strconv.Itoa(sourceVmr.VmId()), "config"))
+	if err != nil {
+		return "", err
+	}
+	return apiString(config["digest"]), nil
+}
+
+// Compares the digest of the clone source against the expected one, an empty expected digest
+// accepts every source. With the action warn a mismatch is only logged.
+func checkCloneSourceDigest(digest string, expected string, action string, source string) error {
+	if expected == "" || digest == expected {
+		return nil
+	}
+	err := fmt.Errorf("The configuration of the clone source %s changed, its digest is %s instead of the source_digest %s", source, digest, expected)
+	if action == "warn" {
+		logger, _ := CreateSubLogger("clone_source")
+		logger.Warn().Msg(err.Error())
+		return nil
+	}
+	return err
+}
+
+// Records the source of a clone, the attributes are only set when the VM is created and keep
+// their value when the source changes later.
+func setCloneSource(d *schema.ResourceData, vmid int, name string, digest string) {
+	d.Set("clone_source_vmid", vmid)
+	d.Set("clone_source_name", name)
+	d.Set("clone_source_digest", digest)
+}
@@ -0,0 +1,70 @@
+package proxmox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCloneSourceDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/nodes/pve1/qemu/9000/config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"name":"debian-template","template":1,"digest":"8a2b5c"}}`))
+	}))
+	defer server.Close()
+
+	session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+	sourceVmr := pxapi.NewVmRef(9000)
+	sourceVmr.SetNode("pve1")
+
+	digest, err := cloneSourceDigest(session, sourceVmr)
+	if err != nil || digest != "8a2b5c" {
+		t.Errorf("expected `8a2b5c`, got `%s` and `%+v`", digest, err)
+	}
+}
+
+func TestCheckCloneSourceDigest(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		action   string
+		valid    bool
+	}{
+		{name: "unpinned", expected: "", action: "error", valid: true},
+		{name: "match", expected: "8a2b5c", action: "error", valid: true},
+		{name: "mismatch", expected: "1f3e9d", action: "error", valid: false},
+		{name: "mismatch warned", expected: "1f3e9d", action: "warn", valid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			err := checkCloneSourceDigest("8a2b5c", test.expected, test.action, "debian-template")
+			if test.valid && err != nil {
+				t.Errorf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+		})
+	}
+}
+
+func TestSetCloneSource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVmQemu().Schema, map[string]interface{}{
+		"name":        "web1",
+		"target_node": "pve1",
+		"clone":       "debian-template",
+	})
+	setCloneSource(d, 9000, "debian-template", "8a2b5c")
+
+	if d.Get("clone_source_vmid").(int) != 9000 || d.Get("clone_source_name").(string) != "debian-template" || d.Get("clone_source_digest").(string) != "8a2b5c" {
+		t.Errorf("unexpected clone source `%v`, `%v` and `%v`", d.Get("clone_source_vmid"), d.Get("clone_source_name"), d.Get("clone_source_digest"))
+	}
+}
@@ -0,0 +1,132 @@
+package proxmox
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+)
+
+// A minimal ISO 9660 image for the NoCloud data source of cloud-init: a volume labeled cidata with
+// the files user-data, meta-data and so on in its root directory. Linux mounts images without
+// Rock Ridge or Joliet extensions with the file names in lower case and without the ";1"
+// version, which is how cloud-init finds the files. The image has no timestamps, the same files
+// always give the same image.
+
+const isoSectorSize = 2048
+
+// the sectors of the image, the data of the files follows the root directory
+const (
+	isoPrimaryVolumeSector = 16
+	isoTerminatorSector    = 17
+	isoLPathTableSector    = 18
+	isoMPathTableSector    = 19
+	isoRootSector          = 20
+	isoDataSector          = 21
+)
+
+func isoSectors(size int) int {
+	return (size + isoSectorSize - 1) / isoSectorSize
+}
+
+// both byte orders of a 32 bit number, as ISO 9660 stores most numbers
+func isoBothEndian32(buffer []byte, value uint32) {
+	binary.LittleEndian.PutUint32(buffer[0:4], value)
+	binary.BigEndian.PutUint32(buffer[4:8], value)
+}
+
+func isoBothEndian16(buffer []byte, value uint16) {
+	binary.LittleEndian.PutUint16(buffer[0:2], value)
+	binary.BigEndian.PutUint16(buffer[2:4], value)
+}
+
+func isoPadded(value string, length int) []byte {
+	return []byte(value + strings.Repeat(" ", length-len(value)))
+}
+
+// A directory record, name is the identifier, \x00 for the directory itself and \x01 for its parent.
+func isoDirectoryRecord(name string, sector int, size int, directory bool) []byte {
+	length := 33 + len(name)
+	if len(name)%2 == 0 {
+		length++
+	}
+	record := make([]byte, length)
+	record[0] = byte(length)
+	isoBothEndian32(record[2:10], uint32(sector))
+	isoBothEndian32(record[10:18], uint32(size))
+	if directory {
+		record[25] = 2
+	}
+	isoBothEndian16(record[28:32], 1)
+	record[32] = byte(len(name))
+	copy(record[33:], name)
+	return record
+}
+
+// The identifier of a file in the root directory, e.g. USER-DATA;1.
+func isoFileIdentifier(name string) string {
+	return strings.ToUpper(name) + ";1"
+}
+
+// The image with files in its root directory, label is the volume identifier.
+func isoImage(label string, files map[string][]byte) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	// the records of a directory are sorted by their identifier
+	sort.Slice(names, func(i, j int) bool {
+		return isoFileIdentifier(names[i]) < isoFileIdentifier(names[j])
+	})
+
+	root := isoDirectoryRecord("\x00", isoRootSector, isoSectorSize, true)
+	directory := append([]byte{}, root...)
+	directory = append(directory, isoDirectoryRecord("\x01", isoRootSector, isoSectorSize, true)...)
+	sector := isoDataSector
+	for _, name := range names {
+		directory = append(directory, isoDirectoryRecord(isoFileIdentifier(name), sector, len(files[name]), false)...)
+		sector += isoSectors(len(files[name]))
+	}
+	image := make([]byte, sector*isoSectorSize)
+
+	volume := image[isoPrimaryVolumeSector*isoSectorSize:]
+	volume[0] = 1
+	copy(volume[1:6], "CD001")
+	volume[6] = 1
+	copy(volume[8:40], isoPadded("", 32))
+	copy(volume[40:72], isoPadded(label, 32))
+	isoBothEndian32(volume[80:88], uint32(sector))
+	isoBothEndian16(volume[120:124], 1)
+	isoBothEndian16(volume[124:128], 1)
+	isoBothEndian16(volume[128:132], isoSectorSize)
+	// the path table only lists the root directory
+	isoBothEndian32(volume[132:140], 10)
+	binary.LittleEndian.PutUint32(volume[140:144], isoLPathTableSector)
+	binary.BigEndian.PutUint32(volume[148:152], isoMPathTableSector)
+	copy(volume[156:190], root)
+	copy(volume[190:813], isoPadded("", 623))
+	// the dates of the volume are not specified
+	for _, offset := range []int{813, 830, 847, 864} {
+		copy(volume[offset:offset+16], strings.Repeat("0", 16))
+	}
+	volume[881] = 1
+
+	terminator := image[isoTerminatorSector*isoSectorSize:]
+	terminator[0] = 255
+	copy(terminator[1:6], "CD001")
+	terminator[6] = 1
+
+	for table, order := range map[int]binary.ByteOrder{isoLPathTableSector: binary.LittleEndian, isoMPathTableSector: binary.BigEndian} {
+		record := image[table*isoSectorSize:]
+		record[0] = 1
+		order.PutUint32(record[2:6], isoRootSector)
+		order.PutUint16(record[6:8], 1)
+	}
+
+	copy(image[isoRootSector*isoSectorSize:], directory)
+	sector = isoDataSector
+	for _, name := range names {
+		copy(image[sector*isoSectorSize:], files[name])
+		sector += isoSectors(len(files[name]))
+	}
+	return image
+}
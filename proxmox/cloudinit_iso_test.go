@@ -0,0 +1,61 @@
+package proxmox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestIsoImage(t *testing.T) {
+	files := map[string][]byte{
+		"user-data":      []byte("#cloud-config\nhostname: web\n"),
+		"meta-data":      []byte("instance-id: web\n"),
+		"network-config": bytes.Repeat([]byte("#"), isoSectorSize+1),
+	}
+	image := isoImage("cidata", files)
+
+	// the files take 1, 2 and 1 sectors after the root directory
+	if len(image) != (isoDataSector+4)*isoSectorSize {
+		t.Fatalf("expected %d sectors, got %d bytes", isoDataSector+4, len(image))
+	}
+	volume := image[isoPrimaryVolumeSector*isoSectorSize:]
+	if string(volume[1:6]) != "CD001" || string(bytes.TrimRight(volume[40:72], " ")) != "cidata" {
+		t.Errorf("expected the primary volume descriptor of cidata, got %q %q", volume[1:6], volume[40:72])
+	}
+	if size := binary.LittleEndian.Uint32(volume[80:84]); size != isoDataSector+4 {
+		t.Errorf("expected a volume of %d sectors, got %d", isoDataSector+4, size)
+	}
+	if image[isoTerminatorSector*isoSectorSize] != 255 {
+		t.Errorf("expected the volume descriptor set terminator")
+	}
+
+	// the records of the root directory after . and .. are sorted by identifier
+	directory := image[isoRootSector*isoSectorSize:]
+	offset := int(directory[0]) + int(directory[directory[0]])
+	expected := []struct {
+		identifier string
+		sector     uint32
+		file       string
+	}{
+		{"META-DATA;1", isoDataSector, "meta-data"},
+		{"NETWORK-CONFIG;1", isoDataSector + 1, "network-config"},
+		{"USER-DATA;1", isoDataSector + 3, "user-data"},
+	}
+	for _, file := range expected {
+		record := directory[offset:]
+		identifier := string(record[33 : 33+record[32]])
+		sector := binary.LittleEndian.Uint32(record[2:6])
+		size := binary.LittleEndian.Uint32(record[10:14])
+		if identifier != file.identifier || sector != file.sector || int(size) != len(files[file.file]) {
+			t.Errorf("expected %s at sector %d, got %s at %d", file.identifier, file.sector, identifier, sector)
+		}
+		if content := image[int(sector)*isoSectorSize : int(sector)*isoSectorSize+int(size)]; !bytes.Equal(content, files[file.file]) {
+			t.Errorf("%s: expected the content of %s", identifier, file.file)
+		}
+		offset += int(record[0])
+	}
+
+	if !bytes.Equal(image, isoImage("cidata", files)) {
+		t.Errorf("expected the same image for the same files")
+	}
+}
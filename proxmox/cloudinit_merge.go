@@ -0,0 +1,77 @@
+package proxmox
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// The base user-data of the provider (pm_cloudinit_base_user_data) is merged into the user-data
+// snippets with merge_base_user_data, so every VM runs the bootstrap of the organisation. The
+// snippet is a MIME multipart archive of both cloud-configs, cloud-init merges the parts in order
+// following the Merge-Type header of each part, see
+// https://cloudinit.readthedocs.io/en/latest/reference/merging.html
+
+// Appends the lists of the resource user-data (e.g. packages, runcmd) to the ones of the base, the
+// other values of the base are kept, so the resource user-data can not turn off the baseline.
+const cloudInitDefaultMergeHow = "list(append)+dict(recurse_array)+str()"
+
+// Fixed, so the same user-data always results in the same snippet.
+const cloudInitMergeBoundary = "terraform-provider-proxmox-cloud-config"
+
+func cloudInitIsCloudConfig(userData string) bool {
+	return strings.HasPrefix(strings.TrimLeft(userData, " \t\r\n"), "#cloud-config")
+}
+
+// Merges the user-data of a resource into the base user-data. Without base the user-data is
+// returned as it is.
+func cloudInitMergedUserData(base string, userData string, mergeHow string) (string, error) {
+	if base == "" {
+		return userData, nil
+	}
+	if !cloudInitIsCloudConfig(base) {
+		return "", fmt.Errorf("The base user-data of the provider has to start with #cloud-config")
+	}
+	if !cloudInitIsCloudConfig(userData) {
+		return "", fmt.Errorf("User-data merged with the base user-data has to start with #cloud-config")
+	}
+	if mergeHow == "" {
+		mergeHow = cloudInitDefaultMergeHow
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(cloudInitMergeBoundary); err != nil {
+		return "", err
+	}
+	for _, part := range []string{base, userData} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", `text/cloud-config; charset="utf-8"`)
+		header.Set("Merge-Type", mergeHow)
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasSuffix(part, "\n") {
+			part += "\n"
+		}
+		if _, err = partWriter.Write([]byte(part)); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\nMIME-Version: 1.0\r\n\r\n%s", writer.Boundary(), body.String()), nil
+}
+
+// The sha256 checksum of the base user-data, empty without base.
+func cloudInitBaseUserDataHash(base string) (string, error) {
+	if base == "" {
+		return "", nil
+	}
+	return fileChecksum(strings.NewReader(base), "sha256")
+}
@@ -0,0 +1,85 @@
+package proxmox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloudInitMergedUserData(t *testing.T) {
+	base := "#cloud-config\npackages:\n  - monitoring-agent\n"
+	userData := "#cloud-config\npackages:\n  - nginx"
+
+	merged, err := cloudInitMergedUserData(base, userData, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Content-Type: multipart/mixed; boundary="terraform-provider-proxmox-cloud-config"
+MIME-Version: 1.0
+
+--terraform-provider-proxmox-cloud-config
+Content-Type: text/cloud-config; charset="utf-8"
+Merge-Type: list(append)+dict(recurse_array)+str()
+
+#cloud-config
+packages:
+  - monitoring-agent
+
+--terraform-provider-proxmox-cloud-config
+Content-Type: text/cloud-config; charset="utf-8"
+Merge-Type: list(append)+dict(recurse_array)+str()
+
+#cloud-config
+packages:
+  - nginx
+
+--terraform-provider-proxmox-cloud-config--
+`
+	if strings.ReplaceAll(merged, "\r\n", "\n") != expected {
+		t.Errorf("unexpected merged user-data:\n%s", merged)
+	}
+
+	merged, err = cloudInitMergedUserData(base, userData, "list(prepend)+dict(replace)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(merged, "Merge-Type: list(prepend)+dict(replace)") != 2 {
+		t.Errorf("merge_how not set on both parts:\n%s", merged)
+	}
+}
+
+func TestCloudInitMergedUserDataWithoutBase(t *testing.T) {
+	merged, err := cloudInitMergedUserData("", "#!/bin/sh\necho hello", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged != "#!/bin/sh\necho hello" {
+		t.Errorf("user-data changed without base: %s", merged)
+	}
+}
+
+func TestCloudInitMergedUserDataErrors(t *testing.T) {
+	tests := []struct {
+		base     string
+		userData string
+	}{
+		{"#!/bin/sh\necho base", "#cloud-config\npackages: [nginx]"},
+		{"#cloud-config\npackages: [agent]", "#!/bin/sh\necho hello"},
+		{"#cloud-config\npackages: [agent]", ""},
+	}
+	for _, test := range tests {
+		if _, err := cloudInitMergedUserData(test.base, test.userData, ""); err == nil {
+			t.Errorf("expected an error merging %q into %q", test.userData, test.base)
+		}
+	}
+}
+
+func TestCloudInitBaseUserDataHash(t *testing.T) {
+	if hash, _ := cloudInitBaseUserDataHash(""); hash != "" {
+		t.Errorf("expected no hash without base, got %s", hash)
+	}
+	first, _ := cloudInitBaseUserDataHash("#cloud-config\npackages: [agent]")
+	second, _ := cloudInitBaseUserDataHash("#cloud-config\npackages: [agent, scanner]")
+	if len(first) != 64 || first == second {
+		t.Errorf("unexpected hashes %s and %s", first, second)
+	}
+}
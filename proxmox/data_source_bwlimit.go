@@ -0,0 +1,58 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// operation classes of the datacenter bwlimit option
+var bwLimitClasses = []string{"clone", "default", "migration", "move", "restore"}
+
+func dataSourceBwLimit() *schema.Resource {
+	bwLimitSchema := map[string]*schema.Schema{}
+	for _, class := range bwLimitClasses {
+		bwLimitSchema[class] = &schema.Schema{
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Datacenter bandwidth limit in KiB/s for " + class + " operations, 0 if unlimited",
+		}
+	}
+
+	return &schema.Resource{
+		Description: "Reads the bandwidth limits of the datacenter.",
+
+		Read:   dataSourceBwLimitRead,
+		Schema: bwLimitSchema,
+	}
+}
+
+func dataSourceBwLimitRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	options, err := apiGetMap(pconf.Session, "/cluster/options")
+	if err != nil {
+		return err
+	}
+
+	limits := parseBwLimit(apiString(options["bwlimit"]))
+	for _, class := range bwLimitClasses {
+		d.Set(class, limits[class])
+	}
+	d.SetId("cluster/options/bwlimit")
+
+	return nil
+}
+
+// the bwlimit option looks like "clone=51200,default=102400", classes which are missing are unlimited
+func parseBwLimit(bwlimit string) map[string]int {
+	limits := map[string]int{}
+	if bwlimit == "" {
+		return limits
+	}
+	for class, limit := range pxapi.ParsePMConf(bwlimit, "") {
+		limits[class] = apiInt(limit)
+	}
+	return limits
+}
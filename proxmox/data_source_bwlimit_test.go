@@ -0,0 +1,26 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBwLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output map[string]int
+	}{
+		{name: "empty", input: "", output: map[string]int{}},
+		{name: "single", input: "default=102400", output: map[string]int{"default": 102400}},
+		{name: "several", input: "clone=51200,migration=0,restore=2048", output: map[string]int{"clone": 51200, "migration": 0, "restore": 2048}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := parseBwLimit(test.input); !reflect.DeepEqual(result, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package proxmox
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the options of a guest config which are secrets, their value is replaced by guestConfigRedacted
+var guestConfigSecrets = []string{"cipassword"}
+
+// the digest changes on every write of the config without being part of it
+var guestConfigVolatile = []string{"digest"}
+
+const guestConfigRedacted = "**redacted**"
+
+func dataSourceGuestConfigs() *schema.Resource {
+	return &schema.Resource{
+		Description: "Exports the sanitized configs of the guests of the cluster as JSON, e.g. to audit them against policies in CI.",
+
+		Read: dataSourceGuestConfigsRead,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only export the guests on this node",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only export the guests in this pool",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"qemu", "lxc"}, false),
+				Description:  "Only export guests of this type: qemu or lxc",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only export the guests which have all of these tags",
+			},
+			"include_templates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Export templates too.",
+			},
+			"redact": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Options whose value is redacted besides `cipassword`, e.g. `description`",
+			},
+			"json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The guests with their config as a JSON array, ordered by vmid",
+			},
+		},
+	}
+}
+
+// a guest as it is exported
+type exportedGuest struct {
+	VmId     int                    `json:"vmid"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Node     string                 `json:"node"`
+	Pool     string                 `json:"pool"`
+	Tags     []string               `json:"tags"`
+	Template bool                   `json:"template"`
+	Config   map[string]interface{} `json:"config"`
+}
+
+// The config of a guest without the volatile options and with the secrets redacted. A redacted
+// option is kept, so an audit still sees that it is set.
+func sanitizeGuestConfig(config map[string]interface{}, redact []string) map[string]interface{} {
+	sanitized := map[string]interface{}{}
+	for key, value := range config {
+		switch {
+		case stringInList(key, guestConfigVolatile):
+			continue
+		case stringInList(key, guestConfigSecrets) || stringInList(key, redact):
+			sanitized[key] = guestConfigRedacted
+		default:
+			sanitized[key] = value
+		}
+	}
+	return sanitized
+}
+
+// whether tags contains every one of required
+func hasAllTags(tags []string, required []string) bool {
+	for _, tag := range required {
+		if !stringInList(tag, tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func dataSourceGuestConfigsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	pool := d.Get("pool").(string)
+	guestType := d.Get("type").(string)
+	tags := schemaStringList(d.Get("tags"))
+	redact := schemaStringList(d.Get("redact"))
+
+	list, err := apiGetGuests(pconf.Session)
+	if err != nil {
+		return err
+	}
+
+	guests := []exportedGuest{}
+	for _, item := range list {
+		guest := exportedGuest{
+			VmId:     apiInt(item["vmid"]),
+			Name:     apiString(item["name"]),
+			Type:     apiString(item["type"]),
+			Node:     apiString(item["node"]),
+			Pool:     apiString(item["pool"]),
+			Tags:     parseTags(apiString(item["tags"])),
+			Template: apiBool(item["template"]),
+		}
+		if (node != "" && guest.Node != node) || (pool != "" && guest.Pool != pool) || (guestType != "" && guest.Type != guestType) {
+			continue
+		}
+		if (guest.Template && !d.Get("include_templates").(bool)) || !hasAllTags(guest.Tags, tags) {
+			continue
+		}
+
+		config, err := apiWithoutDebug(func() (interface{}, error) {
+			return apiGetMap(pconf.Session, apiPath("nodes", guest.Node, guest.Type, strconv.Itoa(guest.VmId), "config"))
+		})
+		if err != nil {
+			return fmt.Errorf("Reading the config of guest %d failed: %v", guest.VmId, err)
+		}
+		guest.Config = sanitizeGuestConfig(config.(map[string]interface{}), redact)
+		guests = append(guests, guest)
+	}
+	sort.Slice(guests, func(i, j int) bool { return guests[i].VmId < guests[j].VmId })
+
+	jsonString, err := json.MarshalIndent(guests, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(tags)
+	d.SetId(fmt.Sprintf("cluster/configs/%s/%s/%s/%s", node, pool, guestType, strings.Join(tags, ";")))
+	d.Set("json", string(jsonString))
+	return nil
+}
@@ -0,0 +1,32 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeGuestConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"name": "web1", "agent": "1", "cores": float64(2), "cipassword": "secret",
+		"description": "token: abc", "digest": "0123abcd",
+	}
+	expected := map[string]interface{}{
+		"name": "web1", "agent": "1", "cores": float64(2), "cipassword": guestConfigRedacted,
+		"description": guestConfigRedacted,
+	}
+	if sanitized := sanitizeGuestConfig(config, []string{"description"}); !reflect.DeepEqual(sanitized, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, sanitized)
+	}
+	if config["cipassword"] != "secret" {
+		t.Errorf("expected the config to be left unchanged")
+	}
+}
+
+func TestHasAllTags(t *testing.T) {
+	if !hasAllTags([]string{"prod", "web"}, []string{"web"}) || !hasAllTags([]string{"prod"}, []string{}) {
+		t.Errorf("expected the tags to match")
+	}
+	if hasAllTags([]string{"prod"}, []string{"prod", "web"}) {
+		t.Errorf("expected a missing tag not to match")
+	}
+}
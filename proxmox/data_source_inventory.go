@@ -0,0 +1,206 @@
+package proxmox
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceInventory() *schema.Resource {
+	return &schema.Resource{
+		Description: "Renders the guests of the cluster as an inventory in JSON, YAML or `/etc/hosts` format.",
+
+		Read: dataSourceInventoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the guests on this node",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the guests in this pool",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"qemu", "lxc"}, false),
+				Description:  "Only list guests of this type: qemu or lxc",
+			},
+			"include_templates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "List templates too.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Domain appended to the names of the guests in the hosts block",
+			},
+			"json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The guests as a JSON array",
+			},
+			"yaml": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The guests as a YAML sequence",
+			},
+			"hosts": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The static addresses of the guests as /etc/hosts lines",
+			},
+		},
+	}
+}
+
+// a guest as it is exported, in the order of the fields in the yaml
+type inventoryGuest struct {
+	VmId   int      `json:"vmid"`
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Node   string   `json:"node"`
+	Status string   `json:"status"`
+	Pool   string   `json:"pool"`
+	Tags   []string `json:"tags"`
+	Ips    []string `json:"ips"`
+}
+
+// The static addresses of a guest from its cloud-init ipconfigN (qemu) or its netN (lxc),
+// addresses assigned by dhcp or slaac are unknown to the config and left out.
+func inventoryGuestIps(guestType string, config map[string]interface{}) []string {
+	prefix := "ipconfig"
+	if guestType == "lxc" {
+		prefix = "net"
+	}
+	keys := []string{}
+	for key := range config {
+		if _, err := strconv.Atoi(strings.TrimPrefix(key, prefix)); err == nil && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		first, _ := strconv.Atoi(strings.TrimPrefix(keys[i], prefix))
+		second, _ := strconv.Atoi(strings.TrimPrefix(keys[j], prefix))
+		return first < second
+	})
+
+	ips := []string{}
+	for _, key := range keys {
+		conf := pxapi.ParsePMConf(apiString(config[key]), "")
+		for _, parameter := range []string{"ip", "ip6"} {
+			ip := apiString(conf[parameter])
+			if ip == "" || ip == "dhcp" || ip == "auto" || ip == "manual" {
+				continue
+			}
+			ips = append(ips, strings.SplitN(ip, "/", 2)[0])
+		}
+	}
+	return ips
+}
+
+// JSON strings are valid double quoted YAML scalars and JSON arrays valid YAML flow sequences.
+func inventoryYaml(guests []inventoryGuest) string {
+	if len(guests) == 0 {
+		return "[]\n"
+	}
+	quote := func(value interface{}) string {
+		quoted, _ := json.Marshal(value)
+		return string(quoted)
+	}
+	var yaml strings.Builder
+	for _, guest := range guests {
+		fmt.Fprintf(&yaml, "- vmid: %d\n", guest.VmId)
+		fmt.Fprintf(&yaml, "  name: %s\n", quote(guest.Name))
+		fmt.Fprintf(&yaml, "  type: %s\n", quote(guest.Type))
+		fmt.Fprintf(&yaml, "  node: %s\n", quote(guest.Node))
+		fmt.Fprintf(&yaml, "  status: %s\n", quote(guest.Status))
+		fmt.Fprintf(&yaml, "  pool: %s\n", quote(guest.Pool))
+		fmt.Fprintf(&yaml, "  tags: %s\n", quote(guest.Tags))
+		fmt.Fprintf(&yaml, "  ips: %s\n", quote(guest.Ips))
+	}
+	return yaml.String()
+}
+
+// one line per address, e.g. "10.0.0.5 web1.example.com web1"
+func inventoryHosts(guests []inventoryGuest, domain string) string {
+	var hosts strings.Builder
+	for _, guest := range guests {
+		if guest.Name == "" {
+			continue
+		}
+		names := guest.Name
+		if domain != "" {
+			names = fmt.Sprintf("%s.%s %s", guest.Name, strings.TrimPrefix(domain, "."), guest.Name)
+		}
+		for _, ip := range guest.Ips {
+			fmt.Fprintf(&hosts, "%s %s\n", ip, names)
+		}
+	}
+	return hosts.String()
+}
+
+func dataSourceInventoryRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	pool := d.Get("pool").(string)
+	guestType := d.Get("type").(string)
+
+	list, err := apiGetGuests(pconf.Session)
+	if err != nil {
+		return err
+	}
+
+	guests := []inventoryGuest{}
+	for _, item := range list {
+		guest := inventoryGuest{
+			VmId:   apiInt(item["vmid"]),
+			Name:   apiString(item["name"]),
+			Type:   apiString(item["type"]),
+			Node:   apiString(item["node"]),
+			Status: apiString(item["status"]),
+			Pool:   apiString(item["pool"]),
+			Tags:   parseTags(apiString(item["tags"])),
+		}
+		if (node != "" && guest.Node != node) || (pool != "" && guest.Pool != pool) || (guestType != "" && guest.Type != guestType) {
+			continue
+		}
+		if apiBool(item["template"]) && !d.Get("include_templates").(bool) {
+			continue
+		}
+
+		// guests whose config the credentials may not read are listed without addresses
+		config, err := apiGetMap(pconf.Session, apiPath("nodes", guest.Node, guest.Type, strconv.Itoa(guest.VmId), "config"))
+		if err != nil && !apiPermissionDenied(err) {
+			return err
+		}
+		guest.Ips = inventoryGuestIps(guest.Type, config)
+		guests = append(guests, guest)
+	}
+	sort.Slice(guests, func(i, j int) bool { return guests[i].VmId < guests[j].VmId })
+
+	jsonString, err := json.MarshalIndent(guests, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("cluster/inventory/%s/%s/%s", node, pool, guestType))
+	d.Set("json", string(jsonString))
+	d.Set("yaml", inventoryYaml(guests))
+	d.Set("hosts", inventoryHosts(guests, d.Get("domain").(string)))
+	return nil
+}
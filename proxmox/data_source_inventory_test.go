@@ -0,0 +1,79 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInventoryGuestIps(t *testing.T) {
+	tests := []struct {
+		name      string
+		guestType string
+		config    map[string]interface{}
+		expected  []string
+	}{
+		{"qemu static", "qemu", map[string]interface{}{
+			"ipconfig1": "ip=192.168.1.5/24",
+			"ipconfig0": "ip=10.0.0.5/24,gw=10.0.0.1,ip6=fd00::5/64",
+			"net0":      "virtio=AA:BB:CC:DD:EE:FF,bridge=vmbr0",
+		}, []string{"10.0.0.5", "fd00::5", "192.168.1.5"}},
+		{"qemu dhcp", "qemu", map[string]interface{}{"ipconfig0": "ip=dhcp,ip6=auto"}, []string{}},
+		{"lxc", "lxc", map[string]interface{}{
+			"net0": "name=eth0,bridge=vmbr0,hwaddr=AA:BB:CC:DD:EE:FF,ip=10.0.0.6/24,type=veth",
+			"net1": "name=eth1,bridge=vmbr1,ip=dhcp,ip6=manual",
+		}, []string{"10.0.0.6"}},
+		{"no config", "lxc", nil, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if ips := inventoryGuestIps(test.guestType, test.config); !reflect.DeepEqual(ips, test.expected) {
+				t.Errorf("%s: expected `%v`, got `%v`", test.name, test.expected, ips)
+			}
+		})
+	}
+}
+
+func TestInventoryFormats(t *testing.T) {
+	guests := []inventoryGuest{
+		{VmId: 100, Name: "web1", Type: "qemu", Node: "pve1", Status: "running", Tags: []string{"web"}, Ips: []string{"10.0.0.5", "fd00::5"}},
+		{VmId: 101, Name: "db1", Type: "lxc", Node: "pve2", Status: "stopped", Pool: "prod", Tags: []string{}, Ips: []string{}},
+	}
+
+	expectedYaml := `- vmid: 100
+  name: "web1"
+  type: "qemu"
+  node: "pve1"
+  status: "running"
+  pool: ""
+  tags: ["web"]
+  ips: ["10.0.0.5","fd00::5"]
+- vmid: 101
+  name: "db1"
+  type: "lxc"
+  node: "pve2"
+  status: "stopped"
+  pool: "prod"
+  tags: []
+  ips: []
+`
+	if yaml := inventoryYaml(guests); yaml != expectedYaml {
+		t.Errorf("expected yaml `%s`, got `%s`", expectedYaml, yaml)
+	}
+	if yaml := inventoryYaml([]inventoryGuest{}); yaml != "[]\n" {
+		t.Errorf("expected yaml of no guests `[]`, got `%s`", yaml)
+	}
+
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"", "10.0.0.5 web1\nfd00::5 web1\n"},
+		{"example.com", "10.0.0.5 web1.example.com web1\nfd00::5 web1.example.com web1\n"},
+	}
+	for _, test := range tests {
+		if hosts := inventoryHosts(guests, test.domain); hosts != test.expected {
+			t.Errorf("expected hosts `%s`, got `%s`", test.expected, hosts)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceNodePciDevices() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the PCI devices of a node with their IOMMU groups, to check a device can be passed through on its own.",
+
+		Read: dataSourceNodePciDevicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The node the devices are listed of",
+			},
+			"vendor_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return devices of this vendor, e.g. 10de",
+			},
+			"device_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return devices with this device id, e.g. 1eb8",
+			},
+			"iommu_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the IOMMU is enabled on the node, without it no device can be passed through",
+			},
+			"devices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The PCI address of the device, e.g. 0000:01:00.0",
+						},
+						"vendor_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vendor id of the device",
+						},
+						"device_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The device id of the device",
+						},
+						"vendor_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the vendor reported by the node",
+						},
+						"device_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the device reported by the node",
+						},
+						"class": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The PCI class of the device, e.g. 0x030000 for a VGA controller",
+						},
+						"mdev": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the device supports mediated devices",
+						},
+						"iommu_group": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The IOMMU group of the device, -1 without IOMMU",
+						},
+						"iommu_group_devices": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The addresses of the other devices in the IOMMU group of the device",
+						},
+						"isolated": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the IOMMU group of the device only contains the device and the other functions of its slot, so it can be passed through without other devices of the node",
+						},
+					},
+				},
+				Description: "The matching devices, sorted by address.",
+			},
+		},
+	}
+}
+
+// a device of /nodes/<node>/hardware/pci
+type nodePciDevice struct {
+	path              string
+	vendorID          string
+	deviceID          string
+	vendorName        string
+	deviceName        string
+	class             string
+	mdev              bool
+	iommuGroup        int
+	iommuGroupDevices []string
+	isolated          bool
+}
+
+// the slot of a PCI address, 0000:01:00.1 is function 1 of slot 0000:01:00
+func pciSlot(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// Groups the devices by their IOMMU group. A device is isolated when the other devices of its
+// group are functions of its own slot, like the audio function of a GPU, which are passed
+// through together with it. Without IOMMU, group -1, no device is isolated.
+func nodePciDevices(entries interface{}) []nodePciDevice {
+	devices := []nodePciDevice{}
+	groups := map[int][]string{}
+	list, _ := entries.([]interface{})
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		device := nodePciDevice{
+			path:       apiString(entry["id"]),
+			vendorID:   strings.TrimPrefix(apiString(entry["vendor"]), "0x"),
+			deviceID:   strings.TrimPrefix(apiString(entry["device"]), "0x"),
+			vendorName: apiString(entry["vendor_name"]),
+			deviceName: apiString(entry["device_name"]),
+			class:      apiString(entry["class"]),
+			mdev:       apiBool(entry["mdev"]),
+			iommuGroup: -1,
+		}
+		if _, ok := entry["iommugroup"]; ok {
+			device.iommuGroup = apiInt(entry["iommugroup"])
+		}
+		devices = append(devices, device)
+		groups[device.iommuGroup] = append(groups[device.iommuGroup], device.path)
+	}
+
+	for i := range devices {
+		device := &devices[i]
+		device.iommuGroupDevices = []string{}
+		device.isolated = device.iommuGroup >= 0
+		for _, path := range groups[device.iommuGroup] {
+			if path == device.path || device.iommuGroup < 0 {
+				continue
+			}
+			device.iommuGroupDevices = append(device.iommuGroupDevices, path)
+			if pciSlot(path) != pciSlot(device.path) {
+				device.isolated = false
+			}
+		}
+		sort.Strings(device.iommuGroupDevices)
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		return devices[i].path < devices[j].path
+	})
+	return devices
+}
+
+func dataSourceNodePciDevicesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	vendorID := strings.TrimPrefix(d.Get("vendor_id").(string), "0x")
+	deviceID := strings.TrimPrefix(d.Get("device_id").(string), "0x")
+
+	// bridges, memory controllers and processors are left out by the node, they don't prevent a
+	// passthrough of the other devices of their group
+	entries, err := apiGet(pconf.Session, apiPath("nodes", node, "hardware", "pci"))
+	if err != nil {
+		return err
+	}
+
+	iommuEnabled := false
+	result := []map[string]interface{}{}
+	for _, device := range nodePciDevices(entries) {
+		iommuEnabled = iommuEnabled || device.iommuGroup >= 0
+		if (vendorID != "" && device.vendorID != vendorID) || (deviceID != "" && device.deviceID != deviceID) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"path":                device.path,
+			"vendor_id":           device.vendorID,
+			"device_id":           device.deviceID,
+			"vendor_name":         device.vendorName,
+			"device_name":         device.deviceName,
+			"class":               device.class,
+			"mdev":                device.mdev,
+			"iommu_group":         device.iommuGroup,
+			"iommu_group_devices": device.iommuGroupDevices,
+			"isolated":            device.isolated,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("nodes/%s/hardware/pci/%s:%s", node, vendorID, deviceID))
+	d.Set("iommu_enabled", iommuEnabled)
+	return d.Set("devices", result)
+}
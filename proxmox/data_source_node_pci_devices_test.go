@@ -0,0 +1,42 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodePciDevices(t *testing.T) {
+	entries := []interface{}{
+		map[string]interface{}{"id": "0000:01:00.1", "vendor": "0x10de", "device": "0x10f8", "class": "0x040300", "iommugroup": 12},
+		map[string]interface{}{"id": "0000:01:00.0", "vendor": "0x10de", "device": "0x1eb8", "class": "0x030000", "iommugroup": 12, "device_name": "TU104GL [Tesla T4]"},
+		map[string]interface{}{"id": "0000:02:00.0", "vendor": "0x8086", "device": "0x1533", "class": "0x020000", "iommugroup": 13},
+		map[string]interface{}{"id": "0000:03:00.0", "vendor": "0x8086", "device": "0x1533", "class": "0x020000", "iommugroup": 13},
+	}
+
+	devices := nodePciDevices(entries)
+	paths := []string{}
+	isolated := []bool{}
+	for _, device := range devices {
+		paths = append(paths, device.path)
+		isolated = append(isolated, device.isolated)
+	}
+	if !reflect.DeepEqual(paths, []string{"0000:01:00.0", "0000:01:00.1", "0000:02:00.0", "0000:03:00.0"}) {
+		t.Errorf("unexpected order `%v`", paths)
+	}
+	// the audio function of the GPU doesn't break its isolation, the NICs sharing a group do
+	if !reflect.DeepEqual(isolated, []bool{true, true, false, false}) {
+		t.Errorf("unexpected isolation `%v`", isolated)
+	}
+	if devices[0].vendorID != "10de" || devices[0].deviceID != "1eb8" || !reflect.DeepEqual(devices[0].iommuGroupDevices, []string{"0000:01:00.1"}) {
+		t.Errorf("unexpected device `%+v`", devices[0])
+	}
+
+	// without IOMMU every device is in group -1
+	devices = nodePciDevices([]interface{}{
+		map[string]interface{}{"id": "0000:01:00.0", "iommugroup": -1},
+		map[string]interface{}{"id": "0000:02:00.0", "iommugroup": -1},
+	})
+	if devices[0].isolated || len(devices[0].iommuGroupDevices) != 0 {
+		t.Errorf("unexpected device without IOMMU `%+v`", devices[0])
+	}
+}
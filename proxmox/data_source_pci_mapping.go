@@ -0,0 +1,253 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// capabilities the pci mappings can be filtered by
+var pciMappingCapabilities = []string{"mdev", "live_migration", "virtual_function"}
+
+func dataSourcePciMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the PCI resource mappings of the cluster with a device on a node.",
+
+		Read: dataSourcePciMappingRead,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Only mappings with a device on this node are returned",
+			},
+			"capability": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(pciMappingCapabilities, false),
+				Description:  "Only return mappings with this capability: mdev, live_migration or virtual_function (an SR-IOV VF)",
+			},
+			"exclude_in_use": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Leave out mappings which are used by a hostpci device of a VM on the node",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ids of the matching mappings, usable as mapping of a hostpci device",
+			},
+			"mappings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the mapping.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the mapping.",
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The PCI address of the device on the node",
+						},
+						"device_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vendor and device id, e.g. 8086:154c",
+						},
+						"device_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the device reported by the node.",
+						},
+						"iommu_group": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The IOMMU group of the device.",
+						},
+					},
+				},
+				Description: "The matching mappings, sorted by id.",
+			},
+		},
+	}
+}
+
+// a mapping of /cluster/mapping/pci reduced to its device on one node
+type pciMapping struct {
+	id            string
+	description   string
+	path          string
+	deviceID      string
+	deviceName    string
+	iommuGroup    int
+	mdev          bool
+	liveMigration bool
+}
+
+func (mapping pciMapping) hasCapability(capability string) bool {
+	switch capability {
+	case "mdev":
+		return mapping.mdev
+	case "live_migration":
+		return mapping.liveMigration
+	case "virtual_function":
+		// lspci names VFs e.g. "Ethernet Virtual Function 700 Series" or "MT28800 Family [ConnectX-5 Ex Virtual Function]"
+		return strings.Contains(mapping.deviceName, "Virtual Function")
+	}
+	return true
+}
+
+// Picks the device on node out of the map of every mapping. Mappings without a device on the
+// node or with failed checks are left out.
+func pciMappingsOnNode(entries interface{}, node string) []pciMapping {
+	mappings := []pciMapping{}
+	list, _ := entries.([]interface{})
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		failed := false
+		checks, _ := entry["checks"].([]interface{})
+		for _, check := range checks {
+			if check, ok := check.(map[string]interface{}); ok && apiString(check["severity"]) == "error" {
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+		for _, device := range apiStringList(entry["map"], "") {
+			conf := pxapi.ParsePMConf(device, "")
+			if apiString(conf["node"]) != node {
+				continue
+			}
+			mappings = append(mappings, pciMapping{
+				id:            apiString(entry["id"]),
+				description:   apiString(entry["description"]),
+				path:          apiString(conf["path"]),
+				deviceID:      apiString(conf["id"]),
+				iommuGroup:    apiInt(conf["iommugroup"]),
+				mdev:          apiBool(entry["mdev"]),
+				liveMigration: apiBool(entry["live-migration-capable"]),
+			})
+			break
+		}
+	}
+	return mappings
+}
+
+// The ids of the mappings used by the hostpci devices of the VMs on node. VMs whose config the
+// credentials may not read are skipped.
+func pciMappingsInUse(session *pxapi.Session, node string) ([]string, error) {
+	guests, err := apiGetGuests(session)
+	if err != nil {
+		return nil, err
+	}
+	used := []string{}
+	for _, guest := range guests {
+		if apiString(guest["node"]) != node || apiString(guest["type"]) != "qemu" {
+			continue
+		}
+		config, err := apiGetMap(session, apiPath("nodes", node, "qemu", strconv.Itoa(apiInt(guest["vmid"])), "config"))
+		if apiPermissionDenied(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range config {
+			if !strings.HasPrefix(key, "hostpci") {
+				continue
+			}
+			if mapping := apiString(pxapi.ParsePMConf(apiString(value), "host")["mapping"]); mapping != "" {
+				used = append(used, mapping)
+			}
+		}
+	}
+	return used, nil
+}
+
+func dataSourcePciMappingRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	capability := d.Get("capability").(string)
+
+	entries, err := apiGetWithParams(pconf.Session, "/cluster/mapping/pci", map[string]interface{}{
+		"check-node": node,
+	})
+	if err != nil {
+		return err
+	}
+	mappings := pciMappingsOnNode(entries, node)
+
+	// listing the devices of the node requires Sys.Audit on it, only the vf filter needs their names
+	devices, err := apiGet(pconf.Session, apiPath("nodes", node, "hardware", "pci"))
+	if apiPermissionDenied(err) && capability != "virtual_function" {
+		devices, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	deviceNames := map[string]string{}
+	deviceList, _ := devices.([]interface{})
+	for _, item := range deviceList {
+		device, _ := item.(map[string]interface{})
+		deviceNames[apiString(device["id"])] = apiString(device["device_name"])
+	}
+
+	used := []string{}
+	if d.Get("exclude_in_use").(bool) {
+		if used, err = pciMappingsInUse(pconf.Session, node); err != nil {
+			return err
+		}
+	}
+
+	ids := []string{}
+	result := []map[string]interface{}{}
+	for _, mapping := range mappings {
+		// lspci leaves out the domain of the address when it is 0000
+		mapping.deviceName = deviceNames[mapping.path]
+		if mapping.deviceName == "" {
+			mapping.deviceName = deviceNames[strings.TrimPrefix(mapping.path, "0000:")]
+		}
+		if !mapping.hasCapability(capability) || stringInList(mapping.id, used) {
+			continue
+		}
+		ids = append(ids, mapping.id)
+		result = append(result, map[string]interface{}{
+			"id":          mapping.id,
+			"description": mapping.description,
+			"path":        mapping.path,
+			"device_id":   mapping.deviceID,
+			"device_name": mapping.deviceName,
+			"iommu_group": mapping.iommuGroup,
+		})
+	}
+	sort.Strings(ids)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i]["id"].(string) < result[j]["id"].(string)
+	})
+
+	d.SetId(fmt.Sprintf("cluster/mapping/pci/%s/%s", node, capability))
+	d.Set("ids", ids)
+	return d.Set("mappings", result)
+}
@@ -0,0 +1,68 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPciMappingsOnNode(t *testing.T) {
+	entries := []interface{}{
+		map[string]interface{}{
+			"id":          "vf-1",
+			"description": "first VF",
+			"map": []interface{}{
+				"node=pve1,path=0000:01:02.0,id=8086:154c,iommugroup=50",
+				"node=pve2,path=0000:03:02.0,id=8086:154c,iommugroup=60",
+			},
+			"live-migration-capable": 1,
+		},
+		map[string]interface{}{
+			"id":     "broken",
+			"map":    []interface{}{"node=pve1,path=0000:01:02.1,id=8086:154c,iommugroup=51"},
+			"checks": []interface{}{map[string]interface{}{"severity": "error", "message": "device not found"}},
+		},
+		map[string]interface{}{
+			"id":  "other-node",
+			"map": []interface{}{"node=pve2,path=0000:04:00.0,id=10de:1eb8,iommugroup=70"},
+		},
+	}
+
+	result := pciMappingsOnNode(entries, "pve1")
+	expected := []pciMapping{{
+		id:            "vf-1",
+		description:   "first VF",
+		path:          "0000:01:02.0",
+		deviceID:      "8086:154c",
+		iommuGroup:    50,
+		liveMigration: true,
+	}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected `%+v`, got `%+v`", expected, result)
+	}
+}
+
+func TestPciMappingHasCapability(t *testing.T) {
+	vf := pciMapping{id: "vf", deviceName: "Ethernet Virtual Function 700 Series"}
+	gpu := pciMapping{id: "gpu", deviceName: "TU104GL [Tesla T4]", mdev: true}
+
+	tests := []struct {
+		name       string
+		mapping    pciMapping
+		capability string
+		output     bool
+	}{
+		{name: "no filter", mapping: gpu, capability: "", output: true},
+		{name: "vf", mapping: vf, capability: "virtual_function", output: true},
+		{name: "not a vf", mapping: gpu, capability: "virtual_function", output: false},
+		{name: "mdev", mapping: gpu, capability: "mdev", output: true},
+		{name: "no live migration", mapping: vf, capability: "live_migration", output: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := test.mapping.hasCapability(test.capability); result != test.output {
+				t.Errorf("%s: expected `%v`, got `%v`", test.name, test.output, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceVmQemuAgentFile() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads a file from a running guest through the QEMU guest agent.",
+
+		Read: dataSourceVmQemuAgentFileRead,
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The id of the guest. The guest must be running and have the guest agent installed and enabled.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Absolute path of the file inside the guest",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The content of the file.",
+			},
+			"truncated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "The guest agent only returns the first 16MiB of a file, true if the file is larger",
+			},
+		},
+	}
+}
+
+func dataSourceVmQemuAgentFileRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmr, err := apiGuestVmRef(pconf.Session, d.Get("vmid").(int))
+	if err != nil {
+		return err
+	}
+	path := d.Get("path").(string)
+
+	data, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiGetWithParams(pconf.Session, apiPath("nodes", vmr.Node(), "qemu", strconv.Itoa(vmr.VmId()), "agent", "file-read"), map[string]interface{}{
+			"file": path,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Reading %s from guest %d failed: %v", path, vmr.VmId(), err)
+	}
+	file, _ := data.(map[string]interface{})
+
+	d.SetId(fmt.Sprintf("%d:%s", vmr.VmId(), path))
+	d.Set("content", apiString(file["content"]))
+	d.Set("truncated", apiBool(file["truncated"]))
+	return nil
+}
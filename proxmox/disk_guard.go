@@ -0,0 +1,78 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// Resizing a disk of a running VM can leave the file systems of the guest inconsistent when it
+// writes at the same time. disk_operation_guard freezes the file systems through the guest agent
+// or suspends the VM while the disks are resized. Updates don't move disks between storages, so
+// resizes are the only guarded operations.
+
+var diskOperationGuards = []string{"none", "freeze", "suspend"}
+
+type diskGuard struct {
+	session *pxapi.Session
+	client  *pxapi.Client
+	vmr     *pxapi.VmRef
+	mode    string
+}
+
+// Runs operation, guarded when the VM is running. A nil guard runs it unguarded, e.g. while
+// creating the VM.
+func (g *diskGuard) run(operation func() error) error {
+	if g == nil || g.mode == "" || g.mode == "none" {
+		return operation()
+	}
+	state, err := g.client.GetVmState(g.vmr)
+	if err != nil {
+		return err
+	}
+	if state["status"] != "running" {
+		return operation()
+	}
+
+	logger, _ := CreateSubLogger("disk_guard")
+	vmID := strconv.Itoa(g.vmr.VmId())
+	agentPath := func(command string) string {
+		return apiPath("nodes", g.vmr.Node(), "qemu", vmID, "agent", command)
+	}
+	statusPath := func(command string) string {
+		return apiPath("nodes", g.vmr.Node(), "qemu", vmID, "status", command)
+	}
+
+	switch g.mode {
+	case "freeze":
+		logger.Info().Int("vmid", g.vmr.VmId()).Msg("Freezing the file systems of the guest")
+		if _, err = apiPost(g.session, agentPath("fsfreeze-freeze"), map[string]interface{}{}); err != nil {
+			return fmt.Errorf("Freezing the file systems of VM %d failed, the guest agent has to run: %v", g.vmr.VmId(), err)
+		}
+		err = operation()
+		if _, thawErr := apiPost(g.session, agentPath("fsfreeze-thaw"), map[string]interface{}{}); thawErr != nil {
+			return diskGuardError(err, fmt.Errorf("Thawing the file systems of VM %d failed: %v", g.vmr.VmId(), thawErr))
+		}
+		return err
+	case "suspend":
+		logger.Info().Int("vmid", g.vmr.VmId()).Msg("Suspending the VM")
+		if _, err = apiPostTask(g.session, g.client, statusPath("suspend"), map[string]interface{}{}); err != nil {
+			return fmt.Errorf("Suspending VM %d failed: %v", g.vmr.VmId(), err)
+		}
+		err = operation()
+		if _, resumeErr := apiPostTask(g.session, g.client, statusPath("resume"), map[string]interface{}{}); resumeErr != nil {
+			return diskGuardError(err, fmt.Errorf("Resuming VM %d failed: %v", g.vmr.VmId(), resumeErr))
+		}
+		return err
+	}
+	return fmt.Errorf("Unknown disk_operation_guard %s", g.mode)
+}
+
+// The error of a guarded operation together with the one of lifting the guard afterwards.
+func diskGuardError(operationErr error, guardErr error) error {
+	if operationErr == nil {
+		return guardErr
+	}
+	return fmt.Errorf("%v, %v as well", operationErr, guardErr)
+}
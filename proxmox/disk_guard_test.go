@@ -0,0 +1,87 @@
+package proxmox
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestDiskGuard(t *testing.T) {
+	upid := "UPID:pve1:00001234:00005678:61000000:qmsuspend:100:root@pam:"
+	apiTaskPollInterval = time.Millisecond
+	defer func() { apiTaskPollInterval = time.Duration(pxapi.TaskStatusCheckInterval) * time.Second }()
+
+	tests := []struct {
+		name     string
+		mode     string
+		status   string
+		expected []string
+	}{
+		{name: "none", mode: "none", status: "running", expected: []string{"resize"}},
+		{name: "freeze", mode: "freeze", status: "running", expected: []string{"status", "fsfreeze-freeze", "resize", "fsfreeze-thaw"}},
+		{name: "suspend", mode: "suspend", status: "running", expected: []string{"status", "suspend", "resize", "resume"}},
+		{name: "stopped", mode: "freeze", status: "stopped", expected: []string{"status", "resize"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			calls := []string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/status/current"):
+					calls = append(calls, "status")
+					w.Write([]byte(`{"data":{"status":"` + test.status + `"}}`))
+				case strings.Contains(r.URL.Path, "/agent/"):
+					calls = append(calls, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+					w.Write([]byte(`{"data":null}`))
+				case strings.HasSuffix(r.URL.Path, "/status/suspend"), strings.HasSuffix(r.URL.Path, "/status/resume"):
+					calls = append(calls, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+					w.Write([]byte(`{"data":"` + upid + `"}`))
+				case strings.HasSuffix(r.URL.Path, "/tasks/"+upid+"/status"):
+					w.Write([]byte(`{"data":{"status":"stopped","exitstatus":"OK"}}`))
+				case strings.HasSuffix(r.URL.Path, "/tasks/"+upid+"/log"):
+					w.Write([]byte(`{"data":[]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+			client, _ := pxapi.NewClient(server.URL, server.Client(), nil, 10)
+			vmr := pxapi.NewVmRef(100)
+			vmr.SetNode("pve1")
+			vmr.SetVmType("qemu")
+
+			guard := &diskGuard{session: session, client: client, vmr: vmr, mode: test.mode}
+			err := guard.run(func() error {
+				calls = append(calls, "resize")
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if !reflect.DeepEqual(calls, test.expected) {
+				t.Errorf("%s: expected calls `%v`, got `%v`", test.name, test.expected, calls)
+			}
+		})
+	}
+}
+
+func TestDiskGuardError(t *testing.T) {
+	guardErr := errors.New("Thawing the file systems of VM 100 failed: 500 agent not running")
+	if err := diskGuardError(nil, guardErr); err != guardErr {
+		t.Errorf("expected `%v`, got `%v`", guardErr, err)
+	}
+	err := diskGuardError(errors.New("resize failed"), guardErr)
+	if !strings.Contains(err.Error(), "resize failed") || !strings.Contains(err.Error(), "Thawing") {
+		t.Errorf("expected both errors, got `%v`", err)
+	}
+}
@@ -0,0 +1,141 @@
+package proxmox
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pm_api_url may list the API of several nodes of the cluster, e.g.
+// "https://pve1:8006/api2/json,https://pve2:8006/api2/json". Tickets and API tokens are valid
+// on every node, so requests move on to the next endpoint when one cannot be reached.
+
+// how long an endpoint which could not be reached is skipped
+var failoverDownTime = 30 * time.Second
+
+// Splits pm_api_url into the URLs of the endpoints, separated by commas and/or whitespace.
+func apiURLs(pmApiUrl string) ([]string, error) {
+	urls := strings.FieldsFunc(pmApiUrl, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("pm_api_url must contain at least one URL")
+	}
+	for _, apiUrl := range urls {
+		parsed, err := url.Parse(apiUrl)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("Invalid URL %q in pm_api_url, e.g. https://host.fqdn:8006/api2/json", apiUrl)
+		}
+	}
+	return urls, nil
+}
+
+// failoverTransport sends the requests to the first endpoint which can be reached. The
+// requests are built for the first endpoint of the list, the transport moves them to the
+// endpoint in use. An endpoint which could not be reached is skipped for failoverDownTime,
+// the endpoint in use only changes when it fails.
+type failoverTransport struct {
+	base      http.RoundTripper
+	endpoints []*url.URL
+	mutex     sync.Mutex
+	current   int
+	downUntil []time.Time
+}
+
+func newFailoverTransport(base http.RoundTripper, urls []string) (http.RoundTripper, error) {
+	if len(urls) == 1 {
+		return base, nil
+	}
+	endpoints := []*url.URL{}
+	for _, apiUrl := range urls {
+		endpoint, err := url.Parse(apiUrl)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+		endpoints = append(endpoints, endpoint)
+	}
+	return &failoverTransport{base: base, endpoints: endpoints, downUntil: make([]time.Time, len(endpoints))}, nil
+}
+
+// the endpoints in the order they are tried, endpoints marked as down last
+func (t *failoverTransport) order() []int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	up, down := []int{}, []int{}
+	now := time.Now()
+	for i := range t.endpoints {
+		index := (t.current + i) % len(t.endpoints)
+		if now.Before(t.downUntil[index]) {
+			down = append(down, index)
+		} else {
+			up = append(up, index)
+		}
+	}
+	return append(up, down...)
+}
+
+func (t *failoverTransport) reached(index int, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err != nil {
+		t.downUntil[index] = time.Now().Add(failoverDownTime)
+		return
+	}
+	t.current = index
+	t.downUntil[index] = time.Time{}
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	primary := t.endpoints[0]
+	var resp *http.Response
+	var err error
+	for attempt, index := range t.order() {
+		endpoint := t.endpoints[index]
+		endpointReq := req.Clone(req.Context())
+		endpointReq.URL.Scheme = endpoint.Scheme
+		endpointReq.URL.Host = endpoint.Host
+		endpointReq.Host = ""
+		if strings.HasPrefix(req.URL.Path, primary.Path) {
+			endpointReq.URL.Path = endpoint.Path + strings.TrimPrefix(req.URL.Path, primary.Path)
+		}
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			if endpointReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = t.base.RoundTrip(endpointReq)
+		if err == nil || !failoverError(req, err) {
+			t.reached(index, nil)
+			return resp, err
+		}
+		t.reached(index, err)
+
+		logger, _ := CreateSubLogger("api_failover")
+		logger.Warn().Msgf("API endpoint %s could not be reached, trying the next one: %v", endpoint.Host, err)
+	}
+	return resp, err
+}
+
+// A request which failed to connect never reached the endpoint and can be sent to another one.
+// Other network errors are only failed over for GET requests, the request may have had an effect.
+func failoverError(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && req.Method == http.MethodGet
+}
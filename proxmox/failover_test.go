@@ -0,0 +1,72 @@
+package proxmox
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApiURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output []string
+		err    bool
+	}{
+		{name: "single", input: "https://pve1:8006/api2/json", output: []string{"https://pve1:8006/api2/json"}},
+		{name: "list", input: "https://pve1:8006/api2/json, https://pve2:8006/api2/json", output: []string{"https://pve1:8006/api2/json", "https://pve2:8006/api2/json"}},
+		{name: "empty", input: " ", err: true},
+		{name: "no host", input: "https://pve1:8006/api2/json,pve2", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			urls, err := apiURLs(test.input)
+			if (err != nil) != test.err {
+				t.Fatalf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+			if !test.err && !reflect.DeepEqual(urls, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, urls)
+			}
+		})
+	}
+}
+
+func TestFailoverTransport(t *testing.T) {
+	var paths, bodies []string
+	alive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, string(body))
+	}))
+	defer alive.Close()
+	// a server which is gone refuses the connections
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	dead.Close()
+
+	transport, err := newFailoverTransport(http.DefaultTransport, []string{dead.URL + "/api2/json", alive.URL + "/api2/json"})
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	if _, err = client.Post(dead.URL+"/api2/json/nodes/pve1/qemu", "application/x-www-form-urlencoded", strings.NewReader("vmid=100")); err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	if _, err = client.Get(dead.URL + "/api2/json/version"); err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	if expected := []string{"/api2/json/nodes/pve1/qemu", "/api2/json/version"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected requests `%v` on the endpoint alive, got `%v`", expected, paths)
+	}
+	if bodies[0] != "vmid=100" {
+		t.Errorf("expected the body to be sent to the endpoint alive, got `%s`", bodies[0])
+	}
+	if failover := transport.(*failoverTransport); failover.current != 1 || failover.order()[0] != 1 {
+		t.Errorf("expected the endpoint alive to be in use, got `%d`", failover.current)
+	}
+}
@@ -0,0 +1,130 @@
+package proxmox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault injection for testing the retry and cleanup logic against a real cluster. It is only
+// enabled through the environment, e.g. PM_FAULT_INJECTION="5xx=0.1,timeout=0.05,lock=0.2"
+// fails 10% of the requests with a 503 service unavailable, 5% with a timeout and 20% with
+// the lock error proxmox answers with when a guest config is locked by another task.
+// PM_FAULT_INJECTION_SEED makes the sequence of faults reproducible.
+
+const faultInjectionEnv = "PM_FAULT_INJECTION"
+const faultInjectionSeedEnv = "PM_FAULT_INJECTION_SEED"
+
+var faultInjectionKinds = []string{"5xx", "timeout", "lock"}
+
+func parseFaultInjectionRates(spec string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || !stringInList(kv[0], faultInjectionKinds) {
+			return nil, fmt.Errorf("Invalid %s entry %q, must be one of %v with a rate, e.g. 5xx=0.1", faultInjectionEnv, item, faultInjectionKinds)
+		}
+		rate, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("Invalid %s rate %q for %s, must be between 0 and 1", faultInjectionEnv, kv[1], kv[0])
+		}
+		rates[kv[0]] = rate
+	}
+	return rates, nil
+}
+
+// Wraps base with a faultInjectionTransport when PM_FAULT_INJECTION is set.
+func faultInjectionFromEnv(base http.RoundTripper) (http.RoundTripper, error) {
+	spec := os.Getenv(faultInjectionEnv)
+	if spec == "" {
+		return base, nil
+	}
+	rates, err := parseFaultInjectionRates(spec)
+	if err != nil {
+		return nil, err
+	}
+	seed := time.Now().UnixNano()
+	if seedEnv := os.Getenv(faultInjectionSeedEnv); seedEnv != "" {
+		if seed, err = strconv.ParseInt(seedEnv, 10, 64); err != nil {
+			return nil, fmt.Errorf("Invalid %s: %v", faultInjectionSeedEnv, err)
+		}
+	}
+	return &faultInjectionTransport{base: base, rates: rates, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+type faultInjectionTransport struct {
+	base  http.RoundTripper
+	rates map[string]float64
+	rand  *rand.Rand
+	mutex sync.Mutex
+}
+
+// the error of an injected timeout, it satisfies net.Error just like a real one
+type faultInjectionTimeout struct{}
+
+func (faultInjectionTimeout) Error() string   { return "injected fault: i/o timeout" }
+func (faultInjectionTimeout) Timeout() bool   { return true }
+func (faultInjectionTimeout) Temporary() bool { return true }
+
+func (t *faultInjectionTransport) fault() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	roll := t.rand.Float64()
+	for _, kind := range faultInjectionKinds {
+		if roll < t.rates[kind] {
+			return kind
+		}
+		roll -= t.rates[kind]
+	}
+	return ""
+}
+
+func (t *faultInjectionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// failing the login would only test the patience of the tester
+	if strings.HasSuffix(req.URL.Path, "/access/ticket") {
+		return t.base.RoundTrip(req)
+	}
+
+	fault := t.fault()
+	if fault == "" {
+		return t.base.RoundTrip(req)
+	}
+	// like any RoundTripper the request body is closed, also when the request is not sent
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	status := ""
+	switch fault {
+	case "5xx":
+		status = "503 injected fault: service unavailable"
+	case "lock":
+		status = "500 can't lock file '/var/lock/qemu-server/lock-0.conf' - got timeout (injected fault)"
+	case "timeout":
+		return nil, faultInjectionTimeout{}
+	}
+
+	logger, _ := CreateSubLogger("fault_injection")
+	logger.Debug().Msgf("Injecting %s into %s %s", status, req.Method, req.URL.Path)
+	statusCode, _ := strconv.Atoi(status[:3])
+	return &http.Response{
+		Status:     status,
+		StatusCode: statusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"data":null}`)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,163 @@
+package proxmox
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFaultInjectionRates(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output map[string]float64
+		err    bool
+	}{
+		{name: "all kinds", input: "5xx=0.1, timeout=0.05,lock=1", output: map[string]float64{"5xx": 0.1, "timeout": 0.05, "lock": 1}},
+		{name: "empty", input: "", output: map[string]float64{}},
+		{name: "unknown kind", input: "404=0.1", err: true},
+		{name: "rate above one", input: "lock=2", err: true},
+		{name: "missing rate", input: "lock", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			rates, err := parseFaultInjectionRates(test.input)
+			if (err != nil) != test.err {
+				t.Fatalf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+			if !test.err && !reflect.DeepEqual(rates, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, rates)
+			}
+		})
+	}
+}
+
+// failingTransport answers the first failures requests with status (or err) and all later ones with 200 OK
+type failingTransport struct {
+	failures int
+	status   string
+	err      error
+	bodies   []string
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	t.bodies = append(t.bodies, body)
+	status := "200 OK"
+	if len(t.bodies) <= t.failures {
+		if t.err != nil {
+			return nil, t.err
+		}
+		status = t.status
+	}
+	statusCode, _ := strconv.Atoi(status[:3])
+	return &http.Response{Status: status, StatusCode: statusCode, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestRetryTransport(t *testing.T) {
+	apiRetryDelay = time.Millisecond
+	defer func() { apiRetryDelay = time.Second }()
+
+	lockStatus := "500 can't lock file '/var/lock/qemu-server/lock-100.conf' - got timeout"
+	tests := []struct {
+		name      string
+		method    string
+		transport *failingTransport
+		tries     int
+		status    int
+		err       bool
+	}{
+		{name: "locked on post", method: http.MethodPost, transport: &failingTransport{failures: 2, status: lockStatus}, tries: 3, status: 200},
+		{name: "locked for too long", method: http.MethodPut, transport: &failingTransport{failures: 10, status: lockStatus}, tries: apiRetryAttempts, status: 500},
+		{name: "unavailable on get", method: http.MethodGet, transport: &failingTransport{failures: 1, status: "503 Service Unavailable"}, tries: 2, status: 200},
+		{name: "unavailable on post", method: http.MethodPost, transport: &failingTransport{failures: 1, status: "503 Service Unavailable"}, tries: 1, status: 503},
+		{name: "not found on get", method: http.MethodGet, transport: &failingTransport{failures: 1, status: "500 no such user"}, tries: 1, status: 500},
+		{name: "timeout on get", method: http.MethodGet, transport: &failingTransport{failures: 1, err: faultInjectionTimeout{}}, tries: 2, status: 200},
+		{name: "timeout on post", method: http.MethodPost, transport: &failingTransport{failures: 1, err: faultInjectionTimeout{}}, tries: 1, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			var body *bytes.Reader
+			req, _ := http.NewRequest(test.method, "https://pve.example.com/api2/json/nodes", nil)
+			if test.method != http.MethodGet {
+				body = bytes.NewReader([]byte("vmid=100"))
+				req, _ = http.NewRequest(test.method, "https://pve.example.com/api2/json/nodes", body)
+			}
+
+			resp, err := (&retryTransport{base: test.transport}).RoundTrip(req)
+			if (err != nil) != test.err {
+				t.Fatalf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+			if !test.err && resp.StatusCode != test.status {
+				t.Errorf("%s: expected status %d, got %d", test.name, test.status, resp.StatusCode)
+			}
+			if len(test.transport.bodies) != test.tries {
+				t.Errorf("%s: expected %d tries, got %d", test.name, test.tries, len(test.transport.bodies))
+			}
+			for _, sent := range test.transport.bodies {
+				if body != nil && sent != "vmid=100" {
+					t.Errorf("%s: expected the body to be sent with every try, got `%s`", test.name, sent)
+				}
+			}
+		})
+	}
+}
+
+func TestFaultInjectionTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  string
+		status int
+		err    bool
+	}{
+		{name: "5xx", fault: "5xx", status: 503},
+		{name: "lock", fault: "lock", status: 500},
+		{name: "timeout", fault: "timeout", err: true},
+		{name: "none", fault: "", status: 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			transport := &faultInjectionTransport{
+				base:  &failingTransport{},
+				rates: map[string]float64{test.fault: 1},
+				rand:  rand.New(rand.NewSource(1)),
+			}
+			body := &closeTrackingBody{Reader: strings.NewReader("vmid=100")}
+			req, _ := http.NewRequest(http.MethodPost, "https://pve.example.com/api2/json/nodes", body)
+			resp, err := transport.RoundTrip(req)
+			if (err != nil) != test.err {
+				t.Fatalf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+			if !test.err && resp.StatusCode != test.status {
+				t.Errorf("%s: expected status %d, got %d", test.name, test.status, resp.StatusCode)
+			}
+			if test.fault != "" && !body.closed {
+				t.Errorf("%s: expected the request body to be closed", test.name)
+			}
+		})
+	}
+}
+
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
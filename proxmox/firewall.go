@@ -0,0 +1,339 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The proxmox_firewall_* resources share the handling of the firewall of proxmox. A firewall,
+// e.g. /nodes/<node>/qemu/<vmid>/firewall, has an ordered list of rules below rules/, which
+// are addressed by their position. The rules of a security group are listed below the group
+// itself, /cluster/firewall/groups/<group>.
+
+// attribute => parameter of a rule, enabled is sent as enable
+var firewallRuleParameters = map[string]string{
+	"direction": "type",
+	"action":    "action",
+	"macro":     "macro",
+	"source":    "source",
+	"dest":      "dest",
+	"proto":     "proto",
+	"sport":     "sport",
+	"dport":     "dport",
+	"iface":     "iface",
+	"log":       "log",
+	"comment":   "comment",
+}
+
+var firewallLogLevels = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug", "nolog"}
+
+var firewallActions = []string{"ACCEPT", "DROP", "REJECT"}
+
+// the names of aliases and IP sets, which rules refer to in place of an address
+var validateFirewallName = validation.All(
+	validation.StringLenBetween(2, 64),
+	validation.StringMatch(regexp.MustCompile(`^[A-Za-z][A-Za-z0-9\-_]+$`), "must start with a letter and have only letters, digits, - or _"),
+)
+
+// the attributes matching traffic other than the interface, a rule inserting a security group has none
+var firewallMatchAttributes = []string{"macro", "source", "dest", "proto", "sport", "dport"}
+
+// The ordered rules of a firewall, the first rule matching a packet decides. With groups, a rule
+// of direction group inserts the rules of the security group named by its action.
+func firewallRulesSchema(groups bool) *schema.Schema {
+	directions := []string{"in", "out"}
+	directionDescription := "The direction of the traffic the rule matches: in or out"
+	actionDescription := "What happens to the matching traffic: ACCEPT, DROP or REJECT"
+	if groups {
+		directions = append(directions, "group")
+		directionDescription = "The direction of the traffic the rule matches: in or out, or group to insert the rules of a security group"
+		actionDescription = "What happens to the matching traffic: ACCEPT, DROP or REJECT, or the security group inserted by a rule of direction group"
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"direction": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(directions, false),
+					Description:  directionDescription,
+				},
+				"action": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+					Description:  actionDescription,
+				},
+				"macro": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "A predefined set of protocols and ports the rule matches, e.g. `SSH` or `HTTPS`",
+				},
+				"source": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The source addresses, e.g. `10.0.0.0/24`, a range, a list separated by `,` or an alias or IP set",
+				},
+				"dest": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The destination addresses, in the format of `source`",
+				},
+				"proto": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The IP protocol, e.g. `tcp`, `udp` or `icmp`",
+				},
+				"sport": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The source ports, e.g. `80`, `8000:8080` or a list separated by `,`",
+				},
+				"dport": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The destination ports, in the format of `sport`",
+				},
+				"iface": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The network interface the rule applies to, e.g. `net0`",
+				},
+				"log": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "nolog",
+					ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+					Description:  "The log level of the matching traffic, nolog logs nothing",
+				},
+				"comment": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "A comment of the rule",
+				},
+				"enabled": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Whether the rule is active",
+				},
+			},
+		},
+		Description: "The rules of the firewall in the order they are evaluated in",
+	}
+}
+
+// Checks the actions of the rules, which the schema can't as they depend on the direction. A rule
+// inserting a security group only matches by its interface.
+func checkFirewallRules(rules []interface{}) error {
+	for pos, item := range rules {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		direction, _ := rule["direction"].(string)
+		action, _ := rule["action"].(string)
+		if direction == "" || action == "" {
+			// unknown until apply
+			continue
+		}
+		if direction != "group" {
+			if !stringInList(action, firewallActions) {
+				return fmt.Errorf("Rule %d: action must be ACCEPT, DROP or REJECT, got %s", pos, action)
+			}
+			continue
+		}
+		for _, attribute := range firewallMatchAttributes {
+			if value, _ := rule[attribute].(string); value != "" {
+				return fmt.Errorf("Rule %d inserts the security group %s and can't have a %s", pos, action, attribute)
+			}
+		}
+	}
+	return nil
+}
+
+func firewallRulesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return checkFirewallRules(d.Get("rule").([]interface{}))
+}
+
+// The parameters of a rule, the empty ones are returned as the parameters to delete. A rule
+// inserting a security group is not logged itself.
+func firewallRuleParams(rule map[string]interface{}) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"enable": rule["enabled"].(bool),
+	}
+	deletes = []string{}
+	for attribute, parameter := range firewallRuleParameters {
+		if attribute == "log" && rule["direction"] == "group" {
+			deletes = append(deletes, parameter)
+			continue
+		}
+		if value := rule[attribute].(string); value != "" {
+			params[parameter] = value
+		} else {
+			deletes = append(deletes, parameter)
+		}
+	}
+	sort.Strings(deletes)
+	return
+}
+
+// The rules of a rules/ listing, sorted by their position.
+func firewallRules(entries interface{}) []interface{} {
+	list, _ := entries.([]interface{})
+	sorted := []map[string]interface{}{}
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return apiInt(sorted[i]["pos"]) < apiInt(sorted[j]["pos"])
+	})
+
+	rules := []interface{}{}
+	for _, entry := range sorted {
+		rule := map[string]interface{}{
+			"enabled": apiBool(entry["enable"]),
+		}
+		for attribute, parameter := range firewallRuleParameters {
+			rule[attribute] = apiString(entry[parameter])
+		}
+		if rule["log"] == "" {
+			rule["log"] = "nolog"
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func firewallGetRules(session *pxapi.Session, path string) ([]interface{}, error) {
+	entries, err := apiGet(session, path)
+	if err != nil {
+		return nil, err
+	}
+	return firewallRules(entries), nil
+}
+
+// Writes rules to the rule list at path, replacing all rules it has. The rules are updated in
+// place by their position, so the firewall never lacks a rule which is kept. Additional rules
+// are appended and the remaining old ones are deleted from the end.
+func firewallSetRules(session *pxapi.Session, path string, rules []interface{}) error {
+	current, err := firewallGetRules(session, path)
+	if err != nil {
+		return err
+	}
+	for pos, item := range rules {
+		params, deletes := firewallRuleParams(item.(map[string]interface{}))
+		if pos < len(current) {
+			if len(deletes) > 0 {
+				params["delete"] = strings.Join(deletes, ",")
+			}
+			_, err = apiPut(session, path+"/"+strconv.Itoa(pos), params)
+		} else {
+			params["pos"] = pos
+			_, err = apiPost(session, path, params)
+		}
+		if err != nil {
+			return fmt.Errorf("Writing rule %d of %s failed: %v", pos, path, err)
+		}
+	}
+	for pos := len(current) - 1; pos >= len(rules); pos-- {
+		if _, err = apiDelete(session, path+"/"+strconv.Itoa(pos)); err != nil {
+			return fmt.Errorf("Deleting rule %d of %s failed: %v", pos, path, err)
+		}
+	}
+	return nil
+}
+
+// The options of the firewall of a guest, the defaults are used when proxmox doesn't return an
+// option. dhcp, ndp and macfilter are on unless they are disabled.
+var firewallGuestOptionDefaults = map[string]interface{}{
+	"enable":        false,
+	"dhcp":          true,
+	"ndp":           true,
+	"radv":          false,
+	"ipfilter":      false,
+	"macfilter":     true,
+	"policy_in":     "DROP",
+	"policy_out":    "ACCEPT",
+	"log_level_in":  "nolog",
+	"log_level_out": "nolog",
+}
+
+// The options of an options/ response, missing ones are set to their default.
+func firewallOptions(config map[string]interface{}, defaults map[string]interface{}) map[string]interface{} {
+	options := map[string]interface{}{}
+	for parameter, defaultValue := range defaults {
+		value, ok := config[parameter]
+		if !ok {
+			options[parameter] = defaultValue
+			continue
+		}
+		switch defaultValue.(type) {
+		case bool:
+			options[parameter] = apiBool(value)
+		case int:
+			options[parameter] = apiInt(value)
+		default:
+			options[parameter] = apiString(value)
+		}
+	}
+	return options
+}
+
+// Sets the options of a firewall from the attributes of the resource, parameters maps the
+// attributes to their parameter.
+func firewallSetOptions(d *schema.ResourceData, session *pxapi.Session, path string, parameters map[string]string) error {
+	params := map[string]interface{}{}
+	for attribute, parameter := range parameters {
+		params[parameter] = d.Get(attribute)
+	}
+	_, err := apiPut(session, path+"/options", params)
+	return err
+}
+
+// Resets the options of a firewall to the defaults of proxmox.
+func firewallResetOptions(session *pxapi.Session, path string, defaults map[string]interface{}) error {
+	deletes := []string{}
+	for parameter := range defaults {
+		deletes = append(deletes, parameter)
+	}
+	sort.Strings(deletes)
+	_, err := apiPut(session, path+"/options", map[string]interface{}{
+		"delete": strings.Join(deletes, ","),
+	})
+	return err
+}
+
+// The id of the firewall of a guest, firewall/<vmid>.
+func parseFirewallGuestId(resId string) (int, error) {
+	_, id, err := parseClusterResourceId(resId)
+	if err != nil {
+		return 0, fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	vmid, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid resource id %s, must be firewall/<vmid>", resId)
+	}
+	return vmid, nil
+}
+
+// The firewall of a guest, the node is looked up so the guest may be migrated.
+func firewallGuestPath(session *pxapi.Session, vmid int) (string, *pxapi.VmRef, error) {
+	vmr, err := apiGuestVmRef(session, vmid)
+	if err != nil {
+		return "", nil, err
+	}
+	return apiPath("nodes", vmr.Node(), vmr.GetVmType(), strconv.Itoa(vmid), "firewall"), vmr, nil
+}
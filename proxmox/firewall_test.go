@@ -0,0 +1,188 @@
+package proxmox
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFirewallRuleParams(t *testing.T) {
+	params, deletes := firewallRuleParams(map[string]interface{}{
+		"direction": "in", "action": "ACCEPT", "macro": "", "source": "10.0.0.0/24", "dest": "",
+		"proto": "tcp", "sport": "", "dport": "22", "iface": "", "log": "nolog", "comment": "", "enabled": true,
+	})
+	expected := map[string]interface{}{
+		"type": "in", "action": "ACCEPT", "source": "10.0.0.0/24", "proto": "tcp", "dport": "22", "log": "nolog", "enable": true,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment", "dest", "iface", "macro", "sport"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestFirewallRules(t *testing.T) {
+	rules := firewallRules([]interface{}{
+		map[string]interface{}{"pos": float64(1), "type": "out", "action": "DROP", "enable": float64(0)},
+		map[string]interface{}{"pos": float64(0), "type": "in", "action": "ACCEPT", "macro": "SSH", "enable": float64(1), "log": "info"},
+	})
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got `%v`", rules)
+	}
+	first := rules[0].(map[string]interface{})
+	second := rules[1].(map[string]interface{})
+	if first["macro"] != "SSH" || first["log"] != "info" || first["enabled"] != true {
+		t.Errorf("unexpected first rule `%v`", first)
+	}
+	if second["direction"] != "out" || second["log"] != "nolog" || second["enabled"] != false {
+		t.Errorf("unexpected second rule `%v`", second)
+	}
+}
+
+func TestFirewallSetRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		rules    int
+		expected []string
+	}{
+		{name: "append", current: `[{"pos":0,"type":"in","action":"DROP"}]`, rules: 3, expected: []string{"PUT /rules/0", "POST /rules pos=1", "POST /rules pos=2"}},
+		{name: "shrink", current: `[{"pos":0},{"pos":1},{"pos":2}]`, rules: 1, expected: []string{"PUT /rules/0", "DELETE /rules/2", "DELETE /rules/1"}},
+		{name: "clear", current: `[{"pos":0},{"pos":1}]`, rules: 0, expected: []string{"DELETE /rules/1", "DELETE /rules/0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			calls := []string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				path := r.URL.Path[len("/nodes/pve1/qemu/100/firewall"):]
+				switch r.Method {
+				case "GET":
+					w.Write([]byte(`{"data":` + test.current + `}`))
+					return
+				case "POST":
+					body, _ := ioutil.ReadAll(r.Body)
+					values, _ := url.ParseQuery(string(body))
+					calls = append(calls, "POST "+path+" pos="+values.Get("pos"))
+				default:
+					calls = append(calls, r.Method+" "+path)
+				}
+				w.Write([]byte(`{"data":null}`))
+			}))
+			defer server.Close()
+
+			session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+			rules := []interface{}{}
+			for i := 0; i < test.rules; i++ {
+				rules = append(rules, map[string]interface{}{
+					"direction": "in", "action": "ACCEPT", "macro": "", "source": "", "dest": "",
+					"proto": "", "sport": "", "dport": "", "iface": "", "log": "nolog", "comment": "", "enabled": true,
+				})
+			}
+			if err := firewallSetRules(session, "/nodes/pve1/qemu/100/firewall/rules", rules); err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if !reflect.DeepEqual(calls, test.expected) {
+				t.Errorf("%s: expected calls `%v`, got `%v`", test.name, test.expected, calls)
+			}
+		})
+	}
+}
+
+func TestFirewallOptions(t *testing.T) {
+	options := firewallOptions(map[string]interface{}{
+		"enable": float64(1), "dhcp": float64(0), "policy_in": "REJECT", "log_level_in": "info",
+	}, firewallGuestOptionDefaults)
+	expected := map[string]interface{}{
+		"enable": true, "dhcp": false, "ndp": true, "radv": false, "ipfilter": false, "macfilter": true,
+		"policy_in": "REJECT", "policy_out": "ACCEPT", "log_level_in": "info", "log_level_out": "nolog",
+	}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, options)
+	}
+}
+
+func TestParseFirewallGuestId(t *testing.T) {
+	if vmid, err := parseFirewallGuestId("firewall/100"); err != nil || vmid != 100 {
+		t.Errorf("expected 100, got %d and `%+v`", vmid, err)
+	}
+	if _, err := parseFirewallGuestId("firewall/web"); err == nil {
+		t.Errorf("expected an error for `firewall/web`")
+	}
+}
+
+func TestCheckFirewallRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  map[string]interface{}
+		valid bool
+	}{
+		{name: "accept", rule: map[string]interface{}{"direction": "in", "action": "ACCEPT", "dport": "22"}, valid: true},
+		{name: "group", rule: map[string]interface{}{"direction": "group", "action": "webserver", "iface": "net0"}, valid: true},
+		{name: "unknown", rule: map[string]interface{}{"direction": "in", "action": ""}, valid: true},
+		{name: "group action", rule: map[string]interface{}{"direction": "out", "action": "webserver"}, valid: false},
+		{name: "group match", rule: map[string]interface{}{"direction": "group", "action": "webserver", "dport": "22"}, valid: false},
+	}
+
+	for _, test := range tests {
+		err := checkFirewallRules([]interface{}{test.rule})
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got `%+v`", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestFirewallGroupRuleParams(t *testing.T) {
+	params, deletes := firewallRuleParams(map[string]interface{}{
+		"direction": "group", "action": "webserver", "macro": "", "source": "", "dest": "",
+		"proto": "", "sport": "", "dport": "", "iface": "net0", "log": "nolog", "comment": "", "enabled": true,
+	})
+	expected := map[string]interface{}{"type": "group", "action": "webserver", "iface": "net0", "enable": true}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment", "dest", "dport", "log", "macro", "proto", "source", "sport"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+// The defaults of the schemas have to be the ones of proxmox, a reset resource would show a diff otherwise.
+func TestFirewallOptionDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		resource   *schema.Resource
+		parameters map[string]string
+		defaults   map[string]interface{}
+	}{
+		{name: "guest", resource: resourceFirewallOptions(), parameters: firewallOptionsParameters, defaults: firewallGuestOptionDefaults},
+		{name: "node", resource: resourceNodeFirewallOptions(), parameters: nodeFirewallOptionsParameters, defaults: nodeFirewallOptionDefaults},
+		{name: "cluster", resource: resourceClusterFirewallOptions(), parameters: clusterFirewallOptionsParameters, defaults: clusterFirewallOptionDefaults},
+	}
+
+	for _, test := range tests {
+		if len(test.parameters) != len(test.defaults) {
+			t.Errorf("%s: expected a default of every parameter", test.name)
+		}
+		for attribute, parameter := range test.parameters {
+			if attribute == "enabled" {
+				// enabling the firewall is what the resources are for
+				continue
+			}
+			if value := test.resource.Schema[attribute].Default; value != test.defaults[parameter] {
+				t.Errorf("%s: expected default `%v` of %s, got `%v`", test.name, test.defaults[parameter], attribute, value)
+			}
+		}
+	}
+	options := firewallOptions(map[string]interface{}{"nf_conntrack_max": "131072"}, nodeFirewallOptionDefaults)
+	if options["nf_conntrack_max"] != 131072 || options["nf_conntrack_tcp_timeout_established"] != 432000 {
+		t.Errorf("unexpected integer options `%v`", options)
+	}
+}
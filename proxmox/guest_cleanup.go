@@ -0,0 +1,107 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Deleting a guest leaves volumes behind which are not in its config any more, e.g. the
+// cloud-init drive of a failed update or the disks of an interrupted clone. They are owned
+// by the id of the guest, so they are found and deleted after the guest.
+
+// Deletes the volumes left by the deleted guest vmid on node and reads them again to verify
+// none is left.
+func deleteGuestVolumes(session *pxapi.Session, client *pxapi.Client, node string, vmid int) error {
+	volids, err := apiGetGuestVolumes(session, node, vmid)
+	if err != nil {
+		return err
+	}
+	logger, _ := CreateSubLogger("guest_cleanup")
+	for _, volid := range volids {
+		logger.Info().Int("vmid", vmid).Msgf("Deleting volume %s left by the guest", volid)
+		if err = apiDeleteVolume(session, client, node, volid); err != nil {
+			return fmt.Errorf("Deleting volume %s of guest %d failed: %v", volid, vmid, err)
+		}
+	}
+
+	if volids, err = apiGetGuestVolumes(session, node, vmid); err != nil {
+		return err
+	}
+	if len(volids) > 0 {
+		return fmt.Errorf("Volumes of guest %d are left on %s after it was deleted: %s", vmid, node, strings.Join(volids, ", "))
+	}
+	return nil
+}
+
+// Fails when vmid is taken by a guest of the cluster. Checked before creating a guest, so a failed
+// creation only ever removes a guest created by terraform.
+func checkGuestIdFree(session *pxapi.Session, vmid int) error {
+	guests, err := apiGetGuests(session)
+	if err != nil {
+		return err
+	}
+	for _, guest := range guests {
+		if apiInt(guest["vmid"]) == vmid {
+			return fmt.Errorf("The id %d is already taken by the guest %s on %s", vmid, apiString(guest["name"]), apiString(guest["node"]))
+		}
+	}
+	return nil
+}
+
+// Removes a guest whose creation failed before terraform took over its id, so neither the
+// guest nor its volumes are left behind. Returns cause together with what the cleanup failed on.
+// When the id was taken by another guest in the meantime, the guest is not the one terraform
+// created and is left alone.
+func destroyFailedGuest(session *pxapi.Session, client *pxapi.Client, vmr *pxapi.VmRef, cause error) error {
+	if strings.Contains(cause.Error(), "already exists") {
+		return cause
+	}
+
+	logger, _ := CreateSubLogger("guest_cleanup")
+	logger.Info().Int("vmid", vmr.VmId()).Msgf("Removing the guest after its creation failed: %v", cause)
+
+	if _, err := apiGuestVmRef(session, vmr.VmId()); err == nil {
+		client.StopVm(vmr)
+		if _, err = client.DeleteVm(vmr); err != nil {
+			return fmt.Errorf("%v, removing the guest %d failed as well: %v", cause, vmr.VmId(), err)
+		}
+	}
+	if err := deleteGuestVolumes(session, client, vmr.Node(), vmr.VmId()); err != nil {
+		return fmt.Errorf("%v, removing the volumes of the guest failed as well: %v", cause, err)
+	}
+	return cause
+}
+
+// The settings of proxmox_vm_qemu and proxmox_lxc for deleting the guest when it is destroyed. They
+// default to false, so guests in a state from before the settings are deleted like before.
+func guestDestroySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"purge_on_destroy": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Remove the guest from backup jobs, replication jobs and HA when it is destroyed",
+		},
+		"keep_unreferenced_disks": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Keep the volumes owned by the guest which are not in its config when it is destroyed, e.g. detached disks, instead of destroying them",
+		},
+	}
+}
+
+// The parameters of deleting a guest by its destroy settings.
+func guestDeleteParams(purge bool, destroyUnreferencedDisks bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	if purge {
+		params["purge"] = true
+	}
+	if destroyUnreferencedDisks {
+		params["destroy-unreferenced-disks"] = true
+	}
+	return params
+}
@@ -0,0 +1,133 @@
+package proxmox
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestDeleteGuestVolumes(t *testing.T) {
+	volumes := map[string][]string{
+		"local-lvm": {"local-lvm:vm-100-cloudinit", "local-lvm:vm-100-disk-1", "local-lvm:vm-101-disk-0"},
+		"nfs":       {"nfs:100/vm-100-disk-0.qcow2"},
+	}
+	deleted := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path, _ := url.PathUnescape(r.URL.EscapedPath())
+		switch {
+		case path == "/nodes/pve1/storage":
+			w.Write([]byte(`{"data":[{"storage":"local-lvm","active":1},{"storage":"nfs","active":1},{"storage":"offline","active":0}]}`))
+		case r.Method == http.MethodDelete:
+			escaped := r.URL.EscapedPath()
+			volid, _ := url.PathUnescape(escaped[strings.LastIndex(escaped, "/")+1:])
+			storage := strings.SplitN(volid, ":", 2)[0]
+			left := []string{}
+			for _, volume := range volumes[storage] {
+				if volume != volid {
+					left = append(left, volume)
+				}
+			}
+			volumes[storage] = left
+			deleted = append(deleted, volid)
+			w.Write([]byte(`{"data":null}`))
+		case strings.HasSuffix(path, "/content"):
+			storage := strings.Split(path, "/")[4]
+			if storage == "offline" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			vmid := r.URL.Query().Get("vmid")
+			entries := []map[string]interface{}{}
+			for _, volume := range volumes[storage] {
+				if vmid == "" || strings.Contains(volume, "vm-"+vmid+"-") {
+					entries = append(entries, map[string]interface{}{"volid": volume})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": entries})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+	client, _ := pxapi.NewClient(server.URL, server.Client(), nil, 10)
+	if err := deleteGuestVolumes(session, client, "pve1", 100); err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	expected := []string{"local-lvm:vm-100-cloudinit", "local-lvm:vm-100-disk-1", "nfs:100/vm-100-disk-0.qcow2"}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected deleted volumes `%v`, got `%v`", expected, deleted)
+	}
+	if left := volumes["local-lvm"]; !reflect.DeepEqual(left, []string{"local-lvm:vm-101-disk-0"}) {
+		t.Errorf("expected the volumes of other guests to be kept, got `%v`", left)
+	}
+}
+
+func TestGuestDeleteParams(t *testing.T) {
+	tests := []struct {
+		purge                    bool
+		destroyUnreferencedDisks bool
+		expected                 map[string]interface{}
+	}{
+		{false, true, map[string]interface{}{"destroy-unreferenced-disks": true}},
+		{true, true, map[string]interface{}{"purge": true, "destroy-unreferenced-disks": true}},
+		{true, false, map[string]interface{}{"purge": true}},
+		{false, false, map[string]interface{}{}},
+	}
+	for _, test := range tests {
+		if params := guestDeleteParams(test.purge, test.destroyUnreferencedDisks); !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("%v %v: expected `%v`, got `%v`", test.purge, test.destroyUnreferencedDisks, test.expected, params)
+		}
+	}
+}
+
+func TestDestroyFailedGuestIdCollision(t *testing.T) {
+	deletes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodDelete {
+			deletes++
+		}
+		switch r.URL.Path {
+		case "/cluster/resources":
+			w.Write([]byte(`{"data":[{"vmid":100,"name":"database","node":"pve1","type":"qemu"}]}`))
+		case "/nodes/pve1/storage":
+			w.Write([]byte(`{"data":[{"storage":"local-lvm","active":1}]}`))
+		case "/nodes/pve1/storage/local-lvm/content":
+			w.Write([]byte(`{"data":[{"volid":"local-lvm:vm-100-disk-0"}]}`))
+		default:
+			w.Write([]byte(`{"data":null}`))
+		}
+	}))
+	defer server.Close()
+
+	session, _ := pxapi.NewSession(server.URL, server.Client(), nil)
+	client, _ := pxapi.NewClient(server.URL, server.Client(), nil, 10)
+
+	if err := checkGuestIdFree(session, 100); err == nil {
+		t.Errorf("expected the id 100 to be taken")
+	}
+	if err := checkGuestIdFree(session, 101); err != nil {
+		t.Errorf("expected the id 101 to be free, got `%v`", err)
+	}
+
+	vmr := pxapi.NewVmRef(100)
+	vmr.SetNode("pve1")
+	cause := errors.New("unable to create VM 100 - VM 100 already exists on node 'pve1'")
+	if err := destroyFailedGuest(session, client, vmr, cause); err != cause {
+		t.Errorf("expected `%v`, got `%v`", cause, err)
+	}
+	if deletes != 0 {
+		t.Errorf("expected the guest of the taken id to be kept, got %d deletes", deletes)
+	}
+}
@@ -0,0 +1,94 @@
+package proxmox
+
+import (
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The summary attributes of proxmox_vm_qemu and proxmox_lxc add up the resources of a guest,
+// e.g. for cost estimates in outputs or policy checks on the plan.
+
+func guestSummarySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"total_disk_gb": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "The size of all disks of the guest in GB",
+		},
+		"total_memory_mb": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The memory of the guest in MB",
+		},
+		"nic_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The number of network interfaces of the guest",
+		},
+	}
+}
+
+var rxGuestDiskSize = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[KMGT]?B?$`)
+
+// The size of a disk in GB, 0 for a disk without a size like a bind mount.
+func guestDiskSizeGB(size interface{}) float64 {
+	if s, ok := size.(string); ok && !rxGuestDiskSize.MatchString(strings.ToUpper(s)) {
+		return 0
+	}
+	return pxapi.DiskSizeGB(size)
+}
+
+// The size of disks in GB.
+func guestTotalDiskGB(disks ...pxapi.QemuDevice) float64 {
+	total := 0.0
+	for _, disk := range disks {
+		total += guestDiskSizeGB(disk["size"])
+	}
+	return total
+}
+
+func setGuestSummary(d *schema.ResourceData, disks []pxapi.QemuDevice, memory int, nics int) {
+	d.Set("total_disk_gb", guestTotalDiskGB(disks...))
+	d.Set("total_memory_mb", memory)
+	d.Set("nic_count", nics)
+}
+
+// The disk a VM boots from, e.g. scsi0, by the bootdisk option or else the first disk of the boot
+// order like order=ide2;scsi0;net0.
+func qemuBootDisk(boot string, bootdisk string, disks pxapi.QemuDevices) string {
+	if bootdisk != "" {
+		return bootdisk
+	}
+	names := map[string]bool{}
+	for slot, disk := range disks {
+		names[qemuDiskName(slot, disk)] = true
+	}
+	for _, option := range strings.Split(boot, ",") {
+		if order := strings.TrimPrefix(option, "order="); order != option {
+			for _, device := range strings.Split(order, ";") {
+				if names[device] {
+					return device
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func qemuDiskName(slot int, disk pxapi.QemuDevice) string {
+	return apiString(disk["type"]) + apiString(slot)
+}
+
+// The size of the boot disk of a VM like 32G, empty when the VM doesn't boot from a disk.
+func qemuBootDiskSize(boot string, bootdisk string, disks pxapi.QemuDevices) string {
+	name := qemuBootDisk(boot, bootdisk, disks)
+	for slot, disk := range disks {
+		if qemuDiskName(slot, disk) == name {
+			return apiString(disk["size"])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package proxmox
+
+import (
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestGuestTotalDiskGB(t *testing.T) {
+	disks := []pxapi.QemuDevice{
+		{"size": "32G"},
+		{"size": "1T"},
+		{"size": "512M"},
+		{"size": 8.0},
+		{"volume": "/mnt/data"},
+		{"size": ""},
+	}
+	if total := guestTotalDiskGB(disks...); total != 32+1024+0.5+8 {
+		t.Errorf("unexpected total %v", total)
+	}
+}
+
+func TestQemuBootDiskSize(t *testing.T) {
+	disks := pxapi.QemuDevices{
+		0: {"type": "scsi", "size": "32G"},
+		1: {"type": "virtio", "size": "100G"},
+	}
+	tests := []struct {
+		boot     string
+		bootdisk string
+		size     string
+	}{
+		{"cdn", "scsi0", "32G"},
+		{"order=ide2;virtio1;scsi0;net0", "", "100G"},
+		{"order=net0", "", ""},
+		{"cdn", "", ""},
+	}
+	for _, test := range tests {
+		if size := qemuBootDiskSize(test.boot, test.bootdisk, disks); size != test.size {
+			t.Errorf("%s %s: expected `%s`, got `%s`", test.boot, test.bootdisk, test.size, size)
+		}
+	}
+}
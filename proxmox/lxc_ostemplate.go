@@ -0,0 +1,93 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The ostemplate of proxmox_lxc may be the URL of a template instead of its volume id. The node
+// then downloads the template to a storage, unless it is there already, and the container is
+// created from the downloaded template.
+
+func lxcOstemplateSchema() map[string]*schema.Schema {
+	algorithms := []string{}
+	for algorithm := range isoChecksumAlgorithms {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Strings(algorithms)
+
+	return map[string]*schema.Schema{
+		"ostemplate_storage": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     "local",
+			Description: "The storage a template given as URL is downloaded to, it must have the vztmpl content type",
+		},
+		"ostemplate_checksum": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			RequiredWith: []string{"ostemplate_checksum_algorithm"},
+			Description:  "The expected checksum of a template given as URL, it is verified when the template is downloaded",
+		},
+		"ostemplate_checksum_algorithm": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			RequiredWith: []string{"ostemplate_checksum"},
+			ValidateFunc: validation.StringInSlice(algorithms, false),
+			Description:  "The algorithm of `ostemplate_checksum`: `md5`, `sha1`, `sha224`, `sha256`, `sha384` or `sha512`.",
+		},
+		"ostemplate_volid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The volume id of the template the container was created from",
+		},
+	}
+}
+
+// whether ostemplate is the URL of a template instead of its volume id
+func lxcOstemplateUrl(ostemplate string) bool {
+	return strings.HasPrefix(ostemplate, "http://") || strings.HasPrefix(ostemplate, "https://")
+}
+
+// The volume id of the template downloaded from templateUrl to storage.
+func lxcOstemplateVolid(storage string, templateUrl string) string {
+	return fmt.Sprintf("%s:vztmpl/%s", storage, isoFilename("", templateUrl))
+}
+
+// Downloads the template from templateUrl to storage on node unless the storage has a template of
+// the same name, and returns its volume id. An existing template is not verified against checksum.
+func lxcDownloadOstemplate(session *pxapi.Session, client *pxapi.Client, node string, storage string, templateUrl string, checksum string, algorithm string) (string, error) {
+	volid := lxcOstemplateVolid(storage, templateUrl)
+	volume, err := apiGetVolume(session, node, volid, "vztmpl")
+	if err != nil {
+		return "", err
+	}
+	if volume != nil {
+		return volid, nil
+	}
+
+	params := map[string]interface{}{
+		"url":      templateUrl,
+		"content":  "vztmpl",
+		"filename": isoFilename("", templateUrl),
+	}
+	if checksum != "" {
+		params["checksum"] = strings.ToLower(checksum)
+		params["checksum-algorithm"] = algorithm
+	}
+
+	logger, _ := CreateSubLogger("resource_lxc_create")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Downloading template %s", templateUrl)
+	if _, err = apiPostTask(session, client, apiPath("nodes", node, "storage", storage, "download-url"), params); err != nil {
+		return "", fmt.Errorf("Downloading the template %s to %s failed: %v", templateUrl, storage, err)
+	}
+	return volid, nil
+}
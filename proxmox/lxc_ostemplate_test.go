@@ -0,0 +1,29 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestLxcOstemplateUrl(t *testing.T) {
+	tests := []struct {
+		ostemplate string
+		url        bool
+	}{
+		{"local:vztmpl/debian-12-standard_12.2-1_amd64.tar.zst", false},
+		{"https://example.com/debian-12-standard_12.2-1_amd64.tar.zst", true},
+		{"http://example.com/debian-12-standard_12.2-1_amd64.tar.zst", true},
+		{"", false},
+	}
+	for _, test := range tests {
+		if url := lxcOstemplateUrl(test.ostemplate); url != test.url {
+			t.Errorf("%s: expected %v, got %v", test.ostemplate, test.url, url)
+		}
+	}
+}
+
+func TestLxcOstemplateVolid(t *testing.T) {
+	volid := lxcOstemplateVolid("local", "https://example.com/images/debian-12-standard_12.2-1_amd64.tar.zst?download=1")
+	if volid != "local:vztmpl/debian-12-standard_12.2-1_amd64.tar.zst" {
+		t.Errorf("unexpected volid %s", volid)
+	}
+}
@@ -0,0 +1,54 @@
+package proxmox
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// Starting a container on a busy node fails now and then because systemd or the cgroups of the
+// container aren't ready in time. The start of a created container is tried again on these
+// errors, start_retries times at most.
+
+// errors of a start which may go away when the container is started again
+var lxcStartRetryableErrors = []string{
+	"timeout waiting on systemd",
+	"failed to create cgroup",
+	"unable to create cgroup",
+	"cgroup: device or resource busy",
+}
+
+// the delay before the start is tried again
+var lxcStartRetryDelay = 10 * time.Second
+
+func lxcStartRetryable(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, retryable := range lxcStartRetryableErrors {
+		if strings.Contains(message, retryable) {
+			return true
+		}
+	}
+	return false
+}
+
+// Calls start until it succeeds, fails with an error which isn't retryable or was retried retries times.
+func lxcStartWithRetries(start func() error, retries int) error {
+	logger, _ := CreateSubLogger("resource_lxc_start")
+	for attempt := 0; ; attempt++ {
+		err := start()
+		if err == nil || attempt >= retries || !lxcStartRetryable(err) {
+			return err
+		}
+		logger.Info().Int("attempt", attempt+1).Msgf("Starting the container failed, trying again in %v: %v", lxcStartRetryDelay, err)
+		time.Sleep(lxcStartRetryDelay)
+	}
+}
+
+func lxcStart(session *pxapi.Session, client *pxapi.Client, node string, vmid int, retries int) error {
+	return lxcStartWithRetries(func() error {
+		_, err := apiPostTask(session, client, apiPath("nodes", node, "lxc", strconv.Itoa(vmid), "status", "start"), map[string]interface{}{})
+		return err
+	}, retries)
+}
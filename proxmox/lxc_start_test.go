@@ -0,0 +1,39 @@
+package proxmox
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLxcStartWithRetries(t *testing.T) {
+	lxcStartRetryDelay = 0
+	systemd := errors.New("command 'systemctl start pve-container@101' failed: timeout waiting on systemd")
+	config := errors.New("unable to parse config line")
+
+	tests := []struct {
+		name     string
+		errors   []error
+		retries  int
+		starts   int
+		expected error
+	}{
+		{name: "started", errors: []error{nil}, retries: 2, starts: 1},
+		{name: "started after a retry", errors: []error{systemd, nil}, retries: 2, starts: 2},
+		{name: "retries exhausted", errors: []error{systemd, systemd, systemd}, retries: 2, starts: 3, expected: systemd},
+		{name: "no retries", errors: []error{systemd}, retries: 0, starts: 1, expected: systemd},
+		{name: "error not retryable", errors: []error{config}, retries: 2, starts: 1, expected: config},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			starts := 0
+			err := lxcStartWithRetries(func() error {
+				starts++
+				return test.errors[starts-1]
+			}, test.retries)
+			if err != test.expected || starts != test.starts {
+				t.Errorf("expected %d starts and `%v`, got %d starts and `%v`", test.starts, test.expected, starts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// A started container is only running its init process when the create returns. The wait_for
+// block of proxmox_lxc waits for the conditions the guests depending on it need.
+
+// how often the conditions of wait_for are checked
+var lxcWaitPollInterval = 5 * time.Second
+
+func lxcWaitSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"running": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Wait until the init process of the container runs",
+				},
+				"network": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Wait until an interface of the container other than lo has an address",
+				},
+				"interface": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Wait for the address of this interface instead of any, e.g. eth0",
+				},
+				"timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      300,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Seconds to wait for the conditions, creating the container fails after that",
+				},
+			},
+		},
+		Description: "Conditions waited for after a started container was created",
+	}
+}
+
+// Whether the interfaces returned by /nodes/{node}/lxc/{vmid}/interfaces have an address, only
+// iface counts when it is set. Link local IPv6 addresses are there before the network is up.
+func lxcNetworkUp(interfaces interface{}, iface string) bool {
+	list, _ := interfaces.([]interface{})
+	for _, item := range list {
+		nic, _ := item.(map[string]interface{})
+		name := apiString(nic["name"])
+		if name == "lo" || (iface != "" && name != iface) {
+			continue
+		}
+		if apiString(nic["inet"]) != "" {
+			return true
+		}
+		if inet6 := apiString(nic["inet6"]); inet6 != "" && !strings.HasPrefix(strings.ToLower(inet6), "fe80:") {
+			return true
+		}
+	}
+	return false
+}
+
+// Waits for the conditions of the wait_for block of a container on node.
+func lxcWait(session *pxapi.Session, node string, vmid int, block []interface{}) error {
+	if len(block) == 0 || block[0] == nil {
+		return nil
+	}
+	wait := block[0].(map[string]interface{})
+	running := wait["running"].(bool)
+	network := wait["network"].(bool)
+	iface := wait["interface"].(string)
+	timeout := time.Duration(wait["timeout"].(int)) * time.Second
+
+	logger, _ := CreateSubLogger("resource_lxc_wait")
+	vmID := fmt.Sprintf("%d", vmid)
+	pending := ""
+	for end := time.Now().Add(timeout); ; time.Sleep(lxcWaitPollInterval) {
+		if time.Now().After(end) {
+			return fmt.Errorf("Container %d did not come up within %v, still waiting for %s", vmid, timeout, pending)
+		}
+
+		if running || network {
+			status, err := apiGetMap(session, apiPath("nodes", node, "lxc", vmID, "status", "current"))
+			if err != nil {
+				return err
+			}
+			if apiString(status["status"]) != "running" || apiInt(status["pid"]) == 0 {
+				pending = "the init process"
+				logger.Debug().Int("vmid", vmid).Msgf("Waiting for %s", pending)
+				continue
+			}
+		}
+
+		if network {
+			interfaces, err := apiGet(session, apiPath("nodes", node, "lxc", vmID, "interfaces"))
+			if err != nil {
+				return err
+			}
+			if !lxcNetworkUp(interfaces, iface) {
+				pending = "an address of the network"
+				logger.Debug().Int("vmid", vmid).Msgf("Waiting for %s", pending)
+				continue
+			}
+		}
+		return nil
+	}
+}
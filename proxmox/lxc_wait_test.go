@@ -0,0 +1,35 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestLxcNetworkUp(t *testing.T) {
+	loopback := map[string]interface{}{"name": "lo", "inet": "127.0.0.1/8", "inet6": "::1/128"}
+	linkLocal := map[string]interface{}{"name": "eth0", "hwaddr": "bc:24:11:00:00:01", "inet6": "fe80::be24:11ff:fe00:1/64"}
+	ipv4 := map[string]interface{}{"name": "eth0", "hwaddr": "bc:24:11:00:00:01", "inet": "10.0.0.5/24"}
+	ipv6 := map[string]interface{}{"name": "eth1", "hwaddr": "bc:24:11:00:00:02", "inet6": "2001:db8::5/64"}
+
+	tests := []struct {
+		name       string
+		interfaces []interface{}
+		iface      string
+		expected   bool
+	}{
+		{name: "no interfaces", interfaces: []interface{}{}, expected: false},
+		{name: "only loopback", interfaces: []interface{}{loopback}, expected: false},
+		{name: "link local address", interfaces: []interface{}{loopback, linkLocal}, expected: false},
+		{name: "ipv4 address", interfaces: []interface{}{loopback, ipv4}, expected: true},
+		{name: "ipv6 address", interfaces: []interface{}{loopback, linkLocal, ipv6}, expected: true},
+		{name: "address of another interface", interfaces: []interface{}{linkLocal, ipv6}, iface: "eth0", expected: false},
+		{name: "address of the interface", interfaces: []interface{}{ipv4, ipv6}, iface: "eth0", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if up := lxcNetworkUp(test.interfaces, test.iface); up != test.expected {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, up)
+			}
+		})
+	}
+}
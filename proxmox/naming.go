@@ -0,0 +1,56 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The naming conventions of pm_name_pattern and pm_tag_pattern are enforced when planning
+// guests, so a name or tag breaking them fails before anything is created.
+
+// compiles a pattern of the provider configuration, which has to match a whole name
+func namingPattern(key string, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	rx, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s %s: %v", key, pattern, err)
+	}
+	return rx, nil
+}
+
+// checks a name and tags against the patterns, a nil pattern allows everything
+func checkNaming(namePattern *regexp.Regexp, tagPattern *regexp.Regexp, name string, tags string) error {
+	if namePattern != nil && name != "" && !namePattern.MatchString(name) {
+		return fmt.Errorf("The name %s does not match pm_name_pattern %s", name, namePattern)
+	}
+	if tagPattern == nil {
+		return nil
+	}
+	for _, tag := range parseTags(tags) {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("The tag %s does not match pm_tag_pattern %s", tag, tagPattern)
+		}
+	}
+	return nil
+}
+
+// The CustomizeDiff of guests named by nameKey, names and tags only known when applying
+// are not checked.
+func namingCustomizeDiff(nameKey string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		pconf := meta.(*providerConfiguration)
+		name, tags := "", ""
+		if d.NewValueKnown(nameKey) {
+			name = d.Get(nameKey).(string)
+		}
+		if d.NewValueKnown("tags") {
+			tags = d.Get("tags").(string)
+		}
+		return checkNaming(pconf.NamePattern, pconf.TagPattern, name, tags)
+	}
+}
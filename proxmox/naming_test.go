@@ -0,0 +1,40 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestCheckNaming(t *testing.T) {
+	namePattern, _ := namingPattern("pm_name_pattern", `(prod|dev)-[a-z0-9-]+`)
+	tagPattern, _ := namingPattern("pm_tag_pattern", `[a-z]+|owner-[a-z]+`)
+
+	tests := []struct {
+		name      string
+		guestName string
+		tags      string
+		err       bool
+	}{
+		{name: "valid", guestName: "prod-web-1", tags: "web;owner-ops"},
+		{name: "no tags", guestName: "dev-db"},
+		{name: "no name", tags: "web"},
+		{name: "name without prefix", guestName: "web-1", err: true},
+		{name: "name matched partially", guestName: "prod-Web", err: true},
+		{name: "tag not matching", guestName: "prod-web", tags: "web,Owner", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			err := checkNaming(namePattern, tagPattern, test.guestName, test.tags)
+			if (err != nil) != test.err {
+				t.Errorf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+		})
+	}
+
+	if err := checkNaming(nil, nil, "Anything Goes", "Any;Tag"); err != nil {
+		t.Errorf("without patterns: expected no error, got `%+v`", err)
+	}
+	if _, err := namingPattern("pm_name_pattern", "prod-("); err == nil {
+		t.Errorf("invalid pattern: expected an error")
+	}
+}
@@ -0,0 +1,95 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The devices of a VM without a type default to the ones the guest OS in qemu_os has drivers
+// for. The defaults are decided when planning, they are shown in default_disk_type and
+// default_network_model, and only apply to the disk and network blocks which don't set a type.
+
+type qemuOsDefaults struct {
+	diskType     string
+	networkModel string
+	scsihw       string
+}
+
+// the defaults of a qemu_os, all OS types not listed are treated like other
+func osDefaults(qemuOs string) qemuOsDefaults {
+	switch {
+	case qemuOs == "l26":
+		return qemuOsDefaults{diskType: "scsi", networkModel: "virtio", scsihw: "virtio-scsi-pci"}
+	// windows has no virtio drivers without the virtio-win ISO
+	case strings.HasPrefix(qemuOs, "w"):
+		return qemuOsDefaults{diskType: "sata", networkModel: "e1000"}
+	default:
+		return qemuOsDefaults{diskType: "ide", networkModel: "e1000"}
+	}
+}
+
+// The CustomizeDiff of the VM, with os_defaults disabled every block has to set its type.
+func osDefaultsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("os_defaults").(bool) {
+		if err := checkOsDefaultsDisabled(d.Get("disk").([]interface{}), "disk", "type"); err != nil {
+			return err
+		}
+		if err := checkOsDefaultsDisabled(d.Get("network").([]interface{}), "network", "model"); err != nil {
+			return err
+		}
+		return setOsDefaults(d, qemuOsDefaults{})
+	}
+	if !d.NewValueKnown("qemu_os") {
+		if err := d.SetNewComputed("default_disk_type"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("default_network_model")
+	}
+	defaults := osDefaults(d.Get("qemu_os").(string))
+	// the controller of existing VMs is read from proxmox, only new ones get a default
+	if d.Id() != "" || d.Get("scsihw").(string) != "" {
+		defaults.scsihw = ""
+	}
+	return setOsDefaults(d, defaults)
+}
+
+func setOsDefaults(d *schema.ResourceDiff, defaults qemuOsDefaults) error {
+	if d.Get("default_disk_type").(string) != defaults.diskType {
+		if err := d.SetNew("default_disk_type", defaults.diskType); err != nil {
+			return err
+		}
+	}
+	if d.Get("default_network_model").(string) != defaults.networkModel {
+		if err := d.SetNew("default_network_model", defaults.networkModel); err != nil {
+			return err
+		}
+	}
+	if defaults.scsihw != "" {
+		return d.SetNew("scsihw", defaults.scsihw)
+	}
+	return nil
+}
+
+// blocks still unknown when planning are not checked
+func checkOsDefaultsDisabled(blocks []interface{}, name string, key string) error {
+	for i, block := range blocks {
+		device, ok := block.(map[string]interface{})
+		if ok && device[key] == "" {
+			return fmt.Errorf("The %s block %d has no %s, which is required when os_defaults is false", name, i, key)
+		}
+	}
+	return nil
+}
+
+// sets the default of the devices which don't set key
+func applyOsDefault(devices pxapi.QemuDevices, key string, value string) {
+	for _, device := range devices {
+		if device[key] == nil || device[key] == "" {
+			device[key] = value
+		}
+	}
+}
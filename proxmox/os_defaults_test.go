@@ -0,0 +1,56 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestOsDefaults(t *testing.T) {
+	tests := []struct {
+		qemuOs   string
+		expected qemuOsDefaults
+	}{
+		{qemuOs: "l26", expected: qemuOsDefaults{diskType: "scsi", networkModel: "virtio", scsihw: "virtio-scsi-pci"}},
+		{qemuOs: "win11", expected: qemuOsDefaults{diskType: "sata", networkModel: "e1000"}},
+		{qemuOs: "w2k8", expected: qemuOsDefaults{diskType: "sata", networkModel: "e1000"}},
+		{qemuOs: "l24", expected: qemuOsDefaults{diskType: "ide", networkModel: "e1000"}},
+		{qemuOs: "other", expected: qemuOsDefaults{diskType: "ide", networkModel: "e1000"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.qemuOs, func(*testing.T) {
+			if defaults := osDefaults(test.qemuOs); defaults != test.expected {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.qemuOs, test.expected, defaults)
+			}
+		})
+	}
+}
+
+func TestApplyOsDefault(t *testing.T) {
+	devices := pxapi.QemuDevices{
+		0: {"storage": "local-lvm"},
+		1: {"storage": "local-lvm", "type": ""},
+		2: {"storage": "local-lvm", "type": "virtio"},
+	}
+	applyOsDefault(devices, "type", "scsi")
+
+	types := []interface{}{devices[0]["type"], devices[1]["type"], devices[2]["type"]}
+	if !reflect.DeepEqual(types, []interface{}{"scsi", "scsi", "virtio"}) {
+		t.Errorf("unexpected types `%v`", types)
+	}
+}
+
+func TestCheckOsDefaultsDisabled(t *testing.T) {
+	blocks := []interface{}{
+		map[string]interface{}{"model": "virtio"},
+		map[string]interface{}{"model": ""},
+	}
+	if err := checkOsDefaultsDisabled(blocks[:1], "network", "model"); err != nil {
+		t.Errorf("unexpected error `%+v`", err)
+	}
+	if err := checkOsDefaultsDisabled(blocks, "network", "model"); err == nil {
+		t.Errorf("expected an error for the network block without a model")
+	}
+}
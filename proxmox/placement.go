@@ -0,0 +1,199 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Guests with target_nodes are placed by the provider on one of the listed nodes when they are
+// created. Guests sharing an anti_affinity_group are kept on different nodes, the group is
+// kept in the tags of the guests so it is known across runs and configurations. Guests with
+// place_with_vmid are created on the node of another guest.
+
+const antiAffinityTagPrefix = "anti-affinity."
+
+// the schema attributes of the placement, shared by proxmox_vm_qemu and proxmox_lxc
+func placementSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"target_nodes": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			Elem:         &schema.Schema{Type: schema.TypeString},
+			ExactlyOneOf: []string{"target_node", "target_nodes", "place_with_vmid"},
+			Description:  "The nodes the guest may be created on, the online node with the most free memory is picked",
+		},
+		"place_with_vmid": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			ExactlyOneOf:  []string{"target_node", "target_nodes", "place_with_vmid"},
+			ConflictsWith: []string{"anti_affinity_group"},
+			Description:   "The guest is created on the node the guest with this id is on when it is created",
+		},
+		"anti_affinity_group": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Guests of the same group are placed on different nodes of target_nodes",
+		},
+	}
+}
+
+// the tags sent to proxmox, the tags of the config with the anti affinity tag of group
+func tagsWithAntiAffinity(tags string, group string) string {
+	parsed := parseTags(tagsWithoutAntiAffinity(tags))
+	if group != "" {
+		parsed = append(parsed, antiAffinityTagPrefix+group)
+	}
+	return formatTags(parsed)
+}
+
+// the tags as configured, without what tagsWithAntiAffinity added
+func tagsWithoutAntiAffinity(tags string) string {
+	configured := []string{}
+	for _, tag := range parseTags(tags) {
+		if !strings.HasPrefix(tag, antiAffinityTagPrefix) {
+			configured = append(configured, tag)
+		}
+	}
+	return formatTags(configured)
+}
+
+func antiAffinityGroup(tags string) string {
+	for _, tag := range parseTags(tags) {
+		if strings.HasPrefix(tag, antiAffinityTagPrefix) {
+			return strings.TrimPrefix(tag, antiAffinityTagPrefix)
+		}
+	}
+	return ""
+}
+
+// a node guests can be placed on
+type placementNode struct {
+	name       string
+	freeMemory int
+}
+
+// The nodes of candidates which are online, without a guest of group other than vmid, and
+// not reserved for a guest of group by this run, the one with the most free memory first.
+func placementCandidates(nodes interface{}, guests []map[string]interface{}, reserved []string, candidates []string, group string, vmid int) []placementNode {
+	occupied := map[string]bool{}
+	for _, node := range reserved {
+		occupied[node] = true
+	}
+	if group != "" {
+		for _, guest := range guests {
+			if apiInt(guest["vmid"]) != vmid && antiAffinityGroup(apiString(guest["tags"])) == group {
+				occupied[apiString(guest["node"])] = true
+			}
+		}
+	}
+
+	online := map[string]int{}
+	list, _ := nodes.([]interface{})
+	for _, item := range list {
+		node, _ := item.(map[string]interface{})
+		if apiString(node["status"]) == "online" {
+			online[apiString(node["node"])] = apiInt(node["maxmem"]) - apiInt(node["mem"])
+		}
+	}
+
+	placement := []placementNode{}
+	for _, candidate := range candidates {
+		if freeMemory, ok := online[candidate]; ok && !occupied[candidate] {
+			placement = append(placement, placementNode{name: candidate, freeMemory: freeMemory})
+		}
+	}
+	// a stable sort keeps the order of target_nodes between nodes with the same free memory
+	sort.SliceStable(placement, func(i, j int) bool { return placement[i].freeMemory > placement[j].freeMemory })
+	return placement
+}
+
+func placementError(candidates []string, group string) error {
+	if group == "" {
+		return fmt.Errorf("None of the target_nodes %v is online", candidates)
+	}
+	return fmt.Errorf("None of the target_nodes %v is online and free of a guest of anti_affinity_group %s", candidates, group)
+}
+
+// Picks the node a guest is created on and reserves it for the anti affinity group until the
+// end of the run, so guests of the group created in parallel end up on different nodes.
+func placeGuest(pconf *providerConfiguration, session *pxapi.Session, candidates []string, group string, vmid int) (string, error) {
+	nodes, err := apiGet(session, "/nodes")
+	if err != nil {
+		return "", err
+	}
+	guests := []map[string]interface{}{}
+	if group != "" {
+		if guests, err = apiGetGuests(session); err != nil {
+			return "", err
+		}
+	}
+
+	pconf.Mutex.Lock()
+	defer pconf.Mutex.Unlock()
+	placement := placementCandidates(nodes, guests, pconf.Placements[group], candidates, group, vmid)
+	if len(placement) == 0 {
+		return "", placementError(candidates, group)
+	}
+	if group != "" {
+		pconf.Placements[group] = append(pconf.Placements[group], placement[0].name)
+	}
+	return placement[0].name, nil
+}
+
+// The node a new guest is created on, target_node or the node picked for target_nodes or
+// place_with_vmid. vmid is the id of the new guest, 0 when proxmox assigns it later.
+func resolvePlacement(d *schema.ResourceData, pconf *providerConfiguration, vmid int) (string, error) {
+	candidates := schemaStringList(d.Get("target_nodes"))
+	withVmid := d.Get("place_with_vmid").(int)
+	if len(candidates) == 0 && withVmid == 0 {
+		return d.Get("target_node").(string), nil
+	}
+
+	session, err := resourceSession(d, pconf)
+	if err != nil {
+		return "", err
+	}
+	if withVmid != 0 {
+		vmr, err := apiGuestVmRef(session, withVmid)
+		if err != nil {
+			return "", fmt.Errorf("Placing the guest with place_with_vmid %d failed: %v", withVmid, err)
+		}
+		return vmr.Node(), nil
+	}
+	return placeGuest(pconf, session, candidates, d.Get("anti_affinity_group").(string), vmid)
+}
+
+// Reports at plan time when a new guest of an anti affinity group has no node left. Guests
+// created by the same run are unknown yet, they are only taken into account when applying.
+func placementCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	group := d.Get("anti_affinity_group").(string)
+	if d.Id() != "" || group == "" || !d.NewValueKnown("target_nodes") {
+		return nil
+	}
+	candidates := schemaStringList(d.Get("target_nodes"))
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	nodes, err := apiGet(pconf.Session, "/nodes")
+	if err != nil {
+		return err
+	}
+	guests, err := apiGetGuests(pconf.Session)
+	if err != nil {
+		return err
+	}
+	if len(placementCandidates(nodes, guests, nil, candidates, group, d.Get("vmid").(int))) == 0 {
+		return placementError(candidates, group)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAntiAffinityTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     string
+		group    string
+		expected string
+	}{
+		{name: "no group", tags: "web;prod", group: "", expected: "web;prod"},
+		{name: "no tags", tags: "", group: "web", expected: "anti-affinity.web"},
+		{name: "added", tags: "web,prod", group: "web", expected: "web;prod;anti-affinity.web"},
+		{name: "replaced", tags: "anti-affinity.db;web", group: "web", expected: "web;anti-affinity.web"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			tags := tagsWithAntiAffinity(test.tags, test.group)
+			if tags != test.expected {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.expected, tags)
+			}
+			if group := antiAffinityGroup(tags); group != test.group {
+				t.Errorf("%s: expected group `%s`, got `%s`", test.name, test.group, group)
+			}
+			if configured := tagsWithoutAntiAffinity(tags); configured != tagsWithoutAntiAffinity(test.tags) {
+				t.Errorf("%s: expected configured tags `%s`, got `%s`", test.name, tagsWithoutAntiAffinity(test.tags), configured)
+			}
+		})
+	}
+}
+
+func TestPlacementCandidates(t *testing.T) {
+	nodes := []interface{}{
+		map[string]interface{}{"node": "pve1", "status": "online", "maxmem": float64(64), "mem": float64(48)},
+		map[string]interface{}{"node": "pve2", "status": "online", "maxmem": float64(64), "mem": float64(16)},
+		map[string]interface{}{"node": "pve3", "status": "online", "maxmem": float64(64), "mem": float64(16)},
+		map[string]interface{}{"node": "pve4", "status": "offline"},
+	}
+	guests := []map[string]interface{}{
+		{"vmid": float64(100), "node": "pve2", "tags": "prod;anti-affinity.web"},
+		{"vmid": float64(101), "node": "pve1", "tags": "anti-affinity.db"},
+	}
+	candidates := []string{"pve1", "pve2", "pve3", "pve4"}
+
+	tests := []struct {
+		name     string
+		reserved []string
+		group    string
+		vmid     int
+		expected []string
+	}{
+		{name: "no group", expected: []string{"pve2", "pve3", "pve1"}},
+		{name: "group", group: "web", expected: []string{"pve3", "pve1"}},
+		{name: "group of the guest itself", group: "web", vmid: 100, expected: []string{"pve2", "pve3", "pve1"}},
+		{name: "reserved by this run", group: "web", reserved: []string{"pve3"}, expected: []string{"pve1"}},
+		{name: "no node left", group: "web", reserved: []string{"pve3", "pve1"}, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			placement := []string{}
+			for _, node := range placementCandidates(nodes, guests, test.reserved, candidates, test.group, test.vmid) {
+				placement = append(placement, node.name)
+			}
+			if !reflect.DeepEqual(placement, test.expected) {
+				t.Errorf("%s: expected `%v`, got `%v`", test.name, test.expected, placement)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package proxmox
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Users give resource types a priority with pm_parallel_priorities, e.g. so slow downloads of
+// ISOs and templates the VMs wait for don't queue behind everything else. The operations of a
+// type are called with a copy of the configuration carrying its priority, which the parallel
+// limiter of pmParallelBegin orders the waiting operations by.
+
+// Wraps the operations of resource so they run with the priority of resourceType.
+func withParallelPriority(resourceType string, resource *schema.Resource) {
+	wrap := func(operation func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if operation == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			return operation(d, parallelPriorityMeta(meta, resourceType))
+		}
+	}
+	resource.Create = wrap(resource.Create)
+	resource.Read = wrap(resource.Read)
+	resource.Update = wrap(resource.Update)
+	resource.Delete = wrap(resource.Delete)
+}
+
+// The configuration for the operations of resourceType. The copy carrying the priority shares
+// the mutable state of the provider with the configuration it is copied from.
+func parallelPriorityMeta(meta interface{}, resourceType string) interface{} {
+	pconf, ok := meta.(*providerConfiguration)
+	if !ok {
+		return meta
+	}
+	priority, ok := pconf.ParallelPriorities[resourceType]
+	if !ok || priority == pconf.Priority {
+		return meta
+	}
+	prioritized := *pconf
+	prioritized.Priority = priority
+	return &prioritized
+}
+
+// whether an operation with a higher priority than priority waits for the parallel limiter
+func parallelHigherWaiting(waiting map[int]int, priority int) bool {
+	for other, count := range waiting {
+		if other > priority && count > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package proxmox
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func testParallelConfiguration(maxParallel int, priorities map[string]int) *providerConfiguration {
+	var mut sync.Mutex
+	return &providerConfiguration{
+		MaxParallel:        maxParallel,
+		ParallelPriorities: priorities,
+		State:              &providerState{Waiting: make(map[int]int)},
+		Mutex:              &mut,
+		Cond:               sync.NewCond(&mut),
+	}
+}
+
+func TestParallelPriorityMeta(t *testing.T) {
+	pconf := testParallelConfiguration(1, map[string]int{"proxmox_iso": 10})
+	if meta := parallelPriorityMeta(pconf, "proxmox_vm_qemu"); meta != pconf {
+		t.Errorf("expected the configuration of a type without a priority to be unchanged")
+	}
+	prioritized := parallelPriorityMeta(pconf, "proxmox_iso").(*providerConfiguration)
+	if prioritized.Priority != 10 || prioritized.State != pconf.State || pconf.Priority != 0 {
+		t.Errorf("expected a copy with priority 10 sharing the state, got `%+v`", prioritized)
+	}
+}
+
+// With one slot the waiting operation of the higher priority goes first, although it started
+// waiting later.
+func TestParallelPriorityOrder(t *testing.T) {
+	pconf := testParallelConfiguration(1, map[string]int{"proxmox_iso": 10})
+	holder := pmParallelBegin(pconf)
+
+	order := make(chan string, 2)
+	wait := func(name string, resourceType string) {
+		lock := pmParallelBegin(parallelPriorityMeta(pconf, resourceType).(*providerConfiguration))
+		order <- name
+		lock.unlock()
+	}
+	waiting := func(count int) {
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+			pconf.Mutex.Lock()
+			total := pconf.State.Waiting[0] + pconf.State.Waiting[10]
+			pconf.Mutex.Unlock()
+			if total == count {
+				return
+			}
+		}
+		t.Fatalf("expected %d waiting operations", count)
+	}
+
+	go wait("vm", "proxmox_vm_qemu")
+	waiting(1)
+	go wait("iso", "proxmox_iso")
+	waiting(2)
+	holder.unlock()
+
+	if first, second := <-order, <-order; first != "iso" || second != "vm" {
+		t.Errorf("expected iso before vm, got %s before %s", first, second)
+	}
+}
+
+func TestParallelHigherWaiting(t *testing.T) {
+	waiting := map[int]int{0: 2, 10: 0, -5: 1}
+	if parallelHigherWaiting(waiting, 0) {
+		t.Errorf("expected no higher priority waiting, priority 10 has no waiting operation")
+	}
+	if !parallelHigherWaiting(waiting, -5) {
+		t.Errorf("expected priority 0 to wait before priority -5")
+	}
+}
@@ -1,32 +1,68 @@
 package proxmox
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 type providerConfiguration struct {
 	Client                             *pxapi.Client
+	APIURL                             string
+	Session                            *pxapi.Session
 	MaxParallel                        int
-	CurrentParallel                    int
-	MaxVMID                            int
+	ParallelPriorities                 map[string]int
+	Priority                           int
+	State                              *providerState
 	Mutex                              *sync.Mutex
 	Cond                               *sync.Cond
 	LogFile                            string
 	LogLevels                          map[string]string
 	DangerouslyIgnoreUnknownAttributes bool
+	TLSInsecure                        bool
+	Timeout                            int
+	TokenClients                       map[string]*pxapi.Client
+	TokenSessions                      map[string]*pxapi.Session
+	BwLimitClone                       int
+	BwLimitMigrate                     int
+	BwLimitRestore                     int
+	RunWorkspace                       string
+	RunURL                             string
+	Placements                         map[string][]string
+	NamePattern                        *regexp.Regexp
+	TagPattern                         *regexp.Regexp
+	DefaultTimeouts                    map[string]time.Duration
+	Standalone                         bool
+	CloudInitBaseUserData              string
+}
+
+// The state changing while the provider runs. The copies of the configuration carrying the
+// priority of a resource type share it, see parallelPriorityMeta. Guarded by the mutex.
+type providerState struct {
+	CurrentParallel int
+	MaxVMID         int
+	// the number of operations waiting for the parallel limiter by their priority
+	Waiting map[int]int
 }
 
 // Provider - Terrafrom properties for proxmox
 func Provider() *schema.Provider {
+	// the descriptions are rendered into the reference docs, see `make docs`
+	schema.DescriptionKind = schema.StringMarkdown
+
 	pmOTPprompt := schema.Schema{
 		Type:        schema.TypeString,
 		Optional:    true,
@@ -41,7 +77,8 @@ func Provider() *schema.Provider {
 			Description: "OTP 2FA code (if required)",
 		}
 	}
-	return &schema.Provider{
+	var provider *schema.Provider
+	provider = &schema.Provider{
 
 		Schema: map[string]*schema.Schema{
 			"pm_user": {
@@ -51,17 +88,18 @@ func Provider() *schema.Provider {
 				Description: "Username e.g. myuser or myuser@pam",
 			},
 			"pm_password": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("PM_PASS", nil),
-				Description: "Password to authenticate into proxmox",
-				Sensitive:   true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("PM_PASS", nil),
+				ConflictsWith: []string{"pm_api_token_id", "pm_api_token_secret"},
+				Description:   "Password to authenticate into proxmox",
+				Sensitive:     true,
 			},
 			"pm_api_url": {
 				Type:        schema.TypeString,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PM_API_URL", nil),
-				Description: "https://host.fqdn:8006/api2/json",
+				Description: "https://host.fqdn:8006/api2/json, a comma separated list of the API of several nodes fails over between them",
 			},
 			"pm_api_token_id": {
 				Type:        schema.TypeString,
@@ -77,9 +115,24 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 			},
 			"pm_parallel": {
-				Type:     schema.TypeInt,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     4,
+				Description: "The number of operations run against the API at the same time",
+			},
+			"pm_parallel_priorities": {
+				Type:     schema.TypeMap,
 				Optional: true,
-				Default:  4,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+				ValidateFunc: func(value interface{}, key string) (warns []string, errs []error) {
+					for resourceType := range value.(map[string]interface{}) {
+						if provider.ResourcesMap[resourceType] == nil && provider.DataSourcesMap[resourceType] == nil {
+							errs = append(errs, fmt.Errorf("%s: %s is not a resource or data source of the provider", key, resourceType))
+						}
+					}
+					return
+				},
+				Description: "Priorities of resource and data source types, e.g. `proxmox_iso = 10`. When more than pm_parallel operations wait for the API, the ones of the type with the highest priority go first. Types default to 0",
 			},
 			"pm_tls_insecure": {
 				Type:        schema.TypeBool,
@@ -104,10 +157,17 @@ func Provider() *schema.Provider {
 				Default:     "terraform-plugin-proxmox.log",
 				Description: "Write logs to this specific file",
 			},
+			"pm_log_per_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Write the logs of every terraform run to a separate, timestamped file next to pm_log_file",
+			},
 			"pm_timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  300,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     300,
+				Description: "The seconds to wait for API calls and tasks",
 			},
 			"pm_dangerously_ignore_unknown_attributes": {
 				Type:        schema.TypeBool,
@@ -115,25 +175,223 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("PM_DANGEROUSLY_IGNORE_UNKNOWN_ATTRIBUTES", false),
 				Description: "By default this provider will exit if an unknown attribute is found. This is to prevent the accidential destruction of VMs or Data when something in the proxmox API has changed/updated and is not confirmed to work with this provider. Set this to true at your own risk. It may allow you to proceed in cases when the provider refuses to work, but be aware of the danger in doing so.",
 			},
+			"pm_bwlimit_clone": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Bandwidth limit in KiB/s for clones, 0 uses the datacenter default",
+			},
+			"pm_bwlimit_migrate": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Bandwidth limit in KiB/s for migrations, 0 uses the datacenter default",
+			},
+			"pm_bwlimit_restore": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Bandwidth limit in KiB/s for restores, 0 uses the datacenter default",
+			},
+			"pm_run_workspace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PM_RUN_WORKSPACE", ""),
+				Description: "Name of the terraform workspace added to the notes of the guests",
+			},
+			"pm_run_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PM_RUN_URL", ""),
+				Description: "URL of the CI run added to the notes of the guests",
+			},
+			"pm_name_pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression the whole name of every VM and container has to match",
+			},
+			"pm_tag_pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression every tag of the VMs and containers has to match",
+			},
+			"pm_default_create_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of creating resources without their own timeouts block, e.g. 30m",
+			},
+			"pm_default_update_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of updating resources without their own timeouts block",
+			},
+			"pm_default_delete_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of deleting resources without their own timeouts block",
+			},
+			"pm_strict_version_check": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PM_STRICT_VERSION_CHECK", false),
+				Description: "Fail instead of warning when the version of Proxmox VE is not tested with the provider",
+			},
+			"pm_cloudinit_base_user_data": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PM_CLOUDINIT_BASE_USER_DATA", ""),
+				Description: "A cloud-config merged into the user-data of every proxmox_snippet with merge_base_user_data",
+			},
 			"pm_otp": &pmOTPprompt,
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"proxmox_vm_qemu":  resourceVmQemu(),
-			"proxmox_lxc":      resourceLxc(),
-			"proxmox_lxc_disk": resourceLxcDisk(),
-			"proxmox_pool":     resourcePool(),
-			// TODO - proxmox_storage_iso
-			// TODO - proxmox_bridge
-			// TODO - proxmox_vm_qemu_template
+			"proxmox_vm_qemu":                  resourceVmQemu(),
+			"proxmox_lxc":                      resourceLxc(),
+			"proxmox_lxc_disk":                 resourceLxcDisk(),
+			"proxmox_pool":                     resourcePool(),
+			"proxmox_user":                     resourceUser(),
+			"proxmox_group":                    resourceGroup(),
+			"proxmox_role":                     resourceRole(),
+			"proxmox_acl":                      resourceAcl(),
+			"proxmox_storage_retention":        resourceStorageRetention(),
+			"proxmox_realm_ldap":               resourceRealmLdap(),
+			"proxmox_realm_ad":                 resourceRealmAd(),
+			"proxmox_realm_openid":             resourceRealmOpenid(),
+			"proxmox_storage_dir":              resourceStorageDir(),
+			"proxmox_storage_nfs":              resourceStorageNfs(),
+			"proxmox_storage_cifs":             resourceStorageCifs(),
+			"proxmox_storage_lvm":              resourceStorageLvm(),
+			"proxmox_storage_lvmthin":          resourceStorageLvmThin(),
+			"proxmox_storage_zfspool":          resourceStorageZfsPool(),
+			"proxmox_storage_rbd":              resourceStorageRbd(),
+			"proxmox_storage_cephfs":           resourceStorageCephFs(),
+			"proxmox_storage_pbs":              resourceStoragePbs(),
+			"proxmox_storage_iscsi":            resourceStorageIscsi(),
+			"proxmox_vm_qemu_agent_file":       resourceVmQemuAgentFile(),
+			"proxmox_vm_qemu_agent_password":   resourceVmQemuAgentPassword(),
+			"proxmox_node_reboot":              resourceNodeReboot(),
+			"proxmox_remote_migration":         resourceRemoteMigration(),
+			"proxmox_iso":                      resourceIso(),
+			"proxmox_lxc_template":             resourceLxcTemplate(),
+			"proxmox_snippet":                  resourceSnippet(),
+			"proxmox_file":                     resourceFile(),
+			"proxmox_bridge":                   resourceBridge(),
+			"proxmox_network_bond":             resourceNetworkBond(),
+			"proxmox_network_vlan":             resourceNetworkVlan(),
+			"proxmox_network_ovs_bridge":       resourceNetworkOvsBridge(),
+			"proxmox_network_ovs_bond":         resourceNetworkOvsBond(),
+			"proxmox_network_ovs_int_port":     resourceNetworkOvsIntPort(),
+			"proxmox_sdn_zone":                 resourceSdnZone(),
+			"proxmox_sdn_controller":           resourceSdnController(),
+			"proxmox_sdn_vnet":                 resourceSdnVnet(),
+			"proxmox_sdn_subnet":               resourceSdnSubnet(),
+			"proxmox_ha_resource":              resourceHaResource(),
+			"proxmox_ha_group":                 resourceHaGroup(),
+			"proxmox_cluster_options":          resourceClusterOptions(),
+			"proxmox_firewall_rules":           resourceFirewallRules(),
+			"proxmox_firewall_options":         resourceFirewallOptions(),
+			"proxmox_firewall_security_group":  resourceFirewallSecurityGroup(),
+			"proxmox_firewall_alias":           resourceFirewallAlias(),
+			"proxmox_firewall_ipset":           resourceFirewallIpset(),
+			"proxmox_cluster_firewall_rules":   resourceClusterFirewallRules(),
+			"proxmox_cluster_firewall_options": resourceClusterFirewallOptions(),
+			"proxmox_node_firewall_rules":      resourceNodeFirewallRules(),
+			"proxmox_node_firewall_options":    resourceNodeFirewallOptions(),
+			"proxmox_acme_plugin":              resourceAcmePlugin(),
+			"proxmox_acme_account":             resourceAcmeAccount(),
+			"proxmox_acme_certificate":         resourceAcmeCertificate(),
+			"proxmox_backup_job":               resourceBackupJob(),
+			"proxmox_vzdump":                   resourceVzdump(),
+			"proxmox_replication_job":          resourceReplicationJob(),
+			"proxmox_snapshot":                 resourceSnapshot(),
+			"proxmox_lxc_snapshot":             resourceLxcSnapshot(),
+			"proxmox_node_certificate":         resourceNodeCertificate(),
+			"proxmox_metrics_server":           resourceMetricsServer(),
+			"proxmox_notification_target":      resourceNotificationTarget(),
+			"proxmox_notification_matcher":     resourceNotificationMatcher(),
+			"proxmox_node_dns":                 resourceNodeDns(),
+			"proxmox_node_hosts_entry":         resourceNodeHostsEntry(),
+			"proxmox_node_time":                resourceNodeTime(),
+			"proxmox_apt_repository":           resourceAptRepository(),
+			"proxmox_ceph_mon":                 resourceCephMon(),
+			"proxmox_ceph_osd":                 resourceCephOsd(),
+			"proxmox_ceph_pool":                resourceCephPool(),
+			"proxmox_cephfs":                   resourceCephFs(),
+			"proxmox_mapping_pci":              resourceMappingPci(),
+			"proxmox_mapping_usb":              resourceMappingUsb(),
+			"proxmox_mapping_dir":              resourceMappingDir(),
+			"proxmox_pool_membership":          resourcePoolMembership(),
+			"proxmox_vm_qemu_template":         resourceVmQemuTemplate(),
+			"proxmox_cloud_init_disk":          resourceCloudInitDisk(),
+			"proxmox_zfs_pool":                 resourceZfsPool(),
+			"proxmox_lvm_volume_group":         resourceLvmVolumeGroup(),
+			"proxmox_lvm_thinpool":             resourceLvmThinpool(),
+			"proxmox_directory_storage_mount":  resourceDirectoryStorageMount(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		DataSourcesMap: map[string]*schema.Resource{
+			"proxmox_bwlimit":            dataSourceBwLimit(),
+			"proxmox_vm_qemu_agent_file": dataSourceVmQemuAgentFile(),
+			"proxmox_pci_mapping":        dataSourcePciMapping(),
+			"proxmox_inventory":          dataSourceInventory(),
+			"proxmox_node_pci_devices":   dataSourceNodePciDevices(),
+			"proxmox_guest_configs":      dataSourceGuestConfigs(),
+		},
+
+		ConfigureContextFunc: providerConfigureContext,
+	}
+
+	for resourceType, resource := range provider.ResourcesMap {
+		withParallelPriority(resourceType, resource)
 	}
+	for resourceType, resource := range provider.DataSourcesMap {
+		withParallelPriority(resourceType, resource)
+	}
+	return provider
+}
+
+// Configures the provider, then checks the version of Proxmox VE and whether the node is part
+// of a cluster.
+func providerConfigureContext(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	config, err := providerConfigure(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	pconf := config.(*providerConfiguration)
+	strict := d.Get("pm_strict_version_check").(bool)
+
+	version, err := pveVersion(pconf.Session)
+	if err != nil {
+		err = fmt.Errorf("Reading the version of Proxmox VE failed: %v", err)
+		if strict {
+			return nil, diag.FromErr(err)
+		}
+		return config, diag.Diagnostics{{Severity: diag.Warning, Summary: err.Error()}}
+	}
+
+	logger, _ := CreateSubLogger("provider")
+	logger.Info().Str("version", version).Msg("Connected to Proxmox VE")
+
+	diags := checkPveVersion(version, strict)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// a failed check is taken for a cluster, which makes HA resources fail loudly
+	if pconf.Standalone, err = apiStandalone(pconf.Session); err != nil {
+		logger.Warn().Msgf("Reading the status of the cluster failed: %v", err)
+	}
+	return config, diags
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	client, err := getClient(
+	client, session, err := getClient(
 		d.Get("pm_api_url").(string),
 		d.Get("pm_user").(string),
 		d.Get("pm_password").(string),
@@ -160,27 +418,70 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	// actually configure logging
 	// note that if enable is false here, the configuration will squash all output
+	logFile := logFilePath(d.Get("pm_log_file").(string), d.Get("pm_log_per_run").(bool))
 	ConfigureLogger(
 		d.Get("pm_log_enable").(bool),
-		d.Get("pm_log_file").(string),
+		logFile,
 		logLevels,
 	)
 
+	namePattern, err := namingPattern("pm_name_pattern", d.Get("pm_name_pattern").(string))
+	if err != nil {
+		return nil, err
+	}
+	tagPattern, err := namingPattern("pm_tag_pattern", d.Get("pm_tag_pattern").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	defaultTimeouts := make(map[string]time.Duration)
+	for _, key := range []string{schema.TimeoutCreate, schema.TimeoutUpdate, schema.TimeoutDelete} {
+		if timeout := d.Get("pm_default_" + key + "_timeout").(string); timeout != "" {
+			// validated by validateDuration
+			defaultTimeouts[key], _ = time.ParseDuration(timeout)
+		}
+	}
+
+	parallelPriorities := make(map[string]int)
+	for resourceType, priority := range d.Get("pm_parallel_priorities").(map[string]interface{}) {
+		parallelPriorities[resourceType] = priority.(int)
+	}
+
 	var mut sync.Mutex
 	return &providerConfiguration{
 		Client:                             client,
+		APIURL:                             d.Get("pm_api_url").(string),
+		Session:                            session,
 		MaxParallel:                        d.Get("pm_parallel").(int),
-		CurrentParallel:                    0,
-		MaxVMID:                            -1,
+		ParallelPriorities:                 parallelPriorities,
+		State:                              &providerState{MaxVMID: -1, Waiting: make(map[int]int)},
 		Mutex:                              &mut,
 		Cond:                               sync.NewCond(&mut),
-		LogFile:                            d.Get("pm_log_file").(string),
+		LogFile:                            logFile,
 		LogLevels:                          logLevels,
 		DangerouslyIgnoreUnknownAttributes: d.Get("pm_dangerously_ignore_unknown_attributes").(bool),
+		TLSInsecure:                        d.Get("pm_tls_insecure").(bool),
+		Timeout:                            d.Get("pm_timeout").(int),
+		TokenClients:                       make(map[string]*pxapi.Client),
+		TokenSessions:                      make(map[string]*pxapi.Session),
+		BwLimitClone:                       d.Get("pm_bwlimit_clone").(int),
+		BwLimitMigrate:                     d.Get("pm_bwlimit_migrate").(int),
+		BwLimitRestore:                     d.Get("pm_bwlimit_restore").(int),
+		RunWorkspace:                       d.Get("pm_run_workspace").(string),
+		RunURL:                             d.Get("pm_run_url").(string),
+		Placements:                         make(map[string][]string),
+		NamePattern:                        namePattern,
+		TagPattern:                         tagPattern,
+		DefaultTimeouts:                    defaultTimeouts,
+		CloudInitBaseUserData:              d.Get("pm_cloudinit_base_user_data").(string),
 	}, nil
 }
 
-func getClient(pm_api_url string, pm_user string, pm_password string, pm_api_token_id string, pm_api_token_secret string, pm_otp string, pm_tls_insecure bool, pm_timeout int) (*pxapi.Client, error) {
+// getClient authenticates once and returns both the proxmox-api-go client and the session
+// used for the API endpoints the client does not cover. The client borrows the
+// authentication of the session (see sessionAuthTransport), so a one time password is
+// only ever sent once.
+func getClient(pm_api_url string, pm_user string, pm_password string, pm_api_token_id string, pm_api_token_secret string, pm_otp string, pm_tls_insecure bool, pm_timeout int) (*pxapi.Client, *pxapi.Session, error) {
 	tlsconf := &tls.Config{InsecureSkipVerify: true}
 	if !pm_tls_insecure {
 		tlsconf = nil
@@ -204,33 +505,263 @@ func getClient(pm_api_url string, pm_user string, pm_password string, pm_api_tok
 		err = fmt.Errorf("Your API TokenID username should contain a !, check your API credentials.")
 	}
 
-	client, _ := pxapi.NewClient(pm_api_url, nil, tlsconf, pm_timeout)
+	// err holds what is wrong with the credentials, it is returned below
+	urls, urlErr := apiURLs(pm_api_url)
+	if urlErr != nil {
+		return nil, nil, urlErr
+	}
+
+	transport, transportErr := faultInjectionFromEnv(&http.Transport{
+		TLSClientConfig:    tlsconf,
+		DisableCompression: true,
+	})
+	if transportErr != nil {
+		return nil, nil, transportErr
+	}
+	if transport, transportErr = newFailoverTransport(transport, urls); transportErr != nil {
+		return nil, nil, transportErr
+	}
+	transport = &retryTransport{base: transport}
+
+	// requests are built for the first URL, the failover transport moves them to the endpoint in use
+	session, _ := pxapi.NewSession(urls[0], &http.Client{Transport: transport}, tlsconf)
 
 	// User+Pass authentication
 	if pm_user != "" && pm_password != "" {
-		err = client.Login(pm_user, pm_password, pm_otp)
+		err = session.Login(pm_user, pm_password, pm_otp)
 	}
 
 	// API authentication
 	if pm_api_token_id != "" && pm_api_token_secret != "" {
 		// Unsure how to get an err for this
-		client.SetAPIToken(pm_api_token_id, pm_api_token_secret)
+		session.SetAPIToken(pm_api_token_id, pm_api_token_secret)
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+
+	httpClient := &http.Client{
+		Transport: &sessionAuthTransport{
+			session: session,
+			base:    transport,
+		},
+	}
+	client, _ := pxapi.NewClient(urls[0], httpClient, tlsconf, pm_timeout)
+	// normally set by client.Login, still needed for the connection info of the guests
+	client.Username = pm_user
+	client.Password = pm_password
+	client.Otp = pm_otp
+
+	return client, session, nil
+}
+
+// sessionAuthTransport adds the credentials of an authenticated session to every request,
+// the client it is used by never logs in on its own.
+type sessionAuthTransport struct {
+	session *pxapi.Session
+	base    http.RoundTripper
+}
+
+func (t *sessionAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authReq := req.Clone(req.Context())
+	if t.session.AuthToken != "" {
+		authReq.Header.Set("Authorization", "PVEAPIToken="+t.session.AuthToken)
+	} else if t.session.AuthTicket != "" {
+		authReq.Header.Set("Cookie", "PVEAuthCookie="+t.session.AuthTicket)
+		authReq.Header.Set("CSRFPreventionToken", t.session.CsrfToken)
+	}
+	return t.base.RoundTrip(authReq)
+}
+
+// number of tries and the delay before the first retry of retryTransport, the delay doubles with every try
+var apiRetryAttempts = 4
+var apiRetryDelay = time.Second
+
+// retryTransport retries requests which failed without any effect on the cluster: requests
+// rejected because the config of a guest was locked by another task, and GET requests which
+// timed out or hit an unavailable proxy.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := apiRetryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= apiRetryAttempts || !retryableResponse(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		logger, _ := CreateSubLogger("api_retry")
+		logger.Debug().Msgf("Retrying %s %s in %v after attempt %d failed: %v", req.Method, req.URL.Path, delay, attempt, retryReason(resp, err))
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func retryableResponse(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		netErr, ok := err.(net.Error)
+		return ok && netErr.Timeout() && req.Method == http.MethodGet
+	}
+	if strings.Contains(resp.Status, "can't lock file") {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return req.Method == http.MethodGet
+	}
+	return false
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// Guests can be managed with a different API token than the one of the provider, e.g. to create
+// them in the authorization scope of a tenant. The clients are created once per token and reused.
+// They are cached by the id and the secret of the token, so a rotated secret gets a new client.
+func resourceClient(d *schema.ResourceData, pconf *providerConfiguration) (*pxapi.Client, error) {
+	// resources without the arguments of resourceClientSchema use the provider credentials
+	tokenID, _ := d.Get("pm_api_token_id").(string)
+	if tokenID == "" {
+		return pconf.Client, nil
+	}
+	tokenSecret := d.Get("pm_api_token_secret").(string)
+	key := resourceTokenKey(tokenID, tokenSecret)
+
+	pconf.Mutex.Lock()
+	defer pconf.Mutex.Unlock()
+	if client, ok := pconf.TokenClients[key]; ok {
+		return client, nil
+	}
+	client, session, err := getClient(
+		pconf.APIURL,
+		"",
+		"",
+		tokenID,
+		tokenSecret,
+		"",
+		pconf.TLSInsecure,
+		pconf.Timeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create a client for the API token %s: %v", tokenID, err)
+	}
+	pconf.TokenClients[key] = client
+	pconf.TokenSessions[key] = session
 	return client, nil
 }
 
+// The key of the cached clients and sessions of an API token, written like the token in the
+// Authorization header.
+func resourceTokenKey(tokenID string, tokenSecret string) string {
+	return tokenID + "=" + tokenSecret
+}
+
+// The session belonging to the client returned by resourceClient, for the API endpoints
+// the client does not cover.
+func resourceSession(d *schema.ResourceData, pconf *providerConfiguration) (*pxapi.Session, error) {
+	if _, err := resourceClient(d, pconf); err != nil {
+		return nil, err
+	}
+	tokenID, _ := d.Get("pm_api_token_id").(string)
+	if tokenID == "" {
+		return pconf.Session, nil
+	}
+
+	pconf.Mutex.Lock()
+	defer pconf.Mutex.Unlock()
+	return pconf.TokenSessions[resourceTokenKey(tokenID, d.Get("pm_api_token_secret").(string))], nil
+}
+
+// The timeouts block of the resources waiting for proxmox tasks. The timeouts default to zero,
+// which stands for the timeout of the provider, see resourceTaskClient.
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(time.Duration(0)),
+		Update: schema.DefaultTimeout(time.Duration(0)),
+		Delete: schema.DefaultTimeout(time.Duration(0)),
+	}
+}
+
+// The client of resourceClient waiting for the tasks of an operation as long as the timeouts
+// block of the resource allows. Without a timeouts block the pm_default_<operation>_timeout
+// of the provider is used and without one of those pm_timeout.
+func resourceTaskClient(d *schema.ResourceData, pconf *providerConfiguration, operation string) (*pxapi.Client, error) {
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return nil, err
+	}
+	timeout := d.Timeout(operation)
+	if timeout <= 0 {
+		timeout = pconf.DefaultTimeouts[operation]
+	}
+	if timeout <= 0 {
+		return client, nil
+	}
+	// the client is shared by all resources, only this operation waits longer or shorter
+	taskClient := *client
+	taskClient.TaskTimeout = int(timeout.Seconds())
+	return &taskClient, nil
+}
+
+func validateDuration(value interface{}, key string) ([]string, []error) {
+	if _, err := time.ParseDuration(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a duration like 30m or 1h30m: %v", key, err)}
+	}
+	return nil, nil
+}
+
+// resource level api token override, see resourceClient
+func resourceClientSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"pm_api_token_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			RequiredWith: []string{"pm_api_token_secret"},
+			Description:  "API TokenID used for this resource instead of the provider credentials e.g. tenant@pve!mytoken",
+		},
+		"pm_api_token_secret": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			RequiredWith: []string{"pm_api_token_id"},
+			Description:  "The secret uuid corresponding to pm_api_token_id",
+		},
+	}
+}
+
 func nextVmId(pconf *providerConfiguration) (nextId int, err error) {
 	pconf.Mutex.Lock()
 	defer pconf.Mutex.Unlock()
-	pconf.MaxVMID, err = pconf.Client.GetNextID(pconf.MaxVMID + 1)
+	pconf.State.MaxVMID, err = pconf.Client.GetNextID(pconf.State.MaxVMID + 1)
 	if err != nil {
 		return 0, err
 	}
-	nextId = pconf.MaxVMID
+	nextId = pconf.State.MaxVMID
 	return nextId, nil
 }
 
@@ -245,11 +776,16 @@ func (lock *pmApiLockHolder) lock() {
 	}
 	lock.locked = true
 	pconf := lock.pconf
+	state := pconf.State
 	pconf.Mutex.Lock()
-	for pconf.CurrentParallel >= pconf.MaxParallel {
+	state.Waiting[pconf.Priority]++
+	for state.CurrentParallel >= pconf.MaxParallel || parallelHigherWaiting(state.Waiting, pconf.Priority) {
 		pconf.Cond.Wait()
 	}
-	pconf.CurrentParallel++
+	state.Waiting[pconf.Priority]--
+	state.CurrentParallel++
+	// the operations of a lower priority may go on when there are slots left
+	pconf.Cond.Broadcast()
 	pconf.Mutex.Unlock()
 }
 
@@ -260,8 +796,9 @@ func (lock *pmApiLockHolder) unlock() {
 	lock.locked = false
 	pconf := lock.pconf
 	pconf.Mutex.Lock()
-	pconf.CurrentParallel--
-	pconf.Cond.Signal()
+	pconf.State.CurrentParallel--
+	// a signal might wake an operation which has to wait for one of a higher priority
+	pconf.Cond.Broadcast()
 	pconf.Mutex.Unlock()
 }
 
@@ -304,3 +841,25 @@ func parseClusterResourceId(resId string) (resType string, id string, err error)
 	idMatch := rxClusterRsId.FindStringSubmatch(resId)
 	return idMatch[1], idMatch[2], nil
 }
+
+// ids of the objects of a node look like <node>/<name>, e.g. pve1/3 for the ceph OSD 3 of pve1
+func nodeResourceId(node string, name string) string {
+	return fmt.Sprintf("%s/%s", node, name)
+}
+
+func parseNodeResourceId(resId string) (node string, name string, err error) {
+	parts := strings.SplitN(resId, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid resource format: %s. Must be node/name", resId)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ids of volumes look like <node>/<volid>, e.g. pve1/local:iso/debian-11.0.0-amd64-netinst.iso
+func parseVolumeResourceId(resId string) (node string, volid string, err error) {
+	parts := strings.SplitN(resId, "/", 2)
+	if len(parts) != 2 || !strings.Contains(parts[1], ":") {
+		return "", "", fmt.Errorf("Invalid resource format: %s. Must be node/volid", resId)
+	}
+	return parts[0], parts[1], nil
+}
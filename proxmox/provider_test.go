@@ -2,7 +2,16 @@ package proxmox
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
 	"testing"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestParseClusteResources(t *testing.T) {
@@ -58,3 +67,184 @@ func TestParseClusteResources(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionAuthTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		session pxapi.Session
+		headers map[string]string
+	}{{
+		name:    "api token",
+		session: pxapi.Session{AuthToken: "user@pve!token=secret"},
+		headers: map[string]string{"Authorization": "PVEAPIToken=user@pve!token=secret"},
+	}, {
+		name:    "ticket",
+		session: pxapi.Session{AuthTicket: "ticket", CsrfToken: "csrf"},
+		headers: map[string]string{"Cookie": "PVEAuthCookie=ticket", "CSRFPreventionToken": "csrf"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			var received http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header
+			}))
+			defer server.Close()
+
+			session := test.session
+			client := &http.Client{Transport: &sessionAuthTransport{session: &session, base: http.DefaultTransport}}
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			if _, err := client.Do(req); err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+
+			for key, value := range test.headers {
+				if received.Get(key) != value {
+					t.Errorf("%s: header %s expected `%+v`, got `%+v`", test.name, key, value, received.Get(key))
+				}
+				if req.Header.Get(key) != "" {
+					t.Errorf("%s: header %s leaked into the original request", test.name, key)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVolumeResourceId(t *testing.T) {
+	node, volid, err := parseVolumeResourceId("pve1/local:iso/debian-11.0.0-amd64-netinst.iso")
+	if err != nil || node != "pve1" || volid != "local:iso/debian-11.0.0-amd64-netinst.iso" {
+		t.Errorf("expected pve1 and local:iso/debian-11.0.0-amd64-netinst.iso, got `%s`, `%s` and `%+v`", node, volid, err)
+	}
+	if _, _, err = parseVolumeResourceId("local:iso/debian.iso"); err == nil {
+		t.Errorf("expected an error for an id without node")
+	}
+}
+
+func TestResourceTaskClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *schema.ResourceTimeout
+		defaults map[string]time.Duration
+		expected int
+	}{
+		{name: "pm_timeout", timeouts: resourceTimeouts(), defaults: map[string]time.Duration{}, expected: 300},
+		{name: "provider default", timeouts: resourceTimeouts(), defaults: map[string]time.Duration{schema.TimeoutCreate: 30 * time.Minute}, expected: 1800},
+		{name: "other operation", timeouts: resourceTimeouts(), defaults: map[string]time.Duration{schema.TimeoutDelete: 30 * time.Minute}, expected: 300},
+		{name: "timeouts block", timeouts: &schema.ResourceTimeout{Create: schema.DefaultTimeout(10 * time.Minute)}, defaults: map[string]time.Duration{schema.TimeoutCreate: 30 * time.Minute}, expected: 600},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			pconf := &providerConfiguration{Client: &pxapi.Client{TaskTimeout: 300}, DefaultTimeouts: test.defaults}
+			d := (&schema.Resource{Schema: resourceClientSchema(), Timeouts: test.timeouts}).Data(nil)
+
+			client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+			if err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if client.TaskTimeout != test.expected {
+				t.Errorf("%s: expected a task timeout of %d, got %d", test.name, test.expected, client.TaskTimeout)
+			}
+			if pconf.Client.TaskTimeout != 300 {
+				t.Errorf("%s: expected the shared client to keep its task timeout, got %d", test.name, pconf.Client.TaskTimeout)
+			}
+		})
+	}
+}
+
+func TestResourceClientTokenRotation(t *testing.T) {
+	pconf := &providerConfiguration{
+		APIURL:        "https://pve1:8006/api2/json",
+		Mutex:         &sync.Mutex{},
+		TokenClients:  map[string]*pxapi.Client{},
+		TokenSessions: map[string]*pxapi.Session{},
+	}
+	resource := &schema.Resource{Schema: resourceClientSchema()}
+	clients := []*pxapi.Client{}
+	for _, secret := range []string{"old", "old", "new"} {
+		d := resource.Data(nil)
+		d.Set("pm_api_token_id", "tenant@pve!terraform")
+		d.Set("pm_api_token_secret", secret)
+		client, err := resourceClient(d, pconf)
+		if err != nil {
+			t.Fatalf("%s: unexpected error `%+v`", secret, err)
+		}
+		clients = append(clients, client)
+	}
+	if clients[0] != clients[1] {
+		t.Error("expected the client of the token to be reused")
+	}
+	if clients[1] == clients[2] {
+		t.Error("expected a new client for the rotated secret")
+	}
+}
+
+func TestGetClientCredentialErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		tokenID     string
+		tokenSecret string
+	}{
+		{"token id without !", "", "terraform@pve", "uuid"},
+		{"password and token secret", "secret", "terraform@pve!token", "uuid"},
+		{"no credentials", "", "terraform@pve!token", ""},
+	}
+	for _, test := range tests {
+		if _, _, err := getClient("https://pve1:8006/api2/json", "", test.password, test.tokenID, test.tokenSecret, "", true, 300); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+	if _, _, err := getClient("https://pve1:8006/api2/json", "", "", "terraform@pve!token", "uuid", "", true, 300); err != nil {
+		t.Errorf("expected the token to be accepted, got `%v`", err)
+	}
+}
+
+func TestProviderPasswordConflictsWithToken(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pm_api_url":          "https://pve1:8006/api2/json",
+		"pm_user":             "terraform@pve",
+		"pm_password":         "secret",
+		"pm_api_token_id":     "terraform@pve!token",
+		"pm_api_token_secret": "uuid",
+	})
+	if diags := Provider().Validate(config); !diags.HasError() {
+		t.Error("expected pm_password to conflict with the API token")
+	}
+}
+
+// The registry docs are generated from the descriptions, every resource, data source and
+// attribute needs one.
+func TestProviderDescriptions(t *testing.T) {
+	provider := Provider()
+	missing := providerMissingDescriptions("provider.", provider.Schema)
+	for name, resource := range provider.ResourcesMap {
+		if resource.Description == "" {
+			missing = append(missing, name)
+		}
+		missing = append(missing, providerMissingDescriptions(name+".", resource.Schema)...)
+	}
+	for name, dataSource := range provider.DataSourcesMap {
+		if dataSource.Description == "" {
+			missing = append(missing, "data."+name)
+		}
+		missing = append(missing, providerMissingDescriptions("data."+name+".", dataSource.Schema)...)
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		t.Errorf("%s has no description", key)
+	}
+}
+
+func providerMissingDescriptions(prefix string, attributes map[string]*schema.Schema) []string {
+	missing := []string{}
+	for key, attribute := range attributes {
+		if attribute.Description == "" {
+			missing = append(missing, prefix+key)
+		}
+		if block, ok := attribute.Elem.(*schema.Resource); ok {
+			missing = append(missing, providerMissingDescriptions(prefix+key+".", block.Schema)...)
+		}
+	}
+	return missing
+}
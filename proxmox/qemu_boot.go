@@ -0,0 +1,43 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Constraints of the boot settings of a VM which the schema can't express, they fail when
+// planning instead of when the VM is created.
+
+// Whether the boot order includes the network, in the legacy format like "cdn" or in the one of
+// Proxmox VE 6.2 and later like "order=scsi0;net0".
+func qemuBootsFromNetwork(boot string) bool {
+	if !strings.Contains(boot, "=") {
+		return strings.Contains(boot, "n")
+	}
+	for _, option := range strings.Split(boot, ",") {
+		if strings.HasPrefix(option, "order=") {
+			for _, device := range strings.Split(strings.TrimPrefix(option, "order="), ";") {
+				if strings.HasPrefix(device, "net") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func qemuBootCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// efidisk is computed, so only an added or changed one is checked
+	bios := d.Get("bios").(string)
+	if d.NewValueKnown("bios") && bios != "ovmf" && d.HasChange("efidisk") && len(d.Get("efidisk").([]interface{})) > 0 {
+		return fmt.Errorf("efidisk: requires bios = \"ovmf\", got %q", bios)
+	}
+	boot := d.Get("boot").(string)
+	if d.Get("pxe").(bool) && d.NewValueKnown("boot") && !qemuBootsFromNetwork(boot) {
+		return fmt.Errorf("pxe: requires a boot order including the network, e.g. \"order=net0;scsi0\", got %q", boot)
+	}
+	return nil
+}
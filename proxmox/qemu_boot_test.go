@@ -0,0 +1,48 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestQemuBootsFromNetwork(t *testing.T) {
+	tests := []struct {
+		boot    string
+		network bool
+	}{
+		{"cdn", true},
+		{"cd", false},
+		{"order=net0;scsi0", true},
+		{"order=scsi0;ide2", false},
+		{"legacy=cdn", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if network := qemuBootsFromNetwork(test.boot); network != test.network {
+			t.Errorf("%s: expected %v, got %v", test.boot, test.network, network)
+		}
+	}
+}
+
+func TestVmQemuExclusiveSources(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		valid  bool
+	}{
+		{map[string]interface{}{"clone": "template"}, true},
+		{map[string]interface{}{"pxe": true, "boot": "order=net0"}, true},
+		{map[string]interface{}{"clone": "template", "iso": "local:iso/debian.iso"}, false},
+		{map[string]interface{}{"pxe": true, "iso": "local:iso/debian.iso"}, false},
+		{map[string]interface{}{"restore_from": "pbs:backup/vm/100/2024-01-01T00:00:00Z", "pxe": true}, false},
+		{map[string]interface{}{"iso": "local:iso/debian.iso", "source_digest": "abc"}, false},
+	}
+	for _, test := range tests {
+		test.config["name"] = "vm"
+		test.config["target_node"] = "pve1"
+		diags := resourceVmQemu().Validate(terraform.NewResourceConfigRaw(test.config))
+		if diags.HasError() == test.valid {
+			t.Errorf("%v: expected valid %v, got `%v`", test.config, test.valid, diags)
+		}
+	}
+}
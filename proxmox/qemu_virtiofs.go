@@ -0,0 +1,156 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The virtiofs<n> devices of a VM share the directories of a proxmox_mapping_dir with the guest,
+// which mounts them by the mapping id. The proxmox-api-go config doesn't know them, they are
+// written to the config of the VM directly.
+
+const qemuVirtiofsMax = 10
+
+func qemuVirtiofsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    qemuVirtiofsMax,
+		Description: "Directories of the nodes shared with the guest through virtiofs, they are added as virtiofs0, virtiofs1, ...",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"dir_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The name of the directory mapping, which is also the tag the guest mounts it by",
+				},
+				"cache": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "auto",
+					ValidateFunc: validation.StringInSlice([]string{"auto", "always", "metadata", "never"}, false),
+					Description:  "The caching of the guest: auto, always, metadata or never",
+				},
+				"direct_io": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Honor the O_DIRECT flag of the guest",
+				},
+				"expose_acl": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Expose the POSIX ACLs of the directory, implies `expose_xattr`",
+				},
+				"expose_xattr": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Expose the extended attributes of the directory",
+				},
+			},
+		},
+	}
+}
+
+var qemuVirtiofsFlags = map[string]string{
+	"direct_io":    "direct-io",
+	"expose_acl":   "expose-acl",
+	"expose_xattr": "expose-xattr",
+}
+
+// The virtiofs<n> value of a block, e.g. "dirid=share,cache=auto,expose-acl=1".
+func qemuVirtiofsParam(virtiofs map[string]interface{}) string {
+	values := []string{
+		"dirid=" + virtiofs["dir_id"].(string),
+		"cache=" + virtiofs["cache"].(string),
+	}
+	flags := []string{}
+	for attribute := range qemuVirtiofsFlags {
+		flags = append(flags, attribute)
+	}
+	sort.Strings(flags)
+	for _, attribute := range flags {
+		if virtiofs[attribute].(bool) {
+			values = append(values, qemuVirtiofsFlags[attribute]+"=1")
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// The indexes of the virtiofs<n> devices in the config of a VM, in ascending order.
+func qemuVirtiofsIndexes(config map[string]interface{}) []int {
+	indexes := []int{}
+	for key := range config {
+		if !strings.HasPrefix(key, "virtiofs") {
+			continue
+		}
+		if index, err := strconv.Atoi(strings.TrimPrefix(key, "virtiofs")); err == nil {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// The blocks of the virtiofs devices in the config of a VM.
+func parseQemuVirtiofs(config map[string]interface{}) []interface{} {
+	result := []interface{}{}
+	for _, index := range qemuVirtiofsIndexes(config) {
+		conf := pxapi.ParsePMConf(apiString(config[fmt.Sprintf("virtiofs%d", index)]), "dirid")
+		cache := apiString(conf["cache"])
+		if cache == "" {
+			cache = "auto"
+		}
+		virtiofs := map[string]interface{}{
+			"dir_id": apiString(conf["dirid"]),
+			"cache":  cache,
+		}
+		for attribute, key := range qemuVirtiofsFlags {
+			virtiofs[attribute] = apiBool(conf[key])
+		}
+		result = append(result, virtiofs)
+	}
+	return result
+}
+
+// The parameters setting the virtiofs devices of a VM with config to the blocks, devices beyond
+// the blocks are returned as the ones to delete.
+func qemuVirtiofsParams(virtiofs []interface{}, config map[string]interface{}) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for index, block := range virtiofs {
+		params[fmt.Sprintf("virtiofs%d", index)] = qemuVirtiofsParam(block.(map[string]interface{}))
+	}
+	for _, index := range qemuVirtiofsIndexes(config) {
+		if index >= len(virtiofs) {
+			deletes = append(deletes, fmt.Sprintf("virtiofs%d", index))
+		}
+	}
+	return
+}
+
+// Writes the virtiofs devices of a VM, proxmox keeps them pending until the next start of a
+// running VM.
+func updateQemuVirtiofs(client *pxapi.Client, vmr *pxapi.VmRef, virtiofs []interface{}) error {
+	config, err := client.GetVmConfig(vmr)
+	if err != nil {
+		return err
+	}
+	params, deletes := qemuVirtiofsParams(virtiofs, config)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	_, err = client.SetVmConfig(vmr, params)
+	return err
+}
@@ -0,0 +1,38 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQemuVirtiofsParams(t *testing.T) {
+	virtiofs := []interface{}{
+		map[string]interface{}{"dir_id": "share", "cache": "auto", "direct_io": false, "expose_acl": false, "expose_xattr": false},
+		map[string]interface{}{"dir_id": "media", "cache": "never", "direct_io": true, "expose_acl": true, "expose_xattr": false},
+	}
+	config := map[string]interface{}{
+		"virtiofs0": "dirid=old",
+		"virtiofs2": "dirid=gone",
+		"virtio0":   "local-lvm:vm-100-disk-0",
+	}
+
+	params, deletes := qemuVirtiofsParams(virtiofs, config)
+	expectedParams := map[string]interface{}{
+		"virtiofs0": "dirid=share,cache=auto",
+		"virtiofs1": "dirid=media,cache=never,direct-io=1,expose-acl=1",
+	}
+	if !reflect.DeepEqual(params, expectedParams) || !reflect.DeepEqual(deletes, []string{"virtiofs2"}) {
+		t.Errorf("expected `%v` deleting [virtiofs2], got `%v` deleting %v", expectedParams, params, deletes)
+	}
+
+	config = map[string]interface{}{
+		"virtiofs0": params["virtiofs0"],
+		"virtiofs1": params["virtiofs1"],
+	}
+	if parsed := parseQemuVirtiofs(config); !reflect.DeepEqual(parsed, virtiofs) {
+		t.Errorf("expected `%v`, got `%v`", virtiofs, parsed)
+	}
+	if parsed := parseQemuVirtiofs(map[string]interface{}{"virtiofs0": "share"}); parsed[0].(map[string]interface{})["dir_id"] != "share" || parsed[0].(map[string]interface{})["cache"] != "auto" {
+		t.Errorf("expected the implicit dirid share with cache auto, got `%v`", parsed)
+	}
+}
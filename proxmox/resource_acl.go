@@ -0,0 +1,266 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var aclResourceDef *schema.Resource
+
+func resourceAcl() *schema.Resource {
+	*pxapi.Debug = true
+
+	aclResourceDef = &schema.Resource{
+		Description: "Grants a role on an access control path to users, groups and API tokens (`/access/acl`).",
+
+		Create: resourceAclCreate,
+		Read:   resourceAclRead,
+		Update: resourceAclUpdate,
+		Delete: resourceAclDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Access control path, e.g. /vms/100, /pool/mypool or /storage/local",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if !strings.HasPrefix(val.(string), "/") {
+						errs = append(errs, fmt.Errorf("%q must start with /, got %s", key, val.(string)))
+					}
+					return
+				},
+			},
+			"roleid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role to grant.",
+			},
+			"users": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "User IDs (user@realm) the role is granted to",
+			},
+			"groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Groups the role is granted to",
+			},
+			"tokens": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "API token IDs (user@realm!token) the role is granted to",
+			},
+			"propagate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the permission is inherited by the paths below path",
+			},
+		},
+	}
+
+	return aclResourceDef
+}
+
+// the path of an acl contains slashes itself, so the id is acl/<roleid><path>
+func aclResourceId(path string, roleID string) string {
+	return clusterResourceId("acl", roleID+path)
+}
+
+func parseAclResourceId(resId string) (path string, roleID string, err error) {
+	parts := strings.SplitN(resId, "/", 3)
+	if len(parts) != 3 || parts[0] != "acl" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid resource format: %s. Must be acl/roleid/path", resId)
+	}
+	return "/" + parts[2], parts[1], nil
+}
+
+// the principals of an acl entry by their type (user, group or token)
+type aclPrincipals map[string][]string
+
+var aclPrincipalParams = map[string]string{"user": "users", "group": "groups", "token": "tokens"}
+
+func aclPrincipalsFromSchema(d *schema.ResourceData) aclPrincipals {
+	return aclPrincipals{
+		"user":  schemaStringList(d.Get("users")),
+		"group": schemaStringList(d.Get("groups")),
+		"token": schemaStringList(d.Get("tokens")),
+	}
+}
+
+// PUT /access/acl grants (or with delete revokes) a role on a path for all given principals
+func aclParams(path string, roleID string, principals aclPrincipals, propagate bool) map[string]interface{} {
+	params := map[string]interface{}{
+		"path":      path,
+		"roles":     roleID,
+		"propagate": propagate,
+	}
+	for principalType, param := range aclPrincipalParams {
+		if len(principals[principalType]) > 0 {
+			params[param] = strings.Join(principals[principalType], ",")
+		}
+	}
+	return params
+}
+
+func (principals aclPrincipals) empty() bool {
+	for _, ugids := range principals {
+		if len(ugids) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func resourceAclCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	path := d.Get("path").(string)
+	roleID := d.Get("roleid").(string)
+	principals := aclPrincipalsFromSchema(d)
+	if principals.empty() {
+		return fmt.Errorf("At least one of users, groups or tokens is required for the acl of %s on %s", roleID, path)
+	}
+
+	_, err := apiPut(pconf.Session, "/access/acl", aclParams(path, roleID, principals, d.Get("propagate").(bool)))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(aclResourceId(path, roleID))
+
+	return _resourceAclRead(d, meta)
+}
+
+func resourceAclRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceAclRead(d, meta)
+}
+
+func _resourceAclRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	path, roleID, err := parseAclResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_acl_read")
+	logger.Info().Str("path", path).Str("roleid", roleID).Msg("Reading acl")
+
+	entries, err := apiGet(pconf.Session, "/access/acl")
+	if err != nil {
+		return err
+	}
+
+	principals, propagate, found := aclEntries(entries, path, roleID)
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("path", path)
+	d.Set("roleid", roleID)
+	for principalType, attribute := range aclPrincipalParams {
+		if err = d.Set(attribute, principals[principalType]); err != nil {
+			return err
+		}
+	}
+	d.Set("propagate", propagate)
+
+	logger.Debug().Str("path", path).Str("roleid", roleID).Msgf("Finished acl read resulting in principals: '%+v'", principals)
+	return nil
+}
+
+// collect the principals which have the role on the path from the acl list
+func aclEntries(entries interface{}, path string, roleID string) (principals aclPrincipals, propagate bool, found bool) {
+	principals = aclPrincipals{"user": {}, "group": {}, "token": {}}
+	list, _ := entries.([]interface{})
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok || apiString(entry["path"]) != path || apiString(entry["roleid"]) != roleID {
+			continue
+		}
+		principalType := apiString(entry["type"])
+		if _, ok := principals[principalType]; !ok {
+			continue
+		}
+		principals[principalType] = append(principals[principalType], apiString(entry["ugid"]))
+		propagate = apiBool(entry["propagate"])
+		found = true
+	}
+	return
+}
+
+func resourceAclUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	path, roleID, err := parseAclResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	principals := aclPrincipalsFromSchema(d)
+	if principals.empty() {
+		return fmt.Errorf("At least one of users, groups or tokens is required for the acl of %s on %s", roleID, path)
+	}
+
+	removed := aclPrincipals{}
+	for principalType, attribute := range aclPrincipalParams {
+		prev, next := d.GetChange(attribute)
+		for _, ugid := range schemaStringList(prev) {
+			if !stringInList(ugid, schemaStringList(next)) {
+				removed[principalType] = append(removed[principalType], ugid)
+			}
+		}
+	}
+	if !removed.empty() {
+		params := aclParams(path, roleID, removed, d.Get("propagate").(bool))
+		params["delete"] = true
+		if _, err = apiPut(pconf.Session, "/access/acl", params); err != nil {
+			return err
+		}
+	}
+
+	// granting again is a no-op for unchanged principals and updates propagate
+	_, err = apiPut(pconf.Session, "/access/acl", aclParams(path, roleID, principals, d.Get("propagate").(bool)))
+	if err != nil {
+		return err
+	}
+
+	return _resourceAclRead(d, meta)
+}
+
+func resourceAclDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	path, roleID, err := parseAclResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params := aclParams(path, roleID, aclPrincipalsFromSchema(d), d.Get("propagate").(bool))
+	params["delete"] = true
+	_, err = apiPut(pconf.Session, "/access/acl", params)
+	return err
+}
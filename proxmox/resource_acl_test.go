@@ -0,0 +1,82 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAclResourceId(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		roleID string
+	}{
+		{name: "root", path: "/", roleID: "Administrator"},
+		{name: "vm", path: "/vms/100", roleID: "PVEVMAdmin"},
+		{name: "storage", path: "/storage/local-lvm", roleID: "PVEDatastoreUser"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			path, roleID, err := parseAclResourceId(aclResourceId(test.path, test.roleID))
+			if err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if path != test.path || roleID != test.roleID {
+				t.Errorf("%s: expected `%s` `%s`, got `%s` `%s`", test.name, test.path, test.roleID, path, roleID)
+			}
+		})
+	}
+
+	if _, _, err := parseAclResourceId("users/test@pve"); err == nil {
+		t.Errorf("expected an error for an id of another resource type")
+	}
+}
+
+func TestAclEntries(t *testing.T) {
+	entries := []interface{}{
+		map[string]interface{}{"path": "/vms/100", "roleid": "PVEVMAdmin", "type": "user", "ugid": "alice@pve", "propagate": float64(1)},
+		map[string]interface{}{"path": "/vms/100", "roleid": "PVEVMAdmin", "type": "group", "ugid": "operators", "propagate": float64(1)},
+		map[string]interface{}{"path": "/vms/100", "roleid": "PVEAuditor", "type": "user", "ugid": "bob@pve", "propagate": float64(1)},
+		map[string]interface{}{"path": "/vms/1000", "roleid": "PVEVMAdmin", "type": "token", "ugid": "ci@pve!deploy", "propagate": float64(0)},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		roleID     string
+		principals aclPrincipals
+		propagate  bool
+		found      bool
+	}{{
+		name:       "users and groups",
+		path:       "/vms/100",
+		roleID:     "PVEVMAdmin",
+		principals: aclPrincipals{"user": {"alice@pve"}, "group": {"operators"}, "token": {}},
+		propagate:  true,
+		found:      true,
+	}, {
+		name:       "token without propagate",
+		path:       "/vms/1000",
+		roleID:     "PVEVMAdmin",
+		principals: aclPrincipals{"user": {}, "group": {}, "token": {"ci@pve!deploy"}},
+		propagate:  false,
+		found:      true,
+	}, {
+		name:       "missing",
+		path:       "/vms/101",
+		roleID:     "PVEVMAdmin",
+		principals: aclPrincipals{"user": {}, "group": {}, "token": {}},
+		found:      false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			principals, propagate, found := aclEntries(entries, test.path, test.roleID)
+			if !reflect.DeepEqual(principals, test.principals) || propagate != test.propagate || found != test.found {
+				t.Errorf("%s: expected `%+v` %v %v, got `%+v` %v %v", test.name,
+					test.principals, test.propagate, test.found, principals, propagate, found)
+			}
+		})
+	}
+}
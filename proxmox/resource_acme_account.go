@@ -0,0 +1,249 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the directory accounts are registered at unless another one is given
+const acmeDefaultDirectory = "https://acme-v02.api.letsencrypt.org/directory"
+
+func resourceAcmeAccount() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an ACME account of the cluster, which the nodes order their certificates with, e.g. at Let's Encrypt.",
+
+		Create:   resourceAcmeAccountCreate,
+		Read:     resourceAcmeAccountRead,
+		Update:   resourceAcmeAccountUpdate,
+		Delete:   resourceAcmeAccountDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: resourceAcmeAccountImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "default",
+				Description: "The name of the account, which the ACME configuration of the nodes refers to",
+			},
+			"contact": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The email address of the account, several are separated by commas",
+			},
+			"directory": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      acmeDefaultDirectory,
+				ValidateFunc: validation.IsURLWithHTTPS,
+				Description:  "The URL of the ACME directory the account is registered at, Let's Encrypt by default",
+			},
+			"accept_tos": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Accept the terms of service of the directory, which most directories require",
+			},
+			"eab_kid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"eab_hmac_key"},
+				Description:  "The key id of the external account binding some directories require",
+			},
+			"eab_hmac_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"eab_kid"},
+				Description:  "The HMAC key of the external account binding",
+			},
+			"tos": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the terms of service which were accepted",
+			},
+			"location": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the account at the directory",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the account at the directory, e.g. valid",
+			},
+		},
+	}
+}
+
+// The contacts of an account as entered, without the mailto: of the ACME protocol.
+func acmeAccountContact(value interface{}) string {
+	contacts := []string{}
+	for _, contact := range apiStringList(value, ",") {
+		contacts = append(contacts, strings.TrimPrefix(contact, "mailto:"))
+	}
+	return strings.Join(contacts, ",")
+}
+
+func resourceAcmeAccountCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	directory := d.Get("directory").(string)
+	params := map[string]interface{}{
+		"name":      name,
+		"contact":   d.Get("contact").(string),
+		"directory": directory,
+	}
+	if d.Get("accept_tos").(bool) {
+		tos, err := apiGetWithParams(pconf.Session, "/cluster/acme/tos", map[string]interface{}{"directory": directory})
+		if err != nil {
+			return err
+		}
+		if apiString(tos) != "" {
+			params["tos_url"] = apiString(tos)
+		}
+	}
+	if kid := d.Get("eab_kid").(string); kid != "" {
+		params["eab-kid"] = kid
+		params["eab-hmac-key"] = d.Get("eab_hmac_key").(string)
+	}
+
+	logger, _ := CreateSubLogger("resource_acme_account_create")
+	logger.Info().Str("name", name).Str("directory", directory).Msg("Registering ACME account")
+
+	// the HMAC key is kept out of the debug log
+	upid, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, "/cluster/acme/account", params)
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = apiWaitForTask(pconf.Session, client, upid); err != nil {
+		return fmt.Errorf("Registering the ACME account %s failed: %v", name, err)
+	}
+	d.SetId(clusterResourceId("account", name))
+	return _resourceAcmeAccountRead(d, meta)
+}
+
+func resourceAcmeAccountRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceAcmeAccountRead(d, meta)
+}
+
+func _resourceAcmeAccountRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_acme_account_read")
+	logger.Info().Str("name", name).Msg("Reading ACME account")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "acme", "account", name))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	account, _ := config["account"].(map[string]interface{})
+
+	d.Set("name", name)
+	d.Set("contact", acmeAccountContact(account["contact"]))
+	d.Set("directory", apiString(config["directory"]))
+	d.Set("tos", apiString(config["tos"]))
+	d.Set("location", apiString(config["location"]))
+	d.Set("status", apiString(account["status"]))
+	return nil
+}
+
+// An imported account accepted the terms of service when it has them.
+func resourceAcmeAccountImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	if err := resourceAcmeAccountRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("ACME account %s not found", id)
+	}
+	d.Set("accept_tos", d.Get("tos").(string) != "")
+	return []*schema.ResourceData{d}, nil
+}
+
+// only the contact of an account can change
+func resourceAcmeAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	upid, err := apiPut(pconf.Session, apiPath("cluster", "acme", "account", name), map[string]interface{}{
+		"contact": d.Get("contact").(string),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = apiWaitForTask(pconf.Session, client, upid); err != nil {
+		return fmt.Errorf("Updating the ACME account %s failed: %v", name, err)
+	}
+	return _resourceAcmeAccountRead(d, meta)
+}
+
+// Deleting the account deactivates it at the directory, the certificates ordered with it stay valid.
+func resourceAcmeAccountDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	upid, err := apiDelete(pconf.Session, apiPath("cluster", "acme", "account", name))
+	if err != nil {
+		return err
+	}
+	_, err = apiWaitForTask(pconf.Session, client, upid)
+	return err
+}
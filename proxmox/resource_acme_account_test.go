@@ -0,0 +1,22 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestAcmeAccountContact(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{[]interface{}{"mailto:ops@example.com"}, "ops@example.com"},
+		{[]interface{}{"mailto:ops@example.com", "mailto:pki@example.com"}, "ops@example.com,pki@example.com"},
+		{"mailto:ops@example.com", "ops@example.com"},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if contact := acmeAccountContact(test.value); contact != test.expected {
+			t.Errorf("%v: expected `%s`, got `%s`", test.value, test.expected, contact)
+		}
+	}
+}
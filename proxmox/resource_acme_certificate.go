@@ -0,0 +1,312 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// proxmox has the options acmedomain0 to acmedomain5 for the domains of a node
+const acmeMaxDomains = 6
+
+func resourceAcmeCertificate() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Orders an ACME certificate for a node and renews it when it is about to expire.",
+
+		Create:        resourceAcmeCertificateCreate,
+		Read:          resourceAcmeCertificateRead,
+		Update:        resourceAcmeCertificateUpdate,
+		Delete:        resourceAcmeCertificateDelete,
+		CustomizeDiff: resourceAcmeCertificateCustomizeDiff,
+		Timeouts:      resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the certificate is ordered for",
+			},
+			"account": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				Description: "The name of the ACME account the certificate is ordered with",
+			},
+			"domain": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    acmeMaxDomains,
+				Description: "The domains of the certificate",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "The domain name",
+						},
+						"plugin": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The DNS plugin solving the challenge of the domain, the node answers the HTTP challenge itself when empty",
+						},
+						"alias": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The domain the DNS challenge is delegated to",
+						},
+					},
+				},
+			},
+			"renew_before_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Renew the certificate when it expires in fewer days than this",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 fingerprint of the certificate",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The issuer of the certificate",
+			},
+			"subject_alternative_names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The subject alternative names of the certificate",
+			},
+			"not_before": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the certificate becomes valid, as a unix timestamp",
+			},
+			"not_after": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the certificate expires, as a unix timestamp",
+			},
+		},
+	}
+}
+
+// The ACME options of the node config, deletes are the domain options which are not used.
+func acmeCertificateNodeParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"acme": "account=" + d.Get("account").(string),
+	}
+	deletes = []string{}
+	domains := d.Get("domain").([]interface{})
+	for i := 0; i < acmeMaxDomains; i++ {
+		option := fmt.Sprintf("acmedomain%d", i)
+		if i >= len(domains) {
+			deletes = append(deletes, option)
+			continue
+		}
+		domain := domains[i].(map[string]interface{})
+		value := []string{"domain=" + domain["domain"].(string)}
+		if plugin := domain["plugin"].(string); plugin != "" {
+			value = append(value, "plugin="+plugin)
+		}
+		if alias := domain["alias"].(string); alias != "" {
+			value = append(value, "alias="+alias)
+		}
+		params[option] = strings.Join(value, ",")
+	}
+	return
+}
+
+// The domains in the node config, in the order of their options.
+func parseAcmeCertificateDomains(config map[string]interface{}) []interface{} {
+	options := []int{}
+	for key := range config {
+		if i, err := strconv.Atoi(strings.TrimPrefix(key, "acmedomain")); err == nil && strings.HasPrefix(key, "acmedomain") {
+			options = append(options, i)
+		}
+	}
+	sort.Ints(options)
+	domains := []interface{}{}
+	for _, i := range options {
+		domain := apiPropertyString(config[fmt.Sprintf("acmedomain%d", i)], "domain")
+		domains = append(domains, map[string]interface{}{
+			"domain": apiString(domain["domain"]),
+			"plugin": apiString(domain["plugin"]),
+			"alias":  apiString(domain["alias"]),
+		})
+	}
+	return domains
+}
+
+// whether a certificate expiring at notAfter is renewed at now
+func acmeCertificateRenewalDue(notAfter int, renewBeforeDays int, now time.Time) bool {
+	return notAfter > 0 && time.Unix(int64(notAfter), 0).Before(now.Add(time.Duration(renewBeforeDays)*24*time.Hour))
+}
+
+// A certificate about to expire is renewed by an update.
+func resourceAcmeCertificateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if acmeCertificateRenewalDue(d.Get("not_after").(int), d.Get("renew_before_days").(int), time.Now()) {
+		for _, key := range []string{"fingerprint", "not_before", "not_after"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Configures the ACME options of the node and orders the certificate, replacing the current one.
+func orderAcmeCertificate(d *schema.ResourceData, pconf *providerConfiguration, operation string) error {
+	client, err := resourceTaskClient(d, pconf, operation)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+
+	params, deletes := acmeCertificateNodeParams(d)
+	params["delete"] = strings.Join(deletes, ",")
+	if _, err = apiPut(pconf.Session, apiPath("nodes", node, "config"), params); err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_acme_certificate_order")
+	logger.Info().Str("node", node).Msg("Ordering ACME certificate of node")
+
+	_, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "certificates", "acme", "certificate"), map[string]interface{}{
+		"force": true,
+	})
+	if err != nil {
+		return fmt.Errorf("Ordering the ACME certificate of node %s failed: %v", node, err)
+	}
+	return nil
+}
+
+func resourceAcmeCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if err := orderAcmeCertificate(d, pconf, schema.TimeoutCreate); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("acme", d.Get("node").(string)))
+	return _resourceAcmeCertificateRead(d, meta)
+}
+
+func resourceAcmeCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceAcmeCertificateRead(d, meta)
+}
+
+func _resourceAcmeCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_acme_certificate_read")
+	logger.Info().Str("node", node).Msg("Reading ACME certificate of node")
+
+	info, err := nodeCustomCertificate(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		d.SetId("")
+		return nil
+	}
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "config"))
+	if err != nil {
+		return err
+	}
+
+	d.Set("node", node)
+	d.Set("account", "default")
+	if account := apiString(apiPropertyString(config["acme"], "account")["account"]); account != "" {
+		d.Set("account", account)
+	}
+	if err = d.Set("domain", parseAcmeCertificateDomains(config)); err != nil {
+		return err
+	}
+	d.Set("fingerprint", apiString(info["fingerprint"]))
+	d.Set("issuer", apiString(info["issuer"]))
+	if err = d.Set("subject_alternative_names", apiStringList(info["san"], " ,")); err != nil {
+		return err
+	}
+	d.Set("not_before", apiInt(info["notbefore"]))
+	d.Set("not_after", apiInt(info["notafter"]))
+	return nil
+}
+
+// orders the certificate again for changed domains or when it is about to expire
+func resourceAcmeCertificateUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if d.HasChanges("account", "domain") || acmeCertificateRenewalDue(d.Get("not_after").(int), d.Get("renew_before_days").(int), time.Now()) {
+		if err := orderAcmeCertificate(d, pconf, schema.TimeoutUpdate); err != nil {
+			return err
+		}
+	}
+	return _resourceAcmeCertificateRead(d, meta)
+}
+
+// Revokes the certificate and removes the ACME options of the node, which serves the
+// certificate signed by the cluster CA again.
+func resourceAcmeCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "certificates", "acme", "certificate"))
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Revoking the ACME certificate of node %s failed: %v", node, err)
+	}
+
+	deletes := []string{"acme"}
+	for i := 0; i < acmeMaxDomains; i++ {
+		deletes = append(deletes, fmt.Sprintf("acmedomain%d", i))
+	}
+	_, err = apiPut(pconf.Session, apiPath("nodes", node, "config"), map[string]interface{}{
+		"delete": strings.Join(deletes, ","),
+	})
+	return err
+}
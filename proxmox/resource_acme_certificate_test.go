@@ -0,0 +1,66 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAcmeCertificateNodeParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAcmeCertificate().Schema, map[string]interface{}{
+		"node":    "pve1",
+		"account": "letsencrypt",
+		"domain": []interface{}{
+			map[string]interface{}{"domain": "pve1.example.com", "plugin": "cloudflare"},
+			map[string]interface{}{"domain": "pve.example.com", "plugin": "cloudflare", "alias": "acme.example.net"},
+		},
+	})
+
+	params, deletes := acmeCertificateNodeParams(d)
+	expected := map[string]interface{}{
+		"acme":        "account=letsencrypt",
+		"acmedomain0": "domain=pve1.example.com,plugin=cloudflare",
+		"acmedomain1": "domain=pve.example.com,plugin=cloudflare,alias=acme.example.net",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"acmedomain2", "acmedomain3", "acmedomain4", "acmedomain5"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+
+	domains := parseAcmeCertificateDomains(map[string]interface{}{
+		"acme":        "account=letsencrypt",
+		"acmedomain1": "domain=pve.example.com,plugin=cloudflare,alias=acme.example.net",
+		"acmedomain0": "pve1.example.com",
+	})
+	expectedDomains := []interface{}{
+		map[string]interface{}{"domain": "pve1.example.com", "plugin": "", "alias": ""},
+		map[string]interface{}{"domain": "pve.example.com", "plugin": "cloudflare", "alias": "acme.example.net"},
+	}
+	if !reflect.DeepEqual(domains, expectedDomains) {
+		t.Errorf("expected domains `%v`, got `%v`", expectedDomains, domains)
+	}
+}
+
+func TestAcmeCertificateRenewalDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notAfter time.Time
+		due      bool
+	}{
+		{now.Add(60 * 24 * time.Hour), false},
+		{now.Add(29 * 24 * time.Hour), true},
+		{now.Add(-time.Hour), true},
+	}
+	for _, test := range tests {
+		if due := acmeCertificateRenewalDue(int(test.notAfter.Unix()), 30, now); due != test.due {
+			t.Errorf("%v: expected %v, got %v", test.notAfter, test.due, due)
+		}
+	}
+	if acmeCertificateRenewalDue(0, 30, now) {
+		t.Errorf("expected no renewal without a certificate")
+	}
+}
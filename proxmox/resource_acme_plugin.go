@@ -0,0 +1,211 @@
+package proxmox
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAcmePlugin() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an ACME DNS plugin of the cluster, which solves the DNS-01 challenges of certificate orders through the API of a DNS provider.",
+
+		Create: resourceAcmePluginCreate,
+		Read:   resourceAcmePluginRead,
+		Update: resourceAcmePluginUpdate,
+		Delete: resourceAcmePluginDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"plugin": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the plugin, which the domains of the nodes refer to",
+			},
+			"api": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The DNS API of acme.sh the plugin uses, e.g. `cf` for Cloudflare or `aws` for Route 53",
+			},
+			"data": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The credentials and settings of the DNS API, e.g. `CF_Token`, as documented by acme.sh. Proxmox doesn't return them, so changes made outside of Terraform are not noticed",
+			},
+			"nodes": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes the plugin is available on, all nodes when empty",
+			},
+			"validation_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntBetween(0, 172800),
+				Description:  "How many seconds to wait after setting the DNS record before the validation is requested",
+			},
+			"disable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the plugin is disabled",
+			},
+		},
+	}
+}
+
+// The data of a plugin as proxmox expects it, KEY=value lines encoded in base64.
+func acmePluginData(data map[string]interface{}) string {
+	lines := []string{}
+	for key, value := range data {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(lines)
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(lines, "\n")))
+}
+
+// The parameters of the plugin, the data is only sent when it is new or changed.
+func acmePluginParams(d *schema.ResourceData, update bool) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"api":              d.Get("api").(string),
+		"validation-delay": d.Get("validation_delay").(int),
+		"disable":          d.Get("disable").(bool),
+	}
+	deletes = []string{}
+	if nodes := schemaStringList(d.Get("nodes")); len(nodes) > 0 {
+		sort.Strings(nodes)
+		params["nodes"] = strings.Join(nodes, ",")
+	} else {
+		deletes = append(deletes, "nodes")
+	}
+	if !update || d.HasChange("data") {
+		if data := d.Get("data").(map[string]interface{}); len(data) > 0 {
+			params["data"] = acmePluginData(data)
+		} else {
+			deletes = append(deletes, "data")
+		}
+	}
+	return
+}
+
+func resourceAcmePluginCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	plugin := d.Get("plugin").(string)
+	params, _ := acmePluginParams(d, false)
+	params["id"] = plugin
+	params["type"] = "dns"
+
+	logger, _ := CreateSubLogger("resource_acme_plugin_create")
+	logger.Info().Str("plugin", plugin).Msg("Creating ACME plugin")
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, "/cluster/acme/plugins", params)
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("plugins", plugin))
+	return _resourceAcmePluginRead(d, meta)
+}
+
+func resourceAcmePluginRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceAcmePluginRead(d, meta)
+}
+
+func _resourceAcmePluginRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, plugin, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_acme_plugin_read")
+	logger.Info().Str("plugin", plugin).Msg("Reading configuration for ACME plugin")
+
+	// the response contains the data, it is kept out of the debug log
+	config, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiGetMap(pconf.Session, apiPath("cluster", "acme", "plugins", plugin))
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	pluginConfig := config.(map[string]interface{})
+	if pluginType := apiString(pluginConfig["type"]); pluginType != "dns" {
+		return fmt.Errorf("ACME plugin %s is of type %s, not dns", plugin, pluginType)
+	}
+
+	d.Set("plugin", plugin)
+	d.Set("api", apiString(pluginConfig["api"]))
+	if err = d.Set("nodes", apiStringList(pluginConfig["nodes"], ",")); err != nil {
+		return err
+	}
+	d.Set("validation_delay", 30)
+	if value, ok := pluginConfig["validation-delay"]; ok {
+		d.Set("validation_delay", apiInt(value))
+	}
+	d.Set("disable", apiBool(pluginConfig["disable"]))
+	return nil
+}
+
+func resourceAcmePluginUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, plugin, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := acmePluginParams(d, true)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPut(pconf.Session, apiPath("cluster", "acme", "plugins", plugin), params)
+	})
+	if err != nil {
+		return err
+	}
+	return _resourceAcmePluginRead(d, meta)
+}
+
+func resourceAcmePluginDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, plugin, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "acme", "plugins", plugin))
+	return err
+}
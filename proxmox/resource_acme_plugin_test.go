@@ -0,0 +1,17 @@
+package proxmox
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestAcmePluginData(t *testing.T) {
+	data := map[string]interface{}{"CF_Token": "secret", "CF_Account_ID": "1234"}
+	decoded, err := base64.StdEncoding.DecodeString(acmePluginData(data))
+	if err != nil {
+		t.Fatalf("expected base64, got `%+v`", err)
+	}
+	if string(decoded) != "CF_Account_ID=1234\nCF_Token=secret" {
+		t.Errorf("expected sorted KEY=value lines, got `%s`", decoded)
+	}
+}
@@ -0,0 +1,299 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The repositories of a node are changed one after the other, the digest of the sources guards
+// against changes made at the same time outside of terraform.
+var aptRepositoryMutex sync.Mutex
+
+func resourceAptRepository() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a standard APT repository of Proxmox on a node, e.g. enables the no-subscription repository and disables the enterprise one.",
+
+		Create: resourceAptRepositoryCreate,
+		Read:   resourceAptRepositoryRead,
+		Update: resourceAptRepositoryUpdate,
+		Delete: resourceAptRepositoryDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the repository is on",
+			},
+			"handle": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAptRepositoryHandle,
+				Description:  "The handle of the standard repository, e.g. `enterprise`, `no-subscription`, `test`, `ceph-quincy-enterprise` or `ceph-reef-no-subscription`",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the repository is enabled",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name Proxmox shows for the repository",
+			},
+			"file_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the sources file the repository is configured in",
+			},
+			"index": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The index of the repository in its sources file",
+			},
+		},
+	}
+}
+
+// the repositories of every ceph release, e.g. ceph-quincy-no-subscription
+var aptRepositoryCephKinds = []string{"enterprise", "no-subscription", "test"}
+
+// The URI and component of the standard repository handle, without the scheme of the URI.
+func aptRepositoryHandleSource(handle string) (uri string, component string, err error) {
+	switch handle {
+	case "enterprise":
+		return "enterprise.proxmox.com/debian/pve", "pve-enterprise", nil
+	case "no-subscription":
+		return "download.proxmox.com/debian/pve", "pve-no-subscription", nil
+	case "test":
+		return "download.proxmox.com/debian/pve", "pvetest", nil
+	}
+	if strings.HasPrefix(handle, "ceph-") {
+		for _, kind := range aptRepositoryCephKinds {
+			release := strings.TrimSuffix(strings.TrimPrefix(handle, "ceph-"), "-"+kind)
+			if strings.HasSuffix(handle, "-"+kind) && release != "" && !strings.Contains(release, "-") {
+				host := "download.proxmox.com"
+				if kind == "enterprise" {
+					host = "enterprise.proxmox.com"
+				}
+				return host + "/debian/ceph-" + release, kind, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("Unknown APT repository handle %s", handle)
+}
+
+func validateAptRepositoryHandle(value interface{}, key string) ([]string, []error) {
+	if _, _, err := aptRepositoryHandleSource(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %v", key, err)}
+	}
+	return nil, nil
+}
+
+func aptRepositoryId(node string, handle string) string {
+	return fmt.Sprintf("%s/%s", node, handle)
+}
+
+func parseAptRepositoryId(id string) (node string, handle string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid resource format: %s. Must be node/handle", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+type aptRepository struct {
+	name    string
+	path    string
+	index   int
+	enabled bool
+}
+
+// The standard repository handle in the repositories of a node as returned by
+// /nodes/{node}/apt/repositories, nil when it is not configured.
+func aptRepositoryFind(repositories map[string]interface{}, handle string) (*aptRepository, error) {
+	uri, component, err := aptRepositoryHandleSource(handle)
+	if err != nil {
+		return nil, err
+	}
+	var found *aptRepository
+	files, _ := repositories["files"].([]interface{})
+	for _, file := range files {
+		file, _ := file.(map[string]interface{})
+		entries, _ := file["repositories"].([]interface{})
+		for index, entry := range entries {
+			entry, _ := entry.(map[string]interface{})
+			if !aptRepositoryMatches(entry, uri, component) {
+				continue
+			}
+			// an enabled entry wins over disabled duplicates
+			if found == nil || (!found.enabled && apiBool(entry["Enabled"])) {
+				found = &aptRepository{
+					path:    apiString(file["path"]),
+					index:   index,
+					enabled: apiBool(entry["Enabled"]),
+				}
+			}
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	standard, _ := repositories["standard-repos"].([]interface{})
+	for _, repository := range standard {
+		repository, _ := repository.(map[string]interface{})
+		if apiString(repository["handle"]) == handle {
+			found.name = apiString(repository["name"])
+		}
+	}
+	return found, nil
+}
+
+func aptRepositoryMatches(entry map[string]interface{}, uri string, component string) bool {
+	if !stringInList(component, apiStringList(entry["Components"], " ")) {
+		return false
+	}
+	for _, entryUri := range apiStringList(entry["URIs"], " ") {
+		if i := strings.Index(entryUri, "://"); i >= 0 {
+			entryUri = entryUri[i+3:]
+		}
+		if strings.TrimSuffix(entryUri, "/") == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds the standard repository handle to the node when it is not configured yet and enables or
+// disables it.
+func writeAptRepository(session *pxapi.Session, node string, handle string, enabled bool) error {
+	aptRepositoryMutex.Lock()
+	defer aptRepositoryMutex.Unlock()
+
+	path := apiPath("nodes", node, "apt", "repositories")
+	repositories, err := apiGetMap(session, path)
+	if err != nil {
+		return err
+	}
+	repository, err := aptRepositoryFind(repositories, handle)
+	if err != nil {
+		return err
+	}
+	if repository == nil {
+		if !enabled {
+			return nil
+		}
+		// proxmox adds standard repositories enabled
+		_, err = apiPut(session, path, map[string]interface{}{
+			"handle": handle,
+			"digest": apiString(repositories["digest"]),
+		})
+		return err
+	}
+	if repository.enabled == enabled {
+		return nil
+	}
+	_, err = apiPost(session, path, map[string]interface{}{
+		"path":    repository.path,
+		"index":   repository.index,
+		"enabled": enabled,
+		"digest":  apiString(repositories["digest"]),
+	})
+	return err
+}
+
+func resourceAptRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	handle := d.Get("handle").(string)
+
+	logger, _ := CreateSubLogger("resource_apt_repository_create")
+	logger.Info().Str("node", node).Msgf("Configuring APT repository %s", handle)
+
+	if err := writeAptRepository(pconf.Session, node, handle, d.Get("enabled").(bool)); err != nil {
+		return err
+	}
+	d.SetId(aptRepositoryId(node, handle))
+	return _resourceAptRepositoryRead(d, meta)
+}
+
+func resourceAptRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceAptRepositoryRead(d, meta)
+}
+
+func _resourceAptRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, handle, err := parseAptRepositoryId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_apt_repository_read")
+	logger.Info().Str("node", node).Msgf("Reading APT repository %s", handle)
+
+	repositories, err := apiGetMap(pconf.Session, apiPath("nodes", node, "apt", "repositories"))
+	if err != nil {
+		return err
+	}
+	repository, err := aptRepositoryFind(repositories, handle)
+	if err != nil {
+		return err
+	}
+
+	d.Set("node", node)
+	d.Set("handle", handle)
+	if repository == nil {
+		// a repository which is not configured at all is as good as a disabled one
+		d.Set("enabled", false)
+		d.Set("name", "")
+		d.Set("file_path", "")
+		d.Set("index", 0)
+		return nil
+	}
+	d.Set("enabled", repository.enabled)
+	d.Set("name", repository.name)
+	d.Set("file_path", repository.path)
+	d.Set("index", repository.index)
+	return nil
+}
+
+func resourceAptRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, handle, err := parseAptRepositoryId(d.Id())
+	if err != nil {
+		return err
+	}
+	if err = writeAptRepository(pconf.Session, node, handle, d.Get("enabled").(bool)); err != nil {
+		return err
+	}
+	return _resourceAptRepositoryRead(d, meta)
+}
+
+// Proxmox can't remove repositories, the repository is left as it is and only removed from the
+// state.
+func resourceAptRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,73 @@
+package proxmox
+
+import "testing"
+
+func TestAptRepositoryHandleSource(t *testing.T) {
+	tests := []struct {
+		handle    string
+		uri       string
+		component string
+		valid     bool
+	}{
+		{"enterprise", "enterprise.proxmox.com/debian/pve", "pve-enterprise", true},
+		{"no-subscription", "download.proxmox.com/debian/pve", "pve-no-subscription", true},
+		{"test", "download.proxmox.com/debian/pve", "pvetest", true},
+		{"ceph-quincy-enterprise", "enterprise.proxmox.com/debian/ceph-quincy", "enterprise", true},
+		{"ceph-reef-no-subscription", "download.proxmox.com/debian/ceph-reef", "no-subscription", true},
+		{"ceph-squid-test", "download.proxmox.com/debian/ceph-squid", "test", true},
+		{"ceph-no-subscription", "", "", false},
+		{"ceph-reef-main", "", "", false},
+		{"backports", "", "", false},
+	}
+	for _, test := range tests {
+		uri, component, err := aptRepositoryHandleSource(test.handle)
+		if (err == nil) != test.valid || uri != test.uri || component != test.component {
+			t.Errorf("%s: expected %s %s valid %v, got %s %s `%v`", test.handle, test.uri, test.component, test.valid, uri, component, err)
+		}
+	}
+}
+
+func TestAptRepositoryFind(t *testing.T) {
+	repositories := map[string]interface{}{
+		"digest": "abc",
+		"files": []interface{}{
+			map[string]interface{}{
+				"path": "/etc/apt/sources.list",
+				"repositories": []interface{}{
+					map[string]interface{}{"URIs": []interface{}{"http://deb.debian.org/debian"}, "Components": []interface{}{"main"}, "Enabled": float64(1)},
+					map[string]interface{}{"URIs": []interface{}{"http://download.proxmox.com/debian/pve/"}, "Components": []interface{}{"pve-no-subscription"}, "Enabled": float64(0)},
+				},
+			},
+			map[string]interface{}{
+				"path": "/etc/apt/sources.list.d/pve.list",
+				"repositories": []interface{}{
+					map[string]interface{}{"URIs": []interface{}{"https://enterprise.proxmox.com/debian/pve"}, "Components": []interface{}{"pve-enterprise"}, "Enabled": float64(1)},
+					map[string]interface{}{"URIs": []interface{}{"http://download.proxmox.com/debian/pve"}, "Components": []interface{}{"pve-no-subscription"}, "Enabled": float64(1)},
+				},
+			},
+		},
+		"standard-repos": []interface{}{
+			map[string]interface{}{"handle": "enterprise", "name": "Enterprise", "status": float64(1)},
+			map[string]interface{}{"handle": "no-subscription", "name": "No-Subscription", "status": float64(1)},
+		},
+	}
+
+	tests := []struct {
+		handle   string
+		expected *aptRepository
+	}{
+		{"enterprise", &aptRepository{name: "Enterprise", path: "/etc/apt/sources.list.d/pve.list", index: 0, enabled: true}},
+		{"no-subscription", &aptRepository{name: "No-Subscription", path: "/etc/apt/sources.list.d/pve.list", index: 1, enabled: true}},
+		{"test", nil},
+	}
+	for _, test := range tests {
+		repository, err := aptRepositoryFind(repositories, test.handle)
+		if err != nil {
+			t.Errorf("%s: %v", test.handle, err)
+			continue
+		}
+		if (repository == nil) != (test.expected == nil) || (repository != nil && *repository != *test.expected) {
+			t.Errorf("%s: expected `%v`, got `%v`", test.handle, test.expected, repository)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the guests a backup job backs up, exactly one of them is set
+var backupJobSelections = []string{"vmids", "pool", "all"}
+
+func resourceBackupJob() *schema.Resource {
+	*pxapi.Debug = true
+
+	pruneBackups := storagePruneBackupsSchema()
+	pruneBackups.Description = "The retention of the backups made by the job, the retention of the storage is used when not set"
+
+	return &schema.Resource{
+		Description: "Manages a scheduled backup job of the cluster, which runs vzdump for the selected guests.",
+
+		Create: resourceBackupJobCreate,
+		Read:   resourceBackupJobRead,
+		Update: resourceBackupJobUpdate,
+		Delete: resourceBackupJobDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z][A-Za-z0-9\-_]+$`), "must start with a letter and only contain letters, digits, - and _"),
+				Description:  "The id of the backup job",
+			},
+			"schedule": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "When the job runs, as a calendar event like `daily`, `sat 02:00` or `mon..fri 21:30`",
+			},
+			"vmids": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeInt},
+				ExactlyOneOf: backupJobSelections,
+				Description:  "The ids of the guests to back up",
+			},
+			"pool": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: backupJobSelections,
+				Description:  "Back up the guests of this pool, guests added to the pool later are backed up too",
+			},
+			"all": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ExactlyOneOf: backupJobSelections,
+				Description:  "Back up all guests",
+			},
+			"exclude": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeInt},
+				ConflictsWith: []string{"vmids", "pool"},
+				Description:   "The ids of the guests not to back up when all is set",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only back up the guests on this node",
+			},
+			"storage": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The storage the backups are written to",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "snapshot",
+				ValidateFunc: validation.StringInSlice([]string{"snapshot", "suspend", "stop"}, false),
+				Description:  "How running guests are backed up: snapshot, suspend or stop",
+			},
+			"compress": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "zstd",
+				ValidateFunc: validation.StringInSlice([]string{"0", "gzip", "lzo", "zstd"}, false),
+				Description:  "The compression of the backups: zstd, gzip, lzo or 0 for none",
+			},
+			"prune_backups": pruneBackups,
+			"mailto": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The addresses the notification mails are sent to",
+			},
+			"mail_notification": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "always",
+				ValidateFunc: validation.StringInSlice([]string{"always", "failure"}, false),
+				Description:  "When a notification mail is sent: always or failure",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the job runs on its schedule",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the backup job",
+			},
+		},
+	}
+}
+
+// The ids of a set as proxmox expects them, e.g. "100,101".
+func backupJobVmids(value interface{}) string {
+	vmids := []int{}
+	for _, vmid := range value.(*schema.Set).List() {
+		vmids = append(vmids, vmid.(int))
+	}
+	sort.Ints(vmids)
+	list := make([]string, 0, len(vmids))
+	for _, vmid := range vmids {
+		list = append(list, strconv.Itoa(vmid))
+	}
+	return strings.Join(list, ",")
+}
+
+func parseBackupJobVmids(value interface{}) []int {
+	vmids := []int{}
+	for _, item := range apiStringList(value, ", ") {
+		if vmid, err := strconv.Atoi(item); err == nil {
+			vmids = append(vmids, vmid)
+		}
+	}
+	return vmids
+}
+
+// The parameters of the job, deletes are the optional parameters which are not set.
+func backupJobParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"schedule":         d.Get("schedule").(string),
+		"storage":          d.Get("storage").(string),
+		"mode":             d.Get("mode").(string),
+		"compress":         d.Get("compress").(string),
+		"mailnotification": d.Get("mail_notification").(string),
+		"enabled":          d.Get("enabled").(bool),
+		"all":              d.Get("all").(bool),
+	}
+	deletes = []string{}
+	optional := map[string]string{
+		"vmid":          backupJobVmids(d.Get("vmids")),
+		"exclude":       backupJobVmids(d.Get("exclude")),
+		"pool":          d.Get("pool").(string),
+		"node":          d.Get("node").(string),
+		"prune-backups": storagePruneBackups(d.Get("prune_backups").([]interface{})),
+		"comment":       d.Get("comment").(string),
+	}
+	if mailto := schemaStringList(d.Get("mailto")); len(mailto) > 0 {
+		sort.Strings(mailto)
+		optional["mailto"] = strings.Join(mailto, ",")
+	} else {
+		optional["mailto"] = ""
+	}
+	for parameter, value := range optional {
+		if value != "" {
+			params[parameter] = value
+		} else {
+			deletes = append(deletes, parameter)
+		}
+	}
+	sort.Strings(deletes)
+	return
+}
+
+func resourceBackupJobCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	jobId := d.Get("job_id").(string)
+	params, _ := backupJobParams(d)
+	params["id"] = jobId
+
+	logger, _ := CreateSubLogger("resource_backup_job_create")
+	logger.Info().Str("job_id", jobId).Msg("Creating backup job")
+
+	if _, err := apiPost(pconf.Session, "/cluster/backup", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("backup", jobId))
+	return _resourceBackupJobRead(d, meta)
+}
+
+func resourceBackupJobRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceBackupJobRead(d, meta)
+}
+
+func _resourceBackupJobRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_backup_job_read")
+	logger.Info().Str("job_id", jobId).Msg("Reading configuration for backup job")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "backup", jobId))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("job_id", jobId)
+	d.Set("schedule", apiString(config["schedule"]))
+	if err = d.Set("vmids", parseBackupJobVmids(config["vmid"])); err != nil {
+		return err
+	}
+	d.Set("pool", apiString(config["pool"]))
+	d.Set("all", apiBool(config["all"]))
+	if err = d.Set("exclude", parseBackupJobVmids(config["exclude"])); err != nil {
+		return err
+	}
+	d.Set("node", apiString(config["node"]))
+	d.Set("storage", apiString(config["storage"]))
+	d.Set("mode", "snapshot")
+	if mode := apiString(config["mode"]); mode != "" {
+		d.Set("mode", mode)
+	}
+	d.Set("compress", "0")
+	if compress := apiString(config["compress"]); compress != "" {
+		d.Set("compress", compress)
+	}
+	if err = d.Set("prune_backups", parseStoragePruneBackups(apiString(config["prune-backups"]))); err != nil {
+		return err
+	}
+	if err = d.Set("mailto", apiStringList(config["mailto"], ",; ")); err != nil {
+		return err
+	}
+	d.Set("mail_notification", "always")
+	if notification := apiString(config["mailnotification"]); notification != "" {
+		d.Set("mail_notification", notification)
+	}
+	// a job without enabled is enabled
+	d.Set("enabled", true)
+	if enabled, ok := config["enabled"]; ok {
+		d.Set("enabled", apiBool(enabled))
+	}
+	d.Set("comment", apiString(config["comment"]))
+
+	logger.Debug().Str("job_id", jobId).Msgf("Finished backup job read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceBackupJobUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := backupJobParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	logger, _ := CreateSubLogger("resource_backup_job_update")
+	logger.Info().Str("job_id", jobId).Msg("Updating backup job")
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "backup", jobId), params); err != nil {
+		return err
+	}
+	return _resourceBackupJobRead(d, meta)
+}
+
+// Deleting the job leaves the backups it made alone.
+func resourceBackupJobDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "backup", jobId))
+	return err
+}
@@ -0,0 +1,52 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBackupJobParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceBackupJob().Schema, map[string]interface{}{
+		"job_id":   "nightly",
+		"schedule": "sat 02:00",
+		"vmids":    []interface{}{101, 100},
+		"storage":  "pbs",
+		"mode":     "stop",
+		"prune_backups": []interface{}{map[string]interface{}{
+			"keep_last":  3,
+			"keep_daily": 7,
+		}},
+		"mailto": []interface{}{"ops@example.com"},
+	})
+
+	params, deletes := backupJobParams(d)
+	expected := map[string]interface{}{
+		"schedule":         "sat 02:00",
+		"storage":          "pbs",
+		"mode":             "stop",
+		"compress":         "zstd",
+		"mailnotification": "always",
+		"enabled":          true,
+		"all":              false,
+		"vmid":             "100,101",
+		"prune-backups":    "keep-last=3,keep-daily=7",
+		"mailto":           "ops@example.com",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment", "exclude", "node", "pool"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestParseBackupJobVmids(t *testing.T) {
+	if vmids := parseBackupJobVmids("100,101, 102"); !reflect.DeepEqual(vmids, []int{100, 101, 102}) {
+		t.Errorf("unexpected vmids `%v`", vmids)
+	}
+	if vmids := parseBackupJobVmids(nil); len(vmids) != 0 {
+		t.Errorf("expected no vmids, got `%v`", vmids)
+	}
+}
@@ -0,0 +1,58 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var bridgeResourceDef *schema.Resource
+
+var bridgeParameters = networkTypeParameters(map[string]string{
+	"ports":      "bridge_ports",
+	"vlan_aware": "bridge_vlan_aware",
+	"vlan_ids":   "bridge_vids",
+})
+
+func resourceBridge() *schema.Resource {
+	*pxapi.Debug = true
+
+	bridgeResourceDef = &schema.Resource{
+		Description: "Manages a Linux bridge on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "bridge", bridgeResourceDef.Schema, bridgeParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "bridge", bridgeResourceDef.Schema, bridgeParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "bridge", bridgeResourceDef.Schema, bridgeParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			"ports": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The interfaces attached to the bridge, e.g. eno1 or bond0",
+			},
+			"vlan_aware": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Pass VLAN tags through the bridge, so guests can use the tag of their network device",
+			},
+			"vlan_ids": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The VLAN ids allowed on a VLAN aware bridge, e.g. 2-4094 or 10 20 30-40",
+			},
+		}),
+	}
+
+	return bridgeResourceDef
+}
@@ -0,0 +1,155 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCephMon() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a ceph monitor on a node.",
+
+		Create:   resourceCephMonCreate,
+		Read:     resourceCephMonRead,
+		Delete:   resourceCephMonDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the monitor runs on",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The id of the monitor, defaults to the name of the node",
+			},
+			"address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The address the monitor listens on, defaults to the address of the node in the public network of ceph",
+			},
+			"addr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The address of the monitor as reported by ceph",
+			},
+		},
+	}
+}
+
+// The monitor name in the monitors of node, nil when there is none.
+func cephMon(session *pxapi.Session, node string, name string) (map[string]interface{}, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "ceph", "mon"))
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if mon, ok := item.(map[string]interface{}); ok && apiString(mon["name"]) == name {
+			return mon, nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceCephMonCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	if name == "" {
+		name = node
+	}
+	params := map[string]interface{}{}
+	if address := d.Get("address").(string); address != "" {
+		params["mon-address"] = address
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_mon_create")
+	logger.Info().Str("node", node).Msgf("Creating ceph monitor %s", name)
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "ceph", "mon", name), params); err != nil {
+		return fmt.Errorf("Creating the ceph monitor %s on %s failed: %v", name, node, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceCephMonRead(d, meta)
+}
+
+func resourceCephMonRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceCephMonRead(d, meta)
+}
+
+func _resourceCephMonRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_mon_read")
+	logger.Info().Str("node", node).Msgf("Reading ceph monitor %s", name)
+
+	mon, err := cephMon(pconf.Session, node, name)
+	if err != nil {
+		return err
+	}
+	if mon == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("addr", apiString(mon["addr"]))
+	return nil
+}
+
+func resourceCephMonDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "ceph", "mon", name))
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil && !strings.Contains(err.Error(), "no such monitor") {
+		return fmt.Errorf("Deleting the ceph monitor %s on %s failed: %v", name, node, err)
+	}
+	return nil
+}
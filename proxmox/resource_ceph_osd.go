@@ -0,0 +1,275 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => the parameter of the OSD, all of them only apply when creating it
+var cephOsdParameters = map[string]string{
+	"db_device":    "db_dev",
+	"db_size":      "db_dev_size",
+	"wal_device":   "wal_dev",
+	"wal_size":     "wal_dev_size",
+	"device_class": "crush-device-class",
+}
+
+func resourceCephOsd() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a ceph OSD on a disk of a node.",
+
+		Create:   resourceCephOsdCreate,
+		Read:     resourceCephOsdRead,
+		Delete:   resourceCephOsdDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disk of the OSD is in",
+			},
+			"device": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/sdb"),
+				Description:  "The path of the disk of the OSD, e.g. `/dev/sdb`",
+			},
+			"db_device": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/nvme0n1"),
+				Description:  "The path of a separate disk for the RocksDB of the OSD",
+			},
+			"db_size": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"db_device"},
+				Description:  "The size of the RocksDB in GiB, defaults to 10% of the OSD",
+			},
+			"wal_device": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/nvme0n1"),
+				Description:  "The path of a separate disk for the write ahead log of the OSD",
+			},
+			"wal_size": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"wal_device"},
+				Description:  "The size of the write ahead log in GiB, defaults to 1% of the OSD",
+			},
+			"encrypted": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the OSD is encrypted",
+			},
+			"device_class": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The crush device class of the OSD, e.g. `ssd`, detected by ceph by default",
+			},
+			"osd_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The id of the OSD",
+			},
+		},
+	}
+}
+
+// block devices are given by their path, e.g. /dev/sdb or /dev/disk/by-id/nvme-...
+var rxBlockDevice = regexp.MustCompile(`^/dev/\S+$`)
+
+// The disks of node with the OSDs on them as listed by /nodes/{node}/disks/list.
+func cephOsdDisks(session *pxapi.Session, node string) ([]map[string]interface{}, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "disks", "list"))
+	if err != nil {
+		return nil, err
+	}
+	disks := []map[string]interface{}{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if disk, ok := item.(map[string]interface{}); ok {
+			disks = append(disks, disk)
+		}
+	}
+	return disks, nil
+}
+
+// The ids of the OSDs on disk, a disk may hold several OSDs.
+func cephDiskOsds(disk map[string]interface{}) []int {
+	osds := []int{}
+	if list, ok := disk["osdid-list"].([]interface{}); ok {
+		for _, osd := range list {
+			osds = append(osds, apiInt(osd))
+		}
+		return osds
+	}
+	if osd, ok := disk["osdid"]; ok && apiInt(osd) >= 0 {
+		osds = append(osds, apiInt(osd))
+	}
+	return osds
+}
+
+// The device and id of the OSD on device when osd is -1 or the OSD osd otherwise, the id is -1
+// when there is none.
+func cephOsdFind(disks []map[string]interface{}, device string, osd int) (string, int) {
+	for _, disk := range disks {
+		devpath := apiString(disk["devpath"])
+		for _, id := range cephDiskOsds(disk) {
+			if (osd < 0 && devpath == device) || id == osd {
+				return devpath, id
+			}
+		}
+	}
+	return "", -1
+}
+
+func resourceCephOsdCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	device := d.Get("device").(string)
+
+	params := map[string]interface{}{
+		"dev": device,
+	}
+	for attribute, parameter := range cephOsdParameters {
+		if value, ok := d.GetOk(attribute); ok {
+			params[parameter] = value
+		}
+	}
+	if d.Get("encrypted").(bool) {
+		params["encrypted"] = true
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_osd_create")
+	logger.Info().Str("node", node).Msgf("Creating ceph OSD on %s", device)
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "ceph", "osd"), params); err != nil {
+		return fmt.Errorf("Creating the ceph OSD on %s of %s failed: %v", device, node, err)
+	}
+	disks, err := cephOsdDisks(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	_, osd := cephOsdFind(disks, device, -1)
+	if osd < 0 {
+		return fmt.Errorf("The ceph OSD on %s of %s was created, but it is not listed on the disk", device, node)
+	}
+	d.SetId(nodeResourceId(node, strconv.Itoa(osd)))
+	return _resourceCephOsdRead(d, meta)
+}
+
+func resourceCephOsdRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceCephOsdRead(d, meta)
+}
+
+func parseCephOsdId(resId string) (node string, osd int, err error) {
+	node, id, err := parseNodeResourceId(resId)
+	if err == nil {
+		osd, err = strconv.Atoi(id)
+	}
+	if err != nil || osd < 0 {
+		return "", -1, fmt.Errorf("Invalid resource format: %s. Must be node/osd id", resId)
+	}
+	return node, osd, nil
+}
+
+func _resourceCephOsdRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, osd, err := parseCephOsdId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_osd_read")
+	logger.Info().Str("node", node).Msgf("Reading ceph OSD %d", osd)
+
+	disks, err := cephOsdDisks(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	device, osd := cephOsdFind(disks, "", osd)
+	if osd < 0 {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("device", device)
+	d.Set("osd_id", osd)
+	return nil
+}
+
+// Proxmox only destroys OSDs which are out and stopped, the data of the OSD is moved to the other
+// OSDs by ceph as soon as it is out.
+func resourceCephOsdDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, osd, err := parseCephOsdId(d.Id())
+	if err != nil {
+		return err
+	}
+	id := strconv.Itoa(osd)
+
+	logger, _ := CreateSubLogger("resource_ceph_osd_delete")
+	logger.Info().Str("node", node).Msgf("Destroying ceph OSD %d", osd)
+
+	if _, err = apiPost(pconf.Session, apiPath("nodes", node, "ceph", "osd", id, "out"), map[string]interface{}{}); err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil
+		}
+		return fmt.Errorf("Marking the ceph OSD %d out failed: %v", osd, err)
+	}
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "ceph", "stop"), map[string]interface{}{
+		"service": "osd." + id,
+	}); err != nil {
+		return fmt.Errorf("Stopping the ceph OSD %d failed: %v", osd, err)
+	}
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "ceph", "osd", id)+"?cleanup=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Destroying the ceph OSD %d failed: %v", osd, err)
+	}
+	return nil
+}
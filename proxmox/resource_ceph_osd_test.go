@@ -0,0 +1,42 @@
+package proxmox
+
+import "testing"
+
+func TestCephOsdFind(t *testing.T) {
+	disks := []map[string]interface{}{
+		{"devpath": "/dev/sda", "osdid": float64(-1)},
+		{"devpath": "/dev/sdb", "osdid": float64(3)},
+		{"devpath": "/dev/nvme0n1", "osdid": float64(4), "osdid-list": []interface{}{float64(4), float64(5)}},
+	}
+
+	tests := []struct {
+		device         string
+		osd            int
+		expectedDevice string
+		expectedOsd    int
+	}{
+		{"/dev/sdb", -1, "/dev/sdb", 3},
+		{"/dev/sda", -1, "", -1},
+		{"/dev/sdc", -1, "", -1},
+		{"", 5, "/dev/nvme0n1", 5},
+		{"", 3, "/dev/sdb", 3},
+		{"", 7, "", -1},
+	}
+	for _, test := range tests {
+		device, osd := cephOsdFind(disks, test.device, test.osd)
+		if device != test.expectedDevice || osd != test.expectedOsd {
+			t.Errorf("%s %d: expected %s %d, got %s %d", test.device, test.osd, test.expectedDevice, test.expectedOsd, device, osd)
+		}
+	}
+}
+
+func TestParseCephOsdId(t *testing.T) {
+	if node, osd, err := parseCephOsdId("pve1/3"); node != "pve1" || osd != 3 || err != nil {
+		t.Errorf("expected pve1 3, got %s %d `%v`", node, osd, err)
+	}
+	for _, id := range []string{"pve1/osd.3", "pve1/-1", "3", "/3"} {
+		if _, _, err := parseCephOsdId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
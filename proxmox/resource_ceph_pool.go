@@ -0,0 +1,216 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCephPool() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a ceph pool of the cluster, e.g. for the disks of guests on RBD storages.",
+
+		Create:   resourceCephPoolCreate,
+		Read:     resourceCephPoolRead,
+		Update:   resourceCephPoolUpdate,
+		Delete:   resourceCephPoolDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The node the pool is managed through, any node running ceph",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the pool",
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntBetween(1, 7),
+				Description:  "The number of replicas of the objects in the pool",
+			},
+			"min_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(1, 7),
+				Description:  "The number of replicas the pool needs to serve requests",
+			},
+			"pg_autoscale_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "on",
+				ValidateFunc: validation.StringInSlice([]string{"on", "off", "warn"}, false),
+				Description:  "Whether ceph scales the number of placement groups of the pool: on, off or warn",
+			},
+			"pg_num": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(1, 32768),
+				Description:  "The number of placement groups of the pool, the autoscaler changes it unless `pg_autoscale_mode` is off",
+			},
+			"application": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "rbd",
+				ValidateFunc: validation.StringInSlice([]string{"rbd", "cephfs", "rgw"}, false),
+				Description:  "The application of the pool: rbd, cephfs or rgw",
+			},
+			"crush_rule": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The crush rule of the pool, `replicated_rule` by default",
+			},
+		},
+	}
+}
+
+// pg_num is only sent when it is set, when the autoscaler is on ceph changes it by itself.
+func cephPoolParams(d *schema.ResourceData) map[string]interface{} {
+	params := map[string]interface{}{
+		"size":              d.Get("size").(int),
+		"min_size":          d.Get("min_size").(int),
+		"pg_autoscale_mode": d.Get("pg_autoscale_mode").(string),
+		"application":       d.Get("application").(string),
+	}
+	if pgNum, ok := d.GetOk("pg_num"); ok && (d.Id() == "" || d.HasChange("pg_num")) {
+		params["pg_num"] = pgNum.(int)
+	}
+	if crushRule, ok := d.GetOk("crush_rule"); ok {
+		params["crush_rule"] = crushRule.(string)
+	}
+	return params
+}
+
+func resourceCephPoolCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	params := cephPoolParams(d)
+	params["name"] = name
+
+	logger, _ := CreateSubLogger("resource_ceph_pool_create")
+	logger.Info().Str("node", node).Msgf("Creating ceph pool %s", name)
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "ceph", "pool"), params); err != nil {
+		return fmt.Errorf("Creating the ceph pool %s failed: %v", name, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceCephPoolRead(d, meta)
+}
+
+func resourceCephPoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceCephPoolRead(d, meta)
+}
+
+func _resourceCephPoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_pool_read")
+	logger.Info().Str("node", node).Msgf("Reading ceph pool %s", name)
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "ceph", "pool", name))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("size", apiInt(config["size"]))
+	d.Set("min_size", apiInt(config["min_size"]))
+	d.Set("pg_autoscale_mode", apiString(config["pg_autoscale_mode"]))
+	d.Set("pg_num", apiInt(config["pg_num"]))
+	d.Set("application", apiString(config["application"]))
+	d.Set("crush_rule", apiString(config["crush_rule"]))
+	return nil
+}
+
+func resourceCephPoolUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	_, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	// the pool belongs to the cluster, another node only changes the node it is managed through
+	node := d.Get("node").(string)
+
+	upid, err := apiPut(pconf.Session, apiPath("nodes", node, "ceph", "pool", name), cephPoolParams(d))
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Updating the ceph pool %s failed: %v", name, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceCephPoolRead(d, meta)
+}
+
+func resourceCephPoolDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_ceph_pool_delete")
+	logger.Info().Str("node", node).Msgf("Destroying ceph pool %s", name)
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "ceph", "pool", name))
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Destroying the ceph pool %s failed: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCephPoolParams(t *testing.T) {
+	tests := []struct {
+		config   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			map[string]interface{}{"node": "pve1", "name": "vms"},
+			map[string]interface{}{"size": 3, "min_size": 2, "pg_autoscale_mode": "on", "application": "rbd"},
+		},
+		{
+			map[string]interface{}{"node": "pve1", "name": "vms", "size": 2, "min_size": 1, "pg_autoscale_mode": "off", "pg_num": 128, "crush_rule": "ssd"},
+			map[string]interface{}{"size": 2, "min_size": 1, "pg_autoscale_mode": "off", "application": "rbd", "pg_num": 128, "crush_rule": "ssd"},
+		},
+	}
+	for _, test := range tests {
+		d := schema.TestResourceDataRaw(t, resourceCephPool().Schema, test.config)
+		if params := cephPoolParams(d); !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("%v: expected params `%v`, got `%v`", test.config, test.expected, params)
+		}
+	}
+}
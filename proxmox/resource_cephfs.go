@@ -0,0 +1,290 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCephFs() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a CephFS file system of the cluster and the metadata servers (MDS) it needs.",
+
+		Create:   resourceCephFsCreate,
+		Read:     resourceCephFsRead,
+		Update:   resourceCephFsUpdate,
+		Delete:   resourceCephFsDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the file system is created through, any node running ceph",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "cephfs",
+				Description: "The name of the file system",
+			},
+			"mds_nodes": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes running a metadata server, the metadata servers are named after their node",
+			},
+			"pg_num": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      128,
+				ValidateFunc: validation.IntBetween(8, 32768),
+				Description:  "The number of placement groups of the data pool of the file system",
+			},
+			"add_storage": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Add a cephfs storage named after the file system, it is removed with the file system",
+			},
+			"data_pool": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ceph pool of the data of the file system",
+			},
+			"metadata_pool": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ceph pool of the metadata of the file system",
+			},
+		},
+	}
+}
+
+// The names of the metadata servers of the cluster, node is any node running ceph.
+func cephMdsNames(session *pxapi.Session, node string) ([]string, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "ceph", "mds"))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if mds, ok := item.(map[string]interface{}); ok {
+			names = append(names, apiString(mds["name"]))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// The metadata servers to create and to destroy to get from the metadata servers existing to
+// the ones wanted.
+func cephMdsChanges(existing []string, wanted []string) (create []string, destroy []string) {
+	create = []string{}
+	destroy = []string{}
+	for _, name := range wanted {
+		if !stringInList(name, existing) {
+			create = append(create, name)
+		}
+	}
+	for _, name := range existing {
+		if !stringInList(name, wanted) {
+			destroy = append(destroy, name)
+		}
+	}
+	sort.Strings(create)
+	sort.Strings(destroy)
+	return
+}
+
+func createCephMds(pconf *providerConfiguration, client *pxapi.Client, names []string) error {
+	for _, name := range names {
+		if _, err := apiPostTask(pconf.Session, client, apiPath("nodes", name, "ceph", "mds", name), map[string]interface{}{}); err != nil {
+			return fmt.Errorf("Creating the ceph metadata server on %s failed: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func destroyCephMds(pconf *providerConfiguration, client *pxapi.Client, names []string) error {
+	for _, name := range names {
+		upid, err := apiDelete(pconf.Session, apiPath("nodes", name, "ceph", "mds", name))
+		if err == nil {
+			_, err = apiWaitForTask(pconf.Session, client, upid)
+		}
+		if err != nil {
+			return fmt.Errorf("Destroying the ceph metadata server on %s failed: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// The file system name of the cluster, nil when there is none.
+func cephFs(session *pxapi.Session, node string, name string) (map[string]interface{}, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "ceph", "fs"))
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if fs, ok := item.(map[string]interface{}); ok && apiString(fs["name"]) == name {
+			return fs, nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceCephFsCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+
+	logger, _ := CreateSubLogger("resource_cephfs_create")
+	logger.Info().Str("node", node).Msgf("Creating ceph file system %s", name)
+
+	// proxmox only creates file systems when a metadata server is running
+	existing, err := cephMdsNames(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	create, _ := cephMdsChanges(existing, schemaStringList(d.Get("mds_nodes")))
+	if err = createCephMds(pconf, client, create); err != nil {
+		return err
+	}
+
+	_, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "ceph", "fs", name), map[string]interface{}{
+		"pg_num":      d.Get("pg_num").(int),
+		"add-storage": d.Get("add_storage").(bool),
+	})
+	if err != nil {
+		return fmt.Errorf("Creating the ceph file system %s failed: %v", name, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceCephFsRead(d, meta)
+}
+
+func resourceCephFsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceCephFsRead(d, meta)
+}
+
+func _resourceCephFsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_cephfs_read")
+	logger.Info().Str("node", node).Msgf("Reading ceph file system %s", name)
+
+	fs, err := cephFs(pconf.Session, node, name)
+	if err != nil {
+		return err
+	}
+	if fs == nil {
+		d.SetId("")
+		return nil
+	}
+	mds, err := cephMdsNames(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("data_pool", apiString(fs["data_pool"]))
+	d.Set("metadata_pool", apiString(fs["metadata_pool"]))
+	return d.Set("mds_nodes", mds)
+}
+
+func resourceCephFsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	node, _, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	existing, err := cephMdsNames(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	create, destroy := cephMdsChanges(existing, schemaStringList(d.Get("mds_nodes")))
+	// the new metadata servers first, the file system must not lose its last one
+	if err = createCephMds(pconf, client, create); err != nil {
+		return err
+	}
+	if err = destroyCephMds(pconf, client, destroy); err != nil {
+		return err
+	}
+	return _resourceCephFsRead(d, meta)
+}
+
+// Proxmox only destroys file systems without metadata servers, they are destroyed first. The
+// pools of the file system are destroyed with it.
+func resourceCephFsDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_cephfs_delete")
+	logger.Info().Str("node", node).Msgf("Destroying ceph file system %s", name)
+
+	if err = destroyCephMds(pconf, client, schemaStringList(d.Get("mds_nodes"))); err != nil {
+		return err
+	}
+	path := apiPath("nodes", node, "ceph", "fs", name) + "?remove-pools=1"
+	if d.Get("add_storage").(bool) {
+		path += "&remove-storages=1"
+	}
+	upid, err := apiDelete(pconf.Session, path)
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil && !strings.Contains(err.Error(), "does not exist") {
+		return fmt.Errorf("Destroying the ceph file system %s failed: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCephMdsChanges(t *testing.T) {
+	tests := []struct {
+		existing []string
+		wanted   []string
+		create   []string
+		destroy  []string
+	}{
+		{[]string{}, []string{"pve2", "pve1"}, []string{"pve1", "pve2"}, []string{}},
+		{[]string{"pve1", "pve2"}, []string{"pve2", "pve3"}, []string{"pve3"}, []string{"pve1"}},
+		{[]string{"pve1"}, []string{"pve1"}, []string{}, []string{}},
+	}
+	for _, test := range tests {
+		create, destroy := cephMdsChanges(test.existing, test.wanted)
+		if !reflect.DeepEqual(create, test.create) || !reflect.DeepEqual(destroy, test.destroy) {
+			t.Errorf("%v => %v: expected create %v destroy %v, got %v %v", test.existing, test.wanted, test.create, test.destroy, create, destroy)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package proxmox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => the file of the NoCloud data source
+var cloudInitDiskFiles = map[string]string{
+	"user_data":      "user-data",
+	"meta_data":      "meta-data",
+	"network_config": "network-config",
+	"vendor_data":    "vendor-data",
+}
+
+// proxmox only stores images named *.iso in the iso content of a storage
+var rxIsoFilename = regexp.MustCompile(`^[^/]+\.iso$`)
+
+func resourceCloudInitDisk() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Stores a cloud-init NoCloud ISO image rendered from user-data, meta-data and network-config on a storage, to attach to VMs as a CD-ROM.",
+
+		Create: resourceCloudInitDiskCreate,
+		Read:   resourceCloudInitDiskRead,
+		Delete: resourceCloudInitDiskDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the image is uploaded to",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the image is stored on, it must have the iso content type",
+			},
+			"filename": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxIsoFilename, "must be a file name ending in .iso"),
+				Description:  "The file name of the image, e.g. `web-cidata.iso`",
+			},
+			"user_data": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The user-data, e.g. a `#cloud-config` document",
+			},
+			"meta_data": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The meta-data, by default an instance-id derived from the other files, so cloud-init runs again when they change",
+			},
+			"network_config": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The network-config, e.g. a version 2 network configuration",
+			},
+			"vendor_data": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The vendor-data",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the image, e.g. `local:iso/web-cidata.iso`, to attach to VMs",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the image in bytes",
+			},
+		},
+	}
+}
+
+// The files of the image, files without content are left out besides the meta-data which
+// cloud-init requires.
+func cloudInitDiskFileContents(d *schema.ResourceData) map[string][]byte {
+	files := map[string][]byte{}
+	for attribute, file := range cloudInitDiskFiles {
+		if content := d.Get(attribute).(string); content != "" {
+			files[file] = []byte(content)
+		}
+	}
+	if _, ok := files["meta-data"]; !ok {
+		hash := sha256.New()
+		for _, file := range []string{"user-data", "network-config", "vendor-data"} {
+			hash.Write([]byte(file + "\x00"))
+			hash.Write(files[file])
+		}
+		files["meta-data"] = []byte(fmt.Sprintf("instance-id: iid-%s\n", hex.EncodeToString(hash.Sum(nil))[:16]))
+	}
+	return files
+}
+
+func resourceCloudInitDiskCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	storage := d.Get("storage").(string)
+	filename := d.Get("filename").(string)
+	image := isoImage("cidata", cloudInitDiskFileContents(d))
+
+	logger, _ := CreateSubLogger("resource_cloud_init_disk_create")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Uploading cloud-init image %s", filename)
+
+	if err := pconf.Client.Upload(node, storage, "iso", filename, bytes.NewReader(image)); err != nil {
+		return fmt.Errorf("Uploading %s to %s failed: %v", filename, storage, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s:iso/%s", node, storage, filename))
+	return _resourceCloudInitDiskRead(d, meta)
+}
+
+func resourceCloudInitDiskRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceCloudInitDiskRead(d, meta)
+}
+
+func _resourceCloudInitDiskRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	storage := strings.SplitN(volid, ":", 2)[0]
+
+	volume, err := apiGetVolume(pconf.Session, node, volid, "iso")
+	if err != nil {
+		return err
+	}
+	// the image or its storage was removed outside of terraform
+	if volume == nil {
+		d.SetId("")
+		return nil
+	}
+
+	// the files can't be read back, they are kept as they are in the state
+	d.Set("node", node)
+	d.Set("storage", storage)
+	d.Set("filename", strings.TrimPrefix(volid, storage+":iso/"))
+	d.Set("volid", volid)
+	d.Set("size", apiInt(volume["size"]))
+	return nil
+}
+
+func resourceCloudInitDiskDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
@@ -0,0 +1,33 @@
+package proxmox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCloudInitDiskFileContents(t *testing.T) {
+	config := map[string]interface{}{
+		"node":      "pve1",
+		"storage":   "local",
+		"filename":  "web-cidata.iso",
+		"user_data": "#cloud-config\nhostname: web\n",
+	}
+	files := cloudInitDiskFileContents(schema.TestResourceDataRaw(t, resourceCloudInitDisk().Schema, config))
+	if len(files) != 2 || string(files["user-data"]) != config["user_data"] || !strings.HasPrefix(string(files["meta-data"]), "instance-id: iid-") {
+		t.Errorf("expected the user-data and a generated meta-data, got `%s`", files)
+	}
+
+	config["network_config"] = "version: 2\n"
+	changed := cloudInitDiskFileContents(schema.TestResourceDataRaw(t, resourceCloudInitDisk().Schema, config))
+	if string(changed["network-config"]) != "version: 2\n" || string(changed["meta-data"]) == string(files["meta-data"]) {
+		t.Errorf("expected the network-config and another instance-id, got `%s`", changed)
+	}
+
+	config["meta_data"] = "instance-id: web\n"
+	files = cloudInitDiskFileContents(schema.TestResourceDataRaw(t, resourceCloudInitDisk().Schema, config))
+	if string(files["meta-data"]) != "instance-id: web\n" {
+		t.Errorf("expected the configured meta-data, got `%s`", files["meta-data"])
+	}
+}
@@ -0,0 +1,121 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => parameter of the options of the cluster firewall
+var clusterFirewallOptionsParameters = map[string]string{
+	"enabled":    "enable",
+	"policy_in":  "policy_in",
+	"policy_out": "policy_out",
+	"ebtables":   "ebtables",
+}
+
+// the defaults of the options of the cluster firewall, the firewall is off unless enabled
+var clusterFirewallOptionDefaults = map[string]interface{}{
+	"enable":     false,
+	"policy_in":  "DROP",
+	"policy_out": "ACCEPT",
+	"ebtables":   true,
+}
+
+func resourceClusterFirewallOptions() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the options of the cluster firewall, which has to be enabled for any firewall of a node or guest to have an effect.",
+
+		Create: resourceClusterFirewallOptionsCreate,
+		Read:   resourceClusterFirewallOptionsRead,
+		Update: resourceClusterFirewallOptionsUpdate,
+		Delete: resourceClusterFirewallOptionsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the firewall of the cluster is enabled",
+			},
+			"policy_in": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DROP",
+				ValidateFunc: validation.StringInSlice(firewallActions, false),
+				Description:  "What happens to incoming traffic of the nodes no rule matches: ACCEPT, DROP or REJECT",
+			},
+			"policy_out": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ACCEPT",
+				ValidateFunc: validation.StringInSlice(firewallActions, false),
+				Description:  "What happens to outgoing traffic of the nodes no rule matches: ACCEPT, DROP or REJECT",
+			},
+			"ebtables": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the ebtables rules of the firewall are managed by proxmox",
+			},
+		},
+	}
+}
+
+func resourceClusterFirewallOptionsCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("cluster")
+	return resourceClusterFirewallOptionsUpdate(d, meta)
+}
+
+func resourceClusterFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceClusterFirewallOptionsRead(d, meta)
+}
+
+func _resourceClusterFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	logger, _ := CreateSubLogger("resource_cluster_firewall_options_read")
+	logger.Info().Msg("Reading firewall options of the cluster")
+
+	config, err := apiGetMap(pconf.Session, "/cluster/firewall/options")
+	if err != nil {
+		return err
+	}
+	options := firewallOptions(config, clusterFirewallOptionDefaults)
+	for attribute, parameter := range clusterFirewallOptionsParameters {
+		d.Set(attribute, options[parameter])
+	}
+
+	logger.Debug().Msgf("Finished cluster firewall options read resulting in data: '%+v'", options)
+	return nil
+}
+
+func resourceClusterFirewallOptionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	logger, _ := CreateSubLogger("resource_cluster_firewall_options_update")
+	logger.Info().Msg("Writing firewall options of the cluster")
+
+	if err := firewallSetOptions(d, pconf.Session, "/cluster/firewall", clusterFirewallOptionsParameters); err != nil {
+		return err
+	}
+	return _resourceClusterFirewallOptionsRead(d, meta)
+}
+
+// The options are reset to the defaults of proxmox, which disables the firewall of the cluster.
+func resourceClusterFirewallOptionsDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return firewallResetOptions(pconf.Session, "/cluster/firewall", clusterFirewallOptionDefaults)
+}
@@ -0,0 +1,79 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceClusterFirewallRules() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the ordered firewall rules of the cluster, which apply to all nodes.",
+
+		Create: resourceClusterFirewallRulesCreate,
+		Read:   resourceClusterFirewallRulesRead,
+		Update: resourceClusterFirewallRulesUpdate,
+		Delete: resourceClusterFirewallRulesDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: firewallRulesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"rule": firewallRulesSchema(true),
+		},
+	}
+}
+
+func resourceClusterFirewallRulesCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("cluster")
+	return resourceClusterFirewallRulesUpdate(d, meta)
+}
+
+func resourceClusterFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceClusterFirewallRulesRead(d, meta)
+}
+
+func _resourceClusterFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	logger, _ := CreateSubLogger("resource_cluster_firewall_rules_read")
+	logger.Info().Msg("Reading firewall rules of the cluster")
+
+	rules, err := firewallGetRules(pconf.Session, "/cluster/firewall/rules")
+	if err != nil {
+		return err
+	}
+	if err = d.Set("rule", rules); err != nil {
+		return err
+	}
+
+	logger.Debug().Msgf("Finished cluster firewall rules read resulting in data: '%+v'", rules)
+	return nil
+}
+
+func resourceClusterFirewallRulesUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	logger, _ := CreateSubLogger("resource_cluster_firewall_rules_update")
+	logger.Info().Msg("Writing firewall rules of the cluster")
+
+	if err := firewallSetRules(pconf.Session, "/cluster/firewall/rules", d.Get("rule").([]interface{})); err != nil {
+		return err
+	}
+	return _resourceClusterFirewallRulesRead(d, meta)
+}
+
+// All rules of the cluster are deleted.
+func resourceClusterFirewallRulesDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return firewallSetRules(pconf.Session, "/cluster/firewall/rules", []interface{}{})
+}
@@ -0,0 +1,300 @@
+package proxmox
+
+import (
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => parameter of the plain options of /cluster/options
+var clusterOptionsParameters = map[string]string{
+	"keyboard":    "keyboard",
+	"language":    "language",
+	"console":     "console",
+	"email_from":  "email_from",
+	"http_proxy":  "http_proxy",
+	"mac_prefix":  "mac_prefix",
+	"max_workers": "max_workers",
+}
+
+// all options managed by the resource, they are cleared when it is deleted
+var clusterOptionsDeletes = []string{"keyboard", "language", "console", "email_from", "http_proxy", "mac_prefix", "max_workers", "migration", "tag-style", "registered-tags"}
+
+func resourceClusterOptions() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the datacenter options of the cluster.",
+
+		Create: resourceClusterOptionsCreate,
+		Read:   resourceClusterOptionsRead,
+		Update: resourceClusterOptionsUpdate,
+		Delete: resourceClusterOptionsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"keyboard": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The keyboard layout of the VNC console, e.g. `en-us` or `de`",
+			},
+			"language": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The default language of the web interface, e.g. `en` or `de`",
+			},
+			"console": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"applet", "vv", "html5", "xtermjs"}, false),
+				Description:  "The default console of the web interface: applet, vv, html5 or xtermjs",
+			},
+			"email_from": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The sender address of the emails of the cluster, e.g. of backup jobs",
+			},
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The proxy the nodes download through, e.g. `http://proxy.example.com:3128`",
+			},
+			"mac_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The prefix of the generated MAC addresses of the guests, e.g. `BC:24:11`",
+			},
+			"max_workers": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How many guests are started, stopped or migrated in parallel by bulk actions and the HA manager",
+			},
+			"migration_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"secure", "insecure"}, false),
+				Description:  "Whether migrations are tunneled through SSH (secure) or sent unencrypted (insecure)",
+			},
+			"migration_network": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "The network migrations are sent through in CIDR notation, e.g. `10.1.0.0/24`",
+			},
+			"tag_style": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"shape": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"full", "circle", "dense", "none"}, false),
+							Description:  "How the tags are shown in the tree of the web interface: full, circle, dense or none",
+						},
+						"ordering": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"config", "alphabetical"}, false),
+							Description:  "The order of the tags: config or alphabetical",
+						},
+						"case_sensitive": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether tags differing in case are different tags",
+						},
+						"color_map": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The colors of tags as hex RGB background color, optionally followed by `:` and the text color, e.g. `FF0000:FFFFFF`",
+						},
+					},
+				},
+				Description: "The style of the tags of the guests in the web interface",
+			},
+			"registered_tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The tags users can set on guests without the permission to create new tags",
+			},
+		},
+	}
+}
+
+// The color map of a tag style, e.g. prod:FF0000:FFFFFF;test:00FF00
+func clusterTagColorMap(colors map[string]interface{}) string {
+	list := []string{}
+	for tag, color := range colors {
+		list = append(list, tag+":"+color.(string))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ";")
+}
+
+func parseClusterTagColorMap(value string) map[string]interface{} {
+	colors := map[string]interface{}{}
+	for _, item := range strings.Split(value, ";") {
+		if parts := strings.SplitN(item, ":", 2); len(parts) == 2 {
+			colors[parts[0]] = parts[1]
+		}
+	}
+	return colors
+}
+
+// The parameters of the options, the empty ones are returned as the options to delete.
+func clusterOptionsParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for attribute, parameter := range clusterOptionsParameters {
+		switch value := d.Get(attribute).(type) {
+		case int:
+			if value != 0 {
+				params[parameter] = value
+				continue
+			}
+		case string:
+			if value != "" {
+				params[parameter] = value
+				continue
+			}
+		}
+		deletes = append(deletes, parameter)
+	}
+
+	migration := []string{}
+	if migrationType := d.Get("migration_type").(string); migrationType != "" {
+		migration = append(migration, "type="+migrationType)
+	}
+	if network := d.Get("migration_network").(string); network != "" {
+		migration = append(migration, "network="+network)
+	}
+	if len(migration) > 0 {
+		params["migration"] = strings.Join(migration, ",")
+	} else {
+		deletes = append(deletes, "migration")
+	}
+
+	if block := d.Get("tag_style").([]interface{}); len(block) > 0 && block[0] != nil {
+		tagStyle := block[0].(map[string]interface{})
+		style := []string{"case-sensitive=0"}
+		if tagStyle["case_sensitive"].(bool) {
+			style[0] = "case-sensitive=1"
+		}
+		for _, key := range []string{"shape", "ordering"} {
+			if value := tagStyle[key].(string); value != "" {
+				style = append(style, key+"="+value)
+			}
+		}
+		if colors := tagStyle["color_map"].(map[string]interface{}); len(colors) > 0 {
+			style = append(style, "color-map="+clusterTagColorMap(colors))
+		}
+		params["tag-style"] = strings.Join(style, ",")
+	} else {
+		deletes = append(deletes, "tag-style")
+	}
+
+	if tags := schemaStringList(d.Get("registered_tags")); len(tags) > 0 {
+		sort.Strings(tags)
+		params["registered-tags"] = strings.Join(tags, ";")
+	} else {
+		deletes = append(deletes, "registered-tags")
+	}
+	return
+}
+
+func resourceClusterOptionsCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("cluster")
+	return resourceClusterOptionsUpdate(d, meta)
+}
+
+func resourceClusterOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceClusterOptionsRead(d, meta)
+}
+
+func _resourceClusterOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	logger, _ := CreateSubLogger("resource_cluster_options_read")
+	logger.Info().Msg("Reading the options of the cluster")
+
+	config, err := apiGetMap(pconf.Session, "/cluster/options")
+	if err != nil {
+		return err
+	}
+
+	for attribute, parameter := range clusterOptionsParameters {
+		if attribute == "max_workers" {
+			d.Set(attribute, apiInt(config[parameter]))
+		} else {
+			d.Set(attribute, apiString(config[parameter]))
+		}
+	}
+
+	migration := apiPropertyString(config["migration"], "type")
+	d.Set("migration_type", apiString(migration["type"]))
+	d.Set("migration_network", apiString(migration["network"]))
+
+	tagStyle := []interface{}{}
+	if _, ok := config["tag-style"]; ok {
+		style := apiPropertyString(config["tag-style"], "")
+		tagStyle = append(tagStyle, map[string]interface{}{
+			"shape":          apiString(style["shape"]),
+			"ordering":       apiString(style["ordering"]),
+			"case_sensitive": apiBool(style["case-sensitive"]),
+			"color_map":      parseClusterTagColorMap(apiString(style["color-map"])),
+		})
+	}
+	if err = d.Set("tag_style", tagStyle); err != nil {
+		return err
+	}
+	if err = d.Set("registered_tags", apiStringList(config["registered-tags"], ";")); err != nil {
+		return err
+	}
+
+	logger.Debug().Msgf("Finished cluster options read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceClusterOptionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	params, deletes := clusterOptionsParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	logger, _ := CreateSubLogger("resource_cluster_options_update")
+	logger.Info().Msg("Updating the options of the cluster")
+
+	if _, err := apiPut(pconf.Session, "/cluster/options", params); err != nil {
+		return err
+	}
+	return _resourceClusterOptionsRead(d, meta)
+}
+
+// The options managed by the resource are reset to the defaults of proxmox.
+func resourceClusterOptionsDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, err := apiPut(pconf.Session, "/cluster/options", map[string]interface{}{
+		"delete": strings.Join(clusterOptionsDeletes, ","),
+	})
+	return err
+}
@@ -0,0 +1,49 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestClusterOptionsParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceClusterOptions().Schema, map[string]interface{}{
+		"keyboard":          "de",
+		"max_workers":       4,
+		"migration_network": "10.1.0.0/24",
+		"tag_style": []interface{}{map[string]interface{}{
+			"shape":     "circle",
+			"color_map": map[string]interface{}{"prod": "FF0000:FFFFFF", "test": "00FF00"},
+		}},
+		"registered_tags": []interface{}{"test", "prod"},
+	})
+
+	params, deletes := clusterOptionsParams(d)
+	expected := map[string]interface{}{
+		"keyboard":        "de",
+		"max_workers":     4,
+		"migration":       "network=10.1.0.0/24",
+		"tag-style":       "case-sensitive=0,shape=circle,color-map=prod:FF0000:FFFFFF;test:00FF00",
+		"registered-tags": "prod;test",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	sort.Strings(deletes)
+	if !reflect.DeepEqual(deletes, []string{"console", "email_from", "http_proxy", "language", "mac_prefix"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestParseClusterTagColorMap(t *testing.T) {
+	colors := parseClusterTagColorMap("prod:FF0000:FFFFFF;test:00FF00")
+	expected := map[string]interface{}{"prod": "FF0000:FFFFFF", "test": "00FF00"}
+	if !reflect.DeepEqual(colors, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, colors)
+	}
+	if value := clusterTagColorMap(colors); value != "prod:FF0000:FFFFFF;test:00FF00" {
+		t.Errorf("unexpected color map `%s`", value)
+	}
+}
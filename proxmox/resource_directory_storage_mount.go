@@ -0,0 +1,180 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceDirectoryStorageMount() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a file system on an unused disk of a node, mounted at /mnt/pve/<name> by a systemd mount unit.",
+
+		Create:   resourceDirectoryStorageMountCreate,
+		Read:     resourceDirectoryStorageMountRead,
+		Delete:   resourceDirectoryStorageMountDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disk of the file system is in",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxDiskStorageName, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The name of the mount point below /mnt/pve, also the id of the storage when `add_storage` is set",
+			},
+			"device": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/sdb"),
+				Description:  "The path of the disk the file system is created on, e.g. `/dev/sdb`",
+			},
+			"filesystem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "ext4",
+				ValidateFunc: validation.StringInSlice([]string{"ext4", "xfs"}, false),
+				Description:  "The file system created on the disk: ext4 or xfs",
+			},
+			"add_storage": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether a dir storage of the mount point is added to the cluster",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path the file system is mounted at",
+			},
+		},
+	}
+}
+
+// Proxmox mounts the file systems it creates at /mnt/pve/<name>.
+func directoryStorageMountPath(name string) string {
+	return "/mnt/pve/" + name
+}
+
+// The mount at path in list, the mounts of a node as listed by /nodes/{node}/disks/directory, nil
+// when there is none.
+func directoryStorageMountFind(list []interface{}, path string) map[string]interface{} {
+	for _, item := range list {
+		if mount, ok := item.(map[string]interface{}); ok && apiString(mount["path"]) == path {
+			return mount
+		}
+	}
+	return nil
+}
+
+func resourceDirectoryStorageMountCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	device := d.Get("device").(string)
+
+	logger, _ := CreateSubLogger("resource_directory_storage_mount_create")
+	logger.Info().Str("node", node).Msgf("Creating file system on %s mounted at %s", device, directoryStorageMountPath(name))
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "disks", "directory"), map[string]interface{}{
+		"name":        name,
+		"device":      device,
+		"filesystem":  d.Get("filesystem").(string),
+		"add_storage": d.Get("add_storage").(bool),
+	}); err != nil {
+		return fmt.Errorf("Creating the directory %s on %s failed: %v", name, node, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceDirectoryStorageMountRead(d, meta)
+}
+
+func resourceDirectoryStorageMountRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceDirectoryStorageMountRead(d, meta)
+}
+
+// The mount unit refers to the file system by its UUID, the device is kept as configured.
+func _resourceDirectoryStorageMountRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_directory_storage_mount_read")
+	logger.Info().Str("node", node).Msgf("Reading directory %s", name)
+
+	data, err := apiGet(pconf.Session, apiPath("nodes", node, "disks", "directory"))
+	if err != nil {
+		return err
+	}
+	list, _ := data.([]interface{})
+	mount := directoryStorageMountFind(list, directoryStorageMountPath(name))
+	if mount == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("name", name)
+	if filesystem := apiString(mount["type"]); filesystem != "" {
+		d.Set("filesystem", filesystem)
+	}
+	d.Set("path", apiString(mount["path"]))
+	return nil
+}
+
+// The mount unit is removed and the disk is wiped, so it can be used again, and the storage of
+// the mount point is removed with it.
+func resourceDirectoryStorageMountDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_directory_storage_mount_delete")
+	logger.Info().Str("node", node).Msgf("Removing directory %s", name)
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "disks", "directory", name)+"?cleanup-config=1&cleanup-disks=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Removing the directory %s on %s failed: %v", name, node, err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package proxmox
+
+import "testing"
+
+func TestDirectoryStorageMountFind(t *testing.T) {
+	list := []interface{}{
+		map[string]interface{}{"path": "/mnt/pve/backup", "type": "xfs", "device": "/dev/disk/by-uuid/1234"},
+		map[string]interface{}{"path": "/mnt/pve/ssd", "type": "ext4", "device": "/dev/disk/by-uuid/5678"},
+	}
+	if mount := directoryStorageMountFind(list, directoryStorageMountPath("ssd")); mount == nil || apiString(mount["type"]) != "ext4" {
+		t.Errorf("expected the mount of ssd, got %v", mount)
+	}
+	if mount := directoryStorageMountFind(list, directoryStorageMountPath("data")); mount != nil {
+		t.Errorf("expected no mount, got %v", mount)
+	}
+}
@@ -0,0 +1,262 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var fileResourceDef *schema.Resource
+
+// the content types of storages files can be uploaded as
+var fileContentTypes = []string{"iso", "vztmpl", "snippets"}
+
+func resourceFile() *schema.Resource {
+	*pxapi.Debug = true
+
+	algorithms := []string{}
+	for algorithm := range isoChecksumAlgorithms {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Strings(algorithms)
+
+	fileResourceDef = &schema.Resource{
+		Description: "Uploads a file to a storage as ISO image, container template or snippet.",
+
+		Create:        resourceFileCreate,
+		Read:          resourceFileRead,
+		Delete:        resourceFileDelete,
+		CustomizeDiff: resourceFileCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the file is uploaded to",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the file is stored on, it must have the content type",
+			},
+			"content_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(fileContentTypes, false),
+				Description:  "The content type of the file, one of iso, vztmpl and snippets",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The file name on the storage, the name of source when not set",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"content", "source"},
+				RequiredWith: []string{"filename"},
+				Description:  "The content of the file",
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"content", "source"},
+				Description:  "Path of a local file to upload, it is uploaded again when its content changes",
+			},
+			"checksum": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum_algorithm"},
+				Description:  "The expected checksum of the file, it is verified before the file is uploaded",
+			},
+			"checksum_algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum"},
+				ValidateFunc: validation.StringInSlice(algorithms, false),
+				Description:  "The algorithm of `checksum`: `md5`, `sha1`, `sha224`, `sha256`, `sha384` or `sha512`.",
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The sha256 checksum of the uploaded source",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the file",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the file in bytes.",
+			},
+		},
+	}
+
+	return fileResourceDef
+}
+
+func sourceHash(source string) (string, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return fileChecksum(file, "sha256")
+}
+
+// A changed source replaces the file, so it is uploaded again.
+func resourceFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	source := d.Get("source").(string)
+	if source == "" || !d.NewValueKnown("source") {
+		return nil
+	}
+	hash, err := sourceHash(source)
+	if err != nil {
+		// the file may be created by the same run, it is hashed when uploading
+		if os.IsNotExist(err) {
+			return d.SetNewComputed("source_hash")
+		}
+		return err
+	}
+	if hash != d.Get("source_hash").(string) {
+		return d.SetNew("source_hash", hash)
+	}
+	return nil
+}
+
+func resourceFileCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	storage := d.Get("storage").(string)
+	contentType := d.Get("content_type").(string)
+	source := d.Get("source").(string)
+	checksum := strings.ToLower(d.Get("checksum").(string))
+	algorithm := d.Get("checksum_algorithm").(string)
+
+	filename := d.Get("filename").(string)
+	if filename == "" {
+		filename = filepath.Base(source)
+	}
+
+	var file io.ReadSeeker = strings.NewReader(d.Get("content").(string))
+	if source != "" {
+		sourceFile, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer sourceFile.Close()
+		file = sourceFile
+
+		hash, err := fileChecksum(file, "sha256")
+		if err != nil {
+			return err
+		}
+		d.Set("source_hash", hash)
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	if checksum != "" {
+		actual, err := fileChecksum(file, algorithm)
+		if err != nil {
+			return err
+		}
+		if actual != checksum {
+			return fmt.Errorf("The %s checksum of %s is %s, expected %s", algorithm, filename, actual, checksum)
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	logger, _ := CreateSubLogger("resource_file_create")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Uploading %s %s", contentType, filename)
+
+	if err := pconf.Client.Upload(node, storage, contentType, filename, file); err != nil {
+		return fmt.Errorf("Uploading %s to %s failed: %v", filename, storage, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s:%s/%s", node, storage, contentType, filename))
+	return _resourceFileRead(d, meta)
+}
+
+func resourceFileRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFileRead(d, meta)
+}
+
+func _resourceFileRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	// volume ids of uploaded files look like <storage>:<content type>/<file name>
+	storage := strings.SplitN(volid, ":", 2)[0]
+	path := strings.SplitN(strings.TrimPrefix(volid, storage+":"), "/", 2)
+	if len(path) != 2 {
+		d.SetId("")
+		return fmt.Errorf("Unexpected volume id %s, expected <storage>:<content type>/<file name>", volid)
+	}
+
+	volume, err := apiGetVolume(pconf.Session, node, volid, path[0])
+	if err != nil {
+		return err
+	}
+	// the file or its storage was removed outside of terraform
+	if volume == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", node)
+	d.Set("storage", storage)
+	d.Set("content_type", path[0])
+	d.Set("filename", path[1])
+	d.Set("volid", volid)
+	d.Set("size", apiInt(volume["size"]))
+	return nil
+}
+
+func resourceFileDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
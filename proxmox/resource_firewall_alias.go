@@ -0,0 +1,139 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFirewallAlias() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an alias of the cluster firewall, a named address or network which firewall rules and IP sets refer to.",
+
+		Create: resourceFirewallAliasCreate,
+		Read:   resourceFirewallAliasRead,
+		Update: resourceFirewallAliasUpdate,
+		Delete: resourceFirewallAliasDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateFirewallName,
+				Description:  "The name of the alias, which is used in place of an address in rules and IP sets",
+			},
+			"cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.Any(validation.IsCIDR, validation.IsIPAddress),
+				Description:  "The address or network of the alias, e.g. `10.0.0.1` or `10.0.0.0/24`",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the alias",
+			},
+		},
+	}
+}
+
+func resourceFirewallAliasCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params := map[string]interface{}{
+		"name": name,
+		"cidr": d.Get("cidr").(string),
+	}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_alias_create")
+	logger.Info().Str("name", name).Msg("Creating firewall alias")
+
+	if _, err := apiPost(pconf.Session, "/cluster/firewall/aliases", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("aliases", name))
+	return _resourceFirewallAliasRead(d, meta)
+}
+
+func resourceFirewallAliasRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallAliasRead(d, meta)
+}
+
+func _resourceFirewallAliasRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_alias_read")
+	logger.Info().Str("name", name).Msg("Reading firewall alias")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "firewall", "aliases", name))
+	if err != nil {
+		if strings.Contains(err.Error(), "no such alias") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", name)
+	d.Set("cidr", apiString(config["cidr"]))
+	d.Set("comment", apiString(config["comment"]))
+	return nil
+}
+
+func resourceFirewallAliasUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiPut(pconf.Session, apiPath("cluster", "firewall", "aliases", name), map[string]interface{}{
+		"cidr":    d.Get("cidr").(string),
+		"comment": d.Get("comment").(string),
+	}, "comment")
+	if err != nil {
+		return err
+	}
+	return _resourceFirewallAliasRead(d, meta)
+}
+
+// Proxmox refuses to delete an alias which is still used by rules or IP sets.
+func resourceFirewallAliasDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "firewall", "aliases", name))
+	return err
+}
@@ -0,0 +1,272 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFirewallIpset() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an IP set of the cluster firewall, a named list of addresses and networks which firewall rules refer to.",
+
+		Create: resourceFirewallIpsetCreate,
+		Read:   resourceFirewallIpsetRead,
+		Update: resourceFirewallIpsetUpdate,
+		Delete: resourceFirewallIpsetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateFirewallName,
+				Description:  "The name of the IP set, rules refer to it as `+<name>`",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the IP set",
+			},
+			"entry": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "The address or network of the entry, e.g. `10.0.0.0/24`, or the name of an alias",
+						},
+						"nomatch": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Exclude the address or network from the IP set",
+						},
+						"comment": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "A comment of the entry",
+						},
+					},
+				},
+				Description: "The addresses and networks of the IP set",
+			},
+		},
+	}
+}
+
+// The entries of an IP set listing by their cidr.
+func firewallIpsetEntries(entries []interface{}) map[string]map[string]interface{} {
+	byCidr := map[string]map[string]interface{}{}
+	for _, item := range entries {
+		if entry, ok := item.(map[string]interface{}); ok {
+			byCidr[apiString(entry["cidr"])] = map[string]interface{}{
+				"cidr":    apiString(entry["cidr"]),
+				"nomatch": apiBool(entry["nomatch"]),
+				"comment": apiString(entry["comment"]),
+			}
+		}
+	}
+	return byCidr
+}
+
+// The changes turning the current entries into the desired ones, an entry is identified by its
+// cidr. Entries with another comment or nomatch are updated in place, so the set never lacks
+// an address which is kept.
+func firewallIpsetChanges(current []interface{}, desired []interface{}) (add []map[string]interface{}, update []map[string]interface{}, remove []string) {
+	currentEntries := firewallIpsetEntries(current)
+	desiredEntries := firewallIpsetEntries(desired)
+	for cidr, entry := range desiredEntries {
+		old, ok := currentEntries[cidr]
+		if !ok {
+			add = append(add, entry)
+		} else if old["nomatch"] != entry["nomatch"] || old["comment"] != entry["comment"] {
+			update = append(update, entry)
+		}
+	}
+	for cidr := range currentEntries {
+		if _, ok := desiredEntries[cidr]; !ok {
+			remove = append(remove, cidr)
+		}
+	}
+	sort.Slice(add, func(i, j int) bool { return add[i]["cidr"].(string) < add[j]["cidr"].(string) })
+	sort.Slice(update, func(i, j int) bool { return update[i]["cidr"].(string) < update[j]["cidr"].(string) })
+	sort.Strings(remove)
+	return
+}
+
+func firewallSetIpsetEntries(session *pxapi.Session, name string, entries []interface{}) error {
+	path := apiPath("cluster", "firewall", "ipset", name)
+	current, err := apiGet(session, path)
+	if err != nil {
+		return err
+	}
+	list, _ := current.([]interface{})
+	add, update, remove := firewallIpsetChanges(list, entries)
+
+	for _, cidr := range remove {
+		if _, err = apiDelete(session, apiPath("cluster", "firewall", "ipset", name, cidr)); err != nil {
+			return fmt.Errorf("Deleting %s from IP set %s failed: %v", cidr, name, err)
+		}
+	}
+	for _, entry := range update {
+		_, err = apiPut(session, apiPath("cluster", "firewall", "ipset", name, entry["cidr"].(string)), map[string]interface{}{
+			"nomatch": entry["nomatch"],
+			"comment": entry["comment"],
+		}, "comment")
+		if err != nil {
+			return fmt.Errorf("Updating %s of IP set %s failed: %v", entry["cidr"], name, err)
+		}
+	}
+	for _, entry := range add {
+		params := map[string]interface{}{
+			"cidr":    entry["cidr"],
+			"nomatch": entry["nomatch"],
+		}
+		if entry["comment"] != "" {
+			params["comment"] = entry["comment"]
+		}
+		if _, err = apiPost(session, path, params); err != nil {
+			return fmt.Errorf("Adding %s to IP set %s failed: %v", entry["cidr"], name, err)
+		}
+	}
+	return nil
+}
+
+func resourceFirewallIpsetCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params := map[string]interface{}{"name": name}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_ipset_create")
+	logger.Info().Str("name", name).Msg("Creating firewall IP set")
+
+	if _, err := apiPost(pconf.Session, "/cluster/firewall/ipset", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("ipset", name))
+
+	if err := firewallSetIpsetEntries(pconf.Session, name, d.Get("entry").(*schema.Set).List()); err != nil {
+		return err
+	}
+	return _resourceFirewallIpsetRead(d, meta)
+}
+
+func resourceFirewallIpsetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallIpsetRead(d, meta)
+}
+
+func _resourceFirewallIpsetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_ipset_read")
+	logger.Info().Str("name", name).Msg("Reading firewall IP set")
+
+	// the comment of the set is only part of the listing of all sets
+	sets, err := apiGet(pconf.Session, "/cluster/firewall/ipset")
+	if err != nil {
+		return err
+	}
+	var config map[string]interface{}
+	list, _ := sets.([]interface{})
+	for _, item := range list {
+		if set, ok := item.(map[string]interface{}); ok && apiString(set["name"]) == name {
+			config = set
+		}
+	}
+	if config == nil {
+		d.SetId("")
+		return nil
+	}
+
+	data, err := apiGet(pconf.Session, apiPath("cluster", "firewall", "ipset", name))
+	if err != nil {
+		return err
+	}
+	entries, _ := data.([]interface{})
+	result := []interface{}{}
+	for _, entry := range firewallIpsetEntries(entries) {
+		result = append(result, entry)
+	}
+
+	d.Set("name", name)
+	d.Set("comment", apiString(config["comment"]))
+	if err = d.Set("entry", result); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("name", name).Msgf("Finished firewall IP set read resulting in data: '%+v'", result)
+	return nil
+}
+
+func resourceFirewallIpsetUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// renaming a set to its own name updates its comment
+	if d.HasChange("comment") {
+		_, err = apiPost(pconf.Session, "/cluster/firewall/ipset", map[string]interface{}{
+			"name":    name,
+			"rename":  name,
+			"comment": d.Get("comment").(string),
+		}, "comment")
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("entry") {
+		if err = firewallSetIpsetEntries(pconf.Session, name, d.Get("entry").(*schema.Set).List()); err != nil {
+			return err
+		}
+	}
+	return _resourceFirewallIpsetRead(d, meta)
+}
+
+// Proxmox only deletes empty IP sets, so the entries are deleted first.
+func resourceFirewallIpsetDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	if err = firewallSetIpsetEntries(pconf.Session, name, []interface{}{}); err != nil {
+		return err
+	}
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "firewall", "ipset", name))
+	return err
+}
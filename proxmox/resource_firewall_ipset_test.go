@@ -0,0 +1,29 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFirewallIpsetChanges(t *testing.T) {
+	current := []interface{}{
+		map[string]interface{}{"cidr": "10.0.0.0/24", "comment": "office", "digest": "abc"},
+		map[string]interface{}{"cidr": "10.0.1.0/24", "nomatch": float64(1)},
+		map[string]interface{}{"cidr": "10.0.2.0/24"},
+	}
+	desired := []interface{}{
+		map[string]interface{}{"cidr": "10.0.0.0/24", "comment": "office", "nomatch": false},
+		map[string]interface{}{"cidr": "10.0.1.0/24", "comment": "", "nomatch": false},
+		map[string]interface{}{"cidr": "dmz", "comment": "alias", "nomatch": false},
+	}
+	add, update, remove := firewallIpsetChanges(current, desired)
+	if !reflect.DeepEqual(add, []map[string]interface{}{{"cidr": "dmz", "comment": "alias", "nomatch": false}}) {
+		t.Errorf("unexpected entries to add `%v`", add)
+	}
+	if !reflect.DeepEqual(update, []map[string]interface{}{{"cidr": "10.0.1.0/24", "comment": "", "nomatch": false}}) {
+		t.Errorf("unexpected entries to update `%v`", update)
+	}
+	if !reflect.DeepEqual(remove, []string{"10.0.2.0/24"}) {
+		t.Errorf("unexpected entries to remove `%v`", remove)
+	}
+}
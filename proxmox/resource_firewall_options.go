@@ -0,0 +1,211 @@
+package proxmox
+
+import (
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => parameter of the options of a guest firewall
+var firewallOptionsParameters = map[string]string{
+	"enabled":       "enable",
+	"dhcp":          "dhcp",
+	"ndp":           "ndp",
+	"radv":          "radv",
+	"ipfilter":      "ipfilter",
+	"macfilter":     "macfilter",
+	"policy_in":     "policy_in",
+	"policy_out":    "policy_out",
+	"log_level_in":  "log_level_in",
+	"log_level_out": "log_level_out",
+}
+
+func resourceFirewallOptions() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the firewall options of a VM or container, e.g. whether its firewall is enabled and its default policies.",
+
+		Create: resourceFirewallOptionsCreate,
+		Read:   resourceFirewallOptionsRead,
+		Update: resourceFirewallOptionsUpdate,
+		Delete: resourceFirewallOptionsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the VM or container",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node the guest is on",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the firewall of the guest is enabled",
+			},
+			"dhcp": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether DHCP traffic is allowed",
+			},
+			"ndp": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the IPv6 neighbor discovery protocol is allowed",
+			},
+			"radv": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the guest may send IPv6 router advertisements",
+			},
+			"ipfilter": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the guest may only send from the addresses of its IP set ipfilter-net<n>",
+			},
+			"macfilter": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the guest may only send from the MAC addresses of its network devices",
+			},
+			"policy_in": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DROP",
+				ValidateFunc: validation.StringInSlice([]string{"ACCEPT", "DROP", "REJECT"}, false),
+				Description:  "What happens to incoming traffic no rule matches: ACCEPT, DROP or REJECT",
+			},
+			"policy_out": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ACCEPT",
+				ValidateFunc: validation.StringInSlice([]string{"ACCEPT", "DROP", "REJECT"}, false),
+				Description:  "What happens to outgoing traffic no rule matches: ACCEPT, DROP or REJECT",
+			},
+			"log_level_in": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the incoming traffic handled by the policy, nolog logs nothing",
+			},
+			"log_level_out": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the outgoing traffic handled by the policy, nolog logs nothing",
+			},
+		},
+	}
+}
+
+func resourceFirewallOptionsCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("firewall", strconv.Itoa(d.Get("vmid").(int))))
+	return resourceFirewallOptionsUpdate(d, meta)
+}
+
+func resourceFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallOptionsRead(d, meta)
+}
+
+func _resourceFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_options_read")
+	logger.Info().Int("vmid", vmid).Msg("Reading firewall options of guest")
+
+	path, vmr, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	config, err := apiGetMap(pconf.Session, path+"/options")
+	if err != nil {
+		return err
+	}
+	options := firewallOptions(config, firewallGuestOptionDefaults)
+
+	d.Set("vmid", vmid)
+	d.Set("node", vmr.Node())
+	for attribute, parameter := range firewallOptionsParameters {
+		d.Set(attribute, options[parameter])
+	}
+
+	logger.Debug().Int("vmid", vmid).Msgf("Finished firewall options read resulting in data: '%+v'", options)
+	return nil
+}
+
+func resourceFirewallOptionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, _, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_options_update")
+	logger.Info().Int("vmid", vmid).Msg("Writing firewall options of guest")
+
+	if err = firewallSetOptions(d, pconf.Session, path, firewallOptionsParameters); err != nil {
+		return err
+	}
+	return _resourceFirewallOptionsRead(d, meta)
+}
+
+// The options are reset to the defaults of proxmox, which disables the firewall of the guest. A
+// guest already gone has no options left.
+func resourceFirewallOptionsDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, _, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	return firewallResetOptions(pconf.Session, path, firewallGuestOptionDefaults)
+}
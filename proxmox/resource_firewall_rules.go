@@ -0,0 +1,133 @@
+package proxmox
+
+import (
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFirewallRules() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the ordered firewall rules of a VM or container.",
+
+		Create: resourceFirewallRulesCreate,
+		Read:   resourceFirewallRulesRead,
+		Update: resourceFirewallRulesUpdate,
+		Delete: resourceFirewallRulesDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: firewallRulesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the VM or container",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node the guest is on",
+			},
+			"rule": firewallRulesSchema(true),
+		},
+	}
+}
+
+func resourceFirewallRulesCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("firewall", strconv.Itoa(d.Get("vmid").(int))))
+	return resourceFirewallRulesUpdate(d, meta)
+}
+
+func resourceFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallRulesRead(d, meta)
+}
+
+func _resourceFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_rules_read")
+	logger.Info().Int("vmid", vmid).Msg("Reading firewall rules of guest")
+
+	path, vmr, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	rules, err := firewallGetRules(pconf.Session, path+"/rules")
+	if err != nil {
+		return err
+	}
+
+	d.Set("vmid", vmid)
+	d.Set("node", vmr.Node())
+	if err = d.Set("rule", rules); err != nil {
+		return err
+	}
+
+	logger.Debug().Int("vmid", vmid).Msgf("Finished firewall rules read resulting in data: '%+v'", rules)
+	return nil
+}
+
+func resourceFirewallRulesUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, _, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_rules_update")
+	logger.Info().Int("vmid", vmid).Msg("Writing firewall rules of guest")
+
+	if err = firewallSetRules(pconf.Session, path+"/rules", d.Get("rule").([]interface{})); err != nil {
+		return err
+	}
+	return _resourceFirewallRulesRead(d, meta)
+}
+
+// All rules of the guest are deleted, a guest already gone has no rules left.
+func resourceFirewallRulesDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmid, err := parseFirewallGuestId(d.Id())
+	if err != nil {
+		return err
+	}
+	path, _, err := firewallGuestPath(pconf.Session, vmid)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	return firewallSetRules(pconf.Session, path+"/rules", []interface{}{})
+}
@@ -0,0 +1,170 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFirewallSecurityGroup() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a security group of the cluster firewall, a set of rules which the firewall rules of guests insert.",
+
+		Create: resourceFirewallSecurityGroupCreate,
+		Read:   resourceFirewallSecurityGroupRead,
+		Update: resourceFirewallSecurityGroupUpdate,
+		Delete: resourceFirewallSecurityGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: firewallRulesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Za-z][A-Za-z0-9\-_]{1,17}$`), "must start with a letter and have 2 to 18 letters, digits, - or _"),
+				Description:  "The name of the security group, which rules of direction group refer to",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the security group",
+			},
+			"rule": firewallRulesSchema(false),
+		},
+	}
+}
+
+func resourceFirewallSecurityGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	group := d.Get("group").(string)
+	params := map[string]interface{}{"group": group}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_security_group_create")
+	logger.Info().Str("group", group).Msg("Creating firewall security group")
+
+	if _, err := apiPost(pconf.Session, "/cluster/firewall/groups", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("groups", group))
+
+	if err := firewallSetRules(pconf.Session, apiPath("cluster", "firewall", "groups", group), d.Get("rule").([]interface{})); err != nil {
+		return err
+	}
+	return _resourceFirewallSecurityGroupRead(d, meta)
+}
+
+func resourceFirewallSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceFirewallSecurityGroupRead(d, meta)
+}
+
+func _resourceFirewallSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_security_group_read")
+	logger.Info().Str("group", group).Msg("Reading firewall security group")
+
+	// the rules of a missing group are an empty list, so the group is looked up in the list
+	groups, err := apiGet(pconf.Session, "/cluster/firewall/groups")
+	if err != nil {
+		return err
+	}
+	var config map[string]interface{}
+	list, _ := groups.([]interface{})
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok && apiString(entry["group"]) == group {
+			config = entry
+		}
+	}
+	if config == nil {
+		d.SetId("")
+		return nil
+	}
+
+	rules, err := firewallGetRules(pconf.Session, apiPath("cluster", "firewall", "groups", group))
+	if err != nil {
+		return err
+	}
+
+	d.Set("group", group)
+	d.Set("comment", apiString(config["comment"]))
+	if err = d.Set("rule", rules); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("group", group).Msgf("Finished firewall security group read resulting in data: '%+v'", rules)
+	return nil
+}
+
+func resourceFirewallSecurityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_firewall_security_group_update")
+	logger.Info().Str("group", group).Msg("Updating firewall security group")
+
+	// renaming a group to its own name updates its comment
+	if d.HasChange("comment") {
+		_, err = apiPost(pconf.Session, "/cluster/firewall/groups", map[string]interface{}{
+			"group":   group,
+			"rename":  group,
+			"comment": d.Get("comment").(string),
+		}, "comment")
+		if err != nil {
+			return err
+		}
+	}
+	if d.HasChange("rule") {
+		if err = firewallSetRules(pconf.Session, apiPath("cluster", "firewall", "groups", group), d.Get("rule").([]interface{})); err != nil {
+			return err
+		}
+	}
+	return _resourceFirewallSecurityGroupRead(d, meta)
+}
+
+// Proxmox only deletes empty groups, so the rules are deleted first. A group still inserted by
+// rules of guests can not be deleted.
+func resourceFirewallSecurityGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	path := apiPath("cluster", "firewall", "groups", group)
+	if err = firewallSetRules(pconf.Session, path, []interface{}{}); err != nil {
+		return err
+	}
+	_, err = apiDelete(pconf.Session, path)
+	return err
+}
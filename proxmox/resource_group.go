@@ -0,0 +1,201 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var groupResourceDef *schema.Resource
+
+func resourceGroup() *schema.Resource {
+	*pxapi.Debug = true
+
+	groupResourceDef = &schema.Resource{
+		Description: "Manages an access control group (`/access/groups`).",
+
+		Create: resourceGroupCreate,
+		Read:   resourceGroupRead,
+		Update: resourceGroupUpdate,
+		Delete: resourceGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"groupid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the group.",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"members": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "User IDs (user@realm) which are members of the group",
+			},
+		},
+	}
+
+	return groupResourceDef
+}
+
+func resourceGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	groupID := d.Get("groupid").(string)
+	_, err := apiPost(pconf.Session, "/access/groups", map[string]interface{}{
+		"groupid": groupID,
+		"comment": d.Get("comment").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(clusterResourceId("groups", groupID))
+
+	err = updateGroupMembers(pconf.Session, groupID, []string{}, schemaStringList(d.Get("members")))
+	if err != nil {
+		return err
+	}
+
+	return _resourceGroupRead(d, meta)
+}
+
+func resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceGroupRead(d, meta)
+}
+
+func _resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, groupID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_group_read")
+	logger.Info().Str("groupid", groupID).Msg("Reading configuration for group")
+
+	group, err := apiGetMap(pconf.Session, apiPath("access", "groups", groupID))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("groupid", groupID)
+	d.Set("comment", apiString(group["comment"]))
+	if err = d.Set("members", apiStringList(group["members"], ",")); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("groupid", groupID).Msgf("Finished group read resulting in data: '%+v'", group)
+	return nil
+}
+
+func resourceGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, groupID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("comment") {
+		_, err = apiPut(pconf.Session, apiPath("access", "groups", groupID), map[string]interface{}{
+			"comment": d.Get("comment").(string),
+		}, "comment")
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("members") {
+		prev, next := d.GetChange("members")
+		err = updateGroupMembers(pconf.Session, groupID, schemaStringList(prev), schemaStringList(next))
+		if err != nil {
+			return err
+		}
+	}
+
+	return _resourceGroupRead(d, meta)
+}
+
+func resourceGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, groupID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("access", "groups", groupID))
+	return err
+}
+
+// Proxmox has no endpoint to set the members of a group, the membership is an attribute of
+// the users. Added members get the group appended, removed members get their list of groups
+// rewritten without it.
+func updateGroupMembers(session *pxapi.Session, groupID string, prev []string, next []string) error {
+	for _, userID := range next {
+		if stringInList(userID, prev) {
+			continue
+		}
+		_, err := apiPut(session, apiPath("access", "users", userID), map[string]interface{}{
+			"groups": groupID,
+			"append": 1,
+		})
+		if err != nil {
+			return fmt.Errorf("Adding %s to group %s failed: %v", userID, groupID, err)
+		}
+	}
+
+	for _, userID := range prev {
+		if stringInList(userID, next) {
+			continue
+		}
+		user, err := apiGetMap(session, apiPath("access", "users", userID))
+		if err != nil {
+			// a deleted user is not a member anymore
+			if strings.Contains(err.Error(), "no such user") {
+				continue
+			}
+			return err
+		}
+		groups := []string{}
+		for _, group := range apiStringList(user["groups"], ",") {
+			if group != groupID {
+				groups = append(groups, group)
+			}
+		}
+		_, err = apiPut(session, apiPath("access", "users", userID), map[string]interface{}{
+			"groups": strings.Join(groups, ","),
+		}, "groups")
+		if err != nil {
+			return fmt.Errorf("Removing %s from group %s failed: %v", userID, groupID, err)
+		}
+	}
+
+	return nil
+}
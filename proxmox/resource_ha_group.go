@@ -0,0 +1,225 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceHaGroup() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an HA group, the nodes the highly available guests of the group run on.",
+
+		Create: resourceHaGroupCreate,
+		Read:   resourceHaGroupRead,
+		Update: resourceHaGroupUpdate,
+		Delete: resourceHaGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the HA group",
+			},
+			"nodes": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(0, 1000),
+				},
+				Description: "The nodes of the group with their priority, the guests run on the available nodes with the highest priority",
+			},
+			"restricted": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only run the guests on the nodes of the group, they are stopped when none of them is available",
+			},
+			"no_failback": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Keep the guests on their node when a node with a higher priority comes back online",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the HA group",
+			},
+		},
+	}
+}
+
+// The nodes of a group in the format of proxmox, e.g. pve1:2,pve2:1. Proxmox treats nodes
+// without a priority as priority 0.
+func haGroupNodes(nodes map[string]interface{}) string {
+	list := []string{}
+	for node, priority := range nodes {
+		if priority.(int) == 0 {
+			list = append(list, node)
+		} else {
+			list = append(list, fmt.Sprintf("%s:%d", node, priority.(int)))
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func parseHaGroupNodes(value string) (map[string]interface{}, error) {
+	nodes := map[string]interface{}{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 2)
+		priority := 0
+		if len(parts) == 2 {
+			var err error
+			if priority, err = strconv.Atoi(parts[1]); err != nil {
+				return nil, fmt.Errorf("Invalid priority of node %s: %s", parts[0], parts[1])
+			}
+		}
+		nodes[parts[0]] = priority
+	}
+	return nodes, nil
+}
+
+// The parameters of the HA group, the empty comment is deleted on update.
+func haGroupParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"nodes":      haGroupNodes(d.Get("nodes").(map[string]interface{})),
+		"restricted": d.Get("restricted").(bool),
+		"nofailback": d.Get("no_failback").(bool),
+	}
+	deletes = []string{}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	} else {
+		deletes = append(deletes, "comment")
+	}
+	return
+}
+
+func resourceHaGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	group := d.Get("group").(string)
+	params, _ := haGroupParams(d)
+	params["group"] = group
+	params["type"] = "group"
+
+	logger, _ := CreateSubLogger("resource_ha_group_create")
+	logger.Info().Str("group", group).Msg("Creating HA group")
+
+	if pconf.Standalone {
+		haStandaloneWarning(logger, "HA group "+group)
+		d.SetId(clusterResourceId("groups", group))
+		return nil
+	}
+	if _, err := apiPost(pconf.Session, "/cluster/ha/groups", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("groups", group))
+	return _resourceHaGroupRead(d, meta)
+}
+
+func resourceHaGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceHaGroupRead(d, meta)
+}
+
+func _resourceHaGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	if pconf.Standalone {
+		return nil
+	}
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_ha_group_read")
+	logger.Info().Str("group", group).Msg("Reading configuration for HA group")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "ha", "groups", group))
+	if err != nil {
+		if strings.Contains(err.Error(), "no such ha group") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	nodes, err := parseHaGroupNodes(apiString(config["nodes"]))
+	if err != nil {
+		return err
+	}
+	d.Set("group", group)
+	d.Set("nodes", nodes)
+	d.Set("restricted", apiBool(config["restricted"]))
+	d.Set("no_failback", apiBool(config["nofailback"]))
+	d.Set("comment", apiString(config["comment"]))
+
+	logger.Debug().Str("group", group).Msgf("Finished HA group read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceHaGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if pconf.Standalone {
+		logger, _ := CreateSubLogger("resource_ha_group_update")
+		haStandaloneWarning(logger, "HA group "+group)
+		return nil
+	}
+
+	params, deletes := haGroupParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "ha", "groups", group), params); err != nil {
+		return err
+	}
+	return _resourceHaGroupRead(d, meta)
+}
+
+// proxmox refuses to delete groups which are still used by HA resources
+func resourceHaGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, group, err := parseClusterResourceId(d.Id())
+	if err != nil || pconf.Standalone {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "ha", "groups", group))
+	return err
+}
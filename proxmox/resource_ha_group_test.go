@@ -0,0 +1,21 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHaGroupNodes(t *testing.T) {
+	nodes := map[string]interface{}{"pve1": 2, "pve2": 1, "pve3": 0}
+	value := haGroupNodes(nodes)
+	if value != "pve1:2,pve2:1,pve3" {
+		t.Errorf("expected `pve1:2,pve2:1,pve3`, got `%s`", value)
+	}
+	parsed, err := parseHaGroupNodes(value)
+	if err != nil || !reflect.DeepEqual(parsed, nodes) {
+		t.Errorf("expected `%v`, got `%v` and `%+v`", nodes, parsed, err)
+	}
+	if _, err := parseHaGroupNodes("pve1:high"); err == nil {
+		t.Errorf("expected an error for `pve1:high`")
+	}
+}
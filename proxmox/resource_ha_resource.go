@@ -0,0 +1,233 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var haResourceStates = []string{"started", "stopped", "disabled", "ignored"}
+
+func resourceHaResource() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the high availability of a VM or container.",
+
+		Create: resourceHaResourceCreate,
+		Read:   resourceHaResourceRead,
+		Update: resourceHaResourceUpdate,
+		Delete: resourceHaResourceDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the VM or container",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "vm",
+				ValidateFunc: validation.StringInSlice([]string{"vm", "ct"}, false),
+				Description:  "The type of the guest: vm or ct",
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "started",
+				ValidateFunc: validation.StringInSlice(haResourceStates, false),
+				Description:  "The state the HA manager keeps the guest in: started, stopped, disabled or ignored",
+			},
+			"group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The HA group restricting the nodes the guest runs on, see `proxmox_ha_group`",
+			},
+			"max_restart": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(0, 10),
+				Description:  "How often the guest is restarted on its node after failing to start",
+			},
+			"max_relocate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(0, 10),
+				Description:  "How often the guest is moved to another node after failing to start",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the HA resource",
+			},
+		},
+	}
+}
+
+// The id of a guest in the HA manager, e.g. vm:100.
+func haResourceSid(guestType string, vmid int) string {
+	return fmt.Sprintf("%s:%d", guestType, vmid)
+}
+
+func parseHaResourceSid(sid string) (guestType string, vmid int, err error) {
+	parts := strings.SplitN(sid, ":", 2)
+	if len(parts) == 2 {
+		vmid, err = strconv.Atoi(parts[1])
+	}
+	if len(parts) != 2 || err != nil {
+		return "", 0, fmt.Errorf("Invalid HA resource id %s, must be <type>:<vmid>", sid)
+	}
+	return parts[0], vmid, nil
+}
+
+// The parameters of the HA resource, the empty group and comment are deleted on update.
+func haResourceParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"state":        d.Get("state").(string),
+		"max_restart":  d.Get("max_restart").(int),
+		"max_relocate": d.Get("max_relocate").(int),
+	}
+	deletes = []string{}
+	for _, key := range []string{"group", "comment"} {
+		if value := d.Get(key).(string); value != "" {
+			params[key] = value
+		} else {
+			deletes = append(deletes, key)
+		}
+	}
+	return
+}
+
+func resourceHaResourceCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	sid := haResourceSid(d.Get("type").(string), d.Get("vmid").(int))
+	params, _ := haResourceParams(d)
+	params["sid"] = sid
+
+	logger, _ := CreateSubLogger("resource_ha_resource_create")
+	logger.Info().Str("sid", sid).Msg("Creating HA resource")
+
+	if pconf.Standalone {
+		haStandaloneWarning(logger, "HA resource "+sid)
+		d.SetId(clusterResourceId("resources", sid))
+		return nil
+	}
+	if _, err := apiPost(pconf.Session, "/cluster/ha/resources", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("resources", sid))
+	return _resourceHaResourceRead(d, meta)
+}
+
+func resourceHaResourceRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceHaResourceRead(d, meta)
+}
+
+func _resourceHaResourceRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	if pconf.Standalone {
+		return nil
+	}
+
+	_, sid, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	guestType, vmid, err := parseHaResourceSid(sid)
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_ha_resource_read")
+	logger.Info().Str("sid", sid).Msg("Reading configuration for HA resource")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "ha", "resources", sid))
+	if err != nil {
+		if strings.Contains(err.Error(), "no such resource") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vmid", vmid)
+	d.Set("type", guestType)
+	d.Set("state", apiString(config["state"]))
+	d.Set("group", apiString(config["group"]))
+	d.Set("comment", apiString(config["comment"]))
+	// proxmox omits the limits which are the default
+	d.Set("max_restart", 1)
+	if value, ok := config["max_restart"]; ok {
+		d.Set("max_restart", apiInt(value))
+	}
+	d.Set("max_relocate", 1)
+	if value, ok := config["max_relocate"]; ok {
+		d.Set("max_relocate", apiInt(value))
+	}
+
+	logger.Debug().Str("sid", sid).Msgf("Finished HA resource read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceHaResourceUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, sid, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if pconf.Standalone {
+		logger, _ := CreateSubLogger("resource_ha_resource_update")
+		haStandaloneWarning(logger, "HA resource "+sid)
+		return nil
+	}
+
+	params, deletes := haResourceParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "ha", "resources", sid), params); err != nil {
+		return err
+	}
+	return _resourceHaResourceRead(d, meta)
+}
+
+// The guest keeps running, it is only no longer managed by the HA manager.
+func resourceHaResourceDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, sid, err := parseClusterResourceId(d.Id())
+	if err != nil || pconf.Standalone {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "ha", "resources", sid))
+	return err
+}
@@ -0,0 +1,46 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestHaResourceSid(t *testing.T) {
+	sid := haResourceSid("ct", 101)
+	if sid != "ct:101" {
+		t.Errorf("expected `ct:101`, got `%s`", sid)
+	}
+	guestType, vmid, err := parseHaResourceSid(sid)
+	if err != nil || guestType != "ct" || vmid != 101 {
+		t.Errorf("expected `ct` and 101, got `%s`, %d and `%+v`", guestType, vmid, err)
+	}
+	for _, invalid := range []string{"101", "vm:web"} {
+		if _, _, err := parseHaResourceSid(invalid); err == nil {
+			t.Errorf("expected an error for `%s`", invalid)
+		}
+	}
+}
+
+func TestHaResourceParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceHaResource().Schema, map[string]interface{}{
+		"vmid":  100,
+		"state": "stopped",
+		"group": "prefer-pve1",
+	})
+
+	params, deletes := haResourceParams(d)
+	expected := map[string]interface{}{
+		"state":        "stopped",
+		"max_restart":  1,
+		"max_relocate": 1,
+		"group":        "prefer-pve1",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment"}) {
+		t.Errorf("expected deletes `[comment]`, got `%v`", deletes)
+	}
+}
@@ -0,0 +1,261 @@
+package proxmox
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var isoResourceDef *schema.Resource
+
+// the checksum algorithms of download-url
+var isoChecksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha224": sha256.New224,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+func resourceIso() *schema.Resource {
+	*pxapi.Debug = true
+
+	algorithms := []string{}
+	for algorithm := range isoChecksumAlgorithms {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Strings(algorithms)
+
+	isoResourceDef = &schema.Resource{
+		Description: "Stores an ISO image on a storage, uploaded from the machine running Terraform or downloaded by the node.",
+
+		Create: resourceIsoCreate,
+		Read:   resourceIsoRead,
+		Delete: resourceIsoDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the image is uploaded to or downloaded by",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the image is stored on, it must have the iso content type",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The file name of the image on the storage, the name of source or url when not set",
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"source", "url"},
+				Description:  "Path of a local image to upload",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"}),
+				Description:  "URL the node downloads the image from",
+			},
+			"checksum": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum_algorithm"},
+				Description:  "The expected checksum of the image, it is verified before the image is stored",
+			},
+			"checksum_algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"checksum"},
+				ValidateFunc: validation.StringInSlice(algorithms, false),
+				Description:  "The algorithm of `checksum`: `md5`, `sha1`, `sha224`, `sha256`, `sha384` or `sha512`.",
+			},
+			"verify_certificates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Verify the certificate of the server url is downloaded from",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the image, usable as the iso of a proxmox_vm_qemu",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the image in bytes.",
+			},
+		},
+	}
+
+	return isoResourceDef
+}
+
+// the last element of the path of source or url
+func isoFilename(source string, isoUrl string) string {
+	if source != "" {
+		return filepath.Base(source)
+	}
+	parsed, err := url.Parse(isoUrl)
+	if err != nil {
+		return ""
+	}
+	return path.Base(parsed.Path)
+}
+
+func fileChecksum(file io.Reader, algorithm string) (string, error) {
+	newHash, ok := isoChecksumAlgorithms[algorithm]
+	if !ok {
+		return "", fmt.Errorf("Unknown checksum algorithm %s", algorithm)
+	}
+	checksum := newHash()
+	if _, err := io.Copy(checksum, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(checksum.Sum(nil)), nil
+}
+
+func resourceIsoCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	storage := d.Get("storage").(string)
+	source := d.Get("source").(string)
+	isoUrl := d.Get("url").(string)
+	checksum := strings.ToLower(d.Get("checksum").(string))
+	algorithm := d.Get("checksum_algorithm").(string)
+
+	filename := d.Get("filename").(string)
+	if filename == "" {
+		filename = isoFilename(source, isoUrl)
+	}
+
+	logger, _ := CreateSubLogger("resource_iso_create")
+
+	if source != "" {
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if checksum != "" {
+			actual, err := fileChecksum(file, algorithm)
+			if err != nil {
+				return err
+			}
+			if actual != checksum {
+				return fmt.Errorf("The %s checksum of %s is %s, expected %s", algorithm, source, actual, checksum)
+			}
+			if _, err = file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+
+		logger.Info().Str("node", node).Str("storage", storage).Msgf("Uploading %s as %s", source, filename)
+		if err = pconf.Client.Upload(node, storage, "iso", filename, file); err != nil {
+			return fmt.Errorf("Uploading %s to %s failed: %v", source, storage, err)
+		}
+	} else {
+		params := map[string]interface{}{
+			"url":                 isoUrl,
+			"content":             "iso",
+			"filename":            filename,
+			"verify-certificates": d.Get("verify_certificates").(bool),
+		}
+		if checksum != "" {
+			params["checksum"] = checksum
+			params["checksum-algorithm"] = algorithm
+		}
+
+		logger.Info().Str("node", node).Str("storage", storage).Msgf("Downloading %s as %s", isoUrl, filename)
+		if _, err := apiPostTask(pconf.Session, pconf.Client, apiPath("nodes", node, "storage", storage, "download-url"), params); err != nil {
+			return fmt.Errorf("Downloading %s to %s failed: %v", isoUrl, storage, err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s:iso/%s", node, storage, filename))
+	return _resourceIsoRead(d, meta)
+}
+
+func resourceIsoRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceIsoRead(d, meta)
+}
+
+func _resourceIsoRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	storage := strings.SplitN(volid, ":", 2)[0]
+
+	volume, err := apiGetVolume(pconf.Session, node, volid, "iso")
+	if err != nil {
+		return err
+	}
+	// the image or its storage was removed outside of terraform
+	if volume == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", node)
+	d.Set("storage", storage)
+	d.Set("filename", strings.TrimPrefix(volid, storage+":iso/"))
+	d.Set("volid", volid)
+	d.Set("size", apiInt(volume["size"]))
+	return nil
+}
+
+func resourceIsoDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
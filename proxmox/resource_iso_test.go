@@ -0,0 +1,52 @@
+package proxmox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsoFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		url      string
+		expected string
+	}{
+		{"source", "/tmp/images/debian-11.0.0-amd64-netinst.iso", "", "debian-11.0.0-amd64-netinst.iso"},
+		{"url", "", "https://cdimage.debian.org/debian-cd/current/amd64/iso-cd/debian-11.0.0-amd64-netinst.iso?mirror=1", "debian-11.0.0-amd64-netinst.iso"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if filename := isoFilename(test.source, test.url); filename != test.expected {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.expected, filename)
+			}
+		})
+	}
+}
+
+func TestFileChecksum(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		expected  string
+	}{
+		{"md5", "5d41402abc4b2a76b9719d911017c592"},
+		{"sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.algorithm, func(*testing.T) {
+			checksum, err := fileChecksum(strings.NewReader("hello"), test.algorithm)
+			if err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.algorithm, err)
+			}
+			if checksum != test.expected {
+				t.Errorf("%s: expected `%s`, got `%s`", test.algorithm, test.expected, checksum)
+			}
+		})
+	}
+
+	if _, err := fileChecksum(strings.NewReader("hello"), "crc32"); err == nil {
+		t.Errorf("expected an error for an unknown algorithm")
+	}
+}
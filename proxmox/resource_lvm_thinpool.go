@@ -0,0 +1,179 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLvmThinpool() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an LVM thin pool on an unused disk of a node, in a volume group of the same name.",
+
+		Create:   resourceLvmThinpoolCreate,
+		Read:     resourceLvmThinpoolRead,
+		Delete:   resourceLvmThinpoolDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disk of the thin pool is in",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxDiskStorageName, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The name of the thin pool and its volume group, also the id of the storage when `add_storage` is set",
+			},
+			"device": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/sdb"),
+				Description:  "The path of the disk of the thin pool, e.g. `/dev/sdb`",
+			},
+			"add_storage": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether an lvmthin storage of the thin pool is added to the cluster",
+			},
+			"volume_group": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume group of the thin pool",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the thin pool in bytes",
+			},
+			"metadata_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the metadata of the thin pool in bytes",
+			},
+		},
+	}
+}
+
+// The thin pool called name in list, the thin pools of a node as listed by
+// /nodes/{node}/disks/lvmthin, nil when there is none.
+func lvmThinpoolFind(list []interface{}, name string) map[string]interface{} {
+	for _, item := range list {
+		if pool, ok := item.(map[string]interface{}); ok && apiString(pool["lv"]) == name {
+			return pool
+		}
+	}
+	return nil
+}
+
+func resourceLvmThinpoolCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	device := d.Get("device").(string)
+
+	logger, _ := CreateSubLogger("resource_lvm_thinpool_create")
+	logger.Info().Str("node", node).Msgf("Creating LVM thin pool %s on %s", name, device)
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "disks", "lvmthin"), map[string]interface{}{
+		"name":        name,
+		"device":      device,
+		"add_storage": d.Get("add_storage").(bool),
+	}); err != nil {
+		return fmt.Errorf("Creating the LVM thin pool %s on %s failed: %v", name, node, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceLvmThinpoolRead(d, meta)
+}
+
+func resourceLvmThinpoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceLvmThinpoolRead(d, meta)
+}
+
+// The device of a thin pool only applies when creating it, it is kept as configured.
+func _resourceLvmThinpoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_lvm_thinpool_read")
+	logger.Info().Str("node", node).Msgf("Reading LVM thin pool %s", name)
+
+	data, err := apiGet(pconf.Session, apiPath("nodes", node, "disks", "lvmthin"))
+	if err != nil {
+		return err
+	}
+	list, _ := data.([]interface{})
+	pool := lvmThinpoolFind(list, name)
+	if pool == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("volume_group", apiString(pool["vg"]))
+	d.Set("size", apiInt(pool["lv_size"]))
+	d.Set("metadata_size", apiInt(pool["metadata_size"]))
+	return nil
+}
+
+// The disk of the thin pool is wiped, so it can be used again, and the storage of the thin pool
+// is removed with it. Proxmox removes the volume group when the thin pool was its only volume.
+func resourceLvmThinpoolDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	vg := d.Get("volume_group").(string)
+	if vg == "" {
+		vg = name
+	}
+
+	logger, _ := CreateSubLogger("resource_lvm_thinpool_delete")
+	logger.Info().Str("node", node).Msgf("Destroying LVM thin pool %s/%s", vg, name)
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "disks", "lvmthin", name)+"?volume-group="+vg+"&cleanup-config=1&cleanup-disks=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Destroying the LVM thin pool %s on %s failed: %v", name, node, err)
+	}
+	return nil
+}
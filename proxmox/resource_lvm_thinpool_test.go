@@ -0,0 +1,16 @@
+package proxmox
+
+import "testing"
+
+func TestLvmThinpoolFind(t *testing.T) {
+	list := []interface{}{
+		map[string]interface{}{"lv": "data", "vg": "pve", "lv_size": float64(300)},
+		map[string]interface{}{"lv": "fast", "vg": "fast", "lv_size": float64(900), "metadata_size": float64(9)},
+	}
+	if pool := lvmThinpoolFind(list, "fast"); pool == nil || apiString(pool["vg"]) != "fast" {
+		t.Errorf("expected the thin pool fast, got %v", pool)
+	}
+	if pool := lvmThinpoolFind(list, "pve"); pool != nil {
+		t.Errorf("expected no thin pool, got %v", pool)
+	}
+}
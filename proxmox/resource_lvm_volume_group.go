@@ -0,0 +1,169 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLvmVolumeGroup() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an LVM volume group on an unused disk of a node.",
+
+		Create:   resourceLvmVolumeGroupCreate,
+		Read:     resourceLvmVolumeGroupRead,
+		Delete:   resourceLvmVolumeGroupDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disk of the volume group is in",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxDiskStorageName, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The name of the volume group, also the id of the storage when `add_storage` is set",
+			},
+			"device": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/sdb"),
+				Description:  "The path of the disk of the volume group, e.g. `/dev/sdb`",
+			},
+			"add_storage": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether an lvm storage of the volume group is added to the cluster",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the volume group in bytes",
+			},
+			"free": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The free space of the volume group in bytes",
+			},
+		},
+	}
+}
+
+// The volume group called name in tree, the volume groups of a node as listed by
+// /nodes/{node}/disks/lvm, nil when there is none. The volume groups are the children of the tree.
+func lvmVolumeGroupFind(tree map[string]interface{}, name string) map[string]interface{} {
+	children, _ := tree["children"].([]interface{})
+	for _, child := range children {
+		if vg, ok := child.(map[string]interface{}); ok && apiString(vg["name"]) == name {
+			return vg
+		}
+	}
+	return nil
+}
+
+func resourceLvmVolumeGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	device := d.Get("device").(string)
+
+	logger, _ := CreateSubLogger("resource_lvm_volume_group_create")
+	logger.Info().Str("node", node).Msgf("Creating LVM volume group %s on %s", name, device)
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "disks", "lvm"), map[string]interface{}{
+		"name":        name,
+		"device":      device,
+		"add_storage": d.Get("add_storage").(bool),
+	}); err != nil {
+		return fmt.Errorf("Creating the LVM volume group %s on %s failed: %v", name, node, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceLvmVolumeGroupRead(d, meta)
+}
+
+func resourceLvmVolumeGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceLvmVolumeGroupRead(d, meta)
+}
+
+// The device of a volume group only applies when creating it, it is kept as configured.
+func _resourceLvmVolumeGroupRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_lvm_volume_group_read")
+	logger.Info().Str("node", node).Msgf("Reading LVM volume group %s", name)
+
+	tree, err := apiGetMap(pconf.Session, apiPath("nodes", node, "disks", "lvm"))
+	if err != nil {
+		return err
+	}
+	vg := lvmVolumeGroupFind(tree, name)
+	if vg == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("size", apiInt(vg["size"]))
+	d.Set("free", apiInt(vg["free"]))
+	return nil
+}
+
+// The disk of the volume group is wiped, so it can be used again, and the storage of the volume
+// group is removed with it.
+func resourceLvmVolumeGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_lvm_volume_group_delete")
+	logger.Info().Str("node", node).Msgf("Destroying LVM volume group %s", name)
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "disks", "lvm", name)+"?cleanup-config=1&cleanup-disks=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Destroying the LVM volume group %s on %s failed: %v", name, node, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package proxmox
+
+import "testing"
+
+func TestLvmVolumeGroupFind(t *testing.T) {
+	tree := map[string]interface{}{
+		"leaf": float64(0),
+		"children": []interface{}{
+			map[string]interface{}{"name": "pve", "size": float64(500), "free": float64(16)},
+			map[string]interface{}{"name": "data", "size": float64(1000), "free": float64(1000), "children": []interface{}{
+				map[string]interface{}{"name": "/dev/sdb", "size": float64(1000), "free": float64(1000)},
+			}},
+		},
+	}
+	if vg := lvmVolumeGroupFind(tree, "data"); vg == nil || apiInt(vg["size"]) != 1000 {
+		t.Errorf("expected the volume group data, got %v", vg)
+	}
+	if vg := lvmVolumeGroupFind(tree, "/dev/sdb"); vg != nil {
+		t.Errorf("expected no volume group, got %v", vg)
+	}
+	if vg := lvmVolumeGroupFind(map[string]interface{}{"leaf": float64(0)}, "data"); vg != nil {
+		t.Errorf("expected no volume group, got %v", vg)
+	}
+}
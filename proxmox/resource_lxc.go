@@ -8,7 +8,9 @@ import (
 	"time"
 
 	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var lxcResourceDef *schema.Resource
@@ -17,6 +19,8 @@ func resourceLxc() *schema.Resource {
 	*pxapi.Debug = true
 
 	lxcResourceDef = &schema.Resource{
+		Description: "Manages an LXC container.",
+
 		Create: resourceLxcCreate,
 		Read:   resourceLxcRead,
 		Update: resourceLxcUpdate,
@@ -24,59 +28,89 @@ func resourceLxc() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: customdiff.All(placementCustomizeDiff, namingCustomizeDiff("hostname")),
 
 		Schema: map[string]*schema.Schema{
 			"ostemplate": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"clone", "restore_from"},
+				Description:   "The [volume identifier](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_volumes) that points to the OS template or backup file, or the HTTP(S) URL of a template which is downloaded to `ostemplate_storage`.",
 			},
 			"arch": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "amd64",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "amd64",
+				Description: "Sets the container OS architecture type. Default is `\"amd64\"`.",
 			},
 			"bwlimit": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "A number for setting the override I/O bandwidth limit (in KiB/s).",
 			},
 			"clone": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"ostemplate", "restore_from"},
+				Description:   "The VMID of the container to clone.",
+			},
+			"restore_from": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"clone", "ostemplate", "restore"},
+				Description:   "The volume id of a backup the container is restored from, e.g. `pbs:backup/ct/100/2024-01-01T00:00:00Z`. The configuration of the resource overrides the one of the backup.",
+			},
+			"restore_storage": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"restore_from"},
+				Description:  "The storage the volumes of the restored container are created on, defaults to `local` like for pct restore",
 			},
 			"clone_storage": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"clone"},
+				Description:  "Target storage for full clone.",
 			},
 			"cmode": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "tty",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "tty",
+				Description: "Configures console mode. `\"tty\"` tries to open a connection to one of the available tty devices. `\"console\"` tries to attach to `/dev/console` instead. `\"shell\"` simply invokes a shell inside the container (no login). Default is `\"tty\"`.",
 			},
 			"console": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "A boolean to attach a console device to the container. Default is `true`.",
 			},
 			"cores": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The number of cores assigned to the container. A container can use all available cores by default.",
 			},
 			"cpulimit": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "A number to limit CPU usage by. Default is `0`.",
 			},
 			"cpuunits": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  1024,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1024,
+				Description: "A number of the CPU weight that the container possesses. Default is `1024`.",
 			},
 			"description": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Sets the container description seen in the web interface.",
 			},
 			"features": {
 				Type:     schema.TypeSet,
@@ -85,60 +119,71 @@ func resourceLxc() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"fuse": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "A boolean for enabling FUSE mounts.",
 						},
 						"keyctl": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "A boolean for enabling the `keyctl()` system call.",
 						},
 						"mount": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Defines the filesystem types (separated by semi-colons) that are allowed to be mounted.",
 						},
 						"nesting": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "A boolean to allow nested virtualization.",
 						},
 					},
 				},
+				Description: "An object for allowing the container to access advanced features.",
 			},
 			"full": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"clone"},
+				Description:  "When cloning, create a full copy of all disks. This is always done when you clone a normal CT. For CT template it creates a linked clone by default.",
 			},
 			"force": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "A boolean that allows the overwriting of pre-existing containers.",
 			},
 			"hastate": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Requested HA state for the resource. One of \"started\", \"stopped\", \"enabled\", \"disabled\", or \"ignored\". See the [docs about HA](https://pve.proxmox.com/pve-docs/chapter-ha-manager.html#ha_manager_resource_config) for more info.",
 			},
 			"hookscript": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A string containing [a volume identifier to a script](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_hookscripts_2) that will be executed during various steps throughout the container's lifetime. The script must be an executable file.",
 			},
 			"hostname": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the host name of the container.",
 			},
 			"ignore_unpack_errors": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "A boolean that determines if template extraction errors are ignored during container creation.",
 			},
 			"lock": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"tags": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A string for locking or unlocking the container.",
 			},
+			"tags": tagsSchema("Tags of the container, separated by ;"),
 			"memory": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  512,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     512,
+				Description: "A number containing the amount of RAM to assign to the container (in MB).",
 			},
 			"mountpoint": {
 				Type:     schema.TypeList,
@@ -147,45 +192,54 @@ func resourceLxc() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						// Total Hackery here. A TypeMap would be amazing if it supported Resources as values...
 						"key": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The number that identifies the mount point (i.e. the `n` in [`mp[n]`](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#pct_mount_points)).",
 						},
 						"slot": {
-							Type:     schema.TypeInt,
-							Required: true,
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "A string containing the number that identifies the mount point (i.e. the `n` in [`mp[n]`](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#pct_mount_points)).",
 						},
 						"storage": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "A string containing the [volume](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_storage_backed_mount_points), [directory](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_bind_mount_points), or [device](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_device_mount_points) to be mounted into the container (at the path specified by `mp`). E.g. `local-lvm`, `local-zfs`, `local` etc.",
 						},
 						"mp": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The path to the mount point as seen from inside the container. The path must not contain symlinks for security reasons.",
 						},
 						"acl": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "A boolean for enabling ACL support. Default is `false`.",
 						},
 						"backup": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "A boolean for including the mount point in backups. Default is `false`.",
 						},
 						"quota": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "A boolean for enabling user quotas inside the container for this mount point. Default is `false`.",
 						},
 						"replicate": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "A boolean for including this volume in a storage replica job. Default is `false`.",
 						},
 						"shared": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "A boolean for marking the volume as available on all nodes. Default is `false`.",
 						},
 						"size": {
 							Type:     schema.TypeString,
@@ -197,23 +251,28 @@ func resourceLxc() *schema.Resource {
 								}
 								return
 							},
+							Description: "Size of the underlying volume. Must end in G, M, or K (e.g. `\"1G\"`, `\"1024M\"`, `\"1048576K\"`). Note that this is a read only value.",
 						},
 						"volume": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The volume of the mount point, e.g. `local-lvm:vm-100-disk-1`.",
 						},
 						"file": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The file name of the volume of the mount point, computed from `storage`.",
 						},
 					},
 				},
+				Description: "An object for defining a volume to use as a container mount point. Can be specified multiple times.",
 			},
 			"nameserver": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The DNS server IP address used by the container. If neither `nameserver` nor `searchdomain` are specified, the values of the Proxmox host will be used by default.",
 			},
 			"network": {
 				Type:     schema.TypeList,
@@ -221,92 +280,112 @@ func resourceLxc() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the network interface as seen from inside the container (e.g. `\"eth0\"`).",
 						},
 						"bridge": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The bridge to attach the network interface to (e.g. `\"vmbr0\"`).",
 						},
 						"firewall": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "A boolean to enable the firewall on the network interface.",
 						},
 						"gw": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The IPv4 address belonging to the network interface's default gateway.",
 						},
 						"gw6": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The IPv6 address of the network interface's default gateway.",
 						},
 						"hwaddr": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "A string to set a common MAC address with the I/G (Individual/Group) bit not set. Automatically determined if not set.",
 						},
 						"ip": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The IPv4 address of the network interface. Can be a static IPv4 address (in CIDR notation), `\"dhcp\"`, or `\"manual\"`.",
 						},
 						"ip6": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The IPv6 address of the network interface. Can be a static IPv6 address (in CIDR notation), `\"auto\"`, `\"dhcp\"`, or `\"manual\"`.",
 						},
 						"mtu": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "A string to set the MTU on the network interface.",
 						},
 						"rate": {
-							Type:     schema.TypeInt,
-							Optional: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "A number that sets rate limiting on the network interface (Mbps).",
 						},
 						"tag": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "A number that specifies the VLAN tag of the network interface. Automatically determined if not set.",
 						},
 						"trunks": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The VLAN tags passed through the network interface, separated by `;`.",
 						},
 						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The type of the network interface, `veth`.",
 						},
 					},
 				},
+				Description: "An object defining a network interface for the container. Can be specified multiple times.",
 			},
 			"onboot": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "A boolean that determines if the container will start on boot. Default is `false`.",
 			},
 			"ostype": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The operating system type, used by LXC to setup and configure the container. Automatically determined if not set.",
 			},
 			"password": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
-				ForceNew:  true, // Proxmox doesn't support password changes
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				ForceNew:    true, // Proxmox doesn't support password changes
+				Description: "Sets the root password inside the container.",
 			},
 			"pool": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the Proxmox resource pool to add this container to.",
 			},
 			"protection": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "A boolean that enables the protection flag on this container. Stops the container and its disk from being removed/updated. Default is `false`.",
 			},
 			"restore": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "A boolean to mark the container creation/update as a restore task.",
 			},
 			"rootfs": {
 				Type:     schema.TypeList,
@@ -315,9 +394,10 @@ func resourceLxc() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"storage": &schema.Schema{
-							Type:     schema.TypeString,
-							ForceNew: true,
-							Required: true,
+							Type:        schema.TypeString,
+							ForceNew:    true,
+							Required:    true,
+							Description: "A string containing the [volume](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_storage_backed_mount_points), [directory](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_bind_mount_points), or [device](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#_device_mount_points) to be mounted into the container (at the path specified by `mp`). E.g. `local-lvm`, `local-zfs`, `local` etc.",
 						},
 						"size": &schema.Schema{
 							Type:     schema.TypeString,
@@ -329,54 +409,73 @@ func resourceLxc() *schema.Resource {
 								}
 								return
 							},
+							Description: "Size of the underlying volume. Must end in G, M, or K (e.g. `\"1G\"`, `\"1024M\"`, `\"1048576K\"`). Note that this is a read only value.",
 						},
 						"volume": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The volume of the root mount point, e.g. `local-lvm:vm-100-disk-0`.",
 						},
 					},
 				},
+				Description: "An object for configuring the root mount point of the container. Can only be specified once.",
 			},
 			"searchdomain": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Sets the DNS search domains for the container. If neither `nameserver` nor `searchdomain` are specified, the values of the Proxmox host will be used by default.",
 			},
 			"ssh_public_keys": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Multi-line string of SSH public keys that will be added to the container. Can be defined using Terraform's [heredoc syntax](https://www.terraform.io/docs/configuration/expressions/strings.html#heredoc-strings).",
 			},
 			"start": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "A boolean that determines if the container is started after creation. Default is `false`.",
+			},
+			"start_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How often the start after creation is tried again when it fails because systemd or the cgroups of the container weren't ready in time.",
 			},
 			"startup": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The [startup and shutdown behaviour](https://pve.proxmox.com/pve-docs/pve-admin-guide.html#pct_startup_and_shutdown) of the container.",
 			},
 			"swap": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  512,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     512,
+				Description: "A number that sets the amount of swap memory available to the container. Default is `512`.",
 			},
 			"template": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "A boolean that determines if this container is a template.",
 			},
 			"tty": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  2,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     2,
+				Description: "A number that specifies the TTYs available to the container. Default is `2`.",
 			},
 			"unique": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "A boolean that determines if a unique random ethernet address is assigned to the container.",
 			},
 			"unprivileged": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "A boolean that makes the container run as an unprivileged user. Default is `false`.",
 			},
 			"unused": {
 				Type:     schema.TypeList,
@@ -384,20 +483,40 @@ func resourceLxc() *schema.Resource {
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
+				Description: "The volumes of the container which are not mounted.",
 			},
 			"target_node": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "A string containing the cluster node name. Not required when `target_nodes` or `place_with_vmid` is set.",
 			},
 			"vmid": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "A number that sets the VMID of the container. If set to `0`, the next available VMID is used. Default is `0`.",
 			},
+			"wait_for": lxcWaitSchema(),
 		},
 	}
 
+	for key, value := range resourceClientSchema() {
+		lxcResourceDef.Schema[key] = value
+	}
+	for key, value := range placementSchema() {
+		lxcResourceDef.Schema[key] = value
+	}
+	for key, value := range lxcOstemplateSchema() {
+		lxcResourceDef.Schema[key] = value
+	}
+	for key, value := range guestSummarySchema() {
+		lxcResourceDef.Schema[key] = value
+	}
+	for key, value := range guestDestroySchema() {
+		lxcResourceDef.Schema[key] = value
+	}
 	return lxcResourceDef
 }
 
@@ -407,7 +526,10 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
 
-	client := pconf.Client
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
 
 	config := pxapi.NewConfigLxc()
 	config.Ostemplate = d.Get("ostemplate").(string)
@@ -420,7 +542,7 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 	config.Cores = d.Get("cores").(int)
 	config.CPULimit = d.Get("cpulimit").(int)
 	config.CPUUnits = d.Get("cpuunits").(int)
-	config.Description = d.Get("description").(string)
+	config.Description = descriptionWithRunInfo(d.Get("description").(string), pconf.RunWorkspace, pconf.RunURL)
 	features := d.Get("features").(*schema.Set)
 	featureSetList := features.List()
 	if len(featureSetList) > 0 {
@@ -445,10 +567,11 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 	config.Restore = d.Get("restore").(bool)
 	config.SearchDomain = d.Get("searchdomain").(string)
 	config.SSHPublicKeys = d.Get("ssh_public_keys").(string)
-	config.Start = d.Get("start").(bool)
+	// the container is started after it was created, so the start can be tried again
+	config.Start = false
 	config.Startup = d.Get("startup").(string)
 	config.Swap = d.Get("swap").(int)
-	config.Tags = d.Get("tags").(string)
+	config.Tags = tagsWithAntiAffinity(d.Get("tags").(string), d.Get("anti_affinity_group").(string))
 	config.Template = d.Get("template").(bool)
 	config.Tty = d.Get("tty").(int)
 	config.Unique = d.Get("unique").(bool)
@@ -462,8 +585,6 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 	config.Unused = volumes
 
-	targetNode := d.Get("target_node").(string)
-
 	// proxmox api allows multiple network sets,
 	// having a unique 'id' parameter foreach set
 	networks := d.Get("network").([]interface{})
@@ -497,9 +618,34 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	targetNode, err := resolvePlacement(d, pconf, nextid)
+	if err != nil {
+		return err
+	}
+
 	vmr := pxapi.NewVmRef(nextid)
 	vmr.SetNode(targetNode)
 
+	if lxcOstemplateUrl(config.Ostemplate) {
+		config.Ostemplate, err = lxcDownloadOstemplate(pconf.Session, client, targetNode, d.Get("ostemplate_storage").(string), config.Ostemplate, d.Get("ostemplate_checksum").(string), d.Get("ostemplate_checksum_algorithm").(string))
+		if err != nil {
+			return err
+		}
+	}
+	d.Set("ostemplate_volid", config.Ostemplate)
+
+	// a restore is a create from the backup instead of a template, like pct restore
+	if archive := d.Get("restore_from").(string); archive != "" {
+		config.Ostemplate = archive
+		config.Restore = true
+		if storage := d.Get("restore_storage").(string); storage != "" {
+			config.Storage = storage
+		}
+		if config.BWLimit == 0 {
+			config.BWLimit = pconf.BwLimitRestore
+		}
+	}
+
 	if d.Get("clone").(string) != "" {
 
 		log.Print("[DEBUG] cloning LXC")
@@ -539,6 +685,19 @@ func resourceLxcCreate(d *schema.ResourceData, meta interface{}) error {
 	// The existence of a non-blank ID is what tells Terraform that a resource was created
 	d.SetId(resourceId(targetNode, "lxc", vmr.VmId()))
 
+	if d.Get("start").(bool) {
+		session, err := resourceSession(d, pconf)
+		if err != nil {
+			return err
+		}
+		if err = lxcStart(session, client, targetNode, vmr.VmId(), d.Get("start_retries").(int)); err != nil {
+			return err
+		}
+		if err = lxcWait(session, targetNode, vmr.VmId(), d.Get("wait_for").([]interface{})); err != nil {
+			return err
+		}
+	}
+
 	return _resourceLxcRead(d, meta)
 
 }
@@ -548,7 +707,10 @@ func resourceLxcUpdate(d *schema.ResourceData, meta interface{}) error {
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
 
-	client := pconf.Client
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
 
 	_, _, vmID, err := parseResourceId(d.Id())
 	if err != nil {
@@ -569,7 +731,7 @@ func resourceLxcUpdate(d *schema.ResourceData, meta interface{}) error {
 	config.Cores = d.Get("cores").(int)
 	config.CPULimit = d.Get("cpulimit").(int)
 	config.CPUUnits = d.Get("cpuunits").(int)
-	config.Description = d.Get("description").(string)
+	config.Description = descriptionWithRunInfo(d.Get("description").(string), pconf.RunWorkspace, pconf.RunURL)
 	features := d.Get("features").(*schema.Set)
 	featureSetList := features.List()
 	if len(featureSetList) > 0 {
@@ -595,7 +757,7 @@ func resourceLxcUpdate(d *schema.ResourceData, meta interface{}) error {
 	config.Start = d.Get("start").(bool)
 	config.Startup = d.Get("startup").(string)
 	config.Swap = d.Get("swap").(int)
-	config.Tags = d.Get("tags").(string)
+	config.Tags = tagsWithAntiAffinity(d.Get("tags").(string), d.Get("anti_affinity_group").(string))
 	config.Template = d.Get("template").(bool)
 	config.Tty = d.Get("tty").(int)
 	config.Unique = d.Get("unique").(bool)
@@ -626,7 +788,7 @@ func resourceLxcUpdate(d *schema.ResourceData, meta interface{}) error {
 		oldRootFs := oldSet.([]interface{})[0].(map[string]interface{})
 		newRootFs := newSet.([]interface{})[0].(map[string]interface{})
 
-		processLxcDiskChanges(DeviceToMap(oldRootFs, 0), DeviceToMap(newRootFs, 0), pconf, vmr)
+		processLxcDiskChanges(DeviceToMap(oldRootFs, 0), DeviceToMap(newRootFs, 0), client, vmr)
 		config.RootFs = newRootFs
 	}
 
@@ -634,7 +796,7 @@ func resourceLxcUpdate(d *schema.ResourceData, meta interface{}) error {
 		oldSet, newSet := d.GetChange("mountpoint")
 		oldMounts := DevicesListToMapByKey(oldSet.([]interface{}), "key")
 		newMounts := DevicesListToMapByKey(newSet.([]interface{}), "key")
-		processLxcDiskChanges(oldMounts, newMounts, pconf, vmr)
+		processLxcDiskChanges(oldMounts, newMounts, client, vmr)
 
 		lxcMountpoints := DevicesListToDevices(newSet.([]interface{}), "slot")
 		config.Mountpoints = lxcMountpoints
@@ -674,7 +836,10 @@ func resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 
 func _resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 	_, _, vmID, err := parseResourceId(d.Id())
 	if err != nil {
 		d.SetId("")
@@ -745,6 +910,16 @@ func _resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	// Summary
+	summaryDisks := []pxapi.QemuDevice{}
+	if config.RootFs != nil {
+		summaryDisks = append(summaryDisks, config.RootFs)
+	}
+	for _, mountpoint := range config.Mountpoints {
+		summaryDisks = append(summaryDisks, mountpoint)
+	}
+	setGuestSummary(d, summaryDisks, config.Memory, len(config.Networks))
+
 	// Pool
 	pools, err := client.GetPoolList()
 	if err == nil {
@@ -767,7 +942,7 @@ func _resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("cores", config.Cores)
 	d.Set("cpulimit", config.CPULimit)
 	d.Set("cpuunits", config.CPUUnits)
-	d.Set("description", config.Description)
+	d.Set("description", descriptionWithoutRunInfo(config.Description))
 	d.Set("force", config.Force)
 	d.Set("hastate", vmr.HaState)
 	d.Set("hookscript", config.Hookscript)
@@ -783,7 +958,8 @@ func _resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("searchdomain", config.SearchDomain)
 	d.Set("startup", config.Startup)
 	d.Set("swap", config.Swap)
-	d.Set("tags", config.Tags)
+	d.Set("tags", tagsWithoutAntiAffinity(config.Tags))
+	d.Set("anti_affinity_group", antiAffinityGroup(config.Tags))
 	d.Set("template", config.Template)
 	d.Set("tty", config.Tty)
 	d.Set("unique", config.Unique)
@@ -800,7 +976,7 @@ func _resourceLxcRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func processLxcDiskChanges(
-	prevDiskSet KeyedDeviceMap, newDiskSet KeyedDeviceMap, pconf *providerConfiguration,
+	prevDiskSet KeyedDeviceMap, newDiskSet KeyedDeviceMap, client *pxapi.Client,
 	vmr *pxapi.VmRef,
 ) error {
 	// 1. Delete slots that either a. Don't exist in the new set or b. Have a different volume in the new set
@@ -823,11 +999,11 @@ func processLxcDiskChanges(
 		params := map[string]interface{}{}
 		params["delete"] = strings.Join(deleteDiskKeys, ", ")
 		if vmr.GetVmType() == "lxc" {
-			if _, err := pconf.Client.SetLxcConfig(vmr, params); err != nil {
+			if _, err := client.SetLxcConfig(vmr, params); err != nil {
 				return err
 			}
 		} else {
-			if _, err := pconf.Client.SetVmConfig(vmr, params); err != nil {
+			if _, err := client.SetVmConfig(vmr, params); err != nil {
 				return err
 			}
 		}
@@ -854,11 +1030,11 @@ func processLxcDiskChanges(
 	}
 	if len(newParams) > 0 {
 		if vmr.GetVmType() == "lxc" {
-			if _, err := pconf.Client.SetLxcConfig(vmr, newParams); err != nil {
+			if _, err := client.SetLxcConfig(vmr, newParams); err != nil {
 				return err
 			}
 		} else {
-			if _, err := pconf.Client.SetVmConfig(vmr, newParams); err != nil {
+			if _, err := client.SetVmConfig(vmr, newParams); err != nil {
 				return err
 			}
 		}
@@ -873,12 +1049,12 @@ func processLxcDiskChanges(
 			newStorage, ok := newDisk["storage"].(string)
 			if ok && newStorage != prevDisk["storage"] {
 				if vmr.GetVmType() == "lxc" {
-					_, err := pconf.Client.MoveLxcDisk(vmr, diskSlotName(prevDisk), newStorage)
+					_, err := client.MoveLxcDisk(vmr, diskSlotName(prevDisk), newStorage)
 					if err != nil {
 						return err
 					}
 				} else {
-					_, err := pconf.Client.MoveQemuDisk(vmr, diskSlotName(prevDisk), newStorage)
+					_, err := client.MoveQemuDisk(vmr, diskSlotName(prevDisk), newStorage)
 					if err != nil {
 						return err
 					}
@@ -886,14 +1062,14 @@ func processLxcDiskChanges(
 			}
 
 			// 3. Resize disks with different sizes
-			if err := processDiskResize(prevDisk, newDisk, diskName, pconf, vmr); err != nil {
+			if err := processDiskResize(prevDisk, newDisk, diskName, client, vmr); err != nil {
 				return err
 			}
 		}
 	}
 
 	// Update Volume info
-	apiResult, err := pconf.Client.GetVmConfig(vmr)
+	apiResult, err := client.GetVmConfig(vmr)
 	if err != nil {
 		return err
 	}
@@ -922,12 +1098,12 @@ func diskSlotName(disk pxapi.QemuDevice) string {
 func processDiskResize(
 	prevDisk pxapi.QemuDevice, newDisk pxapi.QemuDevice,
 	diskName string,
-	pconf *providerConfiguration, vmr *pxapi.VmRef,
+	client *pxapi.Client, vmr *pxapi.VmRef,
 ) error {
 	newSize, ok := newDisk["size"]
 	if ok && newSize != prevDisk["size"] {
 		log.Print("[DEBUG] resizing disk " + diskName)
-		_, err := pconf.Client.ResizeQemuDiskRaw(vmr, diskName, newDisk["size"].(string))
+		_, err := client.ResizeQemuDiskRaw(vmr, diskName, newDisk["size"].(string))
 		if err != nil {
 			return err
 		}
@@ -10,7 +10,9 @@ import (
 
 func resourceLxcDisk() *schema.Resource {
 	*pxapi.Debug = true
-	return &schema.Resource{
+	lxcDiskResourceDef := &schema.Resource{
+		Description: "Manages a mount point of an LXC container as its own resource.",
+
 		Create: resourceLxcDiskCreate,
 		Read:   resourceLxcDiskRead,
 		Update: resourceLxcDiskUpdate,
@@ -22,46 +24,55 @@ func resourceLxcDisk() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"container": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the container the disk is mounted into, e.g. `pve1/lxc/100`.",
 			},
 			"slot": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The number that identifies the mount point, i.e. the `n` in `mp[n]`.",
 			},
 			"storage": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The storage the volume of the disk is created on, e.g. `local-lvm`.",
 			},
 			"mp": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The path to the mount point as seen from inside the container.",
 			},
 			"acl": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Enable ACL support on the mount point.",
 			},
 			"backup": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Include the mount point in backups.",
 			},
 			"quota": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Enable user quotas inside the container for the mount point.",
 			},
 			"replicate": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Include the volume in storage replication jobs.",
 			},
 			"shared": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Mark the volume as available on all nodes.",
 			},
 			"size": {
 				Type:     schema.TypeString,
@@ -73,6 +84,7 @@ func resourceLxcDisk() *schema.Resource {
 					}
 					return
 				},
+				Description: "The size of the volume, ending in G, M or K, e.g. `8G`.",
 			},
 			"mountoptions": {
 				Type:     schema.TypeList,
@@ -81,31 +93,52 @@ func resourceLxcDisk() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"noatime": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Do not update the access times of files.",
 						},
 						"nodev": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Do not interpret device files.",
 						},
 						"noexec": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Do not allow executing files.",
 						},
 						"nosuid": {
-							Type:     schema.TypeBool,
-							Optional: true,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Ignore the set-user-id and set-group-id bits of files.",
 						},
 					},
 				},
+				Description: "The mount options of the mount point.",
 			},
 			"volume": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The volume of the disk, e.g. `local-lvm:vm-100-disk-1`.",
 			},
 		},
 	}
+
+	// the disk is managed with the API token of its container, see resourceClient
+	for key, value := range resourceClientSchema() {
+		lxcDiskResourceDef.Schema[key] = value
+	}
+	return lxcDiskResourceDef
+}
+
+// The options of the disk in value, the arguments of the resource without the API token.
+func lxcDiskOptions(value interface{}) map[string]interface{} {
+	disk := value.(map[string]interface{})
+	for key := range resourceClientSchema() {
+		delete(disk, key)
+	}
+	return disk
 }
 
 func resourceLxcDiskCreate(d *schema.ResourceData, meta interface{}) error {
@@ -119,7 +152,10 @@ func resourceLxcDiskCreate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 	vmr := pxapi.NewVmRef(vmID)
 	vmr.SetVmType("lxc")
 	_, err = client.GetVmInfo(vmr)
@@ -127,7 +163,7 @@ func resourceLxcDiskCreate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	disk := d.Get("").(map[string]interface{})
+	disk := lxcDiskOptions(d.Get(""))
 
 	if mountoptions, ok := disk["mountoptions"]; ok {
 		if len(mountoptions.([]interface{})) > 0 {
@@ -140,7 +176,7 @@ func resourceLxcDiskCreate(d *schema.ResourceData, meta interface{}) error {
 	params := map[string]interface{}{}
 	mpName := fmt.Sprintf("mp%v", d.Get("slot").(int))
 	params[mpName] = pxapi.FormatDiskParam(disk)
-	exitStatus, err := pconf.Client.SetLxcConfig(vmr, params)
+	exitStatus, err := client.SetLxcConfig(vmr, params)
 	if err != nil {
 		return fmt.Errorf("Error updating LXC Mountpoint: %v, error status: %s (params: %v)", err, exitStatus, params)
 	}
@@ -157,7 +193,10 @@ func resourceLxcDiskUpdate(d *schema.ResourceData, meta interface{}) error {
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
 
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 
 	_, _, vmID, err := parseResourceId(d.Get("container").(string))
 	if err != nil {
@@ -171,11 +210,11 @@ func resourceLxcDiskUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	oldValue, newValue := d.GetChange("")
-	oldDisk := extractDiskOptions(oldValue.(map[string]interface{}))
-	newDisk := extractDiskOptions(newValue.(map[string]interface{}))
+	oldDisk := extractDiskOptions(lxcDiskOptions(oldValue))
+	newDisk := extractDiskOptions(lxcDiskOptions(newValue))
 
 	// Apply Changes
-	err = processLxcDiskChanges(DeviceToMap(oldDisk, 0), DeviceToMap(newDisk, 0), pconf, vmr)
+	err = processLxcDiskChanges(DeviceToMap(oldDisk, 0), DeviceToMap(newDisk, 0), client, vmr)
 	if err != nil {
 		return fmt.Errorf("Error updating LXC Mountpoint: %v", err)
 	}
@@ -192,7 +231,10 @@ func resourceLxcDiskRead(d *schema.ResourceData, meta interface{}) error {
 
 func _resourceLxcDiskRead(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 
 	_, _, vmID, err := parseResourceId(d.Get("container").(string))
 	if err != nil {
@@ -241,7 +283,10 @@ func resourceLxcDiskDelete(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 	vmr := pxapi.NewVmRef(vmID)
 	_, err = client.GetVmInfo(vmr)
 	if err != nil {
@@ -250,7 +295,7 @@ func resourceLxcDiskDelete(d *schema.ResourceData, meta interface{}) error {
 
 	params := map[string]interface{}{}
 	params["delete"] = fmt.Sprintf("mp%v", d.Get("slot").(int))
-	if exitStatus, err := pconf.Client.SetLxcConfig(vmr, params); err != nil {
+	if exitStatus, err := client.SetLxcConfig(vmr, params); err != nil {
 		return fmt.Errorf("Error deleting LXC Mountpoint: %v, error status: %s (params: %v)", err, exitStatus, params)
 	}
 
@@ -0,0 +1,155 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLxcSnapshot() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a named snapshot of a container, which changing `rollback_triggers` rolls the container back to.",
+
+		Create:   resourceLxcSnapshotCreate,
+		Read:     resourceLxcSnapshotRead,
+		Update:   resourceLxcSnapshotUpdate,
+		Delete:   resourceLxcSnapshotDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the container to snapshot",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: snapshotNameValidation,
+				Description:  "The name of the snapshot",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the snapshot",
+			},
+			"rollback_triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Changing a value rolls the container back to the snapshot",
+			},
+			"start_after_rollback": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start the container after it was rolled back, the rollback stops a running container",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node of the container",
+			},
+			"snaptime": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the snapshot was taken, as a unix timestamp",
+			},
+			"parent": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the snapshot this snapshot was taken after, empty for the first snapshot",
+			},
+		},
+	}
+}
+
+func resourceLxcSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if err := createGuestSnapshot(d, pconf, "lxc", map[string]interface{}{}); err != nil {
+		return err
+	}
+	return _resourceLxcSnapshotRead(d, meta)
+}
+
+func resourceLxcSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceLxcSnapshotRead(d, meta)
+}
+
+func _resourceLxcSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, err := readGuestSnapshot(d, pconf, "lxc")
+	return err
+}
+
+// Updates the description and rolls the container back when rollback_triggers changed.
+func resourceLxcSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if d.HasChange("description") {
+		if err := updateGuestSnapshotDescription(d, pconf, "lxc"); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("rollback_triggers") {
+		if err := rollbackLxcSnapshot(d, pconf); err != nil {
+			return err
+		}
+	}
+	return _resourceLxcSnapshotRead(d, meta)
+}
+
+func rollbackLxcSnapshot(d *schema.ResourceData, pconf *providerConfiguration) error {
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	vmid, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		return err
+	}
+	vmr, err := snapshotGuest(pconf.Session, vmid, "lxc")
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	if d.Get("start_after_rollback").(bool) {
+		params["start"] = true
+	}
+
+	logger, _ := CreateSubLogger("resource_lxc_snapshot_update")
+	logger.Info().Int("vmid", vmid).Str("name", name).Msg("Rolling back container to snapshot")
+
+	if _, err = apiPostTask(pconf.Session, client, snapshotPath(vmr, name, "rollback"), params); err != nil {
+		return fmt.Errorf("Rolling back container %d to the snapshot %s failed: %v", vmid, name, err)
+	}
+	return nil
+}
+
+func resourceLxcSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	return deleteGuestSnapshot(d, pconf, "lxc")
+}
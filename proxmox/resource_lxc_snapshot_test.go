@@ -0,0 +1,26 @@
+package proxmox
+
+import (
+	"testing"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+func TestLxcSnapshotPath(t *testing.T) {
+	vmr := pxapi.NewVmRef(101)
+	vmr.SetNode("pve")
+	vmr.SetVmType("lxc")
+	if path := snapshotPath(vmr, "clean", "rollback"); path != "/nodes/pve/lxc/101/snapshot/clean/rollback" {
+		t.Errorf("unexpected path %s", path)
+	}
+}
+
+// changing rollback_triggers must roll back the container instead of replacing the snapshot
+func TestLxcSnapshotRollbackTriggersInPlace(t *testing.T) {
+	resource := resourceLxcSnapshot()
+	for _, key := range []string{"rollback_triggers", "start_after_rollback", "description"} {
+		if resource.Schema[key].ForceNew {
+			t.Errorf("%s must not replace the snapshot", key)
+		}
+	}
+}
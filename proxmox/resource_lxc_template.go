@@ -0,0 +1,189 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var lxcTemplateResourceDef *schema.Resource
+
+func resourceLxcTemplate() *schema.Resource {
+	*pxapi.Debug = true
+
+	lxcTemplateResourceDef = &schema.Resource{
+		Description: "Downloads a container template of the Proxmox appliance list into a storage.",
+
+		Create: resourceLxcTemplateCreate,
+		Read:   resourceLxcTemplateRead,
+		Delete: resourceLxcTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node downloading the template",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the template is stored on, it must have the vztmpl content type",
+			},
+			"template": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The file name of the template as listed by pveam available, or its package name like debian-12-standard for the latest version",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The file name of the downloaded template",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the template, usable as the ostemplate of a proxmox_lxc",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the template in bytes.",
+			},
+		},
+	}
+
+	return lxcTemplateResourceDef
+}
+
+var rxVersionNumbers = regexp.MustCompile(`\d+`)
+
+// compares versions like 12.2-1 by their numbers, 12.10-1 is newer than 12.9-3
+func compareVersions(a string, b string) int {
+	numbersA := rxVersionNumbers.FindAllString(a, -1)
+	numbersB := rxVersionNumbers.FindAllString(b, -1)
+	for i := 0; i < len(numbersA) && i < len(numbersB); i++ {
+		first, _ := strconv.Atoi(numbersA[i])
+		second, _ := strconv.Atoi(numbersB[i])
+		if first != second {
+			return first - second
+		}
+	}
+	return len(numbersA) - len(numbersB)
+}
+
+// Finds template in the appliance list of a node, either by its file name or by its
+// package name, which selects the latest version of the package.
+func resolveLxcTemplate(appliances interface{}, template string) (string, error) {
+	latest, latestVersion := "", ""
+	list, _ := appliances.([]interface{})
+	for _, item := range list {
+		appliance, _ := item.(map[string]interface{})
+		if apiString(appliance["template"]) == template {
+			return template, nil
+		}
+		if apiString(appliance["package"]) != template {
+			continue
+		}
+		if version := apiString(appliance["version"]); latest == "" || compareVersions(version, latestVersion) > 0 {
+			latest, latestVersion = apiString(appliance["template"]), version
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("Template %s is not available, see pveam available", template)
+	}
+	return latest, nil
+}
+
+func resourceLxcTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	storage := d.Get("storage").(string)
+
+	appliances, err := apiGet(pconf.Session, apiPath("nodes", node, "aplinfo"))
+	if err != nil {
+		return err
+	}
+	filename, err := resolveLxcTemplate(appliances, d.Get("template").(string))
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_lxc_template_create")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Downloading template %s", filename)
+
+	_, err = apiPostTask(pconf.Session, pconf.Client, apiPath("nodes", node, "aplinfo"), map[string]interface{}{
+		"storage":  storage,
+		"template": filename,
+	})
+	if err != nil {
+		return fmt.Errorf("Downloading template %s to %s failed: %v", filename, storage, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s:vztmpl/%s", node, storage, filename))
+	return _resourceLxcTemplateRead(d, meta)
+}
+
+func resourceLxcTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceLxcTemplateRead(d, meta)
+}
+
+func _resourceLxcTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	storage := strings.SplitN(volid, ":", 2)[0]
+
+	volume, err := apiGetVolume(pconf.Session, node, volid, "vztmpl")
+	if err != nil {
+		return err
+	}
+	// the template or its storage was removed outside of terraform
+	if volume == nil {
+		d.SetId("")
+		return nil
+	}
+
+	filename := strings.TrimPrefix(volid, storage+":vztmpl/")
+	d.Set("node", node)
+	d.Set("storage", storage)
+	// a package name resolved to this file is kept, an imported template has none
+	if d.Get("template").(string) == "" {
+		d.Set("template", filename)
+	}
+	d.Set("filename", filename)
+	d.Set("volid", volid)
+	d.Set("size", apiInt(volume["size"]))
+	return nil
+}
+
+func resourceLxcTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
@@ -0,0 +1,38 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestResolveLxcTemplate(t *testing.T) {
+	appliances := []interface{}{
+		map[string]interface{}{"package": "debian-12-standard", "version": "12.9-1", "template": "debian-12-standard_12.9-1_amd64.tar.zst"},
+		map[string]interface{}{"package": "debian-12-standard", "version": "12.10-1", "template": "debian-12-standard_12.10-1_amd64.tar.zst"},
+		map[string]interface{}{"package": "debian-12-standard", "version": "12.2-1", "template": "debian-12-standard_12.2-1_amd64.tar.zst"},
+		map[string]interface{}{"package": "alpine-3.18-default", "version": "3.18-1", "template": "alpine-3.18-default_20230607_amd64.tar.xz"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		err      bool
+	}{
+		{name: "file name", template: "debian-12-standard_12.2-1_amd64.tar.zst", expected: "debian-12-standard_12.2-1_amd64.tar.zst"},
+		{name: "latest version of a package", template: "debian-12-standard", expected: "debian-12-standard_12.10-1_amd64.tar.zst"},
+		{name: "single version", template: "alpine-3.18-default", expected: "alpine-3.18-default_20230607_amd64.tar.xz"},
+		{name: "not available", template: "ubuntu-22.04-standard", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			template, err := resolveLxcTemplate(appliances, test.template)
+			if (err != nil) != test.err {
+				t.Fatalf("%s: expected error %v, got `%+v`", test.name, test.err, err)
+			}
+			if template != test.expected {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.expected, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The hardware resource mappings of /cluster/mapping/<type> give devices a cluster wide name,
+// guests refer to the name instead of the device of one node, e.g. with
+// hostpci0: mapping=gpu, and can migrate to every node the mapping has a device on.
+// fields maps the attributes of the device blocks to the keys of the map property strings,
+// options the attributes of the mapping to its parameters.
+
+// The blocks of the mapping on each node are devices, except for directory mappings.
+func mappingBlock(mappingType string) string {
+	if mappingType == "dir" {
+		return "directory"
+	}
+	return "device"
+}
+
+func mappingDeviceSchema(mappingType string, deviceSchema map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    true,
+		MinItems:    1,
+		Elem:        &schema.Resource{Schema: deviceSchema},
+		Description: fmt.Sprintf("The %s of the mapping on each node, at most one per node", mappingBlock(mappingType)),
+	}
+}
+
+// The map property strings of the device blocks, e.g. node=pve1,path=0000:01:00.0,id=10de:2204.
+// Integer fields are left out when they are negative.
+func mappingDevices(devices []interface{}, fields map[string]string) []string {
+	attributes := make([]string, 0, len(fields))
+	for attribute := range fields {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	result := []string{}
+	for _, device := range devices {
+		device, _ := device.(map[string]interface{})
+		values := []string{}
+		for _, attribute := range attributes {
+			switch value := device[attribute].(type) {
+			case int:
+				if value >= 0 {
+					values = append(values, fmt.Sprintf("%s=%d", fields[attribute], value))
+				}
+			case string:
+				if value != "" {
+					values = append(values, fmt.Sprintf("%s=%s", fields[attribute], value))
+				}
+			}
+		}
+		result = append(result, strings.Join(values, ","))
+	}
+	return result
+}
+
+// The device blocks of the map property strings of a mapping, the reverse of mappingDevices.
+func parseMappingDevices(value interface{}, deviceSchema map[string]*schema.Schema, fields map[string]string) []interface{} {
+	devices := []interface{}{}
+	for _, property := range apiStringList(value, "") {
+		conf := apiPropertyString(property, "")
+		device := map[string]interface{}{}
+		for attribute, field := range fields {
+			if deviceSchema[attribute].Type == schema.TypeInt {
+				device[attribute] = -1
+				if value, ok := conf[field]; ok {
+					device[attribute] = apiInt(value)
+				}
+			} else {
+				device[attribute] = apiString(conf[field])
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+func checkMappingDevices(mappingType string, devices []interface{}) error {
+	nodes := map[string]bool{}
+	for _, device := range devices {
+		node := device.(map[string]interface{})["node"].(string)
+		if nodes[node] {
+			return fmt.Errorf("The mapping has more than one %s on node %s", mappingBlock(mappingType), node)
+		}
+		nodes[node] = true
+	}
+	return nil
+}
+
+// The parameters of the mapping, an empty description is returned as the parameter to delete.
+func mappingParams(d *schema.ResourceData, mappingType string, fields map[string]string, options map[string]string) (params map[string]interface{}, deletes []string, err error) {
+	devices := d.Get(mappingBlock(mappingType)).([]interface{})
+	if err = checkMappingDevices(mappingType, devices); err != nil {
+		return nil, nil, err
+	}
+	params = map[string]interface{}{
+		"map": mappingDevices(devices, fields),
+	}
+	deletes = []string{}
+	for attribute, parameter := range options {
+		params[parameter] = d.Get(attribute).(bool)
+	}
+	if description := d.Get("description").(string); description != "" {
+		params["description"] = description
+	} else {
+		deletes = append(deletes, "description")
+	}
+	return params, deletes, nil
+}
+
+func resourceMappingCreate(d *schema.ResourceData, meta interface{}, mappingType string, deviceSchema map[string]*schema.Schema, fields map[string]string, options map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params, _, err := mappingParams(d, mappingType, fields, options)
+	if err != nil {
+		return err
+	}
+	params["id"] = name
+
+	logger, _ := CreateSubLogger("resource_mapping_create")
+	logger.Info().Str("name", name).Msgf("Creating %s mapping", mappingType)
+
+	if _, err = apiPost(pconf.Session, apiPath("cluster", "mapping", mappingType), params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId(mappingType, name))
+	return _resourceMappingRead(d, meta, mappingType, deviceSchema, fields, options)
+}
+
+func resourceMappingRead(d *schema.ResourceData, meta interface{}, mappingType string, deviceSchema map[string]*schema.Schema, fields map[string]string, options map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceMappingRead(d, meta, mappingType, deviceSchema, fields, options)
+}
+
+func _resourceMappingRead(d *schema.ResourceData, meta interface{}, mappingType string, deviceSchema map[string]*schema.Schema, fields map[string]string, options map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+
+	idType, name, err := parseClusterResourceId(d.Id())
+	if err == nil && idType != mappingType {
+		err = fmt.Errorf("%s is not a %s mapping", d.Id(), mappingType)
+	}
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_mapping_read")
+	logger.Info().Str("name", name).Msgf("Reading configuration for %s mapping", mappingType)
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "mapping", mappingType, name))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", name)
+	d.Set("description", apiString(config["description"]))
+	for attribute, parameter := range options {
+		d.Set(attribute, apiBool(config[parameter]))
+	}
+	return d.Set(mappingBlock(mappingType), parseMappingDevices(config["map"], deviceSchema, fields))
+}
+
+func resourceMappingUpdate(d *schema.ResourceData, meta interface{}, mappingType string, deviceSchema map[string]*schema.Schema, fields map[string]string, options map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	params, deletes, err := mappingParams(d, mappingType, fields, options)
+	if err != nil {
+		return err
+	}
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "mapping", mappingType, name), params); err != nil {
+		return err
+	}
+	return _resourceMappingRead(d, meta, mappingType, deviceSchema, fields, options)
+}
+
+// Proxmox refuses to delete mappings used by guests.
+func resourceMappingDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	mappingType, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "mapping", mappingType, name))
+	return err
+}
@@ -0,0 +1,68 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var mappingDirFields = map[string]string{
+	"node": "node",
+	"path": "path",
+}
+
+// directories are given by their absolute path on the node
+var rxAbsolutePath = regexp.MustCompile(`^/`)
+
+func resourceMappingDir() *schema.Resource {
+	*pxapi.Debug = true
+
+	directorySchema := map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The node the directory is on",
+		},
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(rxAbsolutePath, "must be an absolute path"),
+			Description:  "The absolute path of the directory on the node",
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Manages a directory mapping of the cluster, a cluster wide name of directories of the nodes which VMs share through virtiofs.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingCreate(d, meta, "dir", directorySchema, mappingDirFields, nil)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingRead(d, meta, "dir", directorySchema, mappingDirFields, nil)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingUpdate(d, meta, "dir", directorySchema, mappingDirFields, nil)
+		},
+		Delete: resourceMappingDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the mapping, which VMs refer to and mount the directory by",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the mapping",
+			},
+			"directory": mappingDeviceSchema("dir", directorySchema),
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var mappingPciFields = map[string]string{
+	"node":         "node",
+	"path":         "path",
+	"device_id":    "id",
+	"subsystem_id": "subsystem-id",
+	"iommu_group":  "iommugroup",
+}
+
+var mappingPciOptions = map[string]string{
+	"mdev":           "mdev",
+	"live_migration": "live-migration-capable",
+}
+
+// vendor and device ids like 10de:2204
+var rxHardwareId = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{4}$`)
+
+// PCI addresses like 0000:01:00.0, several functions of a device are separated by ;
+var rxPciPath = regexp.MustCompile(`^([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}(\.[0-7])?(;([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}(\.[0-7])?)*$`)
+
+func resourceMappingPci() *schema.Resource {
+	*pxapi.Debug = true
+
+	deviceSchema := map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The node the device is in",
+		},
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(rxPciPath, "must be a PCI address like 0000:01:00.0"),
+			Description:  "The PCI address of the device on the node, e.g. `0000:01:00.0`, or `0000:01:00` for all functions of the device",
+		},
+		"device_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(rxHardwareId, "must be a vendor and device id like 10de:2204"),
+			Description:  "The vendor and device id of the device, e.g. `10de:2204`, checked by Proxmox when a guest starts",
+		},
+		"subsystem_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(rxHardwareId, "must be a vendor and device id like 10de:1454"),
+			Description:  "The subsystem vendor and device id of the device, checked by Proxmox when a guest starts",
+		},
+		"iommu_group": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      -1,
+			ValidateFunc: validation.IntAtLeast(-1),
+			Description:  "The IOMMU group of the device, checked by Proxmox when a guest starts. -1 leaves the check out",
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Manages a PCI resource mapping of the cluster, a cluster wide name of PCI devices for the hostpci devices of VMs.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingCreate(d, meta, "pci", deviceSchema, mappingPciFields, mappingPciOptions)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingRead(d, meta, "pci", deviceSchema, mappingPciFields, mappingPciOptions)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingUpdate(d, meta, "pci", deviceSchema, mappingPciFields, mappingPciOptions)
+		},
+		Delete: resourceMappingDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the mapping, which guests refer to",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the mapping",
+			},
+			"mdev": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the devices are split into mediated devices, e.g. vGPUs",
+			},
+			"live_migration": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether guests with the devices may be migrated while running",
+			},
+			"device": mappingDeviceSchema("pci", deviceSchema),
+		},
+	}
+}
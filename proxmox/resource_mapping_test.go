@@ -0,0 +1,42 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMappingDevices(t *testing.T) {
+	deviceSchema := resourceMappingPci().Schema["device"].Elem.(*schema.Resource).Schema
+	devices := []interface{}{
+		map[string]interface{}{"node": "pve1", "path": "0000:01:00.0", "device_id": "10de:2204", "subsystem_id": "", "iommu_group": 12},
+		map[string]interface{}{"node": "pve2", "path": "0000:41:00", "device_id": "10de:2204", "subsystem_id": "10de:1454", "iommu_group": -1},
+	}
+	expected := []string{
+		"id=10de:2204,iommugroup=12,node=pve1,path=0000:01:00.0",
+		"id=10de:2204,node=pve2,path=0000:41:00,subsystem-id=10de:1454",
+	}
+
+	properties := mappingDevices(devices, mappingPciFields)
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, properties)
+	}
+	if parsed := parseMappingDevices([]interface{}{properties[0], properties[1]}, deviceSchema, mappingPciFields); !reflect.DeepEqual(parsed, devices) {
+		t.Errorf("expected `%v`, got `%v`", devices, parsed)
+	}
+}
+
+func TestCheckMappingDevices(t *testing.T) {
+	devices := []interface{}{
+		map[string]interface{}{"node": "pve1", "device_id": "0781:5583"},
+		map[string]interface{}{"node": "pve2", "device_id": "0781:5583"},
+	}
+	if err := checkMappingDevices("usb", devices); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	devices = append(devices, map[string]interface{}{"node": "pve1", "device_id": "0781:5591"})
+	if err := checkMappingDevices("usb", devices); err == nil {
+		t.Errorf("expected an error for two devices on pve1")
+	}
+}
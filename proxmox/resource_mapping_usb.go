@@ -0,0 +1,75 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var mappingUsbFields = map[string]string{
+	"node":      "node",
+	"device_id": "id",
+	"path":      "path",
+}
+
+// USB ports like 1-2 or 1-2.4
+var rxUsbPath = regexp.MustCompile(`^\d+-\d+(\.\d+)*$`)
+
+func resourceMappingUsb() *schema.Resource {
+	*pxapi.Debug = true
+
+	deviceSchema := map[string]*schema.Schema{
+		"node": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The node the device is plugged into",
+		},
+		"device_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(rxHardwareId, "must be a vendor and product id like 0781:5583"),
+			Description:  "The vendor and product id of the device, e.g. `0781:5583`",
+		},
+		"path": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(rxUsbPath, "must be a USB port like 1-2"),
+			Description:  "The USB port of the device, e.g. `1-2`. Passes through whatever device is plugged into the port instead of the device with `device_id`",
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Manages a USB resource mapping of the cluster, a cluster wide name of USB devices for the usb devices of VMs.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingCreate(d, meta, "usb", deviceSchema, mappingUsbFields, nil)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingRead(d, meta, "usb", deviceSchema, mappingUsbFields, nil)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceMappingUpdate(d, meta, "usb", deviceSchema, mappingUsbFields, nil)
+		},
+		Delete: resourceMappingDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the mapping, which guests refer to",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the mapping",
+			},
+			"device": mappingDeviceSchema("usb", deviceSchema),
+		},
+	}
+}
@@ -0,0 +1,315 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the protocols of each type of metric server
+var metricsServerProtocols = map[string][]string{
+	"graphite": {"udp", "tcp"},
+	"influxdb": {"udp", "http", "https"},
+}
+
+func resourceMetricsServer() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an external metric server of the cluster, which the nodes send the statistics of the nodes, guests and storages to.",
+
+		Create: resourceMetricsServerCreate,
+		Read:   resourceMetricsServerRead,
+		Update: resourceMetricsServerUpdate,
+		Delete: resourceMetricsServerDelete,
+		// the protocols depend on the type
+		CustomizeDiff: resourceMetricsServerCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the metric server",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"influxdb", "graphite"}, false),
+				Description:  "The type of the metric server, `influxdb` or `graphite`",
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The host name or IP address of the metric server",
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port of the metric server",
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "udp",
+				ValidateFunc: validation.StringInSlice([]string{"udp", "tcp", "http", "https"}, false),
+				Description:  "The protocol the metrics are sent with, `udp` or `tcp` for graphite, `udp`, `http` or `https` for influxdb",
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1500,
+				ValidateFunc: validation.IntBetween(512, 65536),
+				Description:  "The MTU of the metrics sent with udp",
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The timeout of the connections in seconds, 0 uses the default of Proxmox",
+			},
+			"disable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether sending metrics to the server is disabled",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The root of the graphite metric paths, `proxmox` when not set",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The InfluxDB 2 organization the metrics are sent to with http(s)",
+			},
+			"bucket": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The InfluxDB 2 bucket the metrics are sent to with http(s)",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The InfluxDB 2 API token used with http(s). Proxmox doesn't return it, so changes made outside of Terraform are not noticed",
+			},
+			"max_body_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum size of the requests sent to InfluxDB with http(s) in bytes, 0 uses the default of Proxmox",
+			},
+			"api_path_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A path prefix of the InfluxDB API, e.g. when InfluxDB is behind a reverse proxy",
+			},
+			"verify_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the certificate of InfluxDB is verified with https",
+			},
+		},
+	}
+}
+
+func metricsServerCheckProtocol(serverType string, protocol string) error {
+	for _, candidate := range metricsServerProtocols[serverType] {
+		if candidate == protocol {
+			return nil
+		}
+	}
+	return fmt.Errorf("A metric server of type %s can not use protocol %s, use one of %s", serverType, protocol, strings.Join(metricsServerProtocols[serverType], ", "))
+}
+
+func resourceMetricsServerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("protocol") {
+		return nil
+	}
+	return metricsServerCheckProtocol(d.Get("type").(string), d.Get("protocol").(string))
+}
+
+// The parameters of the metric server, the token is only sent when it is new or changed.
+func metricsServerParams(d *schema.ResourceData, update bool) (params map[string]interface{}, deletes []string, err error) {
+	serverType := d.Get("type").(string)
+	protocol := d.Get("protocol").(string)
+	if err = metricsServerCheckProtocol(serverType, protocol); err != nil {
+		return nil, nil, err
+	}
+
+	params = map[string]interface{}{
+		"server":  d.Get("server").(string),
+		"port":    d.Get("port").(int),
+		"mtu":     d.Get("mtu").(int),
+		"disable": d.Get("disable").(bool),
+	}
+	deletes = []string{}
+	optional := map[string]interface{}{
+		"timeout": d.Get("timeout").(int),
+	}
+	if serverType == "graphite" {
+		params["proto"] = protocol
+		optional["path"] = d.Get("path").(string)
+	} else {
+		params["influxdbproto"] = protocol
+		params["verify-certificate"] = d.Get("verify_certificate").(bool)
+		optional["organization"] = d.Get("organization").(string)
+		optional["bucket"] = d.Get("bucket").(string)
+		optional["max-body-size"] = d.Get("max_body_size").(int)
+		optional["api-path-prefix"] = d.Get("api_path_prefix").(string)
+		if !update || d.HasChange("token") {
+			optional["token"] = d.Get("token").(string)
+		}
+	}
+	for key, value := range optional {
+		if value == "" || value == 0 {
+			deletes = append(deletes, key)
+		} else {
+			params[key] = value
+		}
+	}
+	sort.Strings(deletes)
+	return params, deletes, nil
+}
+
+func resourceMetricsServerCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params, _, err := metricsServerParams(d, false)
+	if err != nil {
+		return err
+	}
+	params["type"] = d.Get("type").(string)
+
+	logger, _ := CreateSubLogger("resource_metrics_server_create")
+	logger.Info().Str("name", name).Msg("Creating metric server")
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, apiPath("cluster", "metrics", "server", name), params)
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("metrics", name))
+	return _resourceMetricsServerRead(d, meta)
+}
+
+func resourceMetricsServerRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceMetricsServerRead(d, meta)
+}
+
+func _resourceMetricsServerRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_metrics_server_read")
+	logger.Info().Str("name", name).Msg("Reading configuration for metric server")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "metrics", "server", name))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	serverType := apiString(config["type"])
+	d.Set("name", name)
+	d.Set("type", serverType)
+	d.Set("server", apiString(config["server"]))
+	d.Set("port", apiInt(config["port"]))
+	d.Set("mtu", 1500)
+	if value, ok := config["mtu"]; ok {
+		d.Set("mtu", apiInt(value))
+	}
+	d.Set("timeout", apiInt(config["timeout"]))
+	d.Set("disable", apiBool(config["disable"]))
+
+	if serverType == "graphite" {
+		d.Set("protocol", "udp")
+		if value, ok := config["proto"]; ok {
+			d.Set("protocol", apiString(value))
+		}
+		d.Set("path", apiString(config["path"]))
+	} else {
+		d.Set("protocol", "udp")
+		if value, ok := config["influxdbproto"]; ok {
+			d.Set("protocol", apiString(value))
+		}
+		d.Set("organization", apiString(config["organization"]))
+		d.Set("bucket", apiString(config["bucket"]))
+		d.Set("max_body_size", apiInt(config["max-body-size"]))
+		d.Set("api_path_prefix", apiString(config["api-path-prefix"]))
+		d.Set("verify_certificate", true)
+		if value, ok := config["verify-certificate"]; ok {
+			d.Set("verify_certificate", apiBool(value))
+		}
+	}
+	return nil
+}
+
+func resourceMetricsServerUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes, err := metricsServerParams(d, true)
+	if err != nil {
+		return err
+	}
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPut(pconf.Session, apiPath("cluster", "metrics", "server", name), params)
+	})
+	if err != nil {
+		return err
+	}
+	return _resourceMetricsServerRead(d, meta)
+}
+
+func resourceMetricsServerDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "metrics", "server", name))
+	return err
+}
@@ -0,0 +1,89 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestMetricsServerParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceMetricsServer().Schema, map[string]interface{}{
+		"name":         "influx",
+		"type":         "influxdb",
+		"server":       "influx.example.com",
+		"port":         8086,
+		"protocol":     "https",
+		"organization": "ops",
+		"bucket":       "proxmox",
+		"token":        "secret",
+	})
+
+	params, deletes, err := metricsServerParams(d, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"server":             "influx.example.com",
+		"port":               8086,
+		"mtu":                1500,
+		"disable":            false,
+		"influxdbproto":      "https",
+		"verify-certificate": true,
+		"organization":       "ops",
+		"bucket":             "proxmox",
+		"token":              "secret",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"api-path-prefix", "max-body-size", "timeout"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceMetricsServer().Schema, map[string]interface{}{
+		"name":     "graphite",
+		"type":     "graphite",
+		"server":   "graphite.example.com",
+		"port":     2003,
+		"protocol": "tcp",
+		"timeout":  5,
+	})
+	params, deletes, err = metricsServerParams(d, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]interface{}{
+		"server":  "graphite.example.com",
+		"port":    2003,
+		"mtu":     1500,
+		"disable": false,
+		"proto":   "tcp",
+		"timeout": 5,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"path"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestMetricsServerCheckProtocol(t *testing.T) {
+	tests := []struct {
+		serverType string
+		protocol   string
+		valid      bool
+	}{
+		{"graphite", "udp", true},
+		{"graphite", "tcp", true},
+		{"graphite", "https", false},
+		{"influxdb", "http", true},
+		{"influxdb", "tcp", false},
+	}
+	for _, test := range tests {
+		if err := metricsServerCheckProtocol(test.serverType, test.protocol); (err == nil) != test.valid {
+			t.Errorf("%s %s: expected valid %v, got `%v`", test.serverType, test.protocol, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The network resources share the handling of /nodes/{node}/network, every interface type only
+// adds its own attributes to the schema and to the parameters sent to the API. Changes are
+// written to the pending network configuration of the node, which is applied right away.
+
+// schema attribute => parameter of /nodes/{node}/network, shared by all interface types
+var networkParameters = map[string]string{
+	"cidr":      "cidr",
+	"gateway":   "gateway",
+	"cidr6":     "cidr6",
+	"gateway6":  "gateway6",
+	"mtu":       "mtu",
+	"autostart": "autostart",
+	"comment":   "comments",
+}
+
+// Adds the attributes shared by all interface types to the schema of an interface type.
+func networkSchema(typeSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	typeSchema["node"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The node the interface is configured on",
+	}
+	typeSchema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The name of the interface",
+	}
+	typeSchema["cidr"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsCIDR,
+		Description:  "The IPv4 address of the interface in CIDR notation, e.g. 192.168.1.2/24",
+	}
+	typeSchema["gateway"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsIPv4Address,
+		Description:  "The IPv4 default gateway of the node",
+	}
+	typeSchema["cidr6"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsCIDR,
+		Description:  "The IPv6 address of the interface in CIDR notation",
+	}
+	typeSchema["gateway6"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsIPv6Address,
+		Description:  "The IPv6 default gateway of the node",
+	}
+	typeSchema["mtu"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntBetween(1280, 65520),
+		Description:  "The MTU of the interface",
+	}
+	typeSchema["autostart"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     true,
+		Description: "Bring the interface up when the node boots",
+	}
+	typeSchema["comment"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "A comment on the interface",
+	}
+	return typeSchema
+}
+
+// the parameters of an interface type together with the ones shared by all types
+func networkTypeParameters(typeParameters map[string]string) map[string]string {
+	parameters := map[string]string{}
+	for attribute, parameter := range networkParameters {
+		parameters[attribute] = parameter
+	}
+	for attribute, parameter := range typeParameters {
+		parameters[attribute] = parameter
+	}
+	return parameters
+}
+
+// Collects the parameters of an interface from the schema. Empty values are returned as the
+// list of parameters to delete, so they are cleared when the interface is updated. Sets are
+// sent space separated, like in /etc/network/interfaces. The parameters of ForceNew
+// attributes are left out of an update, empty computed attributes are not cleared.
+func networkParams(d *schema.ResourceData, networkSchema map[string]*schema.Schema, parameters map[string]string, update bool) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for attribute, parameter := range parameters {
+		if update && networkSchema[attribute].ForceNew {
+			continue
+		}
+		value := d.Get(attribute)
+		switch networkSchema[attribute].Type {
+		case schema.TypeBool:
+			params[parameter] = value.(bool)
+			continue
+		case schema.TypeInt:
+			if value.(int) != 0 {
+				params[parameter] = value.(int)
+				continue
+			}
+		case schema.TypeSet:
+			if list := schemaStringList(value); len(list) > 0 {
+				params[parameter] = strings.Join(list, " ")
+				continue
+			}
+		default:
+			if value.(string) != "" {
+				params[parameter] = value.(string)
+				continue
+			}
+		}
+		// computed attributes are partly managed by proxmox, e.g. the ports of an OVS bridge
+		if !networkSchema[attribute].Computed {
+			deletes = append(deletes, parameter)
+		}
+	}
+	return
+}
+
+func setNetworkData(d *schema.ResourceData, node string, iface string, networkSchema map[string]*schema.Schema, parameters map[string]string, config map[string]interface{}) error {
+	d.Set("node", node)
+	d.Set("name", iface)
+	for attribute, parameter := range parameters {
+		value := config[parameter]
+		switch networkSchema[attribute].Type {
+		// proxmox omits the options which are not in /etc/network/interfaces, which is false
+		case schema.TypeBool:
+			d.Set(attribute, apiBool(value))
+		case schema.TypeInt:
+			d.Set(attribute, apiInt(value))
+		case schema.TypeSet:
+			if err := d.Set(attribute, apiStringList(value, " ")); err != nil {
+				return err
+			}
+		default:
+			// comments are returned with the line break of every comment line
+			d.Set(attribute, strings.TrimRight(apiString(value), "\n"))
+		}
+	}
+	return nil
+}
+
+// Applies the pending network configuration of a node, proxmox reloads the interfaces with
+// ifupdown2. Pending changes made outside of terraform are applied as well.
+func networkApply(pconf *providerConfiguration, node string) error {
+	upid, err := apiPut(pconf.Session, apiPath("nodes", node, "network"), map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("Applying the network configuration of %s failed: %v", node, err)
+	}
+	if _, err = apiWaitForTask(pconf.Session, pconf.Client, upid); err != nil {
+		return fmt.Errorf("Applying the network configuration of %s failed: %v", node, err)
+	}
+	return nil
+}
+
+func resourceNetworkCreate(d *schema.ResourceData, meta interface{}, ifaceType string, networkSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	iface := d.Get("name").(string)
+	params, _ := networkParams(d, networkSchema, parameters, false)
+	params["iface"] = iface
+	params["type"] = ifaceType
+
+	logger, _ := CreateSubLogger("resource_network_create")
+	logger.Info().Str("node", node).Msgf("Creating %s %s", ifaceType, iface)
+
+	if _, err := apiPost(pconf.Session, apiPath("nodes", node, "network"), params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId(node, iface))
+
+	if err := networkApply(pconf, node); err != nil {
+		return err
+	}
+	return _resourceNetworkRead(d, meta, ifaceType, networkSchema, parameters)
+}
+
+func resourceNetworkRead(d *schema.ResourceData, meta interface{}, ifaceType string, networkSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNetworkRead(d, meta, ifaceType, networkSchema, parameters)
+}
+
+func _resourceNetworkRead(d *schema.ResourceData, meta interface{}, ifaceType string, networkSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, iface, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_network_read")
+	logger.Info().Str("node", node).Msgf("Reading configuration for %s %s", ifaceType, iface)
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "network", iface))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if apiString(config["type"]) != ifaceType {
+		return fmt.Errorf("Interface %s of %s is of type %s, not %s", iface, node, apiString(config["type"]), ifaceType)
+	}
+
+	if err = setNetworkData(d, node, iface, networkSchema, parameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("node", node).Msgf("Finished network read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceNetworkUpdate(d *schema.ResourceData, meta interface{}, ifaceType string, networkSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, iface, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := networkParams(d, networkSchema, parameters, true)
+	params["type"] = ifaceType
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("nodes", node, "network", iface), params); err != nil {
+		return err
+	}
+	if err = networkApply(pconf, node); err != nil {
+		return err
+	}
+	return _resourceNetworkRead(d, meta, ifaceType, networkSchema, parameters)
+}
+
+func resourceNetworkDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, iface, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if _, err = apiDelete(pconf.Session, apiPath("nodes", node, "network", iface)); err != nil {
+		return err
+	}
+	return networkApply(pconf, node)
+}
+
+// The ovs_options of the Open vSwitch interface types, which are passed on to ovs-vsctl.
+// Proxmox stores them as written in /etc/network/interfaces, so only differences in the
+// options themselves are a change, not in the whitespace between them.
+func networkOvsOptionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return normalizeOvsOptions(old) == normalizeOvsOptions(new)
+		},
+		Description: "Options passed on to ovs-vsctl, e.g. other_config:rstp-enable=true",
+	}
+}
+
+// the ovs_options separated by single spaces
+func normalizeOvsOptions(options string) string {
+	return strings.Join(strings.Fields(options), " ")
+}
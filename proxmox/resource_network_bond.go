@@ -0,0 +1,75 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var networkBondResourceDef *schema.Resource
+
+var networkBondParameters = networkTypeParameters(map[string]string{
+	"slaves":      "slaves",
+	"mode":        "bond_mode",
+	"hash_policy": "bond_xmit_hash_policy",
+	"primary":     "bond-primary",
+})
+
+var networkBondModes = []string{"balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"}
+
+var networkBondHashPolicies = []string{"layer2", "layer2+3", "layer3+4"}
+
+func resourceNetworkBond() *schema.Resource {
+	*pxapi.Debug = true
+
+	networkBondResourceDef = &schema.Resource{
+		Description: "Manages a Linux bond on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "bond", networkBondResourceDef.Schema, networkBondParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "bond", networkBondResourceDef.Schema, networkBondParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "bond", networkBondResourceDef.Schema, networkBondParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			"slaves": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The interfaces bonded together, e.g. eno1 and eno2",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "balance-rr",
+				ValidateFunc: validation.StringInSlice(networkBondModes, false),
+				Description:  "The bonding mode, e.g. active-backup or 802.3ad for LACP",
+			},
+			"hash_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(networkBondHashPolicies, false),
+				Description:  "The transmit hash policy of the balance-xor and 802.3ad modes",
+			},
+			"primary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slave used while it is up in the active-backup mode",
+			},
+		}),
+	}
+	// bonds have to be called bond<number>
+	networkBondResourceDef.Schema["name"].ValidateFunc = validation.StringMatch(regexp.MustCompile(`^bond\d+$`), "must be bond followed by a number, e.g. bond0")
+
+	return networkBondResourceDef
+}
@@ -0,0 +1,75 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var networkOvsBondResourceDef *schema.Resource
+
+var networkOvsBondParameters = networkTypeParameters(map[string]string{
+	"bridge":      "ovs_bridge",
+	"slaves":      "ovs_bonds",
+	"mode":        "bond_mode",
+	"tag":         "ovs_tag",
+	"ovs_options": "ovs_options",
+})
+
+var networkOvsBondModes = []string{"active-backup", "balance-slb", "lacp-balance-slb", "lacp-balance-tcp"}
+
+func resourceNetworkOvsBond() *schema.Resource {
+	*pxapi.Debug = true
+
+	networkOvsBondResourceDef = &schema.Resource{
+		Description: "Manages an Open vSwitch bond on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "OVSBond", networkOvsBondResourceDef.Schema, networkOvsBondParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "OVSBond", networkOvsBondResourceDef.Schema, networkOvsBondParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "OVSBond", networkOvsBondResourceDef.Schema, networkOvsBondParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			"bridge": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The OVS bridge the bond is attached to",
+			},
+			"slaves": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The interfaces bonded together, e.g. eno1 and eno2",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "active-backup",
+				ValidateFunc: validation.StringInSlice(networkOvsBondModes, false),
+				Description:  "The bonding mode, e.g. balance-slb or lacp-balance-tcp for LACP",
+			},
+			"tag": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+				Description:  "The VLAN tag of the bond",
+			},
+			"ovs_options": networkOvsOptionsSchema(),
+		}),
+	}
+	// OVS bonds have to be called bond<number> as well
+	networkOvsBondResourceDef.Schema["name"].ValidateFunc = validation.StringMatch(regexp.MustCompile(`^bond\d+$`), "must be bond followed by a number, e.g. bond0")
+
+	return networkOvsBondResourceDef
+}
@@ -0,0 +1,50 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var networkOvsBridgeResourceDef *schema.Resource
+
+var networkOvsBridgeParameters = networkTypeParameters(map[string]string{
+	"ports":       "ovs_ports",
+	"ovs_options": "ovs_options",
+})
+
+func resourceNetworkOvsBridge() *schema.Resource {
+	*pxapi.Debug = true
+
+	networkOvsBridgeResourceDef = &schema.Resource{
+		Description: "Manages an Open vSwitch bridge on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "OVSBridge", networkOvsBridgeResourceDef.Schema, networkOvsBridgeParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "OVSBridge", networkOvsBridgeResourceDef.Schema, networkOvsBridgeParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "OVSBridge", networkOvsBridgeResourceDef.Schema, networkOvsBridgeParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			// proxmox adds the OVS bonds and internal ports naming the bridge in their
+			// ovs_bridge on its own
+			"ports": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The interfaces attached to the bridge, e.g. eno1",
+			},
+			"ovs_options": networkOvsOptionsSchema(),
+		}),
+	}
+
+	return networkOvsBridgeResourceDef
+}
@@ -0,0 +1,54 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var networkOvsIntPortResourceDef *schema.Resource
+
+var networkOvsIntPortParameters = networkTypeParameters(map[string]string{
+	"bridge":      "ovs_bridge",
+	"tag":         "ovs_tag",
+	"ovs_options": "ovs_options",
+})
+
+func resourceNetworkOvsIntPort() *schema.Resource {
+	*pxapi.Debug = true
+
+	networkOvsIntPortResourceDef = &schema.Resource{
+		Description: "Manages an Open vSwitch internal port on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "OVSIntPort", networkOvsIntPortResourceDef.Schema, networkOvsIntPortParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "OVSIntPort", networkOvsIntPortResourceDef.Schema, networkOvsIntPortParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "OVSIntPort", networkOvsIntPortResourceDef.Schema, networkOvsIntPortParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			"bridge": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The OVS bridge the internal port is attached to",
+			},
+			"tag": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+				Description:  "The VLAN tag of the internal port",
+			},
+			"ovs_options": networkOvsOptionsSchema(),
+		}),
+	}
+
+	return networkOvsIntPortResourceDef
+}
@@ -0,0 +1,110 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNetworkParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceBridge().Schema, map[string]interface{}{
+		"node":       "pve1",
+		"name":       "vmbr1",
+		"ports":      []interface{}{"eno2"},
+		"vlan_aware": true,
+		"cidr":       "10.0.0.2/24",
+		"comment":    "guests",
+	})
+
+	params, deletes := networkParams(d, bridgeResourceDef.Schema, bridgeParameters, false)
+	expected := map[string]interface{}{
+		"bridge_ports":      "eno2",
+		"bridge_vlan_aware": true,
+		"cidr":              "10.0.0.2/24",
+		"autostart":         true,
+		"comments":          "guests",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%+v`, got `%+v`", expected, params)
+	}
+	sort.Strings(deletes)
+	if expectedDeletes := []string{"bridge_vids", "cidr6", "gateway", "gateway6", "mtu"}; !reflect.DeepEqual(deletes, expectedDeletes) {
+		t.Errorf("expected deletes `%v`, got `%v`", expectedDeletes, deletes)
+	}
+}
+
+func TestNetworkParamsUpdate(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNetworkVlan().Schema, map[string]interface{}{
+		"node":       "pve1",
+		"name":       "vlan20",
+		"raw_device": "bond0",
+		"vlan_id":    20,
+	})
+
+	params, _ := networkParams(d, networkVlanResourceDef.Schema, networkVlanParameters, false)
+	if params["vlan-raw-device"] != "bond0" || params["vlan-id"] != 20 {
+		t.Errorf("expected the raw device and the tag to be created, got `%+v`", params)
+	}
+	params, deletes := networkParams(d, networkVlanResourceDef.Schema, networkVlanParameters, true)
+	for _, parameter := range []string{"vlan-raw-device", "vlan-id"} {
+		if _, ok := params[parameter]; ok || stringInList(parameter, deletes) {
+			t.Errorf("expected %s to be left out of an update, got `%+v` and deletes `%v`", parameter, params, deletes)
+		}
+	}
+}
+
+func TestSetNetworkData(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceBridge().Schema, map[string]interface{}{})
+	err := setNetworkData(d, "pve1", "vmbr1", bridgeResourceDef.Schema, bridgeParameters, map[string]interface{}{
+		"iface":        "vmbr1",
+		"type":         "bridge",
+		"bridge_ports": "eno2 eno3",
+		"cidr":         "10.0.0.2/24",
+		"comments":     "guests\n",
+		"mtu":          "9000",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	ports := schemaStringList(d.Get("ports"))
+	sort.Strings(ports)
+	if !reflect.DeepEqual(ports, []string{"eno2", "eno3"}) {
+		t.Errorf("expected ports `[eno2 eno3]`, got `%v`", ports)
+	}
+	if d.Get("node").(string) != "pve1" || d.Get("name").(string) != "vmbr1" {
+		t.Errorf("expected node and name to be set, got `%s` and `%s`", d.Get("node"), d.Get("name"))
+	}
+	if d.Get("comment").(string) != "guests" || d.Get("mtu").(int) != 9000 {
+		t.Errorf("expected comment `guests` and mtu 9000, got `%s` and %d", d.Get("comment"), d.Get("mtu"))
+	}
+	if d.Get("vlan_aware").(bool) || d.Get("autostart").(bool) {
+		t.Errorf("expected options missing in the config to be false, got vlan_aware `%v` and autostart `%v`", d.Get("vlan_aware"), d.Get("autostart"))
+	}
+}
+
+func TestNetworkOvs(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNetworkOvsBridge().Schema, map[string]interface{}{
+		"node":        "pve1",
+		"name":        "vmbr2",
+		"ovs_options": "other_config:rstp-enable=true",
+	})
+
+	params, deletes := networkParams(d, networkOvsBridgeResourceDef.Schema, networkOvsBridgeParameters, true)
+	if params["ovs_options"] != "other_config:rstp-enable=true" {
+		t.Errorf("expected the ovs_options to be sent, got `%+v`", params)
+	}
+	if stringInList("ovs_ports", deletes) {
+		t.Errorf("expected the ports added by proxmox to be kept, got deletes `%v`", deletes)
+	}
+
+	suppress := networkOvsOptionsSchema().DiffSuppressFunc
+	if !suppress("ovs_options", "bond_updelay=5000  other_config:lacp-time=fast", " bond_updelay=5000 other_config:lacp-time=fast\n", nil) {
+		t.Errorf("expected ovs_options with other whitespace to be no change")
+	}
+	if suppress("ovs_options", "bond_updelay=5000", "bond_updelay=3000", nil) {
+		t.Errorf("expected changed ovs_options to be a change")
+	}
+}
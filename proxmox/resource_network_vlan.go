@@ -0,0 +1,56 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var networkVlanResourceDef *schema.Resource
+
+var networkVlanParameters = networkTypeParameters(map[string]string{
+	"raw_device": "vlan-raw-device",
+	"vlan_id":    "vlan-id",
+})
+
+func resourceNetworkVlan() *schema.Resource {
+	*pxapi.Debug = true
+
+	networkVlanResourceDef = &schema.Resource{
+		Description: "Manages a VLAN interface on a node.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkCreate(d, meta, "vlan", networkVlanResourceDef.Schema, networkVlanParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkRead(d, meta, "vlan", networkVlanResourceDef.Schema, networkVlanParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceNetworkUpdate(d, meta, "vlan", networkVlanResourceDef.Schema, networkVlanParameters)
+		},
+		Delete: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: networkSchema(map[string]*schema.Schema{
+			"raw_device": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The interface the VLAN is on, required for names like vlan20, implied by names like eno1.20",
+			},
+			"vlan_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+				Description:  "The VLAN tag, implied by names like eno1.20 and vlan20",
+			},
+		}),
+	}
+
+	return networkVlanResourceDef
+}
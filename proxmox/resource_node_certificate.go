@@ -0,0 +1,230 @@
+package proxmox
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// the file of the custom certificate in the certificate info of a node
+const nodeCustomCertificateFile = "pveproxy-ssl.pem"
+
+func resourceNodeCertificate() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the custom TLS certificate the API and web interface of a node are served with.",
+
+		Create: resourceNodeCertificateCreate,
+		Read:   resourceNodeCertificateRead,
+		Update: resourceNodeCertificateUpdate,
+		Delete: resourceNodeCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the certificate is uploaded to",
+			},
+			"certificate": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The certificate in PEM format, followed by the intermediate certificates of its chain",
+			},
+			"private_key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The private key of the certificate in PEM format. Proxmox doesn't return it, so changes made outside of Terraform are not noticed",
+			},
+			"restart": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Restart the API of the node so it serves the new certificate right away",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 fingerprint of the certificate",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The subject of the certificate",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The issuer of the certificate",
+			},
+			"subject_alternative_names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The subject alternative names of the certificate",
+			},
+			"not_before": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the certificate becomes valid, as a unix timestamp",
+			},
+			"not_after": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the certificate expires, as a unix timestamp",
+			},
+		},
+	}
+}
+
+// The SHA-256 fingerprint of the first certificate of a PEM chain, formatted like proxmox does,
+// e.g. 2B:7F:...:C1.
+func certificateFingerprint(certificate string) (string, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("No certificate in PEM format found")
+	}
+	sum := sha256.Sum256(block.Bytes)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":"), nil
+}
+
+// The custom certificate in the certificate info of node, nil when the node has none.
+func nodeCustomCertificate(session *pxapi.Session, node string) (map[string]interface{}, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "certificates", "info"))
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if info, ok := item.(map[string]interface{}); ok && apiString(info["filename"]) == nodeCustomCertificateFile {
+			return info, nil
+		}
+	}
+	return nil, nil
+}
+
+func uploadNodeCertificate(d *schema.ResourceData, pconf *providerConfiguration) error {
+	node := d.Get("node").(string)
+	if _, err := certificateFingerprint(d.Get("certificate").(string)); err != nil {
+		return err
+	}
+	params := map[string]interface{}{
+		"certificates": d.Get("certificate").(string),
+		"key":          d.Get("private_key").(string),
+		"force":        true,
+		"restart":      d.Get("restart").(bool),
+	}
+
+	logger, _ := CreateSubLogger("resource_node_certificate_upload")
+	logger.Info().Str("node", node).Msg("Uploading custom certificate of node")
+
+	// the private key is kept out of the debug log
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, apiPath("nodes", node, "certificates", "custom"), params)
+	})
+	return err
+}
+
+func resourceNodeCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if err := uploadNodeCertificate(d, pconf); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("certificate", d.Get("node").(string)))
+	return _resourceNodeCertificateRead(d, meta)
+}
+
+func resourceNodeCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeCertificateRead(d, meta)
+}
+
+func _resourceNodeCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_certificate_read")
+	logger.Info().Str("node", node).Msg("Reading custom certificate of node")
+
+	info, err := nodeCustomCertificate(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", node)
+	// the node only returns its certificate without the chain, which is kept unless the
+	// certificate was replaced outside of Terraform
+	fingerprint := apiString(info["fingerprint"])
+	if configured, _ := certificateFingerprint(d.Get("certificate").(string)); !strings.EqualFold(configured, fingerprint) {
+		d.Set("certificate", apiString(info["pem"]))
+	}
+	d.Set("fingerprint", fingerprint)
+	d.Set("subject", apiString(info["subject"]))
+	d.Set("issuer", apiString(info["issuer"]))
+	if err = d.Set("subject_alternative_names", apiStringList(info["san"], " ,")); err != nil {
+		return err
+	}
+	d.Set("not_before", apiInt(info["notbefore"]))
+	d.Set("not_after", apiInt(info["notafter"]))
+	return nil
+}
+
+// uploads the certificate again, changing restart alone only changes the state
+func resourceNodeCertificateUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if d.HasChanges("certificate", "private_key") {
+		if err := uploadNodeCertificate(d, pconf); err != nil {
+			return err
+		}
+	}
+	return _resourceNodeCertificateRead(d, meta)
+}
+
+// Deleting the custom certificate makes the node serve the certificate signed by the cluster CA again.
+func resourceNodeCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	path := apiPath("nodes", node, "certificates", "custom")
+	if d.Get("restart").(bool) {
+		path += "?restart=1"
+	}
+	_, err = apiDelete(pconf.Session, path)
+	return err
+}
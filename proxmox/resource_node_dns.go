@@ -0,0 +1,136 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var nodeDnsServers = []string{"dns1", "dns2", "dns3"}
+
+func resourceNodeDns() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the DNS settings of a node, its search domain and name servers in /etc/resolv.conf.",
+
+		Create: resourceNodeDnsCreate,
+		Read:   resourceNodeDnsRead,
+		Update: resourceNodeDnsUpdate,
+		Delete: resourceNodeDnsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the DNS settings are of",
+			},
+			"search": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The search domain of host names",
+			},
+			"dns1": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The address of the first name server",
+			},
+			"dns2": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"dns1"},
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The address of the second name server",
+			},
+			"dns3": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"dns2"},
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The address of the third name server",
+			},
+		},
+	}
+}
+
+// Proxmox rewrites resolv.conf with the parameters, name servers left out are removed.
+func nodeDnsParams(d *schema.ResourceData) map[string]interface{} {
+	params := map[string]interface{}{
+		"search": d.Get("search").(string),
+	}
+	for _, server := range nodeDnsServers {
+		if address := d.Get(server).(string); address != "" {
+			params[server] = address
+		}
+	}
+	return params
+}
+
+func resourceNodeDnsCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("dns", d.Get("node").(string)))
+	return resourceNodeDnsUpdate(d, meta)
+}
+
+func resourceNodeDnsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeDnsRead(d, meta)
+}
+
+func _resourceNodeDnsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_dns_read")
+	logger.Info().Str("node", node).Msg("Reading DNS settings of node")
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "dns"))
+	if err != nil {
+		return err
+	}
+	d.Set("node", node)
+	d.Set("search", apiString(config["search"]))
+	for _, server := range nodeDnsServers {
+		d.Set(server, apiString(config[server]))
+	}
+	return nil
+}
+
+func resourceNodeDnsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_node_dns_update")
+	logger.Info().Str("node", node).Msg("Writing DNS settings of node")
+
+	if _, err = apiPut(pconf.Session, apiPath("nodes", node, "dns"), nodeDnsParams(d)); err != nil {
+		return err
+	}
+	return _resourceNodeDnsRead(d, meta)
+}
+
+// A node needs its name servers, the settings are left as they are and only removed from the
+// state.
+func resourceNodeDnsDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,26 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNodeDnsParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNodeDns().Schema, map[string]interface{}{
+		"node":   "pve1",
+		"search": "example.com",
+		"dns1":   "10.0.0.53",
+		"dns2":   "2001:db8::53",
+	})
+
+	expected := map[string]interface{}{
+		"search": "example.com",
+		"dns1":   "10.0.0.53",
+		"dns2":   "2001:db8::53",
+	}
+	if params := nodeDnsParams(d); !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+}
@@ -0,0 +1,222 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => parameter of the options of the firewall of a node
+var nodeFirewallOptionsParameters = map[string]string{
+	"enabled":                              "enable",
+	"ndp":                                  "ndp",
+	"nosmurfs":                             "nosmurfs",
+	"tcpflags":                             "tcpflags",
+	"protection_synflood":                  "protection_synflood",
+	"log_level_in":                         "log_level_in",
+	"log_level_out":                        "log_level_out",
+	"smurf_log_level":                      "smurf_log_level",
+	"tcp_flags_log_level":                  "tcp_flags_log_level",
+	"log_nf_conntrack":                     "log_nf_conntrack",
+	"nf_conntrack_allow_invalid":           "nf_conntrack_allow_invalid",
+	"nf_conntrack_max":                     "nf_conntrack_max",
+	"nf_conntrack_tcp_timeout_established": "nf_conntrack_tcp_timeout_established",
+}
+
+// the defaults of the options of the firewall of a node
+var nodeFirewallOptionDefaults = map[string]interface{}{
+	"enable":                               true,
+	"ndp":                                  true,
+	"nosmurfs":                             true,
+	"tcpflags":                             false,
+	"protection_synflood":                  false,
+	"log_level_in":                         "nolog",
+	"log_level_out":                        "nolog",
+	"smurf_log_level":                      "nolog",
+	"tcp_flags_log_level":                  "nolog",
+	"log_nf_conntrack":                     false,
+	"nf_conntrack_allow_invalid":           false,
+	"nf_conntrack_max":                     262144,
+	"nf_conntrack_tcp_timeout_established": 432000,
+}
+
+func resourceNodeFirewallOptions() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the options of the firewall of a node, e.g. its log levels and the connection tracking.",
+
+		Create: resourceNodeFirewallOptionsCreate,
+		Read:   resourceNodeFirewallOptionsRead,
+		Update: resourceNodeFirewallOptionsUpdate,
+		Delete: resourceNodeFirewallOptionsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the options are of",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the firewall of the node is enabled, when the firewall of the cluster is",
+			},
+			"ndp": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the IPv6 neighbor discovery protocol is allowed",
+			},
+			"nosmurfs": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether smurf attacks, packets from broadcast addresses, are dropped",
+			},
+			"tcpflags": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether TCP packets with an invalid combination of flags are dropped",
+			},
+			"protection_synflood": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the rate of new connections from one source is limited",
+			},
+			"log_level_in": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the incoming traffic handled by the policy, nolog logs nothing",
+			},
+			"log_level_out": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the outgoing traffic handled by the policy, nolog logs nothing",
+			},
+			"smurf_log_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the dropped smurf packets",
+			},
+			"tcp_flags_log_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "nolog",
+				ValidateFunc: validation.StringInSlice(firewallLogLevels, false),
+				Description:  "The log level of the dropped packets with invalid TCP flags",
+			},
+			"log_nf_conntrack": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the connection tracking information is logged",
+			},
+			"nf_conntrack_allow_invalid": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether packets the connection tracking considers invalid are allowed",
+			},
+			"nf_conntrack_max": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      262144,
+				ValidateFunc: validation.IntAtLeast(32768),
+				Description:  "The maximum number of tracked connections",
+			},
+			"nf_conntrack_tcp_timeout_established": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      432000,
+				ValidateFunc: validation.IntAtLeast(7875),
+				Description:  "The seconds an established TCP connection is tracked without traffic",
+			},
+		},
+	}
+}
+
+func resourceNodeFirewallOptionsCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("nodes", d.Get("node").(string)))
+	return resourceNodeFirewallOptionsUpdate(d, meta)
+}
+
+func resourceNodeFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeFirewallOptionsRead(d, meta)
+}
+
+func _resourceNodeFirewallOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_firewall_options_read")
+	logger.Info().Str("node", node).Msg("Reading firewall options of node")
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "firewall", "options"))
+	if err != nil {
+		return err
+	}
+	options := firewallOptions(config, nodeFirewallOptionDefaults)
+	d.Set("node", node)
+	for attribute, parameter := range nodeFirewallOptionsParameters {
+		d.Set(attribute, options[parameter])
+	}
+
+	logger.Debug().Str("node", node).Msgf("Finished node firewall options read resulting in data: '%+v'", options)
+	return nil
+}
+
+func resourceNodeFirewallOptionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_node_firewall_options_update")
+	logger.Info().Str("node", node).Msg("Writing firewall options of node")
+
+	if err = firewallSetOptions(d, pconf.Session, apiPath("nodes", node, "firewall"), nodeFirewallOptionsParameters); err != nil {
+		return err
+	}
+	return _resourceNodeFirewallOptionsRead(d, meta)
+}
+
+// The options are reset to the defaults of proxmox.
+func resourceNodeFirewallOptionsDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return firewallResetOptions(pconf.Session, apiPath("nodes", node, "firewall"), nodeFirewallOptionDefaults)
+}
@@ -0,0 +1,104 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceNodeFirewallRules() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the ordered firewall rules of a node, which apply to the node itself but not to its guests.",
+
+		Create: resourceNodeFirewallRulesCreate,
+		Read:   resourceNodeFirewallRulesRead,
+		Update: resourceNodeFirewallRulesUpdate,
+		Delete: resourceNodeFirewallRulesDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: firewallRulesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the rules are of",
+			},
+			"rule": firewallRulesSchema(true),
+		},
+	}
+}
+
+func resourceNodeFirewallRulesCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("nodes", d.Get("node").(string)))
+	return resourceNodeFirewallRulesUpdate(d, meta)
+}
+
+func resourceNodeFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeFirewallRulesRead(d, meta)
+}
+
+func _resourceNodeFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_firewall_rules_read")
+	logger.Info().Str("node", node).Msg("Reading firewall rules of node")
+
+	rules, err := firewallGetRules(pconf.Session, apiPath("nodes", node, "firewall", "rules"))
+	if err != nil {
+		return err
+	}
+	d.Set("node", node)
+	if err = d.Set("rule", rules); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("node", node).Msgf("Finished node firewall rules read resulting in data: '%+v'", rules)
+	return nil
+}
+
+func resourceNodeFirewallRulesUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_node_firewall_rules_update")
+	logger.Info().Str("node", node).Msg("Writing firewall rules of node")
+
+	if err = firewallSetRules(pconf.Session, apiPath("nodes", node, "firewall", "rules"), d.Get("rule").([]interface{})); err != nil {
+		return err
+	}
+	return _resourceNodeFirewallRulesRead(d, meta)
+}
+
+// All rules of the node are deleted.
+func resourceNodeFirewallRulesDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return firewallSetRules(pconf.Session, apiPath("nodes", node, "firewall", "rules"), []interface{}{})
+}
@@ -0,0 +1,222 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Proxmox only reads and writes /etc/hosts of a node as a whole. The entries of the same node are
+// changed one after the other, the digest of the file guards against changes made at the same
+// time outside of terraform.
+var nodeHostsMutex sync.Mutex
+
+func resourceNodeHostsEntry() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages an entry of /etc/hosts of a node, the host names an IP address resolves to on the node.",
+
+		Create: resourceNodeHostsEntryCreate,
+		Read:   resourceNodeHostsEntryRead,
+		Update: resourceNodeHostsEntryUpdate,
+		Delete: resourceNodeHostsEntryDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the entry is on",
+			},
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The IP address of the entry",
+			},
+			"hostnames": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The host names the address resolves to, the first one is the canonical name",
+			},
+		},
+	}
+}
+
+func nodeHostsEntryId(node string, address string) string {
+	return fmt.Sprintf("%s/%s", node, address)
+}
+
+func parseNodeHostsEntryId(id string) (node string, address string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid resource format: %s. Must be node/address", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// The fields of a line of /etc/hosts without its comment, empty for comments and blank lines.
+func nodeHostsFields(line string) []string {
+	if comment := strings.Index(line, "#"); comment >= 0 {
+		line = line[:comment]
+	}
+	return strings.Fields(line)
+}
+
+// The host names of address in hosts, nil when it has no entry. The names of several lines of
+// the address are merged.
+func nodeHostsFind(hosts string, address string) []string {
+	var hostnames []string
+	for _, line := range strings.Split(hosts, "\n") {
+		if fields := nodeHostsFields(line); len(fields) > 1 && fields[0] == address {
+			hostnames = append(hostnames, fields[1:]...)
+		}
+	}
+	return hostnames
+}
+
+// hosts with the entry of address replaced by hostnames, or removed when hostnames is empty. A
+// new entry is appended, the other lines are kept as they are.
+func nodeHostsSet(hosts string, address string, hostnames []string) string {
+	lines := []string{}
+	replaced := false
+	for _, line := range strings.Split(strings.TrimRight(hosts, "\n"), "\n") {
+		if line == "" && len(lines) == 0 && hosts == "" {
+			continue
+		}
+		if fields := nodeHostsFields(line); len(fields) > 0 && fields[0] == address {
+			if !replaced && len(hostnames) > 0 {
+				lines = append(lines, address+" "+strings.Join(hostnames, " "))
+			}
+			replaced = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if !replaced && len(hostnames) > 0 {
+		lines = append(lines, address+" "+strings.Join(hostnames, " "))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func nodeHosts(session *pxapi.Session, node string) (hosts string, digest string, err error) {
+	config, err := apiGetMap(session, apiPath("nodes", node, "hosts"))
+	if err != nil {
+		return "", "", err
+	}
+	return apiString(config["data"]), apiString(config["digest"]), nil
+}
+
+// Writes the entry of address on node, hostnames empty removes it.
+func writeNodeHostsEntry(session *pxapi.Session, node string, address string, hostnames []string) error {
+	nodeHostsMutex.Lock()
+	defer nodeHostsMutex.Unlock()
+
+	hosts, digest, err := nodeHosts(session, node)
+	if err != nil {
+		return err
+	}
+	_, err = apiPost(session, apiPath("nodes", node, "hosts"), map[string]interface{}{
+		"data":   nodeHostsSet(hosts, address, hostnames),
+		"digest": digest,
+	})
+	return err
+}
+
+func resourceNodeHostsEntryCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	address := d.Get("address").(string)
+
+	hosts, _, err := nodeHosts(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	if hostnames := nodeHostsFind(hosts, address); hostnames != nil {
+		return fmt.Errorf("/etc/hosts of %s already has an entry for %s (%s), import it instead", node, address, strings.Join(hostnames, " "))
+	}
+
+	logger, _ := CreateSubLogger("resource_node_hosts_entry_create")
+	logger.Info().Str("node", node).Msgf("Adding hosts entry for %s", address)
+
+	if err = writeNodeHostsEntry(pconf.Session, node, address, schemaStringList(d.Get("hostnames"))); err != nil {
+		return err
+	}
+	d.SetId(nodeHostsEntryId(node, address))
+	return _resourceNodeHostsEntryRead(d, meta)
+}
+
+func resourceNodeHostsEntryRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeHostsEntryRead(d, meta)
+}
+
+func _resourceNodeHostsEntryRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, address, err := parseNodeHostsEntryId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_hosts_entry_read")
+	logger.Info().Str("node", node).Msgf("Reading hosts entry for %s", address)
+
+	hosts, _, err := nodeHosts(pconf.Session, node)
+	if err != nil {
+		return err
+	}
+	hostnames := nodeHostsFind(hosts, address)
+	if hostnames == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", node)
+	d.Set("address", address)
+	return d.Set("hostnames", hostnames)
+}
+
+func resourceNodeHostsEntryUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, address, err := parseNodeHostsEntryId(d.Id())
+	if err != nil {
+		return err
+	}
+	if err = writeNodeHostsEntry(pconf.Session, node, address, schemaStringList(d.Get("hostnames"))); err != nil {
+		return err
+	}
+	return _resourceNodeHostsEntryRead(d, meta)
+}
+
+func resourceNodeHostsEntryDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, address, err := parseNodeHostsEntryId(d.Id())
+	if err != nil {
+		return err
+	}
+	return writeNodeHostsEntry(pconf.Session, node, address, nil)
+}
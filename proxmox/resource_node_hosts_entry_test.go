@@ -0,0 +1,67 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testNodeHosts = `127.0.0.1 localhost.localdomain localhost
+10.0.0.11 pve1.example.com pve1
+# build servers
+10.0.0.50 build1 # old name below
+10.0.0.50 ci
+
+::1 ip6-localhost ip6-loopback
+`
+
+func TestNodeHostsFind(t *testing.T) {
+	if hostnames := nodeHostsFind(testNodeHosts, "10.0.0.50"); !reflect.DeepEqual(hostnames, []string{"build1", "ci"}) {
+		t.Errorf("unexpected host names `%v`", hostnames)
+	}
+	if hostnames := nodeHostsFind(testNodeHosts, "10.0.0.5"); hostnames != nil {
+		t.Errorf("expected no entry, got `%v`", hostnames)
+	}
+}
+
+func TestNodeHostsSet(t *testing.T) {
+	expected := `127.0.0.1 localhost.localdomain localhost
+10.0.0.11 pve1.example.com pve1
+# build servers
+10.0.0.50 build1.example.com build1
+
+::1 ip6-localhost ip6-loopback
+`
+	if hosts := nodeHostsSet(testNodeHosts, "10.0.0.50", []string{"build1.example.com", "build1"}); hosts != expected {
+		t.Errorf("unexpected replaced hosts:\n%s", hosts)
+	}
+
+	expected = testNodeHosts + "10.0.0.60 web1\n"
+	if hosts := nodeHostsSet(testNodeHosts, "10.0.0.60", []string{"web1"}); hosts != expected {
+		t.Errorf("unexpected added hosts:\n%s", hosts)
+	}
+
+	expected = `127.0.0.1 localhost.localdomain localhost
+# build servers
+10.0.0.50 build1 # old name below
+10.0.0.50 ci
+
+::1 ip6-localhost ip6-loopback
+`
+	if hosts := nodeHostsSet(testNodeHosts, "10.0.0.11", nil); hosts != expected {
+		t.Errorf("unexpected removed hosts:\n%s", hosts)
+	}
+
+	if hosts := nodeHostsSet("", "10.0.0.60", []string{"web1"}); hosts != "10.0.0.60 web1\n" {
+		t.Errorf("unexpected hosts from empty file: %q", hosts)
+	}
+}
+
+func TestParseNodeHostsEntryId(t *testing.T) {
+	node, address, err := parseNodeHostsEntryId(nodeHostsEntryId("pve1", "2001:db8::50"))
+	if err != nil || node != "pve1" || address != "2001:db8::50" {
+		t.Errorf("unexpected entry `%s` `%s` `%v`", node, address, err)
+	}
+	if _, _, err = parseNodeHostsEntryId("pve1"); err == nil {
+		t.Error("expected an error")
+	}
+}
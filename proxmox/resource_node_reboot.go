@@ -0,0 +1,257 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var nodeRebootResourceDef *schema.Resource
+
+// how often a rebooting node is polled
+var nodeRebootPollInterval = 10 * time.Second
+
+func resourceNodeReboot() *schema.Resource {
+	*pxapi.Debug = true
+
+	nodeRebootResourceDef = &schema.Resource{
+		Description: "Reboots a node for maintenance.",
+
+		Create: resourceNodeRebootCreate,
+		Read:   resourceNodeRebootRead,
+		Update: resourceNodeRebootUpdate,
+		Delete: resourceNodeRebootDelete,
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node to reboot.",
+			},
+			"trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Changing this value reboots the node again",
+			},
+			"drain": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Migrate the guests off the node before rebooting it",
+			},
+			"guests": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The ids of the guests to migrate, all guests on the node when empty",
+			},
+			"drain_targets": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes the guests are spread over, all other online nodes when empty",
+			},
+			"migrate_back": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Migrate the drained guests back once the node is up again",
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      900,
+				ValidateFunc: validation.IntAtLeast(60),
+				Description:  "Seconds to wait for the node to come back after the reboot",
+			},
+			"migrated": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The node each guest was migrated to by the last reboot",
+			},
+		},
+	}
+
+	return nodeRebootResourceDef
+}
+
+func resourceNodeRebootCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("reboot", d.Get("node").(string)))
+	return rebootNode(d, meta)
+}
+
+func resourceNodeRebootUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("trigger") {
+		return rebootNode(d, meta)
+	}
+	return nil
+}
+
+// nothing to read, the reboot is an action
+func resourceNodeRebootRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceNodeRebootDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// a guest to move off the node
+type drainGuest struct {
+	vmid      int
+	guestType string
+	running   bool
+}
+
+// Spreads the guests over the targets round robin, in the order of their ids.
+func drainTargets(guests []drainGuest, targets []string) map[int]string {
+	sorted := make([]drainGuest, len(guests))
+	copy(sorted, guests)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].vmid < sorted[j].vmid })
+	assigned := map[int]string{}
+	for i, guest := range sorted {
+		assigned[guest.vmid] = targets[i%len(targets)]
+	}
+	return assigned
+}
+
+// the online nodes other than node
+func otherOnlineNodes(session *pxapi.Session, node string) ([]string, error) {
+	data, err := apiGet(session, "/nodes")
+	if err != nil {
+		return nil, err
+	}
+	nodes := []string{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		entry, _ := item.(map[string]interface{})
+		if name := apiString(entry["node"]); name != node && apiString(entry["status"]) == "online" {
+			nodes = append(nodes, name)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// VMs are migrated online when running, containers can not be and are restarted on the target.
+func migrateGuest(session *pxapi.Session, client *pxapi.Client, guest drainGuest, from string, to string, bwlimit int) error {
+	params := map[string]interface{}{"target": to}
+	if guest.guestType == "lxc" {
+		params["restart"] = guest.running
+	} else {
+		params["online"] = guest.running
+		params["with-local-disks"] = true
+		if bwlimit != 0 {
+			params["bwlimit"] = bwlimit
+		}
+	}
+	_, err := apiPostTask(session, client, apiPath("nodes", from, guest.guestType, strconv.Itoa(guest.vmid), "migrate"), params)
+	if err != nil {
+		return fmt.Errorf("Migrating guest %d from %s to %s failed: %v", guest.vmid, from, to, err)
+	}
+	return nil
+}
+
+func nodeUptime(session *pxapi.Session, node string) (int, error) {
+	status, err := apiGetMap(session, apiPath("nodes", node, "status"))
+	if err != nil {
+		return 0, err
+	}
+	return apiInt(status["uptime"]), nil
+}
+
+func rebootNode(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	session := pconf.Session
+	client := pconf.Client
+
+	node := d.Get("node").(string)
+	logger, _ := CreateSubLogger("resource_node_reboot")
+
+	guests := []drainGuest{}
+	assigned := map[int]string{}
+	if d.Get("drain").(bool) && pconf.Standalone {
+		logger.Warn().Str("node", node).Msg("The node is not part of a cluster, its guests are shut down by the reboot instead of being drained")
+	} else if d.Get("drain").(bool) {
+		selected := map[int]bool{}
+		for _, vmid := range d.Get("guests").(*schema.Set).List() {
+			selected[vmid.(int)] = true
+		}
+		all, err := apiGetGuests(session)
+		if err != nil {
+			return err
+		}
+		for _, guest := range all {
+			vmid := apiInt(guest["vmid"])
+			if apiString(guest["node"]) != node || apiBool(guest["template"]) || (len(selected) > 0 && !selected[vmid]) {
+				continue
+			}
+			guests = append(guests, drainGuest{vmid: vmid, guestType: apiString(guest["type"]), running: apiString(guest["status"]) == "running"})
+		}
+
+		targets := schemaStringList(d.Get("drain_targets"))
+		if len(targets) == 0 {
+			if targets, err = otherOnlineNodes(session, node); err != nil {
+				return err
+			}
+		}
+		if len(guests) > 0 && len(targets) == 0 {
+			return fmt.Errorf("Can not drain node %s, there is no other online node", node)
+		}
+
+		assigned = drainTargets(guests, targets)
+		for _, guest := range guests {
+			logger.Info().Int("vmid", guest.vmid).Msgf("Draining guest from %s to %s", node, assigned[guest.vmid])
+			if err = migrateGuest(session, client, guest, node, assigned[guest.vmid], pconf.BwLimitMigrate); err != nil {
+				return err
+			}
+		}
+	}
+
+	migrated := map[string]interface{}{}
+	for vmid, target := range assigned {
+		migrated[strconv.Itoa(vmid)] = target
+	}
+	d.Set("migrated", migrated)
+
+	uptime, err := nodeUptime(session, node)
+	if err != nil {
+		return err
+	}
+	logger.Info().Str("node", node).Msg("Rebooting node")
+	if _, err = apiPost(session, apiPath("nodes", node, "status"), map[string]interface{}{"command": "reboot"}); err != nil {
+		return err
+	}
+
+	// the node is back once it answers with an uptime lower than before the reboot
+	timeout := time.Duration(d.Get("timeout").(int)) * time.Second
+	for started := time.Now(); ; {
+		time.Sleep(nodeRebootPollInterval)
+		if current, err := nodeUptime(session, node); err == nil && current < uptime {
+			break
+		}
+		if time.Since(started) > timeout {
+			return fmt.Errorf("Node %s did not come back within %v after the reboot", node, timeout)
+		}
+	}
+	logger.Info().Str("node", node).Msg("Node is back after the reboot")
+
+	if d.Get("migrate_back").(bool) {
+		for _, guest := range guests {
+			if err = migrateGuest(session, client, guest, assigned[guest.vmid], node, pconf.BwLimitMigrate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrainTargets(t *testing.T) {
+	guests := []drainGuest{
+		{vmid: 102, guestType: "lxc"},
+		{vmid: 100, guestType: "qemu", running: true},
+		{vmid: 101, guestType: "qemu"},
+	}
+
+	tests := []struct {
+		name     string
+		targets  []string
+		expected map[int]string
+	}{
+		{"single target", []string{"pve2"}, map[int]string{100: "pve2", 101: "pve2", 102: "pve2"}},
+		{"round robin", []string{"pve2", "pve3"}, map[int]string{100: "pve2", 101: "pve3", 102: "pve2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if assigned := drainTargets(guests, test.targets); !reflect.DeepEqual(assigned, test.expected) {
+				t.Errorf("%s: expected `%v`, got `%v`", test.name, test.expected, assigned)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceNodeTime() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the timezone of a node.",
+
+		Create: resourceNodeTimeCreate,
+		Read:   resourceNodeTimeRead,
+		Update: resourceNodeTimeUpdate,
+		Delete: resourceNodeTimeDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the timezone is of",
+			},
+			"timezone": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTimezone,
+				Description:  "The timezone of the node, e.g. `Europe/Berlin` or `UTC`, as listed by `timedatectl list-timezones`",
+			},
+		},
+	}
+}
+
+// Timezones are names like Europe/Berlin, the list of the node is checked by proxmox.
+var rxTimezone = regexp.MustCompile(`^[A-Za-z0-9_+-]+(/[A-Za-z0-9_+-]+)*$`)
+
+func validateTimezone(value interface{}, key string) ([]string, []error) {
+	if !rxTimezone.MatchString(value.(string)) {
+		return nil, []error{fmt.Errorf("%s is not a timezone like Europe/Berlin or UTC: %s", key, value)}
+	}
+	return nil, nil
+}
+
+func resourceNodeTimeCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(clusterResourceId("time", d.Get("node").(string)))
+	return resourceNodeTimeUpdate(d, meta)
+}
+
+func resourceNodeTimeRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNodeTimeRead(d, meta)
+}
+
+func _resourceNodeTimeRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_node_time_read")
+	logger.Info().Str("node", node).Msg("Reading timezone of node")
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "time"))
+	if err != nil {
+		return err
+	}
+	d.Set("node", node)
+	d.Set("timezone", apiString(config["timezone"]))
+	return nil
+}
+
+func resourceNodeTimeUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, node, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_node_time_update")
+	logger.Info().Str("node", node).Msgf("Setting timezone of node to %s", d.Get("timezone").(string))
+
+	_, err = apiPut(pconf.Session, apiPath("nodes", node, "time"), map[string]interface{}{
+		"timezone": d.Get("timezone").(string),
+	})
+	if err != nil {
+		return err
+	}
+	return _resourceNodeTimeRead(d, meta)
+}
+
+// A node always has a timezone, it is left as it is and only removed from the state.
+func resourceNodeTimeDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,23 @@
+package proxmox
+
+import "testing"
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		valid    bool
+	}{
+		{"UTC", true},
+		{"Europe/Berlin", true},
+		{"America/Argentina/Buenos_Aires", true},
+		{"Etc/GMT+5", true},
+		{"", false},
+		{"Europe/", false},
+		{"Europe Berlin", false},
+	}
+	for _, test := range tests {
+		if _, errs := validateTimezone(test.timezone, "timezone"); (len(errs) == 0) != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.timezone, test.valid, errs)
+		}
+	}
+}
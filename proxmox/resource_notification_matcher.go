@@ -0,0 +1,221 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var notificationSeverities = []string{"info", "notice", "warning", "error", "unknown"}
+
+// attribute => the list parameter of the matcher
+var notificationMatcherLists = map[string]string{
+	"targets":        "target",
+	"match_severity": "match-severity",
+	"match_field":    "match-field",
+	"match_calendar": "match-calendar",
+}
+
+func resourceNotificationMatcher() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a notification matcher of the cluster, which sends the notifications it matches to notification targets.",
+
+		Create: resourceNotificationMatcherCreate,
+		Read:   resourceNotificationMatcherRead,
+		Update: resourceNotificationMatcherUpdate,
+		Delete: resourceNotificationMatcherDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the matcher",
+			},
+			"targets": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the notification targets the matched notifications are sent to",
+			},
+			"match_severity": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(notificationSeverities, false)},
+				Description: "Matches notifications of these severities: info, notice, warning, error or unknown",
+			},
+			"match_field": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Matches the metadata fields of notifications, e.g. `exact:type=vzdump` or `regex:hostname=^pve`",
+			},
+			"match_calendar": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Matches notifications sent at these calendar events, e.g. `mon..fri 8-17`",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "all",
+				ValidateFunc: validation.StringInSlice([]string{"all", "any"}, false),
+				Description:  "Whether all or any of the match rules have to match",
+			},
+			"invert_match": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Match the notifications the rules don't match",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment on the matcher",
+			},
+			"disable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the matcher is disabled",
+			},
+		},
+	}
+}
+
+// The parameters of the matcher, empty values are returned as the parameters to delete.
+func notificationMatcherParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"mode":         d.Get("mode").(string),
+		"invert-match": d.Get("invert_match").(bool),
+		"disable":      d.Get("disable").(bool),
+	}
+	deletes = []string{}
+	for attribute, parameter := range notificationMatcherLists {
+		if list := schemaStringList(d.Get(attribute)); len(list) > 0 {
+			sort.Strings(list)
+			params[parameter] = list
+		} else {
+			deletes = append(deletes, parameter)
+		}
+	}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	} else {
+		deletes = append(deletes, "comment")
+	}
+	sort.Strings(deletes)
+	return
+}
+
+func resourceNotificationMatcherCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	params, _ := notificationMatcherParams(d)
+	params["name"] = name
+
+	logger, _ := CreateSubLogger("resource_notification_matcher_create")
+	logger.Info().Str("name", name).Msg("Creating notification matcher")
+
+	if _, err := apiPost(pconf.Session, "/cluster/notifications/matchers", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("matchers", name))
+	return _resourceNotificationMatcherRead(d, meta)
+}
+
+func resourceNotificationMatcherRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNotificationMatcherRead(d, meta)
+}
+
+func _resourceNotificationMatcherRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_notification_matcher_read")
+	logger.Info().Str("name", name).Msg("Reading configuration for notification matcher")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "notifications", "matchers", name))
+	if err != nil {
+		if notificationNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", name)
+	for attribute, parameter := range notificationMatcherLists {
+		if err = d.Set(attribute, apiStringList(config[parameter], ",")); err != nil {
+			return err
+		}
+	}
+	// the severities of one rule may be comma separated, e.g. from the GUI
+	if err = d.Set("match_severity", apiStringList(strings.Join(apiStringList(config["match-severity"], ","), ","), ",")); err != nil {
+		return err
+	}
+	d.Set("mode", "all")
+	if mode := apiString(config["mode"]); mode != "" {
+		d.Set("mode", mode)
+	}
+	d.Set("invert_match", apiBool(config["invert-match"]))
+	d.Set("comment", apiString(config["comment"]))
+	d.Set("disable", apiBool(config["disable"]))
+	return nil
+}
+
+func resourceNotificationMatcherUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := notificationMatcherParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "notifications", "matchers", name), params); err != nil {
+		return err
+	}
+	return _resourceNotificationMatcherRead(d, meta)
+}
+
+func resourceNotificationMatcherDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "notifications", "matchers", name))
+	return err
+}
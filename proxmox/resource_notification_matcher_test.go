@@ -0,0 +1,33 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNotificationMatcherParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNotificationMatcher().Schema, map[string]interface{}{
+		"name":           "backup-failures",
+		"targets":        []interface{}{"mail", "chat"},
+		"match_severity": []interface{}{"error"},
+		"match_field":    []interface{}{"exact:type=vzdump"},
+	})
+
+	params, deletes := notificationMatcherParams(d)
+	expected := map[string]interface{}{
+		"mode":           "all",
+		"invert-match":   false,
+		"disable":        false,
+		"target":         []string{"chat", "mail"},
+		"match-severity": []string{"error"},
+		"match-field":    []string{"exact:type=vzdump"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment", "match-calendar"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
@@ -0,0 +1,422 @@
+package proxmox
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The notification targets (endpoints) and matchers of Proxmox VE 8.1 and later, the matchers
+// decide which notifications, e.g. of failed backups, are sent to which targets.
+
+var notificationTargetTypes = []string{"smtp", "gotify", "webhook"}
+
+// attribute => the parameter of the target
+var notificationTargetParameters = map[string]string{
+	"server":       "server",
+	"port":         "port",
+	"mode":         "mode",
+	"username":     "username",
+	"from_address": "from-address",
+	"mailto":       "mailto",
+	"mailto_user":  "mailto-user",
+	"author":       "author",
+	"url":          "url",
+	"method":       "method",
+	"body":         "body",
+	"headers":      "header",
+}
+
+// attribute => the target types using it, comment and disable are used by all types
+var notificationTargetTypeOptions = map[string][]string{
+	"server":       {"smtp", "gotify"},
+	"port":         {"smtp"},
+	"mode":         {"smtp"},
+	"username":     {"smtp"},
+	"password":     {"smtp"},
+	"from_address": {"smtp"},
+	"mailto":       {"smtp"},
+	"mailto_user":  {"smtp"},
+	"author":       {"smtp"},
+	"token":        {"gotify"},
+	"url":          {"webhook"},
+	"method":       {"webhook"},
+	"body":         {"webhook"},
+	"headers":      {"webhook"},
+	"secrets":      {"webhook"},
+}
+
+// target type => the attributes it requires
+var notificationTargetRequiredOptions = map[string][]string{
+	"smtp":    {"server", "from_address"},
+	"gotify":  {"server", "token"},
+	"webhook": {"url"},
+}
+
+func resourceNotificationTarget() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a notification target of the cluster, an SMTP server, a Gotify server or a webhook the notifications are sent to.",
+
+		Create: resourceNotificationTargetCreate,
+		Read:   resourceNotificationTargetRead,
+		Update: resourceNotificationTargetUpdate,
+		Delete: resourceNotificationTargetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: sdnOptionsCustomizeDiff("notification targets", notificationTargetTypeOptions, notificationTargetRequiredOptions),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the target, which the matchers refer to",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(notificationTargetTypes, false),
+				Description:  "The type of the target: smtp, gotify or webhook",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment on the target",
+			},
+			"disable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the target is disabled",
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "smtp, gotify: The host name of the SMTP server or the URL of the Gotify server",
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "smtp: The port of the SMTP server, the default port of mode when not set",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"insecure", "starttls", "tls"}, false),
+				Description:  "smtp: How the connection is encrypted: insecure, starttls or tls, tls when not set",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "smtp: The user name to authenticate with",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "smtp: The password to authenticate with. Proxmox doesn't return it, so changes made outside of Terraform are not noticed",
+			},
+			"from_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "smtp: The sender address of the mails",
+			},
+			"mailto": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "smtp: The addresses the mails are sent to",
+			},
+			"mailto_user": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "smtp: The users, e.g. root@pam, the mails are sent to the address of",
+			},
+			"author": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "smtp: The sender name of the mails, `Proxmox VE` when not set",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "gotify: The application token. Proxmox doesn't return it, so changes made outside of Terraform are not noticed",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "webhook: The URL the notifications are sent to, it may use templates like `{{ secrets.token }}`",
+			},
+			"method": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"post", "put", "get"}, false),
+				Description:  "webhook: The HTTP method: post, put or get, post when not set",
+			},
+			"body": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "webhook: The template of the request body, e.g. `{\"text\": \"{{ escape message }}\"}`",
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "webhook: The HTTP headers of the request, the values may use templates",
+			},
+			"secrets": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "webhook: The secrets the templates refer to as `{{ secrets.<name> }}`. Proxmox doesn't return them, so changes made outside of Terraform are not noticed",
+			},
+		},
+	}
+}
+
+// Whether err is proxmox telling that a notification target or matcher doesn't exist.
+func notificationNotFound(err error) bool {
+	return strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist")
+}
+
+// The name=<name>,value=<base64 value> property strings of the headers and secrets of webhooks.
+func notificationWebhookPairs(values map[string]interface{}) []string {
+	pairs := []string{}
+	for name, value := range values {
+		pairs = append(pairs, fmt.Sprintf("name=%s,value=%s", name, base64.StdEncoding.EncodeToString([]byte(value.(string)))))
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// proxmox returns the pairs as property strings or decoded as objects. The values are parsed
+// here instead of by apiPropertyString, which cuts the padding of base64 at the first =.
+func parseNotificationWebhookPairs(value interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	pairs, _ := value.([]interface{})
+	for _, pair := range pairs {
+		conf, ok := pair.(map[string]interface{})
+		if !ok {
+			conf = map[string]interface{}{}
+			for _, item := range strings.Split(apiString(pair), ",") {
+				if keyValue := strings.SplitN(item, "=", 2); len(keyValue) == 2 {
+					conf[keyValue[0]] = keyValue[1]
+				}
+			}
+		}
+		decoded, err := base64.StdEncoding.DecodeString(apiString(conf["value"]))
+		if err != nil {
+			decoded = []byte(apiString(conf["value"]))
+		}
+		values[apiString(conf["name"])] = string(decoded)
+	}
+	return values
+}
+
+// The parameters of the target, empty values are returned as the parameters to delete. The
+// secrets are only sent when they are new or changed.
+func notificationTargetParams(d *schema.ResourceData, update bool) (params map[string]interface{}, deletes []string) {
+	targetType := d.Get("type").(string)
+	params = map[string]interface{}{
+		"disable": d.Get("disable").(bool),
+	}
+	deletes = []string{}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	} else {
+		deletes = append(deletes, "comment")
+	}
+
+	for attribute, parameter := range notificationTargetParameters {
+		if !stringInList(targetType, notificationTargetTypeOptions[attribute]) {
+			continue
+		}
+		var value interface{}
+		switch v := d.Get(attribute).(type) {
+		case int:
+			if v != 0 {
+				value = v
+			}
+		case *schema.Set:
+			if list := schemaStringList(v); len(list) > 0 {
+				sort.Strings(list)
+				value = list
+			}
+		case map[string]interface{}:
+			if len(v) > 0 {
+				value = notificationWebhookPairs(v)
+			}
+		case string:
+			if v != "" && attribute == "body" {
+				value = base64.StdEncoding.EncodeToString([]byte(v))
+			} else if v != "" {
+				value = v
+			}
+		}
+		if value != nil {
+			params[parameter] = value
+		} else if !notificationTargetRequiredParameter(targetType, attribute) {
+			deletes = append(deletes, parameter)
+		}
+	}
+
+	secrets := map[string]string{"smtp": "password", "gotify": "token", "webhook": "secrets"}
+	if attribute := secrets[targetType]; !update || d.HasChange(attribute) {
+		switch v := d.Get(attribute).(type) {
+		case string:
+			if v != "" {
+				params[attribute] = v
+			} else {
+				deletes = append(deletes, attribute)
+			}
+		case map[string]interface{}:
+			if len(v) > 0 {
+				params["secret"] = notificationWebhookPairs(v)
+			} else {
+				deletes = append(deletes, "secret")
+			}
+		}
+	}
+	sort.Strings(deletes)
+	return
+}
+
+func notificationTargetRequiredParameter(targetType string, attribute string) bool {
+	return stringInList(attribute, notificationTargetRequiredOptions[targetType])
+}
+
+func resourceNotificationTargetCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	name := d.Get("name").(string)
+	targetType := d.Get("type").(string)
+	params, _ := notificationTargetParams(d, false)
+	params["name"] = name
+
+	logger, _ := CreateSubLogger("resource_notification_target_create")
+	logger.Info().Str("name", name).Msgf("Creating %s notification target", targetType)
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, apiPath("cluster", "notifications", "endpoints", targetType), params)
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId(targetType, name))
+	return _resourceNotificationTargetRead(d, meta)
+}
+
+func resourceNotificationTargetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceNotificationTargetRead(d, meta)
+}
+
+func _resourceNotificationTargetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	targetType, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_notification_target_read")
+	logger.Info().Str("name", name).Msg("Reading configuration for notification target")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "notifications", "endpoints", targetType, name))
+	if err != nil {
+		if notificationNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", name)
+	d.Set("type", targetType)
+	d.Set("comment", apiString(config["comment"]))
+	d.Set("disable", apiBool(config["disable"]))
+	for attribute, parameter := range notificationTargetParameters {
+		if !stringInList(targetType, notificationTargetTypeOptions[attribute]) {
+			continue
+		}
+		value := config[parameter]
+		switch attribute {
+		case "port":
+			d.Set(attribute, apiInt(value))
+		case "mailto", "mailto_user":
+			if err = d.Set(attribute, apiStringList(value, ",")); err != nil {
+				return err
+			}
+		case "headers":
+			if err = d.Set(attribute, parseNotificationWebhookPairs(value)); err != nil {
+				return err
+			}
+		case "body":
+			decoded, err := base64.StdEncoding.DecodeString(apiString(value))
+			if err != nil {
+				decoded = []byte(apiString(value))
+			}
+			d.Set(attribute, string(decoded))
+		default:
+			d.Set(attribute, apiString(value))
+		}
+	}
+	return nil
+}
+
+func resourceNotificationTargetUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	targetType, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := notificationTargetParams(d, true)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPut(pconf.Session, apiPath("cluster", "notifications", "endpoints", targetType, name), params)
+	})
+	if err != nil {
+		return err
+	}
+	return _resourceNotificationTargetRead(d, meta)
+}
+
+// proxmox refuses to delete targets which matchers still refer to
+func resourceNotificationTargetDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	targetType, name, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "notifications", "endpoints", targetType, name))
+	return err
+}
@@ -0,0 +1,98 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNotificationTargetParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNotificationTarget().Schema, map[string]interface{}{
+		"name":         "mail",
+		"type":         "smtp",
+		"server":       "mail.example.com",
+		"mode":         "starttls",
+		"username":     "pve",
+		"password":     "secret",
+		"from_address": "pve@example.com",
+		"mailto":       []interface{}{"ops@example.com", "admin@example.com"},
+	})
+
+	params, deletes := notificationTargetParams(d, false)
+	expected := map[string]interface{}{
+		"disable":      false,
+		"server":       "mail.example.com",
+		"mode":         "starttls",
+		"username":     "pve",
+		"password":     "secret",
+		"from-address": "pve@example.com",
+		"mailto":       []string{"admin@example.com", "ops@example.com"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"author", "comment", "mailto-user", "port"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceNotificationTarget().Schema, map[string]interface{}{
+		"name":    "chat",
+		"type":    "webhook",
+		"url":     "https://chat.example.com/hooks/{{ secrets.token }}",
+		"body":    `{"text": "{{ escape message }}"}`,
+		"headers": map[string]interface{}{"Content-Type": "application/json"},
+		"secrets": map[string]interface{}{"token": "abc"},
+	})
+	params, deletes = notificationTargetParams(d, false)
+	expected = map[string]interface{}{
+		"disable": false,
+		"url":     "https://chat.example.com/hooks/{{ secrets.token }}",
+		"body":    "eyJ0ZXh0IjogInt7IGVzY2FwZSBtZXNzYWdlIH19In0=",
+		"header":  []string{"name=Content-Type,value=YXBwbGljYXRpb24vanNvbg=="},
+		"secret":  []string{"name=token,value=YWJj"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment", "method"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestParseNotificationWebhookPairs(t *testing.T) {
+	headers := map[string]interface{}{"Content-Type": "application/json", "X-Token": "a=b"}
+	pairs := []interface{}{}
+	for _, pair := range notificationWebhookPairs(headers) {
+		pairs = append(pairs, pair)
+	}
+	if parsed := parseNotificationWebhookPairs(pairs); !reflect.DeepEqual(parsed, headers) {
+		t.Errorf("expected `%v`, got `%v`", headers, parsed)
+	}
+
+	decoded := parseNotificationWebhookPairs([]interface{}{map[string]interface{}{"name": "X-Token", "value": "YT1i"}})
+	if !reflect.DeepEqual(decoded, map[string]interface{}{"X-Token": "a=b"}) {
+		t.Errorf("unexpected pairs `%v`", decoded)
+	}
+}
+
+func TestNotificationTargetOptions(t *testing.T) {
+	tests := []struct {
+		targetType string
+		set        map[string]bool
+		valid      bool
+	}{
+		{"smtp", map[string]bool{"server": true, "from_address": true, "mailto": true}, true},
+		{"smtp", map[string]bool{"server": true}, false},
+		{"gotify", map[string]bool{"server": true, "token": true}, true},
+		{"gotify", map[string]bool{"server": true, "token": true, "mailto": true}, false},
+		{"webhook", map[string]bool{"url": true, "headers": true}, true},
+		{"webhook", map[string]bool{"url": true, "token": true}, false},
+	}
+	for _, test := range tests {
+		err := checkSdnOptions("notification targets", test.targetType, test.set, notificationTargetTypeOptions, notificationTargetRequiredOptions)
+		if (err == nil) != test.valid {
+			t.Errorf("%s %v: expected valid %v, got `%v`", test.targetType, test.set, test.valid, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package proxmox
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,6 +15,8 @@ func resourcePool() *schema.Resource {
 	*pxapi.Debug = true
 
 	poolResourceDef = &schema.Resource{
+		Description: "Manages a resource pool.",
+
 		Create: resourcePoolCreate,
 		Read:   resourcePoolRead,
 		Update: resourcePoolUpdate,
@@ -23,13 +27,21 @@ func resourcePool() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"poolid": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the pool.",
 			},
 			"comment": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Remove the guests and storages from the pool when it is destroyed, instead of failing while the pool has members",
 			},
 		},
 	}
@@ -119,6 +131,44 @@ func resourcePoolUpdate(d *schema.ResourceData, meta interface{}) error {
 	return _resourcePoolRead(d, meta)
 }
 
+// The guests and storages in a pool as listed by /pools/<poolid>, by vmid and storage id.
+func poolMembers(config map[string]interface{}) (vmids []string, storages []string) {
+	vmids = []string{}
+	storages = []string{}
+	members, _ := config["members"].([]interface{})
+	for _, item := range members {
+		member, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch apiString(member["type"]) {
+		case "qemu", "lxc":
+			vmids = append(vmids, apiString(member["vmid"]))
+		case "storage":
+			// a storage is listed once per node
+			if storage := apiString(member["storage"]); !stringInList(storage, storages) {
+				storages = append(storages, storage)
+			}
+		}
+	}
+	sort.Strings(vmids)
+	sort.Strings(storages)
+	return
+}
+
+func poolMembersError(poolID string, vmids []string, storages []string) error {
+	members := []string{}
+	if len(vmids) > 0 {
+		members = append(members, "guests "+strings.Join(vmids, ", "))
+	}
+	if len(storages) > 0 {
+		members = append(members, "storages "+strings.Join(storages, ", "))
+	}
+	return fmt.Errorf("Pool %s still has the members %s, remove them or set force_destroy to remove them from the pool", poolID, strings.Join(members, " and "))
+}
+
+// Proxmox only deletes empty pools. The members are removed from the pool with force_destroy,
+// the guests and storages themselves are kept. A pool already gone is deleted.
 func resourcePoolDelete(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
 	lock := pmParallelBegin(pconf)
@@ -126,6 +176,36 @@ func resourcePoolDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := pconf.Client
 	_, poolID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_pool_delete")
+
+	config, err := apiGetMap(pconf.Session, apiPath("pools", poolID))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil
+		}
+		return err
+	}
+	vmids, storages := poolMembers(config)
+	if len(vmids) > 0 || len(storages) > 0 {
+		if !d.Get("force_destroy").(bool) {
+			return poolMembersError(poolID, vmids, storages)
+		}
+		logger.Info().Str("poolid", poolID).Msgf("Removing guests %v and storages %v from the pool", vmids, storages)
+		params := map[string]interface{}{"delete": true}
+		if len(vmids) > 0 {
+			params["vms"] = strings.Join(vmids, ",")
+		}
+		if len(storages) > 0 {
+			params["storage"] = strings.Join(storages, ",")
+		}
+		if _, err = apiPut(pconf.Session, apiPath("pools", poolID), params); err != nil {
+			return fmt.Errorf("Removing the members of pool %s failed: %v", poolID, err)
+		}
+	}
 
 	err = client.DeletePool(poolID)
 	if err != nil {
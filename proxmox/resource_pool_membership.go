@@ -0,0 +1,177 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourcePoolMembership() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages the membership of an existing guest or storage in a resource pool.",
+
+		Create: resourcePoolMembershipCreate,
+		Read:   resourcePoolMembershipRead,
+		Delete: resourcePoolMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"poolid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the pool",
+			},
+			"vmid": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"vmid", "storage"},
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the VM or container joining the pool",
+			},
+			"storage": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"vmid", "storage"},
+				Description:  "The id of the storage joining the pool",
+			},
+			"allow_move": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				ForceNew:     true,
+				RequiredWith: []string{"vmid"},
+				Description:  "Move the guest out of the pool it is in, a guest is in one pool at most",
+			},
+		},
+	}
+}
+
+// ids look like <type>/<member>/<poolid>, e.g. vm/100/web or storage/local-lvm/web
+func poolMembershipId(poolID string, memberType string, member string) string {
+	return fmt.Sprintf("%s/%s/%s", memberType, member, poolID)
+}
+
+func parsePoolMembershipId(id string) (poolID string, memberType string, member string, err error) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 || (parts[0] != "vm" && parts[0] != "storage") || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("Invalid resource format: %s. Must be vm/vmid/poolid or storage/storage/poolid", id)
+	}
+	if _, err := strconv.Atoi(parts[1]); parts[0] == "vm" && err != nil {
+		return "", "", "", fmt.Errorf("Invalid resource format: %s. %s is not a vmid", id, parts[1])
+	}
+	return parts[2], parts[0], parts[1], nil
+}
+
+// The parameter of /pools/<poolid> adding or removing the member.
+func poolMembershipParameter(memberType string) string {
+	if memberType == "vm" {
+		return "vms"
+	}
+	return "storage"
+}
+
+func resourcePoolMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	poolID := d.Get("poolid").(string)
+	memberType, member := "storage", d.Get("storage").(string)
+	if vmid, ok := d.GetOk("vmid"); ok {
+		memberType, member = "vm", strconv.Itoa(vmid.(int))
+	}
+
+	logger, _ := CreateSubLogger("resource_pool_membership_create")
+	logger.Info().Str("poolid", poolID).Msgf("Adding %s %s to the pool", memberType, member)
+
+	params := map[string]interface{}{
+		poolMembershipParameter(memberType): member,
+	}
+	if d.Get("allow_move").(bool) {
+		params["allow-move"] = true
+	}
+	if _, err := apiPut(pconf.Session, apiPath("pools", poolID), params); err != nil {
+		return fmt.Errorf("Adding %s %s to pool %s failed: %v", memberType, member, poolID, err)
+	}
+	d.SetId(poolMembershipId(poolID, memberType, member))
+	return _resourcePoolMembershipRead(d, meta)
+}
+
+func resourcePoolMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourcePoolMembershipRead(d, meta)
+}
+
+func _resourcePoolMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	poolID, memberType, member, err := parsePoolMembershipId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_pool_membership_read")
+	logger.Info().Str("poolid", poolID).Msgf("Reading membership of %s %s", memberType, member)
+
+	config, err := apiGetMap(pconf.Session, apiPath("pools", poolID))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	vmids, storages := poolMembers(config)
+	members := storages
+	if memberType == "vm" {
+		members = vmids
+	}
+	if !stringInList(member, members) {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("poolid", poolID)
+	if memberType == "vm" {
+		vmid, _ := strconv.Atoi(member)
+		d.Set("vmid", vmid)
+	} else {
+		d.Set("storage", member)
+	}
+	return nil
+}
+
+// Only the membership is removed, the guest or storage is kept.
+func resourcePoolMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	poolID, memberType, member, err := parsePoolMembershipId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiPut(pconf.Session, apiPath("pools", poolID), map[string]interface{}{
+		poolMembershipParameter(memberType): member,
+		"delete":                            true,
+	})
+	if err != nil && !strings.Contains(err.Error(), "does not exist") && !strings.Contains(err.Error(), "not a pool member") {
+		return fmt.Errorf("Removing %s %s from pool %s failed: %v", memberType, member, poolID, err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package proxmox
+
+import "testing"
+
+func TestParsePoolMembershipId(t *testing.T) {
+	tests := []struct {
+		id         string
+		poolID     string
+		memberType string
+		member     string
+		valid      bool
+	}{
+		{"vm/100/web", "web", "vm", "100", true},
+		{"storage/local-lvm/web", "web", "storage", "local-lvm", true},
+		{"vm/100/teams/web", "teams/web", "vm", "100", true},
+		{"vm/web/100", "", "", "", false},
+		{"lxc/100/web", "", "", "", false},
+		{"vm/100", "", "", "", false},
+	}
+	for _, test := range tests {
+		poolID, memberType, member, err := parsePoolMembershipId(test.id)
+		if (err == nil) != test.valid || poolID != test.poolID || memberType != test.memberType || member != test.member {
+			t.Errorf("%s: expected %s %s %s valid %v, got %s %s %s `%v`", test.id, test.poolID, test.memberType, test.member, test.valid, poolID, memberType, member, err)
+		}
+	}
+	if id := poolMembershipId("web", "vm", "100"); id != "vm/100/web" {
+		t.Errorf("expected vm/100/web, got %s", id)
+	}
+}
@@ -0,0 +1,26 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPoolMembers(t *testing.T) {
+	config := map[string]interface{}{
+		"members": []interface{}{
+			map[string]interface{}{"id": "qemu/101", "type": "qemu", "vmid": float64(101), "node": "pve1"},
+			map[string]interface{}{"id": "lxc/100", "type": "lxc", "vmid": float64(100), "node": "pve2"},
+			map[string]interface{}{"id": "storage/pve1/nfs", "type": "storage", "storage": "nfs", "node": "pve1"},
+			map[string]interface{}{"id": "storage/pve2/nfs", "type": "storage", "storage": "nfs", "node": "pve2"},
+		},
+	}
+	vmids, storages := poolMembers(config)
+	if !reflect.DeepEqual(vmids, []string{"100", "101"}) || !reflect.DeepEqual(storages, []string{"nfs"}) {
+		t.Errorf("unexpected members `%v` `%v`", vmids, storages)
+	}
+
+	expected := "Pool tenant still has the members guests 100, 101 and storages nfs, remove them or set force_destroy to remove them from the pool"
+	if err := poolMembersError("tenant", vmids, storages); err.Error() != expected {
+		t.Errorf("expected `%s`, got `%v`", expected, err)
+	}
+}
@@ -0,0 +1,244 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var realmAdResourceDef *schema.Resource
+
+// schema attribute => parameter of /access/domains for realms of type ad
+var realmAdParameters = map[string]string{
+	"comment":         "comment",
+	"default":         "default",
+	"domain":          "domain",
+	"server1":         "server1",
+	"server2":         "server2",
+	"port":            "port",
+	"bind_dn":         "bind_dn",
+	"filter":          "filter",
+	"user_classes":    "user_classes",
+	"group_dn":        "group_dn",
+	"group_filter":    "group_filter",
+	"group_classes":   "group_classes",
+	"group_name_attr": "group_name_attr",
+	"sync_attributes": "sync_attributes",
+	"case_sensitive":  "case-sensitive",
+	"mode":            "mode",
+	"verify":          "verify",
+	"capath":          "capath",
+	"cert":            "cert",
+	"certkey":         "certkey",
+	"sslversion":      "sslversion",
+}
+
+func resourceRealmAd() *schema.Resource {
+	*pxapi.Debug = true
+
+	realmAdResourceDef = &schema.Resource{
+		Description: "Manages an Active Directory authentication realm.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			if err := resourceRealmCreate(d, meta, "ad", realmAdParameters); err != nil {
+				return err
+			}
+			return realmSync(d, meta)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmRead(d, meta, "ad", realmAdParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			if err := resourceRealmUpdate(d, meta, "ad", realmAdParameters); err != nil {
+				return err
+			}
+			return realmSync(d, meta)
+		},
+		Delete: resourceRealmDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"realm": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the realm, users log in as `user@<realm>`.",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use this realm as the default realm of the login form",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The AD domain, e.g. example.com",
+			},
+			"server1": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The domain controller.",
+			},
+			"server2": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fallback server",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The port of the servers, defaults to the port of `mode`.",
+			},
+			"bind_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The DN to bind with for the sync, anonymous binds are used when unset.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of bind_dn",
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LDAP filter for the user sync",
+			},
+			"user_classes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The object classes of users.",
+			},
+			"group_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The base DN of the groups.",
+			},
+			"group_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An LDAP filter for the groups to sync.",
+			},
+			"group_classes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The object classes of groups.",
+			},
+			"group_name_attr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute holding the group name.",
+			},
+			"sync_attributes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma separated list of key=value pairs mapping LDAP attributes to user attributes, e.g. email=mail",
+			},
+			"case_sensitive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether user names are case sensitive. Default is `true`.",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ldap",
+				ValidateFunc: validation.StringInSlice([]string{"ldap", "ldaps", "ldap+starttls"}, false),
+				Description:  "`ldap`, `ldaps` or `ldap+starttls`. Default is `ldap`.",
+			},
+			"verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Verify the certificate of the server",
+			},
+			"capath": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the CA certificate store",
+			},
+			"cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the client certificate",
+			},
+			"certkey": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the client certificate key",
+			},
+			"sslversion": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tlsv1", "tlsv1_1", "tlsv1_2", "tlsv1_3"}, false),
+				Description:  "The minimum TLS version: `tlsv1`, `tlsv1_1`, `tlsv1_2` or `tlsv1_3`.",
+			},
+			"sync_scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"users", "groups", "both"}, false),
+				Description:  "Default scope of a sync: users, groups or both",
+			},
+			"sync_enable_new": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enable users created by a sync by default",
+			},
+			"sync_remove_vanished": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"acl", "entry", "properties"}, false)},
+				Description: "What to remove of users and groups which vanished from the directory: acl, entry and/or properties",
+			},
+			"sync_on_apply": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Sync the users and groups of the realm with the sync defaults whenever the realm is created or updated",
+			},
+		},
+	}
+
+	return realmAdResourceDef
+}
+
+// Starts a sync of the realm with its sync defaults and waits for it when sync_on_apply is set.
+func realmSync(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("sync_on_apply").(bool) {
+		return nil
+	}
+	if d.Get("sync_scope").(string) == "" {
+		return fmt.Errorf("sync_on_apply requires sync_scope to be set")
+	}
+
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_realm_sync")
+	logger.Info().Str("realm", realm).Msg("Syncing users and groups of realm")
+
+	_, err = apiPostTask(pconf.Session, pconf.Client, apiPath("access", "domains", realm, "sync"), map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("Syncing realm %s failed: %v", realm, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRealmAdParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRealmAd().Schema, map[string]interface{}{
+		"realm":      "example",
+		"domain":     "example.com",
+		"server1":    "dc1.example.com",
+		"sync_scope": "users",
+	})
+	params, deletes := realmParams(d, realmSchema("ad"), realmAdParameters, false)
+
+	if params["domain"] != "example.com" || params["server1"] != "dc1.example.com" {
+		t.Errorf("expected domain and server1 in the params, got `%+v`", params)
+	}
+	if params["sync-defaults-options"] != "scope=users,enable-new=1" {
+		t.Errorf("expected sync defaults `scope=users,enable-new=1`, got `%v`", params["sync-defaults-options"])
+	}
+	for _, parameter := range deletes {
+		if parameter == "domain" || parameter == "server1" {
+			t.Errorf("expected %s not to be deleted", parameter)
+		}
+	}
+}
+
+func TestRealmSyncRequiresScope(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRealmAd().Schema, map[string]interface{}{
+		"realm":         "example",
+		"domain":        "example.com",
+		"server1":       "dc1.example.com",
+		"sync_on_apply": true,
+	})
+	if err := realmSync(d, nil); err == nil {
+		t.Errorf("expected an error for sync_on_apply without sync_scope")
+	}
+}
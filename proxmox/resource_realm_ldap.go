@@ -0,0 +1,428 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var realmLdapResourceDef *schema.Resource
+
+// schema attribute => parameter of /access/domains, the attributes of all realm types
+// which are sent as they are
+var realmLdapParameters = map[string]string{
+	"comment":         "comment",
+	"default":         "default",
+	"server1":         "server1",
+	"server2":         "server2",
+	"port":            "port",
+	"base_dn":         "base_dn",
+	"bind_dn":         "bind_dn",
+	"user_attr":       "user_attr",
+	"filter":          "filter",
+	"user_classes":    "user_classes",
+	"group_dn":        "group_dn",
+	"group_filter":    "group_filter",
+	"group_classes":   "group_classes",
+	"group_name_attr": "group_name_attr",
+	"sync_attributes": "sync_attributes",
+	"case_sensitive":  "case-sensitive",
+	"mode":            "mode",
+	"verify":          "verify",
+	"capath":          "capath",
+	"cert":            "cert",
+	"certkey":         "certkey",
+	"sslversion":      "sslversion",
+}
+
+func resourceRealmLdap() *schema.Resource {
+	*pxapi.Debug = true
+
+	realmLdapResourceDef = &schema.Resource{
+		Description: "Manages an LDAP authentication realm.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmCreate(d, meta, "ldap", realmLdapParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmRead(d, meta, "ldap", realmLdapParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmUpdate(d, meta, "ldap", realmLdapParameters)
+		},
+		Delete: resourceRealmDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"realm": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the realm, users log in as `user@<realm>`.",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use this realm as the default realm of the login form",
+			},
+			"server1": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The LDAP server.",
+			},
+			"server2": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fallback server",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The port of the servers, defaults to the port of `mode`.",
+			},
+			"base_dn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The base DN of the users.",
+			},
+			"bind_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The DN to bind with, anonymous binds are used when unset.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of bind_dn",
+			},
+			"user_attr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "uid",
+				Description: "The LDAP attribute holding the user name. Default is `uid`.",
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LDAP filter for the user sync",
+			},
+			"user_classes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The object classes of users.",
+			},
+			"group_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The base DN of the groups.",
+			},
+			"group_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An LDAP filter for the groups to sync.",
+			},
+			"group_classes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The object classes of groups.",
+			},
+			"group_name_attr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute holding the group name.",
+			},
+			"sync_attributes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma separated list of key=value pairs mapping LDAP attributes to user attributes, e.g. email=mail",
+			},
+			"case_sensitive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether user names are case sensitive. Default is `true`.",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ldap",
+				ValidateFunc: validation.StringInSlice([]string{"ldap", "ldaps", "ldap+starttls"}, false),
+				Description:  "`ldap`, `ldaps` or `ldap+starttls`. Default is `ldap`.",
+			},
+			"verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Verify the certificate of the server",
+			},
+			"capath": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the CA certificate store",
+			},
+			"cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the client certificate",
+			},
+			"certkey": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the client certificate key",
+			},
+			"sslversion": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tlsv1", "tlsv1_1", "tlsv1_2", "tlsv1_3"}, false),
+				Description:  "The minimum TLS version: `tlsv1`, `tlsv1_1`, `tlsv1_2` or `tlsv1_3`.",
+			},
+			"sync_scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"users", "groups", "both"}, false),
+				Description:  "Default scope of a sync: users, groups or both",
+			},
+			"sync_enable_new": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enable users created by a sync by default",
+			},
+			"sync_remove_vanished": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"acl", "entry", "properties"}, false)},
+				Description: "What to remove of users and groups which vanished from the directory: acl, entry and/or properties",
+			},
+		},
+	}
+
+	return realmLdapResourceDef
+}
+
+// The sync defaults are a single property string, e.g. "scope=both,enable-new=1,remove-vanished=acl;entry"
+func realmSyncDefaults(d *schema.ResourceData) string {
+	options := []string{}
+	if scope := d.Get("sync_scope").(string); scope != "" {
+		options = append(options, "scope="+scope)
+	}
+	if d.Get("sync_enable_new").(bool) {
+		options = append(options, "enable-new=1")
+	} else {
+		options = append(options, "enable-new=0")
+	}
+	if vanished := schemaStringList(d.Get("sync_remove_vanished")); len(vanished) > 0 {
+		options = append(options, "remove-vanished="+strings.Join(vanished, ";"))
+	}
+	return strings.Join(options, ",")
+}
+
+func setRealmSyncDefaults(d *schema.ResourceData, syncDefaults string) error {
+	options := map[string]string{}
+	for _, option := range strings.Split(syncDefaults, ",") {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) == 2 {
+			options[kv[0]] = kv[1]
+		}
+	}
+	d.Set("sync_scope", options["scope"])
+	d.Set("sync_enable_new", options["enable-new"] == "" || apiBool(options["enable-new"]))
+	return d.Set("sync_remove_vanished", apiStringList(options["remove-vanished"], ";"))
+}
+
+// Collects the parameters of a realm from the schema. Empty values are returned as the
+// list of parameters to delete, so they are cleared when the realm is updated. Proxmox
+// refuses to change the parameters of ForceNew attributes, they are left out of an update.
+func realmParams(d *schema.ResourceData, realmSchema map[string]*schema.Schema, parameters map[string]string, update bool) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for attribute, parameter := range parameters {
+		if update && realmSchema[attribute].ForceNew {
+			continue
+		}
+		value := d.Get(attribute)
+		switch realmSchema[attribute].Type {
+		case schema.TypeBool:
+			params[parameter] = value.(bool)
+			continue
+		case schema.TypeInt:
+			if value.(int) != 0 {
+				params[parameter] = value.(int)
+				continue
+			}
+		default:
+			if value.(string) != "" {
+				params[parameter] = value.(string)
+				continue
+			}
+		}
+		deletes = append(deletes, parameter)
+	}
+	if _, ok := realmSchema["sync_scope"]; ok {
+		params["sync-defaults-options"] = realmSyncDefaults(d)
+	}
+	return
+}
+
+func resourceRealmCreate(d *schema.ResourceData, meta interface{}, realmType string, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	realm := d.Get("realm").(string)
+	params, _ := realmParams(d, realmSchema(realmType), parameters, false)
+	params["realm"] = realm
+	params["type"] = realmType
+	if password, ok := d.GetOk("password"); ok {
+		params["password"] = password.(string)
+	}
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, "/access/domains", params)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(clusterResourceId("domains", realm))
+
+	return _resourceRealmRead(d, meta, realmType, parameters)
+}
+
+func resourceRealmRead(d *schema.ResourceData, meta interface{}, realmType string, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceRealmRead(d, meta, realmType, parameters)
+}
+
+func _resourceRealmRead(d *schema.ResourceData, meta interface{}, realmType string, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_realm_read")
+	logger.Info().Str("realm", realm).Msgf("Reading configuration for %s realm", realmType)
+
+	config, err := apiGetMap(pconf.Session, apiPath("access", "domains", realm))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if apiString(config["type"]) != realmType {
+		return fmt.Errorf("Realm %s is of type %s, not %s", realm, apiString(config["type"]), realmType)
+	}
+
+	if err = setRealmData(d, realm, realmSchema(realmType), parameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("realm", realm).Msgf("Finished realm read resulting in data: '%+v'", config)
+	return nil
+}
+
+func setRealmData(d *schema.ResourceData, realm string, realmSchema map[string]*schema.Schema, parameters map[string]string, config map[string]interface{}) error {
+	d.Set("realm", realm)
+	for attribute, parameter := range parameters {
+		value, ok := config[parameter]
+		switch realmSchema[attribute].Type {
+		case schema.TypeBool:
+			// proxmox omits booleans which are not set, those are at their default
+			if !ok {
+				d.Set(attribute, realmSchema[attribute].Default)
+			} else {
+				d.Set(attribute, apiBool(value))
+			}
+		case schema.TypeInt:
+			d.Set(attribute, apiInt(value))
+		default:
+			if !ok && realmSchema[attribute].Default != nil {
+				d.Set(attribute, realmSchema[attribute].Default)
+			} else {
+				d.Set(attribute, apiString(value))
+			}
+		}
+	}
+	if _, ok := realmSchema["sync_scope"]; ok {
+		return setRealmSyncDefaults(d, apiString(config["sync-defaults-options"]))
+	}
+	return nil
+}
+
+func resourceRealmUpdate(d *schema.ResourceData, meta interface{}, realmType string, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := realmParams(d, realmSchema(realmType), parameters, true)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+	if d.HasChange("password") {
+		if password := d.Get("password").(string); password != "" {
+			params["password"] = password
+		} else {
+			params["delete"] = strings.Trim(apiString(params["delete"])+",password", ",")
+		}
+	}
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPut(pconf.Session, apiPath("access", "domains", realm), params)
+	})
+	if err != nil {
+		return err
+	}
+
+	return _resourceRealmRead(d, meta, realmType, parameters)
+}
+
+func resourceRealmDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, realm, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("access", "domains", realm))
+	return err
+}
+
+// the schema of the realm resource of each type
+func realmSchema(realmType string) map[string]*schema.Schema {
+	switch realmType {
+	case "ldap":
+		return realmLdapResourceDef.Schema
+	case "ad":
+		return realmAdResourceDef.Schema
+	case "openid":
+		return realmOpenidResourceDef.Schema
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRealmSyncDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  map[string]interface{}
+		output string
+	}{{
+		name:   "defaults",
+		input:  map[string]interface{}{},
+		output: "enable-new=1",
+	}, {
+		name: "all options",
+		input: map[string]interface{}{
+			"sync_scope":           "both",
+			"sync_enable_new":      false,
+			"sync_remove_vanished": []interface{}{"acl"},
+		},
+		output: "scope=both,enable-new=0,remove-vanished=acl",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			raw := map[string]interface{}{"realm": "example", "server1": "ldap.example.com", "base_dn": "dc=example,dc=com"}
+			for k, v := range test.input {
+				raw[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceRealmLdap().Schema, raw)
+			result := realmSyncDefaults(d)
+			if result != test.output {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.output, result)
+			}
+
+			read := schema.TestResourceDataRaw(t, resourceRealmLdap().Schema, map[string]interface{}{})
+			if err := setRealmSyncDefaults(read, result); err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if roundTrip := realmSyncDefaults(read); roundTrip != test.output {
+				t.Errorf("%s: expected `%s` after reading it back, got `%s`", test.name, test.output, roundTrip)
+			}
+		})
+	}
+}
+
+func TestRealmParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRealmLdap().Schema, map[string]interface{}{
+		"realm":   "example",
+		"server1": "ldap.example.com",
+		"base_dn": "dc=example,dc=com",
+		"mode":    "ldaps",
+		"verify":  true,
+	})
+	params, deletes := realmParams(d, realmLdapResourceDef.Schema, realmLdapParameters, false)
+
+	expected := map[string]interface{}{
+		"server1":               "ldap.example.com",
+		"base_dn":               "dc=example,dc=com",
+		"user_attr":             "uid",
+		"mode":                  "ldaps",
+		"verify":                true,
+		"default":               false,
+		"case-sensitive":        true,
+		"sync-defaults-options": "enable-new=1",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%+v`, got `%+v`", expected, params)
+	}
+
+	sort.Strings(deletes)
+	expectedDeletes := []string{"bind_dn", "capath", "cert", "certkey", "comment", "filter", "group_classes", "group_dn",
+		"group_filter", "group_name_attr", "port", "server2", "sslversion", "sync_attributes", "user_classes"}
+	if !reflect.DeepEqual(deletes, expectedDeletes) {
+		t.Errorf("expected deletes `%+v`, got `%+v`", expectedDeletes, deletes)
+	}
+}
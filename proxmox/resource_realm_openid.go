@@ -0,0 +1,113 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var realmOpenidResourceDef *schema.Resource
+
+// schema attribute => parameter of /access/domains for realms of type openid
+var realmOpenidParameters = map[string]string{
+	"comment":        "comment",
+	"default":        "default",
+	"issuer_url":     "issuer-url",
+	"client_id":      "client-id",
+	"client_key":     "client-key",
+	"username_claim": "username-claim",
+	"autocreate":     "autocreate",
+	"scopes":         "scopes",
+	"prompt":         "prompt",
+	"acr_values":     "acr-values",
+}
+
+func resourceRealmOpenid() *schema.Resource {
+	*pxapi.Debug = true
+
+	realmOpenidResourceDef = &schema.Resource{
+		Description: "Manages an OpenID Connect authentication realm.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmCreate(d, meta, "openid", realmOpenidParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmRead(d, meta, "openid", realmOpenidParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceRealmUpdate(d, meta, "openid", realmOpenidParameters)
+		},
+		Delete: resourceRealmDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"realm": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the realm, users log in as `user@<realm>`.",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use this realm as the default realm of the login form",
+			},
+			"issuer_url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsURLWithHTTPS,
+				Description:  "URL of the OpenID provider, its discovery document is expected below /.well-known/openid-configuration",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The id of the client registered at the provider.",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Secret of the client",
+			},
+			"username_claim": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Claim used as the user name, proxmox defaults to the subject. It can not be changed once users logged in",
+			},
+			"autocreate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create users on their first login",
+			},
+			"scopes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "email profile",
+				Description: "Space separated list of the scopes requested besides openid",
+			},
+			"prompt": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Whether the provider prompts for reauthentication and/or consent, e.g. login or consent",
+			},
+			"acr_values": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Space separated list of the requested authentication context class references",
+			},
+		},
+	}
+
+	return realmOpenidResourceDef
+}
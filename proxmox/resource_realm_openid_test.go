@@ -0,0 +1,32 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRealmOpenidParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRealmOpenid().Schema, map[string]interface{}{
+		"realm":          "sso",
+		"issuer_url":     "https://login.example.com/realms/main",
+		"client_id":      "proxmox",
+		"username_claim": "email",
+	})
+
+	params, _ := realmParams(d, realmSchema("openid"), realmOpenidParameters, false)
+	if params["username-claim"] != "email" || params["issuer-url"] != "https://login.example.com/realms/main" {
+		t.Errorf("expected username-claim and issuer-url in the params of a new realm, got `%+v`", params)
+	}
+	if _, ok := params["sync-defaults-options"]; ok {
+		t.Errorf("expected no sync defaults for an openid realm, got `%+v`", params)
+	}
+
+	params, deletes := realmParams(d, realmSchema("openid"), realmOpenidParameters, true)
+	if _, ok := params["username-claim"]; ok {
+		t.Errorf("expected username-claim to be left out of an update, got `%+v`", params)
+	}
+	if stringInList("username-claim", deletes) {
+		t.Errorf("expected username-claim not to be deleted by an update, got `%v`", deletes)
+	}
+}
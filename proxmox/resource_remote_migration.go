@@ -0,0 +1,204 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRemoteMigration() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Migrates a VM or container to another Proxmox cluster.",
+
+		Create: resourceRemoteMigrationCreate,
+		Read:   resourceRemoteMigrationRead,
+		Update: resourceRemoteMigrationUpdate,
+		Delete: resourceRemoteMigrationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the guest to migrate",
+			},
+			"target_host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The address of a node of the target cluster",
+			},
+			"target_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      8006,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port of the API of the target cluster",
+			},
+			"target_fingerprint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The SHA-256 fingerprint of the certificate of the target node, required unless it is trusted by the source node",
+			},
+			"target_token_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The API token the target cluster is accessed with, e.g. `root@pam!migration`",
+			},
+			"target_token_secret": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The secret of the API token",
+			},
+			"target_vmid": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the guest in the target cluster, defaults to its id in this cluster",
+			},
+			"target_storage": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The storages of the guest mapped to storages of the target cluster, the key `*` maps all other storages",
+			},
+			"target_bridge": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The bridges of the guest mapped to bridges of the target cluster, the key `*` maps all other bridges",
+			},
+			"online": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Migrate a running VM online, a running container is restarted on the target",
+			},
+			"delete_source": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Delete the guest in this cluster after the migration, otherwise it is kept stopped and locked",
+			},
+			"bwlimit": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The bandwidth limit of the migration in KiB/s, 0 is the limit of the cluster",
+			},
+			"source_node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node the guest was migrated from",
+			},
+		},
+	}
+}
+
+// A storage or bridge mapping of a remote migration, e.g. local-lvm:fast,vmbr0 where the
+// mapping of `*` is the one of all other storages or bridges.
+func remoteMigrationMapping(mapping map[string]interface{}) string {
+	list := []string{}
+	for source, target := range mapping {
+		if source == "*" {
+			list = append(list, target.(string))
+		} else {
+			list = append(list, source+":"+target.(string))
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// The target endpoint of a remote migration, it contains the secret of the token.
+func remoteMigrationEndpoint(host string, port int, fingerprint string, tokenID string, tokenSecret string) string {
+	endpoint := fmt.Sprintf("host=%s,port=%d,apitoken=PVEAPIToken=%s=%s", host, port, tokenID, tokenSecret)
+	if fingerprint != "" {
+		endpoint += ",fingerprint=" + fingerprint
+	}
+	return endpoint
+}
+
+func resourceRemoteMigrationCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmr, err := apiGuestVmRef(pconf.Session, d.Get("vmid").(int))
+	if err != nil {
+		return err
+	}
+	targetVmid := d.Get("target_vmid").(int)
+	if targetVmid == 0 {
+		targetVmid = vmr.VmId()
+	}
+
+	params := map[string]interface{}{
+		"target-endpoint": remoteMigrationEndpoint(d.Get("target_host").(string), d.Get("target_port").(int), d.Get("target_fingerprint").(string), d.Get("target_token_id").(string), d.Get("target_token_secret").(string)),
+		"target-vmid":     targetVmid,
+		"target-storage":  remoteMigrationMapping(d.Get("target_storage").(map[string]interface{})),
+		"target-bridge":   remoteMigrationMapping(d.Get("target_bridge").(map[string]interface{})),
+		"delete":          d.Get("delete_source").(bool),
+	}
+	if vmr.GetVmType() == "lxc" {
+		params["restart"] = d.Get("online").(bool)
+	} else {
+		params["online"] = d.Get("online").(bool)
+	}
+	if bwlimit := d.Get("bwlimit").(int); bwlimit != 0 {
+		params["bwlimit"] = bwlimit
+	}
+
+	logger, _ := CreateSubLogger("resource_remote_migration_create")
+	logger.Info().Int("vmid", vmr.VmId()).Str("target", d.Get("target_host").(string)).Msgf("Migrating guest to vmid %d of another cluster", targetVmid)
+
+	// the target endpoint contains the token secret, it is kept out of the debug log
+	upid, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, apiPath("nodes", vmr.Node(), vmr.GetVmType(), strconv.Itoa(vmr.VmId()), "remote_migrate"), params)
+	})
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, pconf.Client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Migrating guest %d to %s failed: %v", vmr.VmId(), d.Get("target_host").(string), err)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s:%d", vmr.VmId(), d.Get("target_host").(string), targetVmid))
+	d.Set("target_vmid", targetVmid)
+	d.Set("source_node", vmr.Node())
+	return nil
+}
+
+// a new token secret is kept for a migration replacing this one
+func resourceRemoteMigrationUpdate(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// nothing to read, the migration is an action
+func resourceRemoteMigrationRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// the guest stays in the target cluster
+func resourceRemoteMigrationDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,34 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestRemoteMigrationMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  map[string]interface{}
+		expected string
+	}{
+		{name: "all", mapping: map[string]interface{}{"*": "vmbr0"}, expected: "vmbr0"},
+		{name: "mapped", mapping: map[string]interface{}{"local-lvm": "fast", "nfs": "slow"}, expected: "local-lvm:fast,nfs:slow"},
+		{name: "mixed", mapping: map[string]interface{}{"*": "fast", "nfs": "slow"}, expected: "fast,nfs:slow"},
+	}
+
+	for _, test := range tests {
+		if value := remoteMigrationMapping(test.mapping); value != test.expected {
+			t.Errorf("%s: expected `%s`, got `%s`", test.name, test.expected, value)
+		}
+	}
+}
+
+func TestRemoteMigrationEndpoint(t *testing.T) {
+	endpoint := remoteMigrationEndpoint("pve.example.com", 8006, "", "root@pam!migration", "secret")
+	if endpoint != "host=pve.example.com,port=8006,apitoken=PVEAPIToken=root@pam!migration=secret" {
+		t.Errorf("unexpected endpoint `%s`", endpoint)
+	}
+	endpoint = remoteMigrationEndpoint("10.0.0.1", 443, "AA:BB", "root@pam!migration", "secret")
+	if endpoint != "host=10.0.0.1,port=443,apitoken=PVEAPIToken=root@pam!migration=secret,fingerprint=AA:BB" {
+		t.Errorf("unexpected endpoint `%s`", endpoint)
+	}
+}
@@ -0,0 +1,237 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceReplicationJob() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a storage replication job of the cluster, which replicates the ZFS volumes of a guest to another node.",
+
+		Create: resourceReplicationJobCreate,
+		Read:   resourceReplicationJobRead,
+		Update: resourceReplicationJobUpdate,
+		Delete: resourceReplicationJobDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the guest to replicate",
+			},
+			"job_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 9),
+				Description:  "The number of the job of the guest, a guest has one job per target node",
+			},
+			"target": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The node the guest is replicated to",
+			},
+			"schedule": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "*/15",
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "When the job runs, as a calendar event like `*/15` or `mon..fri 21:30`",
+			},
+			"rate": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "The rate limit of the replication in MB/s, 0 is unlimited",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the job runs on its schedule",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A comment of the replication job",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node the guest was last replicated from",
+			},
+		},
+	}
+}
+
+// Replication jobs are identified by the guest and the job number, e.g. 100-0.
+func replicationJobId(vmid int, jobNumber int) string {
+	return fmt.Sprintf("%d-%d", vmid, jobNumber)
+}
+
+func parseReplicationJobId(jobId string) (vmid int, jobNumber int, err error) {
+	parts := strings.Split(jobId, "-")
+	if len(parts) == 2 {
+		vmid, err = strconv.Atoi(parts[0])
+		if err == nil {
+			jobNumber, err = strconv.Atoi(parts[1])
+		}
+		if err == nil {
+			return
+		}
+	}
+	return 0, 0, fmt.Errorf("Invalid replication job id: %s. Must be <vmid>-<job number>", jobId)
+}
+
+// The parameters of the job which can be updated, deletes are the optional parameters which are not set.
+func replicationJobParams(d *schema.ResourceData) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{
+		"schedule": d.Get("schedule").(string),
+		"disable":  !d.Get("enabled").(bool),
+	}
+	deletes = []string{}
+	if rate := d.Get("rate").(float64); rate > 0 {
+		params["rate"] = strconv.FormatFloat(rate, 'f', -1, 64)
+	} else {
+		deletes = append(deletes, "rate")
+	}
+	if comment := d.Get("comment").(string); comment != "" {
+		params["comment"] = comment
+	} else {
+		deletes = append(deletes, "comment")
+	}
+	sort.Strings(deletes)
+	return
+}
+
+func resourceReplicationJobCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	jobId := replicationJobId(d.Get("vmid").(int), d.Get("job_number").(int))
+	params, _ := replicationJobParams(d)
+	params["id"] = jobId
+	params["type"] = "local"
+	params["target"] = d.Get("target").(string)
+
+	logger, _ := CreateSubLogger("resource_replication_job_create")
+	logger.Info().Str("job_id", jobId).Msg("Creating replication job")
+
+	if _, err := apiPost(pconf.Session, "/cluster/replication", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("replication", jobId))
+	return _resourceReplicationJobRead(d, meta)
+}
+
+func resourceReplicationJobRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceReplicationJobRead(d, meta)
+}
+
+func _resourceReplicationJobRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	vmid, jobNumber, err := parseReplicationJobId(jobId)
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_replication_job_read")
+	logger.Info().Str("job_id", jobId).Msg("Reading configuration for replication job")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "replication", jobId))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vmid", vmid)
+	d.Set("job_number", jobNumber)
+	d.Set("target", apiString(config["target"]))
+	d.Set("schedule", "*/15")
+	if schedule := apiString(config["schedule"]); schedule != "" {
+		d.Set("schedule", schedule)
+	}
+	rate, _ := strconv.ParseFloat(apiString(config["rate"]), 64)
+	d.Set("rate", rate)
+	d.Set("enabled", !apiBool(config["disable"]))
+	d.Set("comment", apiString(config["comment"]))
+	d.Set("source", apiString(config["source"]))
+
+	logger.Debug().Str("job_id", jobId).Msgf("Finished replication job read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceReplicationJobUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := replicationJobParams(d)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	logger, _ := CreateSubLogger("resource_replication_job_update")
+	logger.Info().Str("job_id", jobId).Msg("Updating replication job")
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "replication", jobId), params); err != nil {
+		return err
+	}
+	return _resourceReplicationJobRead(d, meta)
+}
+
+// Deleting the job only marks it for removal, the next run of the replication removes the
+// replicated volumes from the target node and then the job.
+func resourceReplicationJobDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, jobId, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("cluster", "replication", jobId))
+	if err != nil && strings.Contains(err.Error(), "does not exist") {
+		return nil
+	}
+	return err
+}
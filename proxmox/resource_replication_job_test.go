@@ -0,0 +1,40 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestReplicationJobParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceReplicationJob().Schema, map[string]interface{}{
+		"vmid":   100,
+		"target": "pve2",
+		"rate":   12.5,
+	})
+
+	params, deletes := replicationJobParams(d)
+	expected := map[string]interface{}{
+		"schedule": "*/15",
+		"disable":  false,
+		"rate":     "12.5",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if !reflect.DeepEqual(deletes, []string{"comment"}) {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestParseReplicationJobId(t *testing.T) {
+	if vmid, jobNumber, err := parseReplicationJobId(replicationJobId(100, 1)); err != nil || vmid != 100 || jobNumber != 1 {
+		t.Errorf("unexpected job `%d` `%d` `%v`", vmid, jobNumber, err)
+	}
+	for _, jobId := range []string{"100", "100-a", "a-0", "100-0-1"} {
+		if _, _, err := parseReplicationJobId(jobId); err == nil {
+			t.Errorf("%s: expected an error", jobId)
+		}
+	}
+}
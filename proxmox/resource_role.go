@@ -0,0 +1,160 @@
+package proxmox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var roleResourceDef *schema.Resource
+
+func resourceRole() *schema.Resource {
+	*pxapi.Debug = true
+
+	roleResourceDef = &schema.Resource{
+		Description: "Manages a custom role (`/access/roles`).",
+
+		Create: resourceRoleCreate,
+		Read:   resourceRoleRead,
+		Update: resourceRoleUpdate,
+		Delete: resourceRoleDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"roleid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the role.",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Privileges granted by the role, e.g. VM.Allocate or Datastore.AllocateSpace",
+			},
+		},
+	}
+
+	return roleResourceDef
+}
+
+func resourceRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	roleID := d.Get("roleid").(string)
+	_, err := apiPost(pconf.Session, "/access/roles", map[string]interface{}{
+		"roleid": roleID,
+		"privs":  strings.Join(schemaStringList(d.Get("privileges")), ","),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(clusterResourceId("roles", roleID))
+
+	return _resourceRoleRead(d, meta)
+}
+
+func resourceRoleRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceRoleRead(d, meta)
+}
+
+func _resourceRoleRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, roleID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_role_read")
+	logger.Info().Str("roleid", roleID).Msg("Reading configuration for role")
+
+	role, err := apiGet(pconf.Session, apiPath("access", "roles", roleID))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	privileges := rolePrivileges(role)
+	d.Set("roleid", roleID)
+	if err = d.Set("privileges", privileges); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("roleid", roleID).Msgf("Finished role read resulting in privileges: '%v'", privileges)
+	return nil
+}
+
+func resourceRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, roleID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// without append the privileges of the role are replaced, which also drops
+	// privileges that were added out of band
+	_, err = apiPut(pconf.Session, apiPath("access", "roles", roleID), map[string]interface{}{
+		"privs": strings.Join(schemaStringList(d.Get("privileges")), ","),
+	}, "privs")
+	if err != nil {
+		return err
+	}
+
+	return _resourceRoleRead(d, meta)
+}
+
+func resourceRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, roleID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("access", "roles", roleID))
+	return err
+}
+
+// GET /access/roles/<roleid> answers with an object of privilege => 1, older versions
+// send the privileges as a comma separated string
+func rolePrivileges(data interface{}) []string {
+	privileges := []string{}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if privs, ok := v["privs"]; ok {
+			privileges = apiStringList(privs, ", ")
+			break
+		}
+		for privilege, granted := range v {
+			if apiBool(granted) {
+				privileges = append(privileges, privilege)
+			}
+		}
+	default:
+		privileges = apiStringList(v, ", ")
+	}
+	sort.Strings(privileges)
+	return privileges
+}
@@ -0,0 +1,27 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRolePrivileges(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		output []string
+	}{
+		{name: "object", input: map[string]interface{}{"VM.Audit": float64(1), "VM.Allocate": float64(1), "Sys.Audit": float64(0)}, output: []string{"VM.Allocate", "VM.Audit"}},
+		{name: "privs key", input: map[string]interface{}{"privs": "VM.Audit,Datastore.AllocateSpace"}, output: []string{"Datastore.AllocateSpace", "VM.Audit"}},
+		{name: "string", input: "VM.Audit, VM.Allocate", output: []string{"VM.Allocate", "VM.Audit"}},
+		{name: "empty", input: map[string]interface{}{}, output: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := rolePrivileges(test.input); !reflect.DeepEqual(result, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
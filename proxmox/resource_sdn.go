@@ -0,0 +1,151 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The proxmox_sdn_* resources share the handling of /cluster/sdn. Changes to the SDN objects
+// are written to the pending SDN configuration of the cluster, which is applied right away so
+// the objects become usable on the nodes.
+
+// Collects the parameters of an SDN object from the schema. Empty values are returned as the
+// list of parameters to delete, so they are cleared when the object is updated. Sets are sent
+// comma separated. Proxmox refuses to change the parameters of ForceNew attributes, they are
+// left out of an update.
+func sdnParams(d *schema.ResourceData, sdnSchema map[string]*schema.Schema, parameters map[string]string, update bool) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for attribute, parameter := range parameters {
+		if update && sdnSchema[attribute].ForceNew {
+			continue
+		}
+		value := d.Get(attribute)
+		switch sdnSchema[attribute].Type {
+		case schema.TypeBool:
+			params[parameter] = value.(bool)
+			continue
+		case schema.TypeInt:
+			if value.(int) != 0 {
+				params[parameter] = value.(int)
+				continue
+			}
+		case schema.TypeSet:
+			if list := schemaStringList(value); len(list) > 0 {
+				params[parameter] = strings.Join(list, ",")
+				continue
+			}
+		default:
+			if value.(string) != "" {
+				params[parameter] = value.(string)
+				continue
+			}
+		}
+		if !sdnSchema[attribute].ForceNew {
+			deletes = append(deletes, parameter)
+		}
+	}
+	return
+}
+
+func setSdnData(d *schema.ResourceData, sdnSchema map[string]*schema.Schema, parameters map[string]string, config map[string]interface{}) error {
+	for attribute, parameter := range parameters {
+		value := config[parameter]
+		switch sdnSchema[attribute].Type {
+		// proxmox omits booleans which are not set, which is false
+		case schema.TypeBool:
+			d.Set(attribute, apiBool(value))
+		case schema.TypeInt:
+			d.Set(attribute, apiInt(value))
+		case schema.TypeSet:
+			if err := d.Set(attribute, apiStringList(value, ",")); err != nil {
+				return err
+			}
+		default:
+			d.Set(attribute, apiString(value))
+		}
+	}
+	return nil
+}
+
+// Checks the attributes set for an SDN object of a type, e.g. the zones of type vlan. typeOptions
+// are the types using an attribute, the attributes not in it are used by all types.
+// requiredOptions are the attributes a type requires.
+func checkSdnOptions(kind string, objectType string, set map[string]bool, typeOptions map[string][]string, requiredOptions map[string][]string) error {
+	for _, attribute := range requiredOptions[objectType] {
+		if !set[attribute] {
+			return fmt.Errorf("%s is required for %s of type %s", attribute, kind, objectType)
+		}
+	}
+	for attribute, objectTypes := range typeOptions {
+		if set[attribute] && !stringInList(objectType, objectTypes) {
+			return fmt.Errorf("%s is only supported by %s of type %s, not %s", attribute, kind, strings.Join(objectTypes, ", "), objectType)
+		}
+	}
+	return nil
+}
+
+// The CustomizeDiff checking the attributes of the type attribute of an SDN object. Attributes
+// only known when applying count as set.
+func sdnOptionsCustomizeDiff(kind string, typeOptions map[string][]string, requiredOptions map[string][]string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown("type") {
+			return nil
+		}
+		set := map[string]bool{}
+		for attribute := range typeOptions {
+			if !d.NewValueKnown(attribute) {
+				set[attribute] = true
+				continue
+			}
+			switch value := d.Get(attribute).(type) {
+			case bool:
+				set[attribute] = value
+			case string:
+				set[attribute] = value != ""
+			case int:
+				set[attribute] = value != 0
+			case *schema.Set:
+				set[attribute] = value.Len() > 0
+			case map[string]interface{}:
+				set[attribute] = len(value) > 0
+			}
+		}
+		return checkSdnOptions(kind, d.Get("type").(string), set, typeOptions, requiredOptions)
+	}
+}
+
+// Leaves the parameters of the attributes another type uses out of params and deletes, proxmox
+// rejects the parameters a type doesn't have.
+func sdnTypeParams(objectType string, params map[string]interface{}, deletes []string, parameters map[string]string, typeOptions map[string][]string) []string {
+	excluded := map[string]bool{}
+	for attribute, objectTypes := range typeOptions {
+		if !stringInList(objectType, objectTypes) {
+			excluded[parameters[attribute]] = true
+			delete(params, parameters[attribute])
+		}
+	}
+	kept := []string{}
+	for _, parameter := range deletes {
+		if !excluded[parameter] {
+			kept = append(kept, parameter)
+		}
+	}
+	return kept
+}
+
+// Applies the pending SDN configuration of the cluster, proxmox reloads the network of every
+// node. Pending changes made outside of terraform are applied as well.
+func sdnApply(pconf *providerConfiguration) error {
+	upid, err := apiPut(pconf.Session, "/cluster/sdn", map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("Applying the SDN configuration failed: %v", err)
+	}
+	if _, err = apiWaitForTask(pconf.Session, pconf.Client, upid); err != nil {
+		return fmt.Errorf("Applying the SDN configuration failed: %v", err)
+	}
+	return nil
+}
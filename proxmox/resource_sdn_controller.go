@@ -0,0 +1,220 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var sdnControllerResourceDef *schema.Resource
+
+var sdnControllerParameters = map[string]string{
+	"type":                        "type",
+	"asn":                         "asn",
+	"peers":                       "peers",
+	"node":                        "node",
+	"ebgp":                        "ebgp",
+	"ebgp_multihop":               "ebgp-multihop",
+	"loopback":                    "loopback",
+	"bgp_multipath_as_path_relax": "bgp-multipath-as-path-relax",
+}
+
+var sdnControllerTypes = []string{"evpn", "bgp"}
+
+// attribute => the controller types using it, the other attributes are used by all types
+var sdnControllerTypeOptions = map[string][]string{
+	"node":                        {"bgp"},
+	"ebgp":                        {"bgp"},
+	"ebgp_multihop":               {"bgp"},
+	"loopback":                    {"bgp"},
+	"bgp_multipath_as_path_relax": {"bgp"},
+}
+
+// controller type => the attributes it requires
+var sdnControllerRequiredOptions = map[string][]string{
+	"bgp": {"node"},
+}
+
+func resourceSdnController() *schema.Resource {
+	*pxapi.Debug = true
+
+	sdnControllerResourceDef = &schema.Resource{
+		Description: "Manages a controller of the Software Defined Network (SDN) of the cluster, which sets up the routing of EVPN zones.",
+
+		Create: resourceSdnControllerCreate,
+		Read:   resourceSdnControllerRead,
+		Update: resourceSdnControllerUpdate,
+		Delete: resourceSdnControllerDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: sdnOptionsCustomizeDiff("controllers", sdnControllerTypeOptions, sdnControllerRequiredOptions),
+
+		Schema: map[string]*schema.Schema{
+			"controller": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z][a-z0-9]{1,7}$`), "must be 2 to 8 lowercase letters and digits, starting with a letter"),
+				Description:  "The id of the controller",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(sdnControllerTypes, false),
+				Description:  "The type of the controller: evpn or bgp",
+			},
+			"asn": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The autonomous system number of the controller, up to 4294967295",
+			},
+			"peers": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsIPAddress},
+				Description: "The addresses of the BGP peers, the nodes of the cluster for evpn and the routers outside of the cluster for bgp",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "bgp, required: The node the BGP controller runs on",
+			},
+			"ebgp": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "bgp: Peer with routers of another autonomous system",
+			},
+			"ebgp_multihop": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "bgp: The number of hops to the eBGP peers if they are not directly connected",
+			},
+			"loopback": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "bgp: The loopback interface the address of the BGP sessions is taken from",
+			},
+			"bgp_multipath_as_path_relax": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "bgp: Balance the traffic over the paths of peers with different autonomous systems",
+			},
+		},
+	}
+
+	return sdnControllerResourceDef
+}
+
+func resourceSdnControllerCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	controller := d.Get("controller").(string)
+	controllerType := d.Get("type").(string)
+	params, _ := sdnParams(d, sdnControllerResourceDef.Schema, sdnControllerParameters, false)
+	sdnTypeParams(controllerType, params, nil, sdnControllerParameters, sdnControllerTypeOptions)
+	params["controller"] = controller
+
+	logger, _ := CreateSubLogger("resource_sdn_controller_create")
+	logger.Info().Str("controller", controller).Msgf("Creating %s controller", controllerType)
+
+	if _, err := apiPost(pconf.Session, "/cluster/sdn/controllers", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("controllers", controller))
+
+	if err := sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnControllerRead(d, meta)
+}
+
+func resourceSdnControllerRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSdnControllerRead(d, meta)
+}
+
+func _resourceSdnControllerRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, controller, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_sdn_controller_read")
+	logger.Info().Str("controller", controller).Msg("Reading configuration for controller")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "sdn", "controllers", controller))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("controller", controller)
+	if err = setSdnData(d, sdnControllerResourceDef.Schema, sdnControllerParameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("controller", controller).Msgf("Finished controller read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceSdnControllerUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, controller, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := sdnParams(d, sdnControllerResourceDef.Schema, sdnControllerParameters, true)
+	deletes = sdnTypeParams(d.Get("type").(string), params, deletes, sdnControllerParameters, sdnControllerTypeOptions)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "sdn", "controllers", controller), params); err != nil {
+		return err
+	}
+	if err = sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnControllerRead(d, meta)
+}
+
+// proxmox refuses to delete controllers which are still used by zones
+func resourceSdnControllerDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, controller, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if _, err = apiDelete(pconf.Session, apiPath("cluster", "sdn", "controllers", controller)); err != nil {
+		return err
+	}
+	return sdnApply(pconf)
+}
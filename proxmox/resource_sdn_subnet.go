@@ -0,0 +1,264 @@
+package proxmox
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var sdnSubnetResourceDef *schema.Resource
+
+// dhcp_range is sent as the dhcp-range array, see sdnDhcpRanges
+var sdnSubnetParameters = map[string]string{
+	"gateway":         "gateway",
+	"snat":            "snat",
+	"dns_zone_prefix": "dnszoneprefix",
+}
+
+func resourceSdnSubnet() *schema.Resource {
+	*pxapi.Debug = true
+
+	sdnSubnetResourceDef = &schema.Resource{
+		Description: "Manages a subnet of a vnet of the Software Defined Network (SDN) of the cluster.",
+
+		Create: resourceSdnSubnetCreate,
+		Read:   resourceSdnSubnetRead,
+		Update: resourceSdnSubnetUpdate,
+		Delete: resourceSdnSubnetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vnet": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The vnet of the subnet",
+			},
+			"cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSdnSubnetCidr,
+				Description:  "The network of the subnet in CIDR notation, e.g. 10.0.0.0/24",
+			},
+			"gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The address of the gateway of the subnet, which is set up on the vnet in `simple` and `evpn` zones",
+			},
+			"snat": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Masquerade the traffic leaving the subnet behind the address of the node, in `simple` and `evpn` zones",
+			},
+			"dns_zone_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The prefix of the DNS zone of the subnet, e.g. `tenant` for `<host>.tenant.<dns zone>`",
+			},
+			"dhcp_range": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start_address": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPAddress,
+							Description:  "The first address of the range",
+						},
+						"end_address": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsIPAddress,
+							Description:  "The last address of the range",
+						},
+					},
+				},
+				Description: "The ranges of addresses handed out by the DHCP server of the zone, requires Proxmox VE 8.1 and a `simple` zone with `dhcp`",
+			},
+		},
+	}
+
+	return sdnSubnetResourceDef
+}
+
+// proxmox only accepts the network address of a subnet, e.g. 10.0.0.0/24 but not 10.0.0.1/24
+func validateSdnSubnetCidr(value interface{}, key string) ([]string, []error) {
+	ip, network, err := net.ParseCIDR(value.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s is not in CIDR notation: %v", key, err)}
+	}
+	if !ip.Equal(network.IP) {
+		return nil, []error{fmt.Errorf("%s must be the network address %s, not %s", key, network, value)}
+	}
+	return nil, nil
+}
+
+// The id of a subnet in proxmox is its zone followed by its network, e.g. zone1-10.0.0.0-24.
+func sdnSubnetId(zone string, cidr string) string {
+	return zone + "-" + strings.Replace(cidr, "/", "-", 1)
+}
+
+// the network of the id of a subnet, zones only consist of letters and digits
+func sdnSubnetCidr(subnetId string) (string, error) {
+	parts := strings.SplitN(subnetId, "-", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("Invalid subnet id %s, must be <zone>-<address>-<prefix length>", subnetId)
+	}
+	return parts[1] + "/" + parts[2], nil
+}
+
+// the values of the dhcp-range array, e.g. start-address=10.0.0.100,end-address=10.0.0.199
+func sdnDhcpRanges(block []interface{}) []string {
+	ranges := []string{}
+	for _, item := range block {
+		dhcpRange := item.(map[string]interface{})
+		ranges = append(ranges, fmt.Sprintf("start-address=%s,end-address=%s", dhcpRange["start_address"], dhcpRange["end_address"]))
+	}
+	return ranges
+}
+
+// proxmox returns the ranges as objects or, depending on the version, as the strings sent
+func parseSdnDhcpRanges(value interface{}) []interface{} {
+	block := []interface{}{}
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		dhcpRange := apiPropertyString(item, "")
+		block = append(block, map[string]interface{}{
+			"start_address": apiString(dhcpRange["start-address"]),
+			"end_address":   apiString(dhcpRange["end-address"]),
+		})
+	}
+	return block
+}
+
+func resourceSdnSubnetCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vnet := d.Get("vnet").(string)
+	cidr := d.Get("cidr").(string)
+	vnetConfig, err := apiGetMap(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet))
+	if err != nil {
+		return fmt.Errorf("Reading vnet %s failed: %v", vnet, err)
+	}
+
+	params, _ := sdnParams(d, sdnSubnetResourceDef.Schema, sdnSubnetParameters, false)
+	params["subnet"] = cidr
+	params["type"] = "subnet"
+	if ranges := sdnDhcpRanges(d.Get("dhcp_range").([]interface{})); len(ranges) > 0 {
+		params["dhcp-range"] = ranges
+	}
+
+	logger, _ := CreateSubLogger("resource_sdn_subnet_create")
+	logger.Info().Str("vnet", vnet).Msgf("Creating subnet %s", cidr)
+
+	if _, err = apiPost(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet, "subnets"), params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId(vnet, sdnSubnetId(apiString(vnetConfig["zone"]), cidr)))
+
+	if err = sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnSubnetRead(d, meta)
+}
+
+func resourceSdnSubnetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSdnSubnetRead(d, meta)
+}
+
+func _resourceSdnSubnetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	vnet, subnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	cidr, err := sdnSubnetCidr(subnet)
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_sdn_subnet_read")
+	logger.Info().Str("vnet", vnet).Msgf("Reading configuration for subnet %s", cidr)
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet, "subnets", subnet))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vnet", vnet)
+	d.Set("cidr", cidr)
+	if err = setSdnData(d, sdnSubnetResourceDef.Schema, sdnSubnetParameters, config); err != nil {
+		return err
+	}
+	if err = d.Set("dhcp_range", parseSdnDhcpRanges(config["dhcp-range"])); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("vnet", vnet).Msgf("Finished subnet read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceSdnSubnetUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vnet, subnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := sdnParams(d, sdnSubnetResourceDef.Schema, sdnSubnetParameters, true)
+	if ranges := sdnDhcpRanges(d.Get("dhcp_range").([]interface{})); len(ranges) > 0 {
+		params["dhcp-range"] = ranges
+	} else {
+		deletes = append(deletes, "dhcp-range")
+	}
+	params["delete"] = strings.Join(deletes, ",")
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet, "subnets", subnet), params); err != nil {
+		return err
+	}
+	if err = sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnSubnetRead(d, meta)
+}
+
+func resourceSdnSubnetDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vnet, subnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if _, err = apiDelete(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet, "subnets", subnet)); err != nil {
+		return err
+	}
+	return sdnApply(pconf)
+}
@@ -0,0 +1,154 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSdnParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceSdnZone().Schema, map[string]interface{}{
+		"zone":  "tenants",
+		"type":  "vxlan",
+		"peers": []interface{}{"10.0.0.1"},
+		"mtu":   1450,
+	})
+
+	params, deletes := sdnParams(d, sdnZoneResourceDef.Schema, sdnZoneParameters, true)
+	expected := map[string]interface{}{
+		"peers": "10.0.0.1",
+		"mtu":   1450,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%+v`, got `%+v`", expected, params)
+	}
+	sort.Strings(deletes)
+	if expectedDeletes := []string{"bridge", "controller", "exitnodes", "ipam", "mac", "nodes", "vrf-vxlan"}; !reflect.DeepEqual(deletes, expectedDeletes) {
+		t.Errorf("expected deletes `%v`, got `%v`", expectedDeletes, deletes)
+	}
+
+	err := setSdnData(d, sdnZoneResourceDef.Schema, sdnZoneParameters, map[string]interface{}{
+		"type":  "vxlan",
+		"peers": "10.0.0.1,10.0.0.2",
+		"mtu":   "1450",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	peers := schemaStringList(d.Get("peers"))
+	sort.Strings(peers)
+	if !reflect.DeepEqual(peers, []string{"10.0.0.1", "10.0.0.2"}) || d.Get("mtu").(int) != 1450 {
+		t.Errorf("expected peers `[10.0.0.1 10.0.0.2]` and mtu 1450, got `%v` and %d", peers, d.Get("mtu"))
+	}
+}
+
+func TestCheckSdnZoneOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		zoneType string
+		set      map[string]bool
+		valid    bool
+	}{
+		{name: "simple", zoneType: "simple", set: map[string]bool{}, valid: true},
+		{name: "vlan", zoneType: "vlan", set: map[string]bool{"bridge": true}, valid: true},
+		{name: "vlan without bridge", zoneType: "vlan", set: map[string]bool{}, valid: false},
+		{name: "evpn", zoneType: "evpn", set: map[string]bool{"controller": true, "vrf_vxlan": true, "exit_nodes": true}, valid: true},
+		{name: "evpn without vrf", zoneType: "evpn", set: map[string]bool{"controller": true}, valid: false},
+		{name: "peers of vlan", zoneType: "vlan", set: map[string]bool{"bridge": true, "peers": true}, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			err := checkSdnZoneOptions(test.zoneType, test.set)
+			if test.valid && err != nil {
+				t.Errorf("%s: unexpected error `%+v`", test.name, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+		})
+	}
+}
+
+func TestSdnSubnetId(t *testing.T) {
+	subnetId := sdnSubnetId("tenants", "10.0.0.0/24")
+	if subnetId != "tenants-10.0.0.0-24" {
+		t.Errorf("expected `tenants-10.0.0.0-24`, got `%s`", subnetId)
+	}
+	cidr, err := sdnSubnetCidr(subnetId)
+	if err != nil || cidr != "10.0.0.0/24" {
+		t.Errorf("expected `10.0.0.0/24`, got `%s` and `%+v`", cidr, err)
+	}
+	if _, err := sdnSubnetCidr("tenants"); err == nil {
+		t.Errorf("expected an error for `tenants`")
+	}
+}
+
+func TestValidateSdnSubnetCidr(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		valid bool
+	}{
+		{cidr: "10.0.0.0/24", valid: true},
+		{cidr: "fd00::/64", valid: true},
+		{cidr: "10.0.0.1/24", valid: false},
+		{cidr: "10.0.0.0", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cidr, func(*testing.T) {
+			_, errs := validateSdnSubnetCidr(test.cidr, "cidr")
+			if test.valid && len(errs) != 0 {
+				t.Errorf("%s: unexpected errors `%+v`", test.cidr, errs)
+			}
+			if !test.valid && len(errs) == 0 {
+				t.Errorf("%s: expected an error", test.cidr)
+			}
+		})
+	}
+}
+
+func TestSdnDhcpRanges(t *testing.T) {
+	block := []interface{}{
+		map[string]interface{}{"start_address": "10.0.0.100", "end_address": "10.0.0.199"},
+	}
+	ranges := sdnDhcpRanges(block)
+	if !reflect.DeepEqual(ranges, []string{"start-address=10.0.0.100,end-address=10.0.0.199"}) {
+		t.Errorf("unexpected ranges `%v`", ranges)
+	}
+
+	for _, value := range []interface{}{
+		[]interface{}{"start-address=10.0.0.100,end-address=10.0.0.199"},
+		[]interface{}{map[string]interface{}{"start-address": "10.0.0.100", "end-address": "10.0.0.199"}},
+	} {
+		if parsed := parseSdnDhcpRanges(value); !reflect.DeepEqual(parsed, block) {
+			t.Errorf("expected `%v`, got `%v`", block, parsed)
+		}
+	}
+}
+
+func TestSdnTypeParams(t *testing.T) {
+	params := map[string]interface{}{"type": "evpn", "asn": 65000, "ebgp": false, "bgp-multipath-as-path-relax": false}
+	deletes := sdnTypeParams("evpn", params, []string{"ebgp-multihop", "loopback", "node"}, sdnControllerParameters, sdnControllerTypeOptions)
+
+	if !reflect.DeepEqual(params, map[string]interface{}{"type": "evpn", "asn": 65000}) {
+		t.Errorf("unexpected params `%v`", params)
+	}
+	if len(deletes) != 0 {
+		t.Errorf("unexpected deletes `%v`", deletes)
+	}
+}
+
+func TestCheckSdnControllerOptions(t *testing.T) {
+	if err := checkSdnOptions("controllers", "bgp", map[string]bool{"node": true, "ebgp": true}, sdnControllerTypeOptions, sdnControllerRequiredOptions); err != nil {
+		t.Errorf("unexpected error `%+v`", err)
+	}
+	if err := checkSdnOptions("controllers", "bgp", map[string]bool{}, sdnControllerTypeOptions, sdnControllerRequiredOptions); err == nil {
+		t.Errorf("expected an error for a bgp controller without node")
+	}
+	if err := checkSdnOptions("controllers", "evpn", map[string]bool{"ebgp": true}, sdnControllerTypeOptions, sdnControllerRequiredOptions); err == nil {
+		t.Errorf("expected an error for an evpn controller with ebgp")
+	}
+}
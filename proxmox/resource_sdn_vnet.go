@@ -0,0 +1,173 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var sdnVnetResourceDef *schema.Resource
+
+var sdnVnetParameters = map[string]string{
+	"zone":       "zone",
+	"tag":        "tag",
+	"alias":      "alias",
+	"vlan_aware": "vlanaware",
+}
+
+func resourceSdnVnet() *schema.Resource {
+	*pxapi.Debug = true
+
+	sdnVnetResourceDef = &schema.Resource{
+		Description: "Manages a vnet of the Software Defined Network (SDN) of the cluster, a bridge guests are attached to.",
+
+		Create: resourceSdnVnetCreate,
+		Read:   resourceSdnVnetRead,
+		Update: resourceSdnVnetUpdate,
+		Delete: resourceSdnVnetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vnet": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z][a-z0-9]{1,7}$`), "must be 2 to 8 lowercase letters and digits, starting with a letter"),
+				Description:  "The id of the vnet, which is the name of its bridge on the nodes",
+			},
+			// the ids of the subnets of a vnet contain its zone
+			"zone": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The zone of the vnet",
+			},
+			"tag": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 16777215),
+				Description:  "The VLAN tag of the vnet in `vlan` zones, the VXLAN id in `vxlan` and `evpn` zones",
+			},
+			"alias": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A descriptive name of the vnet",
+			},
+			"vlan_aware": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Pass VLAN tags of the guests through the vnet",
+			},
+		},
+	}
+
+	return sdnVnetResourceDef
+}
+
+func resourceSdnVnetCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vnet := d.Get("vnet").(string)
+	params, _ := sdnParams(d, sdnVnetResourceDef.Schema, sdnVnetParameters, false)
+	params["vnet"] = vnet
+
+	logger, _ := CreateSubLogger("resource_sdn_vnet_create")
+	logger.Info().Str("vnet", vnet).Msgf("Creating vnet in zone %s", d.Get("zone").(string))
+
+	if _, err := apiPost(pconf.Session, "/cluster/sdn/vnets", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("vnets", vnet))
+
+	if err := sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnVnetRead(d, meta)
+}
+
+func resourceSdnVnetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSdnVnetRead(d, meta)
+}
+
+func _resourceSdnVnetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, vnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_sdn_vnet_read")
+	logger.Info().Str("vnet", vnet).Msg("Reading configuration for vnet")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vnet", vnet)
+	if err = setSdnData(d, sdnVnetResourceDef.Schema, sdnVnetParameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("vnet", vnet).Msgf("Finished vnet read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceSdnVnetUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, vnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := sdnParams(d, sdnVnetResourceDef.Schema, sdnVnetParameters, true)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet), params); err != nil {
+		return err
+	}
+	if err = sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnVnetRead(d, meta)
+}
+
+// proxmox refuses to delete vnets which still have subnets
+func resourceSdnVnetDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, vnet, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if _, err = apiDelete(pconf.Session, apiPath("cluster", "sdn", "vnets", vnet)); err != nil {
+		return err
+	}
+	return sdnApply(pconf)
+}
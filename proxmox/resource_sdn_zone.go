@@ -0,0 +1,238 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var sdnZoneResourceDef *schema.Resource
+
+var sdnZoneParameters = map[string]string{
+	"type":       "type",
+	"nodes":      "nodes",
+	"mtu":        "mtu",
+	"ipam":       "ipam",
+	"bridge":     "bridge",
+	"peers":      "peers",
+	"controller": "controller",
+	"vrf_vxlan":  "vrf-vxlan",
+	"mac":        "mac",
+	"exit_nodes": "exitnodes",
+}
+
+var sdnZoneTypes = []string{"simple", "vlan", "vxlan", "evpn"}
+
+// attribute => the zone types using it, the other attributes are used by all types
+var sdnZoneTypeOptions = map[string][]string{
+	"bridge":     {"vlan"},
+	"peers":      {"vxlan"},
+	"controller": {"evpn"},
+	"vrf_vxlan":  {"evpn"},
+	"mac":        {"evpn"},
+	"exit_nodes": {"evpn"},
+}
+
+// zone type => the attributes it requires
+var sdnZoneRequiredOptions = map[string][]string{
+	"vlan":  {"bridge"},
+	"vxlan": {"peers"},
+	"evpn":  {"controller", "vrf_vxlan"},
+}
+
+func resourceSdnZone() *schema.Resource {
+	*pxapi.Debug = true
+
+	sdnZoneResourceDef = &schema.Resource{
+		Description: "Manages a zone of the Software Defined Network (SDN) of the cluster.",
+
+		Create: resourceSdnZoneCreate,
+		Read:   resourceSdnZoneRead,
+		Update: resourceSdnZoneUpdate,
+		Delete: resourceSdnZoneDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: sdnOptionsCustomizeDiff("zones", sdnZoneTypeOptions, sdnZoneRequiredOptions),
+
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z][a-z0-9]{1,7}$`), "must be 2 to 8 lowercase letters and digits, starting with a letter"),
+				Description:  "The id of the zone",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(sdnZoneTypes, false),
+				Description:  "The type of the zone: simple, vlan, vxlan or evpn",
+			},
+			"nodes": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes the zone is available on, all nodes when empty",
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1280, 65520),
+				Description:  "The MTU of the vnets of the zone",
+			},
+			"ipam": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The IPAM managing the addresses of the subnets of the zone, e.g. pve",
+			},
+			"bridge": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "vlan: The VLAN aware bridge of the nodes the vnets are tagged on",
+			},
+			"peers": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsIPAddress},
+				Description: "vxlan: The addresses of the nodes the VXLAN tunnels are set up between",
+			},
+			"controller": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "evpn: The EVPN controller of the zone",
+			},
+			"vrf_vxlan": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 16777215),
+				Description:  "evpn: The VXLAN id of the VRF routing between the vnets of the zone",
+			},
+			"mac": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "evpn: The anycast MAC address of the gateways of the vnets",
+			},
+			"exit_nodes": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "evpn: The nodes routing the traffic of the zone to the outside",
+			},
+		},
+	}
+
+	return sdnZoneResourceDef
+}
+
+// Checks the attributes set for a zone type, set contains the attributes with a value.
+func checkSdnZoneOptions(zoneType string, set map[string]bool) error {
+	return checkSdnOptions("zones", zoneType, set, sdnZoneTypeOptions, sdnZoneRequiredOptions)
+}
+
+func resourceSdnZoneCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	zone := d.Get("zone").(string)
+	params, _ := sdnParams(d, sdnZoneResourceDef.Schema, sdnZoneParameters, false)
+	params["zone"] = zone
+
+	logger, _ := CreateSubLogger("resource_sdn_zone_create")
+	logger.Info().Str("zone", zone).Msgf("Creating %s zone", d.Get("type").(string))
+
+	if _, err := apiPost(pconf.Session, "/cluster/sdn/zones", params); err != nil {
+		return err
+	}
+	d.SetId(clusterResourceId("zones", zone))
+
+	if err := sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnZoneRead(d, meta)
+}
+
+func resourceSdnZoneRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSdnZoneRead(d, meta)
+}
+
+func _resourceSdnZoneRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, zone, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_sdn_zone_read")
+	logger.Info().Str("zone", zone).Msg("Reading configuration for zone")
+
+	config, err := apiGetMap(pconf.Session, apiPath("cluster", "sdn", "zones", zone))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("zone", zone)
+	if err = setSdnData(d, sdnZoneResourceDef.Schema, sdnZoneParameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("zone", zone).Msgf("Finished zone read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceSdnZoneUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, zone, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := sdnParams(d, sdnZoneResourceDef.Schema, sdnZoneParameters, true)
+	deletes = sdnTypeParams(d.Get("type").(string), params, deletes, sdnZoneParameters, sdnZoneTypeOptions)
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	if _, err = apiPut(pconf.Session, apiPath("cluster", "sdn", "zones", zone), params); err != nil {
+		return err
+	}
+	if err = sdnApply(pconf); err != nil {
+		return err
+	}
+	return _resourceSdnZoneRead(d, meta)
+}
+
+// proxmox refuses to delete zones which still have vnets
+func resourceSdnZoneDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, zone, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if _, err = apiDelete(pconf.Session, apiPath("cluster", "sdn", "zones", zone)); err != nil {
+		return err
+	}
+	return sdnApply(pconf)
+}
@@ -0,0 +1,301 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// names of snapshots, current is the state of the guest itself
+var snapshotNameValidation = validation.All(
+	validation.StringMatch(regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\-]{1,39}$`), "must start with a letter, only contain letters, digits, - and _ and have 2 to 40 characters"),
+	validation.StringNotInSlice([]string{"current"}, true),
+)
+
+func resourceSnapshot() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Manages a named snapshot of a VM, e.g. right after provisioning it.",
+
+		Create:   resourceSnapshotCreate,
+		Read:     resourceSnapshotRead,
+		Update:   resourceSnapshotUpdate,
+		Delete:   resourceSnapshotDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the VM to snapshot",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: snapshotNameValidation,
+				Description:  "The name of the snapshot",
+			},
+			"vmstate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Include the memory of the running VM in the snapshot",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the snapshot",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node of the VM",
+			},
+			"snaptime": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "When the snapshot was taken, as a unix timestamp",
+			},
+			"parent": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the snapshot this snapshot was taken after, empty for the first snapshot",
+			},
+		},
+	}
+}
+
+// Snapshots are identified by the guest and their name, e.g. 100/provisioned, so the id stays the
+// same when the guest is migrated.
+func snapshotId(vmid int, name string) string {
+	return fmt.Sprintf("%d/%s", vmid, name)
+}
+
+func parseSnapshotId(id string) (vmid int, name string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) == 2 && parts[1] != "" {
+		if vmid, err = strconv.Atoi(parts[0]); err == nil {
+			return vmid, parts[1], nil
+		}
+	}
+	return 0, "", fmt.Errorf("Invalid resource format: %s. Must be vmid/name", id)
+}
+
+// The guest of a snapshot, which must be of guestType, qemu or lxc.
+func snapshotGuest(session *pxapi.Session, vmid int, guestType string) (*pxapi.VmRef, error) {
+	vmr, err := apiGuestVmRef(session, vmid)
+	if err != nil {
+		return nil, err
+	}
+	if vmr.GetVmType() != guestType {
+		return nil, fmt.Errorf("Guest %d is a %s guest, not a %s guest", vmid, vmr.GetVmType(), guestType)
+	}
+	return vmr, nil
+}
+
+func snapshotPath(vmr *pxapi.VmRef, components ...string) string {
+	return apiPath(append([]string{"nodes", vmr.Node(), vmr.GetVmType(), strconv.Itoa(vmr.VmId()), "snapshot"}, components...)...)
+}
+
+// The snapshot of a guest with name, nil when the guest has no such snapshot.
+func guestSnapshot(session *pxapi.Session, vmr *pxapi.VmRef, name string) (map[string]interface{}, error) {
+	data, err := apiGet(session, snapshotPath(vmr))
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if snapshot, ok := item.(map[string]interface{}); ok && apiString(snapshot["name"]) == name {
+			return snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// Takes the snapshot of the guest of guestType with params besides the name and description.
+func createGuestSnapshot(d *schema.ResourceData, pconf *providerConfiguration, guestType string, params map[string]interface{}) error {
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	vmr, err := snapshotGuest(pconf.Session, d.Get("vmid").(int), guestType)
+	if err != nil {
+		return err
+	}
+	name := d.Get("name").(string)
+
+	params["snapname"] = name
+	if description := d.Get("description").(string); description != "" {
+		params["description"] = description
+	}
+
+	logger, _ := CreateSubLogger("resource_snapshot_create")
+	logger.Info().Int("vmid", vmr.VmId()).Str("name", name).Msg("Taking snapshot of guest")
+
+	if _, err = apiPostTask(pconf.Session, client, snapshotPath(vmr), params); err != nil {
+		return fmt.Errorf("Taking the snapshot %s of guest %d failed: %v", name, vmr.VmId(), err)
+	}
+	d.SetId(snapshotId(vmr.VmId(), name))
+	return nil
+}
+
+// Reads the attributes shared by the snapshots of VMs and containers and returns the snapshot,
+// nil when the snapshot or its guest is gone.
+func readGuestSnapshot(d *schema.ResourceData, pconf *providerConfiguration, guestType string) (map[string]interface{}, error) {
+	vmid, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return nil, fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_snapshot_read")
+	logger.Info().Int("vmid", vmid).Str("name", name).Msg("Reading snapshot of guest")
+
+	vmr, err := snapshotGuest(pconf.Session, vmid, guestType)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshot, err := guestSnapshot(pconf.Session, vmr, name)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		d.SetId("")
+		return nil, nil
+	}
+
+	d.Set("vmid", vmid)
+	d.Set("name", name)
+	d.Set("description", strings.TrimSuffix(apiString(snapshot["description"]), "\n"))
+	d.Set("node", vmr.Node())
+	d.Set("snaptime", apiInt(snapshot["snaptime"]))
+	d.Set("parent", apiString(snapshot["parent"]))
+
+	logger.Debug().Int("vmid", vmid).Msgf("Finished snapshot read resulting in data: '%+v'", snapshot)
+	return snapshot, nil
+}
+
+func updateGuestSnapshotDescription(d *schema.ResourceData, pconf *providerConfiguration, guestType string) error {
+	vmid, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		return err
+	}
+	vmr, err := snapshotGuest(pconf.Session, vmid, guestType)
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_snapshot_update")
+	logger.Info().Int("vmid", vmid).Str("name", name).Msg("Updating snapshot of guest")
+
+	params := map[string]interface{}{
+		"description": d.Get("description").(string),
+	}
+	_, err = apiPut(pconf.Session, snapshotPath(vmr, name, "config"), params, "description")
+	return err
+}
+
+func deleteGuestSnapshot(d *schema.ResourceData, pconf *providerConfiguration, guestType string) error {
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	vmid, name, err := parseSnapshotId(d.Id())
+	if err != nil {
+		return err
+	}
+	vmr, err := snapshotGuest(pconf.Session, vmid, guestType)
+	if err != nil {
+		// the snapshots are gone with the guest
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	snapshot, err := guestSnapshot(pconf.Session, vmr, name)
+	if err != nil || snapshot == nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_snapshot_delete")
+	logger.Info().Int("vmid", vmid).Str("name", name).Msg("Deleting snapshot of guest")
+
+	upid, err := apiDelete(pconf.Session, snapshotPath(vmr, name))
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Deleting the snapshot %s of guest %d failed: %v", name, vmid, err)
+	}
+	return nil
+}
+
+func resourceSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	params := map[string]interface{}{}
+	if d.Get("vmstate").(bool) {
+		params["vmstate"] = true
+	}
+	if err := createGuestSnapshot(d, pconf, "qemu", params); err != nil {
+		return err
+	}
+	return _resourceSnapshotRead(d, meta)
+}
+
+func resourceSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSnapshotRead(d, meta)
+}
+
+func _resourceSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	snapshot, err := readGuestSnapshot(d, pconf, "qemu")
+	if err != nil || snapshot == nil {
+		return err
+	}
+	d.Set("vmstate", apiBool(snapshot["vmstate"]))
+	return nil
+}
+
+// only the description of a snapshot can change
+func resourceSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	if err := updateGuestSnapshotDescription(d, pconf, "qemu"); err != nil {
+		return err
+	}
+	return _resourceSnapshotRead(d, meta)
+}
+
+func resourceSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	return deleteGuestSnapshot(d, pconf, "qemu")
+}
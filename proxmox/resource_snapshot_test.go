@@ -0,0 +1,36 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestParseSnapshotId(t *testing.T) {
+	if vmid, name, err := parseSnapshotId(snapshotId(100, "provisioned")); err != nil || vmid != 100 || name != "provisioned" {
+		t.Errorf("unexpected snapshot `%d` `%s` `%v`", vmid, name, err)
+	}
+	for _, id := range []string{"100", "100/", "vm/provisioned", "pve/qemu/100"} {
+		if _, _, err := parseSnapshotId(id); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
+
+func TestSnapshotNameValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"provisioned", true},
+		{"zero-day_1", true},
+		{"current", false},
+		{"1st", false},
+		{"a", false},
+		{"with space", false},
+	}
+	for _, test := range tests {
+		_, errs := snapshotNameValidation(test.name, "name")
+		if valid := len(errs) == 0; valid != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.name, test.valid, errs)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var snippetResourceDef *schema.Resource
+
+func resourceSnippet() *schema.Resource {
+	*pxapi.Debug = true
+
+	snippetResourceDef = &schema.Resource{
+		Description: "Uploads a snippet, e.g. cloud-init user data or a hook script, to a storage.",
+
+		Create: resourceSnippetCreate,
+		Read:   resourceSnippetRead,
+		Delete: resourceSnippetDelete,
+		// a changed base user-data of the provider replaces the merged snippets
+		CustomizeDiff: resourceSnippetCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the snippet is uploaded to",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the snippet is stored on, it must have the snippets content type",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The file name of the snippet on the storage, the name of source when not set",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"content", "source"},
+				RequiredWith: []string{"filename"},
+				Description:  "The content of the snippet, e.g. cloud-init user-data or a hookscript",
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"content", "source"},
+				Description:  "Path of a local file to upload",
+			},
+			"merge_base_user_data": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{"source"},
+				Description:   "Merge content into the pm_cloudinit_base_user_data of the provider, both have to be cloud-configs",
+			},
+			"merge_how": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     cloudInitDefaultMergeHow,
+				Description: "The cloud-init merge types of the base user-data and content",
+			},
+			"base_user_data_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The sha256 checksum of the base user-data merged into the snippet",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the snippet, usable in cicustom and as hookscript",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the snippet in bytes.",
+			},
+		},
+	}
+
+	return snippetResourceDef
+}
+
+func resourceSnippetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("merge_base_user_data").(bool) {
+		return nil
+	}
+	hash, err := cloudInitBaseUserDataHash(meta.(*providerConfiguration).CloudInitBaseUserData)
+	if err != nil {
+		return err
+	}
+	if hash != d.Get("base_user_data_hash").(string) {
+		return d.SetNew("base_user_data_hash", hash)
+	}
+	return nil
+}
+
+func resourceSnippetCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	storage := d.Get("storage").(string)
+	source := d.Get("source").(string)
+
+	filename := d.Get("filename").(string)
+	if filename == "" {
+		filename = filepath.Base(source)
+	}
+
+	content := d.Get("content").(string)
+	baseHash := ""
+	if d.Get("merge_base_user_data").(bool) {
+		var err error
+		content, err = cloudInitMergedUserData(pconf.CloudInitBaseUserData, content, d.Get("merge_how").(string))
+		if err != nil {
+			return err
+		}
+		if baseHash, err = cloudInitBaseUserDataHash(pconf.CloudInitBaseUserData); err != nil {
+			return err
+		}
+	}
+
+	var file io.Reader = strings.NewReader(content)
+	if source != "" {
+		sourceFile, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer sourceFile.Close()
+		file = sourceFile
+	}
+
+	logger, _ := CreateSubLogger("resource_snippet_create")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Uploading snippet %s", filename)
+
+	if err := pconf.Client.Upload(node, storage, "snippets", filename, file); err != nil {
+		return fmt.Errorf("Uploading snippet %s to %s failed: %v", filename, storage, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s:snippets/%s", node, storage, filename))
+	d.Set("base_user_data_hash", baseHash)
+	return _resourceSnippetRead(d, meta)
+}
+
+func resourceSnippetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceSnippetRead(d, meta)
+}
+
+func _resourceSnippetRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+	storage := strings.SplitN(volid, ":", 2)[0]
+
+	volume, err := apiGetVolume(pconf.Session, node, volid, "snippets")
+	if err != nil {
+		return err
+	}
+	// the snippet or its storage was removed outside of terraform
+	if volume == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("node", node)
+	d.Set("storage", storage)
+	d.Set("filename", strings.TrimPrefix(volid, storage+":snippets/"))
+	d.Set("volid", volid)
+	d.Set("size", apiInt(volume["size"]))
+	return nil
+}
+
+func resourceSnippetDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, volid, err := parseVolumeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
@@ -0,0 +1,335 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The proxmox_storage_* resources share the handling of /storage, every storage type only
+// adds its own attributes to the schema and to the parameters sent to the API.
+
+// schema attribute => parameter of /storage, shared by all storage types
+var storageParameters = map[string]string{
+	"content": "content",
+	"nodes":   "nodes",
+	"disable": "disable",
+}
+
+// schema attribute => parameter of the secrets of a storage, proxmox keeps them in a separate file
+// and never returns them
+var storageSecretParameters = map[string]string{
+	"password":       "password",
+	"keyring":        "keyring",
+	"encryption_key": "encryption-key",
+}
+
+// attributes of the prune_backups block, the options of prune-backups with - replaced by _
+var storagePruneOptions = []string{"keep_all", "keep_last", "keep_hourly", "keep_daily", "keep_weekly", "keep_monthly", "keep_yearly"}
+
+// The retention of the backups on a storage, for the storage types with the backup content type.
+func storagePruneBackupsSchema() *schema.Schema {
+	pruneSchema := map[string]*schema.Schema{}
+	for _, option := range storagePruneOptions {
+		pruneSchema[option] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  fmt.Sprintf("The number of %s backups to keep", strings.TrimPrefix(option, "keep_")),
+		}
+	}
+	pruneSchema["keep_all"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Keep all backups, the other options must not be set",
+	}
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Elem:        &schema.Resource{Schema: pruneSchema},
+		Description: "The retention of the backups, proxmox uses the retention of the backup job when not set",
+	}
+}
+
+// e.g. "keep-daily=7,keep-last=3", empty when no option is set
+func storagePruneBackups(block []interface{}) string {
+	if len(block) == 0 || block[0] == nil {
+		return ""
+	}
+	prune := block[0].(map[string]interface{})
+	options := []string{}
+	for _, option := range storagePruneOptions {
+		switch value := prune[option].(type) {
+		case bool:
+			if value {
+				options = append(options, strings.ReplaceAll(option, "_", "-")+"=1")
+			}
+		case int:
+			if value != 0 {
+				options = append(options, fmt.Sprintf("%s=%d", strings.ReplaceAll(option, "_", "-"), value))
+			}
+		}
+	}
+	return strings.Join(options, ",")
+}
+
+func parseStoragePruneBackups(pruneBackups string) []interface{} {
+	if pruneBackups == "" {
+		return []interface{}{}
+	}
+	prune := map[string]interface{}{}
+	for option, value := range pxapi.ParsePMConf(pruneBackups, "") {
+		option = strings.ReplaceAll(option, "-", "_")
+		if option == "keep_all" {
+			prune[option] = apiBool(value)
+		} else {
+			prune[option] = apiInt(value)
+		}
+	}
+	return []interface{}{prune}
+}
+
+// Adds the attributes shared by all storage types to the schema of a storage type.
+// contentTypes are the content types the storage type supports.
+func storageSchema(typeSchema map[string]*schema.Schema, contentTypes []string) map[string]*schema.Schema {
+	typeSchema["storage"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The id of the storage",
+	}
+	typeSchema["content"] = &schema.Schema{
+		Type:        schema.TypeSet,
+		Required:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice(contentTypes, false)},
+		Description: fmt.Sprintf("The content types of the storage: %s", strings.Join(contentTypes, ", ")),
+	}
+	typeSchema["nodes"] = &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The nodes the storage is available on, all nodes when empty",
+	}
+	typeSchema["disable"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Disable the storage",
+	}
+	return typeSchema
+}
+
+// Collects the parameters of a storage from the schema. Empty values are returned as the list
+// of parameters to delete, so they are cleared when the storage is updated. Proxmox refuses
+// to change the parameters of ForceNew attributes, they are left out of an update.
+func storageParams(d *schema.ResourceData, storageSchema map[string]*schema.Schema, parameters map[string]string, update bool) (params map[string]interface{}, deletes []string) {
+	params = map[string]interface{}{}
+	deletes = []string{}
+	for attribute, parameter := range parameters {
+		if update && storageSchema[attribute].ForceNew {
+			continue
+		}
+		value := d.Get(attribute)
+		switch storageSchema[attribute].Type {
+		case schema.TypeBool:
+			params[parameter] = value.(bool)
+			continue
+		case schema.TypeInt:
+			if value.(int) != 0 {
+				params[parameter] = value.(int)
+				continue
+			}
+		case schema.TypeSet:
+			if list := schemaStringList(value); len(list) > 0 {
+				params[parameter] = strings.Join(list, ",")
+				continue
+			}
+		default:
+			if value.(string) != "" {
+				params[parameter] = value.(string)
+				continue
+			}
+		}
+		if !storageSchema[attribute].ForceNew {
+			deletes = append(deletes, parameter)
+		}
+	}
+	if _, ok := storageSchema["prune_backups"]; ok {
+		if pruneBackups := storagePruneBackups(d.Get("prune_backups").([]interface{})); pruneBackups != "" {
+			params["prune-backups"] = pruneBackups
+		} else {
+			deletes = append(deletes, "prune-backups")
+		}
+	}
+	return
+}
+
+func setStorageData(d *schema.ResourceData, storage string, storageSchema map[string]*schema.Schema, parameters map[string]string, config map[string]interface{}) error {
+	d.Set("storage", storage)
+	if _, ok := storageSchema["prune_backups"]; ok {
+		if err := d.Set("prune_backups", parseStoragePruneBackups(apiString(config["prune-backups"]))); err != nil {
+			return err
+		}
+	}
+	for attribute, parameter := range parameters {
+		value, ok := config[parameter]
+		switch storageSchema[attribute].Type {
+		case schema.TypeBool:
+			// proxmox omits booleans which are not set, those are at their default
+			if !ok {
+				d.Set(attribute, storageSchema[attribute].Default)
+			} else {
+				d.Set(attribute, apiBool(value))
+			}
+		case schema.TypeInt:
+			d.Set(attribute, apiInt(value))
+		case schema.TypeSet:
+			if err := d.Set(attribute, apiStringList(value, ",")); err != nil {
+				return err
+			}
+		default:
+			if !ok && storageSchema[attribute].Default != nil {
+				d.Set(attribute, storageSchema[attribute].Default)
+			} else {
+				d.Set(attribute, apiString(value))
+			}
+		}
+	}
+	return nil
+}
+
+// the parameters of a storage type together with the ones shared by all types
+func storageTypeParameters(typeParameters map[string]string) map[string]string {
+	parameters := map[string]string{}
+	for attribute, parameter := range storageParameters {
+		parameters[attribute] = parameter
+	}
+	for attribute, parameter := range typeParameters {
+		parameters[attribute] = parameter
+	}
+	return parameters
+}
+
+func resourceStorageCreate(d *schema.ResourceData, meta interface{}, storageType string, storageSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	storage := d.Get("storage").(string)
+	params, _ := storageParams(d, storageSchema, parameters, false)
+	params["storage"] = storage
+	params["type"] = storageType
+	for attribute, parameter := range storageSecretParameters {
+		if _, ok := storageSchema[attribute]; !ok {
+			continue
+		}
+		if secret, ok := d.GetOk(attribute); ok {
+			params[parameter] = secret.(string)
+		}
+	}
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, "/storage", params)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(clusterResourceId("storage", storage))
+
+	return _resourceStorageRead(d, meta, storageType, storageSchema, parameters)
+}
+
+func resourceStorageRead(d *schema.ResourceData, meta interface{}, storageType string, storageSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceStorageRead(d, meta, storageType, storageSchema, parameters)
+}
+
+func _resourceStorageRead(d *schema.ResourceData, meta interface{}, storageType string, storageSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_storage_read")
+	logger.Info().Str("storage", storage).Msgf("Reading configuration for %s storage", storageType)
+
+	config, err := apiGetMap(pconf.Session, apiPath("storage", storage))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if apiString(config["type"]) != storageType {
+		return fmt.Errorf("Storage %s is of type %s, not %s", storage, apiString(config["type"]), storageType)
+	}
+
+	if err = setStorageData(d, storage, storageSchema, parameters, config); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("storage", storage).Msgf("Finished storage read resulting in data: '%+v'", config)
+	return nil
+}
+
+func resourceStorageUpdate(d *schema.ResourceData, meta interface{}, storageType string, storageSchema map[string]*schema.Schema, parameters map[string]string) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params, deletes := storageParams(d, storageSchema, parameters, true)
+	for attribute, parameter := range storageSecretParameters {
+		if _, ok := storageSchema[attribute]; !ok || !d.HasChange(attribute) {
+			continue
+		}
+		if secret := d.Get(attribute).(string); secret != "" {
+			params[parameter] = secret
+		} else {
+			deletes = append(deletes, parameter)
+		}
+	}
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPut(pconf.Session, apiPath("storage", storage), params)
+	})
+	if err != nil {
+		return err
+	}
+
+	return _resourceStorageRead(d, meta, storageType, storageSchema, parameters)
+}
+
+// only the storage definition is removed, the data on it is left alone
+func resourceStorageDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, storage, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("storage", storage))
+	return err
+}
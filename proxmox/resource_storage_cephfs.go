@@ -0,0 +1,85 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var storageCephFsResourceDef *schema.Resource
+
+var storageCephFsParameters = storageTypeParameters(map[string]string{
+	"fs_name":  "fs-name",
+	"subdir":   "subdir",
+	"path":     "path",
+	"monhost":  "monhost",
+	"username": "username",
+	"fuse":     "fuse",
+})
+
+func resourceStorageCephFs() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageCephFsResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `cephfs`, a CephFS file system mounted by the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "cephfs", storageCephFsResourceDef.Schema, storageCephFsParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "cephfs", storageCephFsResourceDef.Schema, storageCephFsParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "cephfs", storageCephFsResourceDef.Schema, storageCephFsParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"fs_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The Ceph file system, the default file system of the cluster when empty",
+			},
+			"subdir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The directory of the file system to mount",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The mount point on the nodes, proxmox defaults to /mnt/pve/<storage>",
+			},
+			"monhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Space separated list of the monitors of an external cluster, the cluster of the nodes is used when empty",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Ceph user of an external cluster, without the client. prefix",
+			},
+			"keyring": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The secret of username on an external cluster",
+			},
+			"fuse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Mount the file system with ceph-fuse instead of the kernel client",
+			},
+		}, []string{"vztmpl", "iso", "backup", "snippets"}),
+	}
+
+	return storageCephFsResourceDef
+}
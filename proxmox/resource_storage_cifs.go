@@ -0,0 +1,99 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storageCifsResourceDef *schema.Resource
+
+var storageCifsParameters = storageTypeParameters(map[string]string{
+	"server":        "server",
+	"share":         "share",
+	"path":          "path",
+	"subdir":        "subdir",
+	"username":      "username",
+	"domain":        "domain",
+	"smbversion":    "smbversion",
+	"preallocation": "preallocation",
+})
+
+func resourceStorageCifs() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageCifsResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `cifs`, an SMB/CIFS share mounted by the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "cifs", storageCifsResourceDef.Schema, storageCifsParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "cifs", storageCifsResourceDef.Schema, storageCifsParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "cifs", storageCifsResourceDef.Schema, storageCifsParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The CIFS/SMB server",
+			},
+			"share": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the share",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The mount point on the nodes, proxmox defaults to /mnt/pve/<storage>",
+			},
+			"subdir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The subdirectory of the share to mount",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The user to log in as, guest access is used when unset.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of username",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The domain of `username`.",
+			},
+			"smbversion": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"default", "2.0", "2.1", "3", "3.0", "3.11"}, false),
+				Description:  "The SMB protocol version, the highest version supported by client and server when unset",
+			},
+			"preallocation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(storagePreallocations, false),
+				Description:  "Preallocation mode of raw and qcow2 images: off, metadata, falloc or full",
+			},
+		}, storageFileContentTypes),
+	}
+
+	return storageCifsResourceDef
+}
@@ -0,0 +1,65 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storageDirResourceDef *schema.Resource
+
+var storageDirParameters = storageTypeParameters(map[string]string{
+	"path":          "path",
+	"shared":        "shared",
+	"preallocation": "preallocation",
+})
+
+// the content types of the file based storage types
+var storageFileContentTypes = []string{"images", "rootdir", "vztmpl", "iso", "backup", "snippets"}
+
+var storagePreallocations = []string{"off", "metadata", "falloc", "full"}
+
+func resourceStorageDir() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageDirResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `dir`, a directory on the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "dir", storageDirResourceDef.Schema, storageDirParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "dir", storageDirResourceDef.Schema, storageDirParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "dir", storageDirResourceDef.Schema, storageDirParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The directory on the nodes",
+			},
+			"shared": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "The directory has the same content on all nodes, e.g. because it is a cluster file system",
+			},
+			"preallocation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(storagePreallocations, false),
+				Description:  "Preallocation mode of raw and qcow2 images: off, metadata, falloc or full",
+			},
+		}, storageFileContentTypes),
+	}
+
+	return storageDirResourceDef
+}
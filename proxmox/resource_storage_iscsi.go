@@ -0,0 +1,117 @@
+package proxmox
+
+import (
+	"sort"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var storageIscsiResourceDef *schema.Resource
+
+var storageIscsiParameters = storageTypeParameters(map[string]string{
+	"portal": "portal",
+	"target": "target",
+})
+
+func resourceStorageIscsi() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageIscsiResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `iscsi`, an iSCSI target.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			if err := resourceStorageCreate(d, meta, "iscsi", storageIscsiResourceDef.Schema, storageIscsiParameters); err != nil {
+				return err
+			}
+			return readStorageIscsiLuns(d, meta)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			if err := resourceStorageRead(d, meta, "iscsi", storageIscsiResourceDef.Schema, storageIscsiParameters); err != nil || d.Id() == "" {
+				return err
+			}
+			return readStorageIscsiLuns(d, meta)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			if err := resourceStorageUpdate(d, meta, "iscsi", storageIscsiResourceDef.Schema, storageIscsiParameters); err != nil {
+				return err
+			}
+			return readStorageIscsiLuns(d, meta)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"portal": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The address of the iSCSI portal, with the port when it is not 3260",
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The IQN of the iSCSI target, e.g. iqn.2003-01.org.linux-iscsi.san.x8664:sn.0123456789ab",
+			},
+			"scan_node": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The node the LUNs of the target are listed on, no LUNs are listed when empty",
+			},
+			"luns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The LUNs of the target as seen by scan_node",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"volid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The volume id of the LUN, usable as the base of an LVM storage",
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the LUN in bytes",
+						},
+					},
+				},
+			},
+		}, []string{"images", "none"}),
+	}
+
+	return storageIscsiResourceDef
+}
+
+// Lists the LUNs of the target on scan_node, the node logs in to the target if it did not yet.
+func readStorageIscsiLuns(d *schema.ResourceData, meta interface{}) error {
+	node := d.Get("scan_node").(string)
+	if node == "" {
+		return d.Set("luns", []interface{}{})
+	}
+
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	content, err := apiGet(pconf.Session, apiPath("nodes", node, "storage", d.Get("storage").(string), "content"))
+	if err != nil {
+		return err
+	}
+	luns := []interface{}{}
+	volumes, _ := content.([]interface{})
+	for _, item := range volumes {
+		volume, _ := item.(map[string]interface{})
+		luns = append(luns, map[string]interface{}{
+			"volid": apiString(volume["volid"]),
+			"size":  apiInt(volume["size"]),
+		})
+	}
+	sort.Slice(luns, func(i, j int) bool {
+		return luns[i].(map[string]interface{})["volid"].(string) < luns[j].(map[string]interface{})["volid"].(string)
+	})
+	return d.Set("luns", luns)
+}
@@ -0,0 +1,69 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var storageLvmResourceDef *schema.Resource
+
+var storageLvmParameters = storageTypeParameters(map[string]string{
+	"vgname":     "vgname",
+	"base":       "base",
+	"shared":     "shared",
+	"saferemove": "saferemove",
+})
+
+// the content types of the block based storage types
+var storageBlockContentTypes = []string{"images", "rootdir"}
+
+func resourceStorageLvm() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageLvmResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `lvm`, a volume group of the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "lvm", storageLvmResourceDef.Schema, storageLvmParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "lvm", storageLvmResourceDef.Schema, storageLvmParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "lvm", storageLvmResourceDef.Schema, storageLvmParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"vgname": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The volume group on the nodes",
+			},
+			"base": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The volume the volume group is created on, e.g. an iSCSI LUN as <storage>:<volume>",
+			},
+			"shared": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "The volume group is on shared storage and available on all nodes",
+			},
+			"saferemove": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Zero out the data of removed volumes",
+			},
+		}, storageBlockContentTypes),
+	}
+
+	return storageLvmResourceDef
+}
@@ -0,0 +1,52 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var storageLvmThinResourceDef *schema.Resource
+
+var storageLvmThinParameters = storageTypeParameters(map[string]string{
+	"vgname":   "vgname",
+	"thinpool": "thinpool",
+})
+
+func resourceStorageLvmThin() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageLvmThinResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `lvmthin`, a thin pool of the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "lvmthin", storageLvmThinResourceDef.Schema, storageLvmThinParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "lvmthin", storageLvmThinResourceDef.Schema, storageLvmThinParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "lvmthin", storageLvmThinResourceDef.Schema, storageLvmThinParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"vgname": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The volume group of the thin pool",
+			},
+			"thinpool": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The thin pool logical volume",
+			},
+		}, storageBlockContentTypes),
+	}
+
+	return storageLvmThinResourceDef
+}
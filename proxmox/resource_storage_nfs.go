@@ -0,0 +1,73 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storageNfsResourceDef *schema.Resource
+
+var storageNfsParameters = storageTypeParameters(map[string]string{
+	"server":        "server",
+	"export":        "export",
+	"path":          "path",
+	"options":       "options",
+	"preallocation": "preallocation",
+})
+
+func resourceStorageNfs() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageNfsResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `nfs`, an NFS export mounted by the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "nfs", storageNfsResourceDef.Schema, storageNfsParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "nfs", storageNfsResourceDef.Schema, storageNfsParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "nfs", storageNfsResourceDef.Schema, storageNfsParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The NFS server",
+			},
+			"export": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The exported path on the server",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The mount point on the nodes, proxmox defaults to /mnt/pve/<storage>",
+			},
+			"options": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "NFS mount options, e.g. vers=4.2",
+			},
+			"preallocation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(storagePreallocations, false),
+				Description:  "Preallocation mode of raw and qcow2 images: off, metadata, falloc or full",
+			},
+		}, storageFileContentTypes),
+	}
+
+	return storageNfsResourceDef
+}
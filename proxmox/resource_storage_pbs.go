@@ -0,0 +1,90 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storagePbsResourceDef *schema.Resource
+
+var storagePbsParameters = storageTypeParameters(map[string]string{
+	"server":      "server",
+	"port":        "port",
+	"datastore":   "datastore",
+	"namespace":   "namespace",
+	"username":    "username",
+	"fingerprint": "fingerprint",
+})
+
+func resourceStoragePbs() *schema.Resource {
+	*pxapi.Debug = true
+
+	storagePbsResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `pbs`, a datastore of a Proxmox Backup Server.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "pbs", storagePbsResourceDef.Schema, storagePbsParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "pbs", storagePbsResourceDef.Schema, storagePbsParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "pbs", storagePbsResourceDef.Schema, storagePbsParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The host name or address of the Proxmox Backup Server",
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port of the Proxmox Backup Server, 8007 when not set",
+			},
+			"datastore": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The datastore on the Proxmox Backup Server",
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The namespace in the datastore, the root namespace when empty",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The user or API token on the Proxmox Backup Server, e.g. backup@pbs or backup@pbs!pve",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password of the user or the secret of the API token",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The SHA-256 fingerprint of the certificate of the server, required unless it is signed by a trusted CA",
+			},
+			"encryption_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The JSON of the key the backups are encrypted with on the client side",
+			},
+			"prune_backups": storagePruneBackupsSchema(),
+		}, []string{"backup"}),
+	}
+
+	return storagePbsResourceDef
+}
@@ -0,0 +1,84 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var storageRbdResourceDef *schema.Resource
+
+var storageRbdParameters = storageTypeParameters(map[string]string{
+	"pool":      "pool",
+	"data_pool": "data-pool",
+	"namespace": "namespace",
+	"monhost":   "monhost",
+	"username":  "username",
+	"krbd":      "krbd",
+})
+
+func resourceStorageRbd() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageRbdResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `rbd`, a Ceph RBD pool.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "rbd", storageRbdResourceDef.Schema, storageRbdParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "rbd", storageRbdResourceDef.Schema, storageRbdParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "rbd", storageRbdResourceDef.Schema, storageRbdParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"pool": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The Ceph pool the images are created in",
+			},
+			"data_pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A separate pool for the data of the images, e.g. an erasure coded pool",
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The RADOS namespace in the pool",
+			},
+			"monhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Space separated list of the monitors of an external cluster, the cluster of the nodes is used when empty",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Ceph user of an external cluster, without the client. prefix",
+			},
+			"keyring": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The contents of the keyring of username on an external cluster",
+			},
+			"krbd": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Map the images with the kernel module instead of librbd",
+			},
+		}, storageBlockContentTypes),
+	}
+
+	return storageRbdResourceDef
+}
@@ -0,0 +1,269 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storageRetentionResourceDef *schema.Resource
+
+func resourceStorageRetention() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageRetentionResourceDef = &schema.Resource{
+		Description: "Enforces a retention policy for ISO images or container templates on a storage.",
+
+		Create: resourceStorageRetentionCreate,
+		Read:   resourceStorageRetentionRead,
+		Update: resourceStorageRetentionUpdate,
+		Delete: resourceStorageRetentionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		// expired files show up as a change of expired to an empty list, applying it deletes them
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Id() == "" {
+				return nil
+			}
+			// a changed policy is only evaluated against the files when it is applied
+			if d.HasChange("filter") || d.HasChange("keep_last") || d.HasChange("max_age") {
+				if err := d.SetNewComputed("retained"); err != nil {
+					return err
+				}
+				return d.SetNewComputed("expired")
+			}
+			if len(d.Get("expired").([]interface{})) > 0 {
+				return d.SetNew("expired", []string{})
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the storage is read from.",
+			},
+			"storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage holding the files.",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"iso", "vztmpl"}, false),
+				Description:  "Content type the retention applies to, iso or vztmpl",
+			},
+			"filter": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only files whose name matches this regular expression are subject to the retention",
+			},
+			"keep_last": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of the newest files to keep, 0 keeps all",
+			},
+			"max_age": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Files older than this duration (e.g. 720h) are deleted, empty keeps all",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if val.(string) == "" {
+						return
+					}
+					if _, err := time.ParseDuration(val.(string)); err != nil {
+						errs = append(errs, fmt.Errorf("%q must be a duration like 720h, got %s", key, val.(string)))
+					}
+					return
+				},
+			},
+			"retained": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Volume IDs of the files subject to the retention which are kept",
+			},
+			"expired": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Volume IDs of the files which are deleted on the next apply",
+			},
+		},
+	}
+
+	return storageRetentionResourceDef
+}
+
+func storageRetentionId(node string, storage string, content string) string {
+	return fmt.Sprintf("%s/%s/%s", node, storage, content)
+}
+
+func parseStorageRetentionId(resId string) (node string, storage string, content string, err error) {
+	parts := strings.Split(resId, "/")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("Invalid resource format: %s. Must be node/storage/content", resId)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// a file on a storage as listed by /nodes/<node>/storage/<storage>/content
+type storageVolume struct {
+	volid string
+	ctime time.Time
+}
+
+// The files are sorted from new to old. A file expires when it is not among the keepLast
+// newest files or when it is older than maxAge, a limit of 0 disables that rule.
+func storageRetentionExpired(volumes []storageVolume, keepLast int, maxAge time.Duration, now time.Time) (retained []string, expired []string) {
+	retained = []string{}
+	expired = []string{}
+	sorted := make([]storageVolume, len(volumes))
+	copy(sorted, volumes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ctime.After(sorted[j].ctime)
+	})
+	for i, volume := range sorted {
+		if (keepLast > 0 && i >= keepLast) || (maxAge > 0 && now.Sub(volume.ctime) > maxAge) {
+			expired = append(expired, volume.volid)
+		} else {
+			retained = append(retained, volume.volid)
+		}
+	}
+	return
+}
+
+func storageRetentionVolumes(session *pxapi.Session, node string, storage string, content string, filter string) ([]storageVolume, error) {
+	var rxFilter *regexp.Regexp
+	if filter != "" {
+		var err error
+		if rxFilter, err = regexp.Compile(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := apiGetWithParams(session, apiPath("nodes", node, "storage", storage, "content"), map[string]interface{}{
+		"content": content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected content listing of %s on %s: %v", storage, node, data)
+	}
+
+	volumes := []storageVolume{}
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok || apiString(entry["content"]) != content {
+			continue
+		}
+		volid := apiString(entry["volid"])
+		// volid is <storage>:<content>/<filename>
+		if rxFilter != nil && !rxFilter.MatchString(volid[strings.LastIndex(volid, "/")+1:]) {
+			continue
+		}
+		volumes = append(volumes, storageVolume{
+			volid: volid,
+			ctime: time.Unix(int64(apiInt(entry["ctime"])), 0),
+		})
+	}
+	return volumes, nil
+}
+
+func resourceStorageRetentionCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(storageRetentionId(d.Get("node").(string), d.Get("storage").(string), d.Get("content").(string)))
+	return resourceStorageRetentionUpdate(d, meta)
+}
+
+func resourceStorageRetentionRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceStorageRetentionRead(d, meta)
+}
+
+func _resourceStorageRetentionRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, storage, content, err := parseStorageRetentionId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_storage_retention_read")
+	logger.Info().Str("node", node).Str("storage", storage).Msgf("Reading %s files for the retention", content)
+
+	volumes, err := storageRetentionVolumes(pconf.Session, node, storage, content, d.Get("filter").(string))
+	if err != nil {
+		return err
+	}
+
+	maxAge, _ := time.ParseDuration(d.Get("max_age").(string))
+	retained, expired := storageRetentionExpired(volumes, d.Get("keep_last").(int), maxAge, time.Now())
+
+	d.Set("node", node)
+	d.Set("storage", storage)
+	d.Set("content", content)
+	d.Set("retained", retained)
+	d.Set("expired", expired)
+
+	logger.Debug().Str("node", node).Str("storage", storage).Msgf("Finished retention read resulting in expired files: '%v'", expired)
+	return nil
+}
+
+func resourceStorageRetentionUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, storage, _, err := parseStorageRetentionId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// list again, the files might have changed since the plan
+	if err = _resourceStorageRetentionRead(d, meta); err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_storage_retention_update")
+	for _, volid := range schemaStringList(d.Get("expired")) {
+		logger.Info().Str("node", node).Str("storage", storage).Msgf("Deleting expired file %s", volid)
+		upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "storage", storage, "content", volid))
+		if err != nil {
+			return err
+		}
+		// newer versions of proxmox delete the file in a task
+		if _, err = apiWaitForTask(pconf.Session, pconf.Client, upid); err != nil {
+			return err
+		}
+	}
+
+	return _resourceStorageRetentionRead(d, meta)
+}
+
+// removing the retention leaves the files alone
+func resourceStorageRetentionDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
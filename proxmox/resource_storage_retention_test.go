@@ -0,0 +1,39 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStorageRetentionExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	day := 24 * time.Hour
+	volumes := []storageVolume{
+		{volid: "local:iso/old.iso", ctime: now.Add(-40 * day)},
+		{volid: "local:iso/new.iso", ctime: now.Add(-1 * day)},
+		{volid: "local:iso/mid.iso", ctime: now.Add(-10 * day)},
+	}
+
+	tests := []struct {
+		name     string
+		keepLast int
+		maxAge   time.Duration
+		retained []string
+		expired  []string
+	}{
+		{name: "keep all", retained: []string{"local:iso/new.iso", "local:iso/mid.iso", "local:iso/old.iso"}, expired: []string{}},
+		{name: "keep last", keepLast: 2, retained: []string{"local:iso/new.iso", "local:iso/mid.iso"}, expired: []string{"local:iso/old.iso"}},
+		{name: "max age", maxAge: 7 * day, retained: []string{"local:iso/new.iso"}, expired: []string{"local:iso/mid.iso", "local:iso/old.iso"}},
+		{name: "both", keepLast: 1, maxAge: 30 * day, retained: []string{"local:iso/new.iso"}, expired: []string{"local:iso/mid.iso", "local:iso/old.iso"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			retained, expired := storageRetentionExpired(volumes, test.keepLast, test.maxAge, now)
+			if !reflect.DeepEqual(retained, test.retained) || !reflect.DeepEqual(expired, test.expired) {
+				t.Errorf("%s: expected `%v` `%v`, got `%v` `%v`", test.name, test.retained, test.expired, retained, expired)
+			}
+		})
+	}
+}
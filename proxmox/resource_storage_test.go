@@ -0,0 +1,97 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestStorageParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceStorageNfs().Schema, map[string]interface{}{
+		"storage": "backups",
+		"server":  "nas.example.com",
+		"export":  "/volume1/proxmox",
+		"content": []interface{}{"backup"},
+		"nodes":   []interface{}{"pve1"},
+	})
+
+	params, deletes := storageParams(d, storageNfsResourceDef.Schema, storageNfsParameters, false)
+	expected := map[string]interface{}{
+		"server":  "nas.example.com",
+		"export":  "/volume1/proxmox",
+		"content": "backup",
+		"nodes":   "pve1",
+		"disable": false,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%+v`, got `%+v`", expected, params)
+	}
+	sort.Strings(deletes)
+	if expectedDeletes := []string{"options", "preallocation"}; !reflect.DeepEqual(deletes, expectedDeletes) {
+		t.Errorf("expected deletes `%v`, got `%v`", expectedDeletes, deletes)
+	}
+
+	params, _ = storageParams(d, storageNfsResourceDef.Schema, storageNfsParameters, true)
+	if _, ok := params["export"]; ok {
+		t.Errorf("expected export to be left out of an update, got `%+v`", params)
+	}
+}
+
+func TestSetStorageData(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceStorageDir().Schema, map[string]interface{}{})
+	err := setStorageData(d, "local-images", storageDirResourceDef.Schema, storageDirParameters, map[string]interface{}{
+		"type":    "dir",
+		"path":    "/srv/images",
+		"content": "images,rootdir",
+		"shared":  1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	content := schemaStringList(d.Get("content"))
+	sort.Strings(content)
+	if !reflect.DeepEqual(content, []string{"images", "rootdir"}) {
+		t.Errorf("expected content `[images rootdir]`, got `%v`", content)
+	}
+	if d.Get("storage").(string) != "local-images" || d.Get("path").(string) != "/srv/images" {
+		t.Errorf("expected storage and path to be set, got `%s` and `%s`", d.Get("storage"), d.Get("path"))
+	}
+	if !d.Get("shared").(bool) || d.Get("disable").(bool) {
+		t.Errorf("expected shared and not disabled, got shared `%v` and disable `%v`", d.Get("shared"), d.Get("disable"))
+	}
+	if nodes := schemaStringList(d.Get("nodes")); len(nodes) != 0 {
+		t.Errorf("expected no nodes, got `%v`", nodes)
+	}
+}
+
+func TestStoragePruneBackups(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    []interface{}
+		expected string
+	}{
+		{"not set", []interface{}{}, ""},
+		{"no options", []interface{}{map[string]interface{}{"keep_all": false, "keep_last": 0}}, ""},
+		{"keep all", []interface{}{map[string]interface{}{"keep_all": true, "keep_last": 0}}, "keep-all=1"},
+		{"options", []interface{}{map[string]interface{}{"keep_all": false, "keep_last": 3, "keep_daily": 7, "keep_monthly": 6}}, "keep-last=3,keep-daily=7,keep-monthly=6"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if pruneBackups := storagePruneBackups(test.block); pruneBackups != test.expected {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.expected, pruneBackups)
+			}
+		})
+	}
+
+	expected := []interface{}{map[string]interface{}{"keep_last": 3, "keep_daily": 7}}
+	if prune := parseStoragePruneBackups("keep-daily=7,keep-last=3"); !reflect.DeepEqual(prune, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, prune)
+	}
+	if prune := parseStoragePruneBackups(""); len(prune) != 0 {
+		t.Errorf("expected no block, got `%v`", prune)
+	}
+}
@@ -0,0 +1,68 @@
+package proxmox
+
+import (
+	"regexp"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var storageZfsPoolResourceDef *schema.Resource
+
+var storageZfsPoolParameters = storageTypeParameters(map[string]string{
+	"pool":       "pool",
+	"blocksize":  "blocksize",
+	"sparse":     "sparse",
+	"mountpoint": "mountpoint",
+})
+
+func resourceStorageZfsPool() *schema.Resource {
+	*pxapi.Debug = true
+
+	storageZfsPoolResourceDef = &schema.Resource{
+		Description: "Manages a storage of type `zfspool`, a ZFS pool of the nodes.",
+
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageCreate(d, meta, "zfspool", storageZfsPoolResourceDef.Schema, storageZfsPoolParameters)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageRead(d, meta, "zfspool", storageZfsPoolResourceDef.Schema, storageZfsPoolParameters)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return resourceStorageUpdate(d, meta, "zfspool", storageZfsPoolResourceDef.Schema, storageZfsPoolParameters)
+		},
+		Delete: resourceStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: storageSchema(map[string]*schema.Schema{
+			"pool": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ZFS pool or dataset the volumes are created in, e.g. rpool/data",
+			},
+			"blocksize": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[0-9]+[kKmM]?$`), "must be a size like 8k or 16k"),
+				Description:  "The volblocksize of new volumes, e.g. 16k",
+			},
+			"sparse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Create thin provisioned volumes without a reservation",
+			},
+			"mountpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The mount point of the pool, if it differs from /<pool>",
+			},
+		}, storageBlockContentTypes),
+	}
+
+	return storageZfsPoolResourceDef
+}
@@ -0,0 +1,256 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var userResourceDef *schema.Resource
+
+func resourceUser() *schema.Resource {
+	*pxapi.Debug = true
+
+	userResourceDef = &schema.Resource{
+		Description: "Manages a user account (`/access/users`).",
+
+		Create: resourceUserCreate,
+		Read:   resourceUserRead,
+		Update: resourceUserUpdate,
+		Delete: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.Get("password").(string) == "" {
+				return nil
+			}
+			return userPasswordRealmError(d.Get("userid").(string))
+		},
+
+		Schema: map[string]*schema.Schema{
+			"userid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "User ID including the realm, e.g. myuser@pve",
+				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+					if !strings.Contains(val.(string), "@") {
+						errs = append(errs, fmt.Errorf("%q must contain the realm (user@realm), got %s", key, val.(string)))
+					}
+					return
+				},
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A free form comment.",
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The email address of the user.",
+			},
+			"firstname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The first name of the user.",
+			},
+			"lastname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The last name of the user.",
+			},
+			"groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "A set of group ids the user is a member of. Do not combine this with the `members` argument of `proxmox_group` for the same group.",
+			},
+			"keys": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Keys for two factor auth (yubico)",
+			},
+			"enable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "A boolean to enable or disable the account. Default is `true`.",
+			},
+			"expire": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Account expiration date (seconds since epoch), 0 means no expiration",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the user, only supported for users of the pve realm. Removing it keeps the current password.",
+			},
+		},
+	}
+
+	return userResourceDef
+}
+
+// a password on any other realm would change e.g. the linux account of a pam user on the node
+func userPasswordRealmError(userID string) error {
+	if !strings.HasSuffix(userID, "@pve") {
+		return fmt.Errorf("Setting a password is only supported for users of the pve realm, got %s", userID)
+	}
+	return nil
+}
+
+func userParams(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"comment":   d.Get("comment").(string),
+		"email":     d.Get("email").(string),
+		"firstname": d.Get("firstname").(string),
+		"lastname":  d.Get("lastname").(string),
+		"groups":    strings.Join(schemaStringList(d.Get("groups")), ","),
+		"keys":      d.Get("keys").(string),
+		"enable":    d.Get("enable").(bool),
+		"expire":    d.Get("expire").(int),
+	}
+}
+
+func resourceUserCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	userID := d.Get("userid").(string)
+	params := userParams(d)
+	params["userid"] = userID
+	if password := d.Get("password").(string); password != "" {
+		if err := userPasswordRealmError(userID); err != nil {
+			return err
+		}
+		params["password"] = password
+	}
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, "/access/users", params)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(clusterResourceId("users", userID))
+
+	return _resourceUserRead(d, meta)
+}
+
+func resourceUserRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceUserRead(d, meta)
+}
+
+func _resourceUserRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	_, userID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_user_read")
+	logger.Info().Str("userid", userID).Msg("Reading configuration for user")
+
+	user, err := apiGetMap(pconf.Session, apiPath("access", "users", userID))
+	if err != nil {
+		// only forget the user when proxmox tells us it is gone, anything else
+		// (permissions, timeouts, ...) must not lead to a recreation
+		if strings.Contains(err.Error(), "no such user") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	if err = setUserData(d, userID, user); err != nil {
+		return err
+	}
+
+	logger.Debug().Str("userid", userID).Msgf("Finished user read resulting in groups: '%v', enable: '%v', expire: '%v'",
+		d.Get("groups").(*schema.Set).List(), d.Get("enable"), d.Get("expire"))
+	return nil
+}
+
+func setUserData(d *schema.ResourceData, userID string, user map[string]interface{}) error {
+	d.Set("userid", userID)
+	d.Set("comment", apiString(user["comment"]))
+	d.Set("email", apiString(user["email"]))
+	d.Set("firstname", apiString(user["firstname"]))
+	d.Set("lastname", apiString(user["lastname"]))
+	if err := d.Set("groups", apiStringList(user["groups"], ",")); err != nil {
+		return err
+	}
+	d.Set("keys", apiString(user["keys"]))
+	// enable defaults to true when proxmox omits it
+	d.Set("enable", user["enable"] == nil || apiBool(user["enable"]))
+	d.Set("expire", apiInt(user["expire"]))
+	return nil
+}
+
+func resourceUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, userID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_user_update")
+	logger.Info().Str("userid", userID).Msg("Starting update of the User resource")
+
+	_, err = apiPut(pconf.Session, apiPath("access", "users", userID), userParams(d),
+		"comment", "email", "firstname", "lastname", "groups", "keys")
+	if err != nil {
+		return err
+	}
+
+	// an emptied password is not sent, proxmox does not allow users without one
+	if d.HasChange("password") && d.Get("password").(string) != "" {
+		if err = userPasswordRealmError(userID); err != nil {
+			return err
+		}
+		_, err = apiWithoutDebug(func() (interface{}, error) {
+			return apiPut(pconf.Session, "/access/password", map[string]interface{}{
+				"userid":   userID,
+				"password": d.Get("password").(string),
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return _resourceUserRead(d, meta)
+}
+
+func resourceUserDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	_, userID, err := parseClusterResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	_, err = apiDelete(pconf.Session, apiPath("access", "users", userID))
+	return err
+}
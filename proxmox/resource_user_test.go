@@ -0,0 +1,70 @@
+package proxmox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSetUserData(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  map[string]interface{}
+		groups []string
+		enable bool
+		expire int
+	}{{
+		name:   "enable missing",
+		input:  map[string]interface{}{},
+		groups: []string{},
+		enable: true,
+	}, {
+		name:   "disabled as number",
+		input:  map[string]interface{}{"enable": float64(0), "expire": float64(1700000000)},
+		groups: []string{},
+		enable: false,
+		expire: 1700000000,
+	}, {
+		name:   "groups as delimited string",
+		input:  map[string]interface{}{"groups": "operators,admins", "enable": float64(1)},
+		groups: []string{"admins", "operators"},
+		enable: true,
+	}, {
+		name:   "groups as array",
+		input:  map[string]interface{}{"groups": []interface{}{"operators", "admins"}},
+		groups: []string{"admins", "operators"},
+		enable: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{"userid": "test@pve"})
+			if err := setUserData(d, "test@pve", test.input); err != nil {
+				t.Fatalf("%s: unexpected error `%+v`", test.name, err)
+			}
+
+			groups := schemaStringList(d.Get("groups"))
+			sort.Strings(groups)
+			if !reflect.DeepEqual(groups, test.groups) {
+				t.Errorf("%s: groups expected `%+v`, got `%+v`", test.name, test.groups, groups)
+			}
+			if d.Get("enable").(bool) != test.enable {
+				t.Errorf("%s: enable expected `%+v`, got `%+v`", test.name, test.enable, d.Get("enable"))
+			}
+			if d.Get("expire").(int) != test.expire {
+				t.Errorf("%s: expire expected `%+v`, got `%+v`", test.name, test.expire, d.Get("expire"))
+			}
+		})
+	}
+}
+
+func TestUserPasswordRealmError(t *testing.T) {
+	if err := userPasswordRealmError("test@pve"); err != nil {
+		t.Errorf("pve realm: unexpected error `%+v`", err)
+	}
+	if err := userPasswordRealmError("root@pam"); err == nil {
+		t.Errorf("pam realm: expected an error")
+	}
+}
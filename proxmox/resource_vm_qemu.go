@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // using a global variable here so that we have an internally accessible
@@ -107,6 +110,8 @@ func resourceVmQemu() *schema.Resource {
 
 	*pxapi.Debug = true
 	thisResource = &schema.Resource{
+		Description: "Manages a QEMU VM, created from an ISO image, by cloning a template or with PXE boot.",
+
 		Create: resourceVmQemuCreate,
 		Read:   resourceVmQemuRead,
 		Update: resourceVmQemuUpdate,
@@ -114,22 +119,27 @@ func resourceVmQemu() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: customdiff.All(placementCustomizeDiff, namingCustomizeDiff("name"), osDefaultsCustomizeDiff, qemuBootCustomizeDiff),
 
 		Schema: map[string]*schema.Schema{
 			"vmid": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The ID of the VM in Proxmox. The default value of `0` indicates it should use the next available ID in the sequence.",
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the VM within Proxmox.",
 			},
 			"define_connection_info": { // by default define SSH for provisioner info
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to let terraform define the (SSH) connection parameters for preprovisioners.",
 			},
 			"desc": {
 				Type:     schema.TypeString,
@@ -137,64 +147,170 @@ func resourceVmQemu() *schema.Resource {
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "The description of the VM. Shows as the 'Notes' field in the Proxmox GUI.",
 			},
 			"target_node": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the Proxmox node on which to place the VM; required unless `target_nodes` or `place_with_vmid` is set.",
 			},
 			"bios": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "seabios",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "seabios",
+				Description: "The BIOS to use, options are `seabios` or `ovmf` for UEFI.",
+			},
+			"efidisk": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "The disk holding the EFI variables of an ovmf VM",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"storage": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The storage the EFI disk is created on.",
+						},
+						"efitype": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "4m",
+							ValidateFunc: validation.StringInSlice([]string{"2m", "4m"}, false),
+							Description:  "Size of the OVMF vars template, secure boot requires 4m",
+						},
+						"pre_enrolled_keys": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Create the disk from the vars template with the distribution and Microsoft secure boot keys enrolled",
+						},
+					},
+				},
+			},
+			"virtiofs": qemuVirtiofsSchema(),
+			"efi_vars_reset": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Changing this value recreates the EFI disk from its template, which clears the EFI variables including the secure boot state",
 			},
 			"onboot": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to have the VM startup after the PVE node starts.",
 			},
 			"boot": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "cdn",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "cdn",
+				Description: "The boot order for the VM. Ordered string of characters denoting boot order. Options: floppy (`a`), hard disk (`c`), CD-ROM (`d`), or network (`n`).",
 			},
 			"bootdisk": {
-				Type:     schema.TypeString,
-				Computed: true,
-				Optional: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Optional:    true,
+				Description: "Enable booting from specified disk. You shouldn't need to change it under most circumstances.",
+			},
+			"bootdisk_size": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The size of the disk the VM boots from, e.g. `32G`",
 			},
 			"agent": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Set to `1` to enable the QEMU Guest Agent. The [`qemu-guest-agent`](https://pve.proxmox.com/wiki/Qemu-guest-agent) daemon has to run in the guest for this to have any effect.",
 			},
 			"guest_agent_ready_timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  600,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     600,
+				Description: "The seconds to wait for the guest agent to report the network interfaces of the VM.",
 			},
 			"iso": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"clone", "pxe", "restore_from"},
+				Description:   "The name of the ISO image to mount to the VM. One of `clone`, `iso`, `pxe` or `restore_from` needs to be set.",
 			},
 			"clone": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"iso", "pxe", "restore_from"},
+				Description:   "The base VM from which to clone to create the new VM.",
+			},
+			"pxe": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{"clone", "iso", "restore_from"},
+				Description:   "Create an empty VM which boots from the network, `boot` has to include the network",
+			},
+			"restore_from": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"clone", "iso", "pxe"},
+				Description:   "The volume id of a backup the VM is restored from, e.g. `pbs:backup/vm/100/2024-01-01T00:00:00Z`. The configuration of the resource is applied to the restored VM.",
+			},
+			"restore_storage": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"restore_from"},
+				Description:  "The storage the disks of the restored VM are created on, the storages of the backup when not set",
 			},
 			"cloudinit_cdrom_storage": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Set the storage location for the cloud-init drive. Required when specifying `cicustom`.",
+			},
+			"source_digest": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"clone"},
+				Description:  "The expected digest of the configuration of the clone source. The clone fails, or only warns with `source_digest_mismatch = \"warn\"`, when the configuration of the source changed. Only applies when `clone` is set.",
+			},
+			"clone_source_vmid": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The id of the VM the VM was cloned from, recorded when it was created",
+			},
+			"clone_source_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the VM the VM was cloned from, recorded when it was created",
+			},
+			"clone_source_digest": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The digest of the configuration of the VM the VM was cloned from, recorded when it was created",
+			},
+			"source_digest_mismatch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "error",
+				ValidateFunc: validation.StringInSlice(sourceDigestMismatchActions, false),
+				Description:  "What to do when the digest of the clone source doesn't match `source_digest`: error or warn",
 			},
 			"full_clone": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				ForceNew: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Set to `true` to create a full clone, or `false` to create a linked clone. See the [docs about cloning](https://pve.proxmox.com/pve-docs/chapter-qm.html#qm_copy_and_clone) for more info. Only applies when `clone` is set.",
 			},
 			"hastate": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Requested HA state for the resource. One of \"started\", \"stopped\", \"enabled\", \"disabled\", or \"ignored\". See the [docs about HA](https://pve.proxmox.com/pve-docs/chapter-ha-manager.html#ha_manager_resource_config) for more info.",
 			},
 			"qemu_os": {
 				Type:     schema.TypeString,
@@ -206,64 +322,89 @@ func resourceVmQemu() *schema.Resource {
 					}
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "The type of OS in the guest. Set properly to allow Proxmox to enable optimizations for the appropriate guest OS.",
 			},
-			"tags": {
-				Type:     schema.TypeString,
-				Optional: true,
+			"os_defaults": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Derive the type of the disks and the model of the network devices which don't set one, and the `scsihw` of new VMs, from `qemu_os`. When disabled every `disk` and `network` block has to set its type.",
 			},
+			"default_disk_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the disks without a type, derived from `qemu_os`: `scsi` for `l26`, `sata` for Windows and `ide` for all other OS types.",
+			},
+			"default_network_model": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The model of the network devices without a model, derived from `qemu_os`: `virtio` for `l26` and `e1000` for all other OS types.",
+			},
+			"tags": tagsSchema("Tags of the VM, separated by ;"),
 			"args": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arguments passed to QEMU as they are, e.g. `-device usb-host,vendorid=0x0781`. Only `root@pam` may set them.",
 			},
 			"memory": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  512,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     512,
+				Description: "The amount of memory to allocate to the VM in Megabytes.",
 			},
 			"balloon": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "The minimum amount of memory to allocate to the VM in Megabytes, when Automatic Memory Allocation is desired.  Proxmox will enable a balloon device on the guest to manage dynamic allocation.  See the [docs about memory](https://pve.proxmox.com/pve-docs/chapter-qm.html#qm_memory) for more info.",
 			},
 			"cores": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  1,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "The number of CPU cores per CPU socket to allocate to the VM.",
 			},
 			"sockets": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  1,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "The number of CPU sockets to allocate to the VM.",
 			},
 			"vcpus": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  0,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "The number of vCPUs plugged into the VM when it starts. If `0`, this is set automatically by Proxmox to `sockets * cores`.",
 			},
 			"cpu": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "host",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "host",
+				Description: "The type of CPU to emulate in the Guest. See the [docs about CPU Types](https://pve.proxmox.com/pve-docs/chapter-qm.html#qm_cpu) for more info.",
 			},
 			"numa": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to enable [Non-Uniform Memory Access](https://pve.proxmox.com/pve-docs/chapter-qm.html#qm_cpu) in the guest.",
 			},
 			"kvm": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to enable KVM hardware virtualization.",
 			},
 			"hotplug": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "network,disk,usb",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "network,disk,usb",
+				Description: "Comma delimited list of hotplug features to enable. Options: `network`, `disk`, `cpu`, `memory`, `usb`. Set to `0` to disable hotplug.",
 			},
 			"scsihw": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The SCSI controller to emulate. Options: `lsi`, `lsi53c810`, `megasas`, `pvscsi`, `virtio-scsi-pci`, `virtio-scsi-single`.",
 			},
 			"vga": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -271,16 +412,19 @@ func resourceVmQemu() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "std",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "std",
+							Description: "The type of display to virtualize. Options: `cirrus`, `none`, `qxl`, `qxl2`, `qxl3`, `qxl4`, `serial0`, `serial1`, `serial2`, `serial3`, `std`, `virtio`, `vmware`.",
 						},
 						"memory": {
-							Type:     schema.TypeInt,
-							Optional: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "The memory of the display device in megabytes.",
 						},
 					},
 				},
+				Description: "The display device of the VM. Only the first instance of the block is used.",
 			},
 			"network": &schema.Schema{
 				Type:          schema.TypeList,
@@ -294,18 +438,22 @@ func resourceVmQemu() *schema.Resource {
 						//	Optional: true,
 						//},
 						"model": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Network Card Model. The virtio model provides the best performance with very low CPU overhead. If your guest does not support this driver, it is usually best to use `e1000`. Defaults to `default_network_model`.",
 						},
 						"macaddr": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Override the randomly generated MAC Address for the VM.",
 						},
 						"bridge": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "nat",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "nat",
+							Description: "Bridge to which the network device should be attached. The Proxmox VE standard bridge is called `vmbr0`.",
 						},
 						"tag": &schema.Schema{
 							Type:        schema.TypeInt,
@@ -314,27 +462,32 @@ func resourceVmQemu() *schema.Resource {
 							Default:     -1,
 						},
 						"firewall": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether to enable the Proxmox firewall on this network device.",
 						},
 						"rate": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "Network device rate limit in megabytes per second as floating point number. Set to `0` to disable rate limiting.",
 						},
 						"queues": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "Number of packet queues to be used on the device. Requires `virtio` model to have an effect.",
 						},
 						"link_down": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether this interface should be disconnected (like pulling the plug).",
 						},
 					},
 				},
+				Description: "The network devices of the VM. Can be specified multiple times.",
 			},
 			"unused_disk": &schema.Schema{
 				Type:     schema.TypeList,
@@ -344,16 +497,19 @@ func resourceVmQemu() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"storage": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The storage of the unused disk.",
 						},
 						"slot": &schema.Schema{
-							Type:     schema.TypeInt,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of the unused disk, i.e. the `n` in `unused[n]`.",
 						},
 						"file": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The file name of the volume of the unused disk.",
 						},
 					},
 				},
@@ -365,12 +521,15 @@ func resourceVmQemu() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The type of disk device to add. Options: `ide`, `sata`, `scsi`, `virtio`. Defaults to `default_disk_type`.",
 						},
 						"storage": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the storage pool on which to store the disk.",
 						},
 						"size": &schema.Schema{
 							Type:     schema.TypeString,
@@ -382,37 +541,44 @@ func resourceVmQemu() *schema.Resource {
 								}
 								return
 							},
+							Description: "The size of the created disk, format must match the regex `\\d+[GMK]`, where G, M, and K represent Gigabytes, Megabytes, and Kilobytes respectively.",
 						},
 						"format": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The drive’s backing file’s data format.",
 						},
 						"cache": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "none",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "none",
+							Description: "The drive’s cache mode. Options: `directsync`, `none`, `unsafe`, `writeback`, `writethrough`.",
 						},
 						"backup": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Whether the drive should be included when making backups.",
 						},
 						"iothread": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Whether to use iothreads for this drive. Only effective with a disk of type `virtio`, or `scsi` when the emulated controller type (`scsihw`) is `virtio-scsi-single`.",
 						},
 						"replicate": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Whether the drive should be considered for replication jobs.",
 						},
 						//SSD emulation
 						"ssd": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Whether to expose this drive as an SSD, rather than a rotational hard disk.",
 						},
 						"discard": &schema.Schema{
 							Type:     schema.TypeString,
@@ -424,61 +590,73 @@ func resourceVmQemu() *schema.Resource {
 								}
 								return
 							},
+							Description: "Controls whether to pass discard/trim requests to the underlying storage. Only effective when the underlying storage supports thin provisioning. See the [docs about disks](https://pve.proxmox.com/pve-docs/chapter-qm.html#qm_hard_disk) for the caveats.",
 						},
 						//Maximum r/w speed in megabytes per second
 						"mbps": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Maximum r/w speed in megabytes per second. `0` means unlimited.",
 						},
 						"mbps_rd": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Maximum read speed in megabytes per second. `0` means unlimited.",
 						},
 						"mbps_rd_max": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Maximum unthrottled read pool in megabytes per second. `0` means unlimited.",
 						},
 						"mbps_wr": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Maximum write speed in megabytes per second. `0` means unlimited.",
 						},
 						"mbps_wr_max": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  0,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Maximum unthrottled write pool in megabytes per second. `0` means unlimited.",
 						},
 						// Misc
 						"file": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The filename portion of the path to the drive’s backing volume. You shouldn't need to specify this, use the `storage` parameter instead.",
 						},
 						"media": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The drive’s media type. Options: `cdrom`, `disk`.",
 						},
 						"volume": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The full path to the drive’s backing volume including the storage pool name. You shouldn't need to specify this, use the `storage` parameter instead.",
 						},
 						"slot": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "Deprecated, the slot of a disk is its position among the disks of its type.",
 						},
 						"storage_type": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: false,
-							Computed: true,
+							Type:        schema.TypeString,
+							Required:    false,
+							Computed:    true,
+							Description: "The type of pool that `storage` is backed by. You shouldn't need to specify this, use the `storage` parameter instead.",
 						},
 					},
 				},
+				Description: "The disks of the VM. Can be specified multiple times.",
 			},
 			// Deprecated single disk config.
 			"disk_gb": {
@@ -491,11 +669,13 @@ func resourceVmQemu() *schema.Resource {
 					newf, _ := strconv.ParseFloat(new, 64)
 					return oldf >= newf
 				},
+				Description: "Deprecated, use `disk.size` instead.",
 			},
 			"storage": {
-				Type:       schema.TypeString,
-				Deprecated: "Use `disk.storage` instead",
-				Optional:   true,
+				Type:        schema.TypeString,
+				Deprecated:  "Use `disk.storage` instead",
+				Optional:    true,
+				Description: "Deprecated, use `disk.storage` instead.",
 			},
 			"storage_type": {
 				Type:       schema.TypeString,
@@ -508,23 +688,27 @@ func resourceVmQemu() *schema.Resource {
 					}
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "Deprecated, use `disk.type` instead.",
 			},
 			// Deprecated single nic config.
 			"nic": {
-				Type:       schema.TypeString,
-				Deprecated: "Use `network` instead",
-				Optional:   true,
+				Type:        schema.TypeString,
+				Deprecated:  "Use `network` instead",
+				Optional:    true,
+				Description: "Deprecated, use `network` instead.",
 			},
 			"bridge": {
-				Type:       schema.TypeString,
-				Deprecated: "Use `network.bridge` instead",
-				Optional:   true,
+				Type:        schema.TypeString,
+				Deprecated:  "Use `network.bridge` instead",
+				Optional:    true,
+				Description: "Deprecated, use `network.bridge` instead.",
 			},
 			"vlan": {
-				Type:       schema.TypeInt,
-				Deprecated: "Use `network.tag` instead",
-				Optional:   true,
-				Default:    -1,
+				Type:        schema.TypeInt,
+				Deprecated:  "Use `network.tag` instead",
+				Optional:    true,
+				Default:     -1,
+				Description: "Deprecated, use `network.tag` instead.",
 			},
 			"mac": {
 				Type:       schema.TypeString,
@@ -536,6 +720,7 @@ func resourceVmQemu() *schema.Resource {
 					}
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "Deprecated, use `network.macaddr` instead.",
 			},
 			// Other
 			"serial": &schema.Schema{
@@ -544,19 +729,23 @@ func resourceVmQemu() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": &schema.Schema{
-							Type:     schema.TypeInt,
-							Required: true,
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "The ID of the serial device. Must be unique, and between `0-3`.",
 						},
 						"type": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The type of serial device to create. Options: `socket`, or the path to a serial device like `/dev/ttyS0`.",
 						},
 					},
 				},
+				Description: "The serial devices of the VM. Can be specified up to 4 times.",
 			},
 			"os_type": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Which provisioning method to use, based on the OS type. Options: `ubuntu`, `centos`, `cloud-init`.",
 			},
 			"os_network_config": {
 				Type:     schema.TypeString,
@@ -565,15 +754,18 @@ func resourceVmQemu() *schema.Resource {
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "Only applies when `define_connection_info` is true. Network configuration to be copied into the VM when preprovisioning `ubuntu` or `centos` guests. The specified configuration is added to `/etc/network/interfaces` for Ubuntu, or `/etc/sysconfig/network-scripts/ifcfg-eth0` for CentOS. Forces re-creation on change.",
 			},
 			"ssh_forward_ip": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only applies when `define_connection_info` is true. The IP (and optional colon separated port), to use to connect to the host for preprovisioning. If using cloud-init, this can be left blank.",
 			},
 			"ssh_user": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Only applies when `define_connection_info` is true. The user with which to connect to the guest for preprovisioning. Forces re-creation on change.",
 			},
 			"ssh_private_key": {
 				Type:      schema.TypeString,
@@ -582,21 +774,32 @@ func resourceVmQemu() *schema.Resource {
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "Only applies when `define_connection_info` is true. The private key to use when connecting to the guest for preprovisioning. Sensitive.",
 			},
 			"force_create": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If `false`, and a vm of the same name, on the same node exists, terraform will attempt to reconfigure that VM with these settings. Set to true to always create a new VM (note, the name of the VM must still be unique, otherwise an error will be produced.)",
 			},
 			"clone_wait": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  15,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     15,
+				Description: "Provider will wait `clone_wait` seconds after an UpdateConfig operation.",
 			},
 			"additional_wait": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  15,
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     15,
+				Description: "The amount of time in seconds to wait between creating the VM and powering it up.",
+			},
+			"disk_operation_guard": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice(diskOperationGuards, false),
+				Description:  "Freeze the file systems through the guest agent or suspend the VM while its disks are resized, other disk operations are not guarded: none, freeze or suspend",
 			},
 			"ci_wait": { // how long to wait before provision
 				Type:     schema.TypeInt,
@@ -608,10 +811,12 @@ func resourceVmQemu() *schema.Resource {
 					}
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "How long in seconds to wait before provisioning.",
 			},
 			"ciuser": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Override the default cloud-init user for provisioning.",
 			},
 			"cipassword": {
 				Type:      schema.TypeString,
@@ -623,21 +828,25 @@ func resourceVmQemu() *schema.Resource {
 					}
 					return false
 				},
+				Description: "Override the default cloud-init user's password. Sensitive.",
 			},
 			"cicustom": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Instead of specifying `ciuser`, `cipassword`, etc. you can specify the path to a custom cloud-init config file here. Grants more flexibility in configuring cloud-init.",
 			},
 			"searchdomain": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true, // could be pre-existing if we clone from a template with it defined
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true, // could be pre-existing if we clone from a template with it defined
+				Description: "Sets default DNS search domain suffix.",
 			},
 			"nameserver": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true, // could be pre-existing if we clone from a template with it defined
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true, // could be pre-existing if we clone from a template with it defined
+				Description: "Sets default DNS server for guest.",
 			},
 			"sshkeys": {
 				Type:     schema.TypeString,
@@ -645,59 +854,77 @@ func resourceVmQemu() *schema.Resource {
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					return strings.TrimSpace(old) == strings.TrimSpace(new)
 				},
+				Description: "Newline delimited list of SSH public keys to add to authorized keys file for the cloud-init user.",
 			},
 			"ipconfig0": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The first IP address to assign to the guest. Format: `[gw=<GatewayIPv4>] [,gw6=<GatewayIPv6>] [,ip=<IPv4Format/CIDR>] [,ip6=<IPv6Format/CIDR>]`.",
 			},
 			"ipconfig1": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The second IP address to assign to the guest. Same format as `ipconfig0`.",
 			},
 			"ipconfig2": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The third IP address to assign to the guest. Same format as `ipconfig0`.",
 			},
 			"ipconfig3": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The fourth IP address to assign to the guest. Same format as `ipconfig0`.",
 			},
 			"ipconfig4": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The fifth IP address to assign to the guest. Same format as `ipconfig0`.",
 			},
 			"ipconfig5": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The sixth IP address to assign to the guest. Same format as `ipconfig0`.",
 			},
 			"preprovision": {
 				Type:          schema.TypeBool,
 				Optional:      true,
 				Default:       true,
 				ConflictsWith: []string{"ssh_forward_ip", "ssh_user", "ssh_private_key", "os_type", "os_network_config"},
+				Description:   "Whether to preprovision the VM.",
 			},
 			"pool": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The resource pool to which the VM will be added.",
 			},
 			"ssh_host": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The host used to connect to the VM for preprovisioning.",
 			},
 			"ssh_port": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The port used to connect to the VM for preprovisioning.",
 			},
 			"force_recreate_on_change_of": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "If the value of this string changes, the VM will be recreated. Useful for allowing this resource to be recreated when arbitrary attributes change. An example where this is useful is a cloudinit configuration (as the `cicustom` attribute points to a file not the content).",
+			},
+			"manage_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether updates start a stopped VM and restart it for changes which require a reboot. When false the VM is only started once after it was created and its power state is left to others afterwards, e.g. an external orchestrator.",
 			},
 			"reboot_required": {
 				Type:        schema.TypeBool,
@@ -705,11 +932,24 @@ func resourceVmQemu() *schema.Resource {
 				Description: "Internal variable, true if any of the modified parameters require a reboot to take effect.",
 			},
 			"default_ipv4_address": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The IPv4 address of the first network interface reported by the guest agent.",
 			},
 		},
 	}
+	for key, value := range resourceClientSchema() {
+		thisResource.Schema[key] = value
+	}
+	for key, value := range placementSchema() {
+		thisResource.Schema[key] = value
+	}
+	for key, value := range guestSummarySchema() {
+		thisResource.Schema[key] = value
+	}
+	for key, value := range guestDestroySchema() {
+		thisResource.Schema[key] = value
+	}
 	return thisResource
 }
 
@@ -728,20 +968,25 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 	vmName := d.Get("name").(string)
 	vga := d.Get("vga").(*schema.Set)
 	qemuVgaList := vga.List()
 
 	qemuNetworks, _ := ExpandDevicesList(d.Get("network").([]interface{}))
 	qemuDisks, _ := ExpandDevicesList(d.Get("disk").([]interface{}))
+	applyOsDefault(qemuNetworks, "model", d.Get("default_network_model").(string))
+	applyOsDefault(qemuDisks, "type", d.Get("default_disk_type").(string))
 
 	serials := d.Get("serial").(*schema.Set)
 	qemuSerials, _ := DevicesSetToMap(serials)
 
 	config := pxapi.ConfigQemu{
 		Name:         vmName,
-		Description:  d.Get("desc").(string),
+		Description:  descriptionWithRunInfo(d.Get("desc").(string), pconf.RunWorkspace, pconf.RunURL),
 		Pool:         d.Get("pool").(string),
 		Bios:         d.Get("bios").(string),
 		Onboot:       d.Get("onboot").(bool),
@@ -760,7 +1005,7 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 		Scsihw:       d.Get("scsihw").(string),
 		HaState:      d.Get("hastate").(string),
 		QemuOs:       d.Get("qemu_os").(string),
-		Tags:         d.Get("tags").(string),
+		Tags:         tagsWithAntiAffinity(d.Get("tags").(string), d.Get("anti_affinity_group").(string)),
 		Args:         d.Get("args").(string),
 		QemuNetworks: qemuNetworks,
 		QemuDisks:    qemuDisks,
@@ -797,6 +1042,16 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 	targetNode := d.Get("target_node").(string)
 	pool := d.Get("pool").(string)
 
+	// a guest recycled by name stays on its node
+	if targetNode == "" && dupVmr != nil {
+		targetNode = dupVmr.Node()
+	} else if targetNode == "" {
+		var err error
+		if targetNode, err = resolvePlacement(d, pconf, d.Get("vmid").(int)); err != nil {
+			return err
+		}
+	}
+
 	if dupVmr != nil && forceCreate {
 		return fmt.Errorf("Duplicate VM name (%s) with vmId: %d. Set force_create=false to recycle", vmName, dupVmr.VmId())
 	} else if dupVmr != nil && dupVmr.Node() != targetNode {
@@ -822,95 +1077,27 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 		if pool != "" {
 			vmr.SetPool(pool)
 		}
+		session, err := resourceSession(d, pconf)
+		if err != nil {
+			return err
+		}
+		if err = checkGuestIdFree(session, vmr.VmId()); err != nil {
+			return err
+		}
 
-		// check if ISO or clone
-		if d.Get("clone").(string) != "" {
-			fullClone := 1
-			if !d.Get("full_clone").(bool) {
-				fullClone = 0
-			}
-			config.FullClone = &fullClone
-
-			sourceVmrs, err := client.GetVmRefsByName(d.Get("clone").(string))
-			if err != nil {
-				return err
-			}
-
-			// prefer source Vm located on same node
-			sourceVmr := sourceVmrs[0]
-			for _, candVmr := range sourceVmrs {
-				if candVmr.Node() == vmr.Node() {
-					sourceVmr = candVmr
-				}
-			}
-
-			log.Print("[DEBUG] cloning VM")
-			err = config.CloneVm(sourceVmr, vmr, client)
-
-			if err != nil {
-				return err
-			}
-
-			// Waiting for the clone to become ready and
-			// read back all the current disk configurations from proxmox
-			// this allows us to receive updates on the post-clone state of the vm we're building
-			log.Print("[DEBUG] Waiting for clone becoming ready")
-			var config_post_clone *pxapi.ConfigQemu
-			for {
-				// Wait until we can actually retrieve the config from the cloned machine
-				config_post_clone, err = pxapi.NewConfigQemuFromApi(vmr, client)
-				if config_post_clone != nil {
-					break
-					// to prevent an infinite loop we check for any other error
-					// this error is actually fine because the clone is not ready yet
-				} else if err.Error() != "vm locked, could not obtain config" {
-					return err
-				}
-				time.Sleep(5 * time.Second)
-				log.Print("[DEBUG] Clone still not ready, checking again")
-			}
-
-			logger.Debug().Str("vmid", d.Id()).Msgf("Original disks: '%+v', Clone Disks '%+v'", config.QemuDisks, config_post_clone.QemuDisks)
-
-			// update the current working state to use the appropriate file specification
-			// proxmox needs so we can correctly update the existing disks (post-clone)
-			// instead of accidentially causing the existing disk to be detached.
-			// see https://github.com/Telmate/terraform-provider-proxmox/issues/239
-			for slot, disk := range config_post_clone.QemuDisks {
-				// only update the desired configuration if it was not set by the user
-				// we do not want to overwrite the desired config with the results from
-				// proxmox if the user indicates they wish a particular file or volume config
-				if config.QemuDisks[slot]["file"] == "" {
-					config.QemuDisks[slot]["file"] = disk["file"]
-				}
-				if config.QemuDisks[slot]["volume"] == "" {
-					config.QemuDisks[slot]["volume"] = disk["volume"]
-				}
-			}
-
-			err = config.UpdateConfig(vmr, client)
-			if err != nil {
-				// Set the id because when update config fail the vm is still created
-				d.SetId(resourceId(targetNode, "qemu", vmr.VmId()))
-				return err
-			}
-
-			// give sometime to proxmox to catchup
-			time.Sleep(time.Duration(d.Get("clone_wait").(int)) * time.Second)
-
-			err = prepareDiskSize(client, vmr, qemuDisks)
-			if err != nil {
+		// check if ISO, clone or restore
+		if d.Get("clone").(string) != "" || d.Get("restore_from").(string) != "" {
+			if err = createQemuVmCopy(d, pconf, &config, vmr, client, session); err != nil {
 				return err
 			}
-
-		} else if d.Get("iso").(string) != "" {
+		} else if d.Get("iso").(string) != "" || d.Get("pxe").(bool) {
 			config.QemuIso = d.Get("iso").(string)
 			err := config.CreateVm(vmr, client)
 			if err != nil {
-				return err
+				return destroyFailedGuest(session, client, vmr, err)
 			}
 		} else {
-			return fmt.Errorf("Either clone or iso must be set")
+			return fmt.Errorf("One of clone, iso, pxe or restore_from must be set")
 		}
 	} else {
 		log.Printf("[DEBUG] recycling VM vmId: %d", vmr.VmId())
@@ -927,7 +1114,7 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 		// give sometime to proxmox to catchup
 		time.Sleep(5 * time.Second)
 
-		err = prepareDiskSize(client, vmr, qemuDisks)
+		err = prepareDiskSize(client, vmr, qemuDisks, nil)
 		if err != nil {
 			return err
 		}
@@ -946,11 +1133,24 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if efidisk := d.Get("efidisk").([]interface{}); len(efidisk) > 0 {
+		err := updateEfiDisk(client, vmr, efidisk[0].(map[string]interface{}), false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if virtiofs := d.Get("virtiofs").([]interface{}); len(virtiofs) > 0 {
+		if err := updateQemuVirtiofs(client, vmr, virtiofs); err != nil {
+			return err
+		}
+	}
+
 	// give sometime to proxmox to catchup
 	time.Sleep(time.Duration(d.Get("additional_wait").(int)) * time.Second)
 
 	log.Print("[DEBUG] starting VM")
-	_, err := client.StartVm(vmr)
+	_, err = client.StartVm(vmr)
 	if err != nil {
 		return err
 	}
@@ -963,6 +1163,133 @@ func resourceVmQemuCreate(d *schema.ResourceData, meta interface{}) error {
 	return _resourceVmQemuRead(d, meta)
 }
 
+// Creates the VM vmr as a clone of the VM in clone or by restoring the backup in restore_from,
+// then applies config to the copy.
+func createQemuVmCopy(d *schema.ResourceData, pconf *providerConfiguration, config *pxapi.ConfigQemu, vmr *pxapi.VmRef, client *pxapi.Client, session *pxapi.Session) (err error) {
+	logger, _ := CreateSubLogger("resource_vm_create")
+
+	if d.Get("clone").(string) != "" {
+		fullClone := 1
+		if !d.Get("full_clone").(bool) {
+			fullClone = 0
+		}
+		config.FullClone = &fullClone
+
+		sourceVmrs, err := client.GetVmRefsByName(d.Get("clone").(string))
+		if err != nil {
+			return err
+		}
+
+		// prefer source Vm located on same node
+		sourceVmr := sourceVmrs[0]
+		for _, candVmr := range sourceVmrs {
+			if candVmr.Node() == vmr.Node() {
+				sourceVmr = candVmr
+			}
+		}
+
+		sourceDigest, err := cloneSourceDigest(session, sourceVmr)
+		if err != nil {
+			return err
+		}
+		if err = checkCloneSourceDigest(sourceDigest, d.Get("source_digest").(string), d.Get("source_digest_mismatch").(string), d.Get("clone").(string)); err != nil {
+			return err
+		}
+
+		log.Print("[DEBUG] cloning VM")
+		err = cloneQemuVm(*config, sourceVmr, vmr, client, pconf.BwLimitClone)
+
+		if err != nil {
+			return destroyFailedGuest(session, client, vmr, err)
+		}
+		setCloneSource(d, sourceVmr.VmId(), d.Get("clone").(string), sourceDigest)
+	} else {
+		log.Print("[DEBUG] restoring VM")
+		taskClient, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+		if err != nil {
+			return err
+		}
+		err = restoreQemuVm(session, taskClient, vmr, d.Get("restore_from").(string), d.Get("restore_storage").(string), pconf.BwLimitRestore)
+		if err != nil {
+			return destroyFailedGuest(session, client, vmr, err)
+		}
+	}
+
+	// Waiting for the copy to become ready and
+	// read back all the current disk configurations from proxmox
+	// this allows us to receive updates on the post-clone state of the vm we're building
+	log.Print("[DEBUG] Waiting for copy becoming ready")
+	var config_post_clone *pxapi.ConfigQemu
+	for {
+		// Wait until we can actually retrieve the config from the cloned machine
+		config_post_clone, err = pxapi.NewConfigQemuFromApi(vmr, client)
+		if config_post_clone != nil {
+			break
+			// to prevent an infinite loop we check for any other error
+			// this error is actually fine because the clone is not ready yet
+		} else if err.Error() != "vm locked, could not obtain config" {
+			return destroyFailedGuest(session, client, vmr, err)
+		}
+		time.Sleep(5 * time.Second)
+		log.Print("[DEBUG] Copy still not ready, checking again")
+	}
+
+	logger.Debug().Str("vmid", d.Id()).Msgf("Original disks: '%+v', Clone Disks '%+v'", config.QemuDisks, config_post_clone.QemuDisks)
+
+	// update the current working state to use the appropriate file specification
+	// proxmox needs so we can correctly update the existing disks (post-clone)
+	// instead of accidentially causing the existing disk to be detached.
+	// see https://github.com/Telmate/terraform-provider-proxmox/issues/239
+	for slot, disk := range config_post_clone.QemuDisks {
+		// disks of a restored backup missing in the configuration are kept as they are
+		if config.QemuDisks[slot] == nil {
+			continue
+		}
+		// only update the desired configuration if it was not set by the user
+		// we do not want to overwrite the desired config with the results from
+		// proxmox if the user indicates they wish a particular file or volume config
+		if config.QemuDisks[slot]["file"] == "" {
+			config.QemuDisks[slot]["file"] = disk["file"]
+		}
+		if config.QemuDisks[slot]["volume"] == "" {
+			config.QemuDisks[slot]["volume"] = disk["volume"]
+		}
+	}
+
+	err = config.UpdateConfig(vmr, client)
+	if err != nil {
+		// Set the id because when update config fail the vm is still created
+		d.SetId(resourceId(vmr.Node(), "qemu", vmr.VmId()))
+		return err
+	}
+
+	// give sometime to proxmox to catchup
+	time.Sleep(time.Duration(d.Get("clone_wait").(int)) * time.Second)
+
+	err = prepareDiskSize(client, vmr, config.QemuDisks, nil)
+	if err != nil {
+		return destroyFailedGuest(session, client, vmr, err)
+	}
+	return nil
+}
+
+// schema attribute => config parameter of the optional attributes without a default,
+// which are deleted from the config when they are removed
+var qemuClearableParameters = map[string]string{
+	"tags":       "tags",
+	"args":       "args",
+	"ciuser":     "ciuser",
+	"cipassword": "cipassword",
+	"cicustom":   "cicustom",
+	"sshkeys":    "sshkeys",
+	"ipconfig0":  "ipconfig0",
+	"ipconfig1":  "ipconfig1",
+	"ipconfig2":  "ipconfig2",
+	"ipconfig3":  "ipconfig3",
+	"ipconfig4":  "ipconfig4",
+	"ipconfig5":  "ipconfig5",
+}
+
 func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
 
@@ -972,7 +1299,10 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
 
-	client := pconf.Client
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
 	_, _, vmID, err := parseResourceId(d.Id())
 	if err != nil {
 		return err
@@ -997,11 +1327,13 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		delete(diskParamMap, "file")  // removed; causes a crash in proxmox-api-go
 		delete(diskParamMap, "media") // removed; results in a duplicate key issue causing a 400 from proxmox
 	}
+	applyOsDefault(qemuDisks, "type", d.Get("default_disk_type").(string))
 
 	qemuNetworks, err := ExpandDevicesList(d.Get("network").([]interface{}))
 	if err != nil {
 		return fmt.Errorf("Error while processing Network configuration: %v", err)
 	}
+	applyOsDefault(qemuNetworks, "model", d.Get("default_network_model").(string))
 	logger.Debug().Int("vmid", vmID).Msgf("Processed NetworkSet into qemuNetworks as %+v", qemuNetworks)
 
 	serials := d.Get("serial").(*schema.Set)
@@ -1009,7 +1341,7 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	d.Partial(true)
 	if d.HasChange("target_node") {
-		_, err := client.MigrateNode(vmr, d.Get("target_node").(string), true)
+		err := migrateVm(d, pconf, client, vmr, d.Get("target_node").(string))
 		if err != nil {
 			return err
 		}
@@ -1019,7 +1351,7 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	config := pxapi.ConfigQemu{
 		Name:         d.Get("name").(string),
-		Description:  d.Get("desc").(string),
+		Description:  descriptionWithRunInfo(d.Get("desc").(string), pconf.RunWorkspace, pconf.RunURL),
 		Pool:         d.Get("pool").(string),
 		Bios:         d.Get("bios").(string),
 		Onboot:       d.Get("onboot").(bool),
@@ -1038,7 +1370,7 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		Scsihw:       d.Get("scsihw").(string),
 		HaState:      d.Get("hastate").(string),
 		QemuOs:       d.Get("qemu_os").(string),
-		Tags:         d.Get("tags").(string),
+		Tags:         tagsWithAntiAffinity(d.Get("tags").(string), d.Get("anti_affinity_group").(string)),
 		Args:         d.Get("args").(string),
 		QemuNetworks: qemuNetworks,
 		QemuDisks:    qemuDisks,
@@ -1076,10 +1408,46 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	// UpdateConfig leaves out empty parameters, so the ones removed from the config are kept
+	// by proxmox unless they are deleted explicitly.
+	deletes := clearedParameters(d, qemuClearableParameters)
+	// the tags still hold the anti affinity group
+	if config.Tags != "" {
+		kept := []string{}
+		for _, parameter := range deletes {
+			if parameter != "tags" {
+				kept = append(kept, parameter)
+			}
+		}
+		deletes = kept
+	}
+	if d.HasChange("desc") && config.Description == "" {
+		deletes = append(deletes, "description")
+	}
+	if len(deletes) > 0 {
+		logger.Debug().Int("vmid", vmID).Msgf("Deleting the cleared parameters %v", deletes)
+		_, err = client.SetVmConfig(vmr, map[string]interface{}{"delete": strings.Join(deletes, ",")})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Give some time to proxmox to catchup.
 	time.Sleep(5 * time.Second)
 
-	prepareDiskSize(client, vmr, qemuDisks)
+	session, err := resourceSession(d, pconf)
+	if err != nil {
+		return err
+	}
+	err = prepareDiskSize(client, vmr, qemuDisks, &diskGuard{
+		session: session,
+		client:  client,
+		vmr:     vmr,
+		mode:    d.Get("disk_operation_guard").(string),
+	})
+	if err != nil {
+		return err
+	}
 
 	// Give some time to proxmox to catchup.
 	time.Sleep(15 * time.Second)
@@ -1099,6 +1467,12 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("virtiofs") {
+		if err = updateQemuVirtiofs(client, vmr, d.Get("virtiofs").([]interface{})); err != nil {
+			return err
+		}
+	}
+
 	err = initConnInfo(d, pconf, client, vmr, &config, lock)
 	if err != nil {
 		return err
@@ -1133,6 +1507,9 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		"kvm",
 		"vga",
 		"serial",
+		"efidisk",
+		"efi_vars_reset",
+		"virtiofs",
 	) {
 		d.Set("reboot_required", true)
 	}
@@ -1205,9 +1582,12 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	// With manage_state off the power state is left to others, changes requiring a reboot wait for the next one.
+	manageState := d.Get("manage_state").(bool)
+
 	// If a reboot is required: if the VM is running attempt graceful shutdown. If failed, try a forced poweroff.
 	vmState, err := client.GetVmState(vmr)
-	if err == nil && vmState["status"] != "stopped" && d.Get("reboot_required").(bool) {
+	if err == nil && manageState && vmState["status"] != "stopped" && d.Get("reboot_required").(bool) {
 		log.Print("[DEBUG] shutting down VM")
 		_, err = client.ShutdownVm(vmr)
 		// note: the default timeout is 3 min, configurable per VM: Options/Start-Shutdown Order/Shutdown timeout
@@ -1219,13 +1599,35 @@ func resourceVmQemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	// the EFI disk can not be replaced while the VM is running, which it still is when manage_state
+	// is off or the shutdown failed
+	if d.HasChange("efidisk") || d.HasChange("efi_vars_reset") {
+		if efidisk := d.Get("efidisk").([]interface{}); len(efidisk) > 0 {
+			vmState, err = client.GetVmState(vmr)
+			if err != nil {
+				return err
+			}
+			if vmState["status"] != "stopped" {
+				// the state keeps the old values, so the change is applied again by the next run
+				d.Partial(true)
+				return fmt.Errorf("The EFI disk of VM %d can only be replaced while the VM is stopped, stop it and apply again", vmr.VmId())
+			}
+			err = updateEfiDisk(client, vmr, efidisk[0].(map[string]interface{}), d.HasChange("efi_vars_reset"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	// Start VM only if it wasn't running.
-	vmState, err = client.GetVmState(vmr)
-	if err == nil && vmState["status"] == "stopped" {
-		log.Print("[DEBUG] starting VM")
-		_, err = client.StartVm(vmr)
-	} else if err != nil {
-		return err
+	if manageState {
+		vmState, err = client.GetVmState(vmr)
+		if err == nil && vmState["status"] == "stopped" {
+			log.Print("[DEBUG] starting VM")
+			_, err = client.StartVm(vmr)
+		} else if err != nil {
+			return err
+		}
 	}
 
 	return _resourceVmQemuRead(d, meta)
@@ -1240,7 +1642,10 @@ func resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 
 func _resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 	pconf := meta.(*providerConfiguration)
-	client := pconf.Client
+	client, err := resourceClient(d, pconf)
+	if err != nil {
+		return err
+	}
 
 	_, _, vmID, err := parseResourceId(d.Id())
 	if err != nil {
@@ -1273,8 +1678,18 @@ func _resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(resourceId(vmr.Node(), "qemu", vmr.VmId()))
 	d.Set("target_node", vmr.Node())
 	d.Set("name", config.Name)
-	d.Set("desc", config.Description)
+	d.Set("desc", descriptionWithoutRunInfo(config.Description))
 	d.Set("bios", config.Bios)
+	if config.EFIDisk != "" {
+		d.Set("efidisk", []interface{}{parseEfiDisk(config.EFIDisk)})
+	} else {
+		d.Set("efidisk", []interface{}{})
+	}
+	vmConfig, err := client.GetVmConfig(vmr)
+	if err != nil {
+		return err
+	}
+	d.Set("virtiofs", parseQemuVirtiofs(vmConfig))
 	d.Set("onboot", config.Onboot)
 	d.Set("boot", config.Boot)
 	d.Set("bootdisk", config.BootDisk)
@@ -1291,7 +1706,8 @@ func _resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("scsihw", config.Scsihw)
 	d.Set("hastate", vmr.HaState())
 	d.Set("qemu_os", config.QemuOs)
-	d.Set("tags", config.Tags)
+	d.Set("tags", tagsWithoutAntiAffinity(config.Tags))
+	d.Set("anti_affinity_group", antiAffinityGroup(config.Tags))
 	d.Set("args", config.Args)
 	// Cloud-init.
 	d.Set("ciuser", config.CIuser)
@@ -1310,7 +1726,7 @@ func _resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("ipconfig5", config.Ipconfig5)
 
 	// Some dirty hacks to populate undefined keys with default values.
-	checkedKeys := []string{"clone_wait", "additional_wait", "force_create", "define_connection_info", "preprovision"}
+	checkedKeys := []string{"clone_wait", "additional_wait", "force_create", "define_connection_info", "preprovision", "disk_operation_guard", "source_digest_mismatch"}
 	for _, key := range checkedKeys {
 		if _, ok := d.GetOk(key); !ok {
 			d.Set(key, thisResource.Schema[key].Default)
@@ -1395,6 +1811,14 @@ func _resourceVmQemuRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	// Summary.
+	summaryDisks := []pxapi.QemuDevice{}
+	for _, disk := range config.QemuDisks {
+		summaryDisks = append(summaryDisks, disk)
+	}
+	setGuestSummary(d, summaryDisks, config.Memory, len(config.QemuNetworks))
+	d.Set("bootdisk_size", qemuBootDiskSize(config.Boot, config.BootDisk, config.QemuDisks))
+
 	// Deprecated single disk config.
 	d.Set("storage", config.Storage)
 	d.Set("disk_gb", config.DiskSize)
@@ -1443,17 +1867,23 @@ func resourceVmQemuDelete(d *schema.ResourceData, meta interface{}) error {
 	lock := pmParallelBegin(pconf)
 	defer lock.unlock()
 
-	client := pconf.Client
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	session, err := resourceSession(d, pconf)
+	if err != nil {
+		return err
+	}
 	vmId, _ := strconv.Atoi(path.Base(d.Id()))
 	vmr := pxapi.NewVmRef(vmId)
-	_, err := client.StopVm(vmr)
+	_, err = client.StopVm(vmr)
 	if err != nil {
 		return err
 	}
 
 	// Wait until vm is stopped. Otherwise, deletion will fail.
-	waited := 0
-	for waited < 300 {
+	for waited := 0; waited < 300; waited++ {
 		vmState, err := client.GetVmState(vmr)
 		if err == nil && vmState["status"] == "stopped" {
 			break
@@ -1464,8 +1894,16 @@ func resourceVmQemuDelete(d *schema.ResourceData, meta interface{}) error {
 		time.Sleep(1 * time.Second)
 	}
 
-	_, err = client.DeleteVm(vmr)
-	return err
+	destroyUnreferencedDisks := !d.Get("keep_unreferenced_disks").(bool)
+	_, err = client.DeleteVmParams(vmr, guestDeleteParams(d.Get("purge_on_destroy").(bool), destroyUnreferencedDisks))
+	if err != nil {
+		return err
+	}
+	if !destroyUnreferencedDisks {
+		return nil
+	}
+	// e.g. a cloud-init drive which was detached from the config
+	return deleteGuestVolumes(session, client, vmr.Node(), vmId)
 }
 
 // Increase disk size if original disk was smaller than new disk.
@@ -1473,6 +1911,7 @@ func prepareDiskSize(
 	client *pxapi.Client,
 	vmr *pxapi.VmRef,
 	diskConfMap pxapi.QemuDevices,
+	guard *diskGuard,
 ) error {
 	logger, _ := CreateSubLogger("prepareDiskSize")
 	clonedConfig, err := pxapi.NewConfigQemuFromApi(vmr, client)
@@ -1498,7 +1937,10 @@ func prepareDiskSize(
 		logger.Debug().Int("diskId", diskID).Msgf("Checking disk sizing. Original '%+v', New '%+v'", diskSize, clonedDiskSize)
 		if diskSize > clonedDiskSize {
 			logger.Debug().Int("diskId", diskID).Msgf("Resizing disk. Original '%+v', New '%+v'", diskSize, clonedDiskSize)
-			_, err = client.ResizeQemuDiskRaw(vmr, diskName, diskConf["size"].(string))
+			err = guard.run(func() error {
+				_, err := client.ResizeQemuDiskRaw(vmr, diskName, diskConf["size"].(string))
+				return err
+			})
 			if err != nil {
 				return err
 			}
@@ -1785,3 +2227,139 @@ func initConnInfo(
 	})
 	return nil
 }
+
+// Same as ConfigQemu.CloneVm, but with the bandwidth limit of the provider
+func cloneQemuVm(config pxapi.ConfigQemu, sourceVmr *pxapi.VmRef, vmr *pxapi.VmRef, client *pxapi.Client, bwlimit int) error {
+	if bwlimit == 0 {
+		return config.CloneVm(sourceVmr, vmr, client)
+	}
+
+	vmr.SetVmType("qemu")
+	fullclone := "1"
+	if config.FullClone != nil {
+		fullclone = strconv.Itoa(*config.FullClone)
+	}
+	storage := config.Storage
+	if disk0Storage, ok := config.QemuDisks[0]["storage"].(string); ok && len(disk0Storage) > 0 {
+		storage = disk0Storage
+	}
+	params := map[string]interface{}{
+		"newid":   vmr.VmId(),
+		"target":  vmr.Node(),
+		"name":    config.Name,
+		"full":    fullclone,
+		"bwlimit": bwlimit,
+	}
+	if vmr.Pool() != "" {
+		params["pool"] = vmr.Pool()
+	}
+	if fullclone == "1" {
+		params["storage"] = storage
+	}
+
+	_, err := client.CloneQemuVm(sourceVmr, params)
+	return err
+}
+
+// Like qmrestore, restores the backup archive as the VM vmr. storage is the storage of the
+// disks, the storages of the backup when empty.
+func restoreQemuVm(session *pxapi.Session, client *pxapi.Client, vmr *pxapi.VmRef, archive string, storage string, bwlimit int) error {
+	vmr.SetVmType("qemu")
+	_, err := apiPostTask(session, client, apiPath("nodes", vmr.Node(), "qemu"), restoreQemuParams(vmr, archive, storage, bwlimit))
+	return err
+}
+
+func restoreQemuParams(vmr *pxapi.VmRef, archive string, storage string, bwlimit int) map[string]interface{} {
+	params := map[string]interface{}{
+		"vmid":    vmr.VmId(),
+		"archive": archive,
+	}
+	if storage != "" {
+		params["storage"] = storage
+	}
+	if bwlimit != 0 {
+		params["bwlimit"] = bwlimit
+	}
+	if vmr.Pool() != "" {
+		params["pool"] = vmr.Pool()
+	}
+	return params
+}
+
+// Same as Client.MigrateNode, but with the bandwidth limit of the provider
+func migrateVm(d *schema.ResourceData, pconf *providerConfiguration, client *pxapi.Client, vmr *pxapi.VmRef, targetNode string) error {
+	if pconf.BwLimitMigrate == 0 {
+		_, err := client.MigrateNode(vmr, targetNode, true)
+		return err
+	}
+
+	session, err := resourceSession(d, pconf)
+	if err != nil {
+		return err
+	}
+	_, err = apiPostTask(session, client, apiPath("nodes", vmr.Node(), "qemu", strconv.Itoa(vmr.VmId()), "migrate"), map[string]interface{}{
+		"target":  targetNode,
+		"online":  true,
+		"bwlimit": pconf.BwLimitMigrate,
+	})
+	return err
+}
+
+// efidisk0 as it is in the config, e.g. "local-lvm:vm-100-disk-1,efitype=4m,pre-enrolled-keys=1,size=4M".
+// Disks created before efitype was introduced are 2m.
+func parseEfiDisk(efidisk0 string) map[string]interface{} {
+	conf := pxapi.ParsePMConf(efidisk0, "file")
+	efitype := apiString(conf["efitype"])
+	if efitype == "" {
+		efitype = "2m"
+	}
+	return map[string]interface{}{
+		"storage":           strings.SplitN(apiString(conf["file"]), ":", 2)[0],
+		"efitype":           efitype,
+		"pre_enrolled_keys": apiBool(conf["pre-enrolled-keys"]),
+	}
+}
+
+// Proxmox copies the OVMF vars template selected by efitype and pre-enrolled-keys into a new EFI disk.
+func efiDiskParam(efidisk map[string]interface{}) string {
+	preEnrolledKeys := 0
+	if efidisk["pre_enrolled_keys"].(bool) {
+		preEnrolledKeys = 1
+	}
+	return fmt.Sprintf("%s:1,efitype=%s,pre-enrolled-keys=%d", efidisk["storage"], efidisk["efitype"], preEnrolledKeys)
+}
+
+// Creates the EFI disk of a stopped VM. An existing EFI disk is replaced when it was created on
+// another storage or from another vars template, or when reset is set, which clears the variables.
+func updateEfiDisk(client *pxapi.Client, vmr *pxapi.VmRef, efidisk map[string]interface{}, reset bool) error {
+	vmConfig, err := client.GetVmConfig(vmr)
+	if err != nil {
+		return err
+	}
+	current := apiString(vmConfig["efidisk0"])
+	if current != "" {
+		if !reset && reflect.DeepEqual(parseEfiDisk(current), efidisk) {
+			return nil
+		}
+
+		log.Printf("[DEBUG] replacing EFI disk %s", current)
+		// deleting the disk from the config only detaches it, deleting the unused disk destroys the volume
+		if _, err = client.SetVmConfig(vmr, map[string]interface{}{"delete": "efidisk0"}); err != nil {
+			return err
+		}
+		if vmConfig, err = client.GetVmConfig(vmr); err != nil {
+			return err
+		}
+		volume := apiString(pxapi.ParsePMConf(current, "file")["file"])
+		for key, value := range vmConfig {
+			if strings.HasPrefix(key, "unused") && apiString(value) == volume {
+				if _, err = client.SetVmConfig(vmr, map[string]interface{}{"delete": key}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	_, err = client.SetVmConfig(vmr, map[string]interface{}{"efidisk0": efiDiskParam(efidisk)})
+	return err
+}
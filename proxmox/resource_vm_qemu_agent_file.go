@@ -0,0 +1,161 @@
+package proxmox
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var vmQemuAgentFileResourceDef *schema.Resource
+
+// the guest agent refuses to write more than 60KiB at once
+const qemuAgentFileMaxSize = 61440
+
+func resourceVmQemuAgentFile() *schema.Resource {
+	*pxapi.Debug = true
+
+	vmQemuAgentFileResourceDef = &schema.Resource{
+		Description: "Writes a file into a running guest through the QEMU guest agent.",
+
+		Create: resourceVmQemuAgentFileCreate,
+		Read:   resourceVmQemuAgentFileRead,
+		Update: resourceVmQemuAgentFileUpdate,
+		Delete: resourceVmQemuAgentFileDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the guest. The guest must be running and have the guest agent installed and enabled (`agent = 1`).",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Absolute path of the file inside the guest",
+			},
+			"content": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringLenBetween(0, qemuAgentFileMaxSize),
+				Description:  "The content of the file, at most 60KiB.",
+			},
+			"permissions": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile("^[0-7]{3,4}$"), "must be an octal mode like 0644"),
+				Description:  "Octal mode the file is set to with chmod after writing it, e.g. 0600",
+			},
+		},
+	}
+
+	return vmQemuAgentFileResourceDef
+}
+
+func resourceVmQemuAgentFileCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := writeVmQemuAgentFile(d, meta); err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%d:%s", d.Get("vmid").(int), d.Get("path").(string)))
+	return nil
+}
+
+func resourceVmQemuAgentFileUpdate(d *schema.ResourceData, meta interface{}) error {
+	return writeVmQemuAgentFile(d, meta)
+}
+
+func writeVmQemuAgentFile(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	client := pconf.Client
+
+	vmr := pxapi.NewVmRef(d.Get("vmid").(int))
+	path := d.Get("path").(string)
+
+	logger, _ := CreateSubLogger("resource_vm_qemu_agent_file")
+	logger.Info().Int("vmid", vmr.VmId()).Msgf("Writing %s through the guest agent", path)
+
+	_, err := apiWithoutDebug(func() (interface{}, error) {
+		return nil, client.QemuAgentFileWrite(vmr, map[string]interface{}{
+			"file":    path,
+			"content": d.Get("content").(string),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Writing %s into guest %d failed: %v", path, vmr.VmId(), err)
+	}
+
+	if permissions := d.Get("permissions").(string); permissions != "" {
+		err = qemuAgentExec(pconf.Session, client, vmr, []string{"chmod", permissions, path})
+		if err != nil {
+			return fmt.Errorf("Setting the permissions of %s in guest %d failed: %v", path, vmr.VmId(), err)
+		}
+	}
+	return nil
+}
+
+// The file is only written, reading it back is left to the data source. Only the guest itself
+// is checked, so the file is written again into a recreated guest.
+func resourceVmQemuAgentFileRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmr := pxapi.NewVmRef(d.Get("vmid").(int))
+	if err := pconf.Client.CheckVmRef(vmr); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// the file is left in the guest
+func resourceVmQemuAgentFileDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// Runs a command through the guest agent and waits for it to exit. Every element of command
+// is sent as a separate command parameter, which the client can not do.
+func qemuAgentExec(session *pxapi.Session, client *pxapi.Client, vmr *pxapi.VmRef, command []string) error {
+	if err := client.CheckVmRef(vmr); err != nil {
+		return err
+	}
+
+	values := url.Values{"command": command}
+	reqbody := []byte(values.Encode())
+	data, err := apiResponseData(session.Post(apiPath("nodes", vmr.Node(), "qemu", strconv.Itoa(vmr.VmId()), "agent", "exec"), nil, nil, &reqbody))
+	if err != nil {
+		return err
+	}
+	result, _ := data.(map[string]interface{})
+	pid := apiString(result["pid"])
+
+	for waited := 0; waited < client.TaskTimeout; waited++ {
+		status, err := client.GetExecStatus(vmr, pid)
+		if err != nil {
+			return err
+		}
+		if apiBool(status["exited"]) {
+			if exitCode := apiInt(status["exitcode"]); exitCode != 0 {
+				return fmt.Errorf("%v exited with %d: %s", command, exitCode, apiString(status["err-data"]))
+			}
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%v did not exit within %d seconds", command, client.TaskTimeout)
+}
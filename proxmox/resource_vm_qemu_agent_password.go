@@ -0,0 +1,123 @@
+package proxmox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceVmQemuAgentPassword() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Sets the password of a local user of a running guest through the QEMU guest agent.",
+
+		Create: resourceVmQemuAgentPasswordCreate,
+		Read:   resourceVmQemuAgentPasswordRead,
+		Update: resourceVmQemuAgentPasswordUpdate,
+		Delete: resourceVmQemuAgentPasswordDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the guest. The guest must be running and have the guest agent installed and enabled.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The local user of the guest whose password is set",
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				StateFunc:    agentPasswordHash,
+				ValidateFunc: validation.StringLenBetween(5, 1024),
+				Description:  "The new password, only its sha256 hash is kept in the state. Changing it sets the password again",
+			},
+			"crypted": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the password is already encrypted, e.g. by `mkpasswd`, instead of plain text",
+			},
+			"trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Changing this value sets the password again",
+			},
+		},
+	}
+}
+
+// The state keeps a hash of the password, so the password itself is never written to it.
+func agentPasswordHash(value interface{}) string {
+	sum := sha256.Sum256([]byte(value.(string)))
+	return hex.EncodeToString(sum[:])
+}
+
+func resourceVmQemuAgentPasswordCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := setAgentPassword(d, meta); err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%d:%s", d.Get("vmid").(int), d.Get("username").(string)))
+	return nil
+}
+
+func resourceVmQemuAgentPasswordUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChanges("password", "crypted", "trigger") {
+		return setAgentPassword(d, meta)
+	}
+	return nil
+}
+
+// nothing to read, the guest agent can not tell the password
+func resourceVmQemuAgentPasswordRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// the password is kept in the guest
+func resourceVmQemuAgentPasswordDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+func setAgentPassword(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	vmr, err := apiGuestVmRef(pconf.Session, d.Get("vmid").(int))
+	if err != nil {
+		return err
+	}
+	if vmr.GetVmType() != "qemu" {
+		return fmt.Errorf("Guest %d is a container, the password can only be set on VMs with the guest agent", vmr.VmId())
+	}
+	username := d.Get("username").(string)
+
+	logger, _ := CreateSubLogger("resource_vm_qemu_agent_password")
+	logger.Info().Int("vmid", vmr.VmId()).Str("username", username).Msg("Setting password through the guest agent")
+
+	// the password is kept out of the debug log
+	_, err = apiWithoutDebug(func() (interface{}, error) {
+		return apiPost(pconf.Session, apiPath("nodes", vmr.Node(), "qemu", strconv.Itoa(vmr.VmId()), "agent", "set-user-password"), map[string]interface{}{
+			"username": username,
+			"password": d.Get("password").(string),
+			"crypted":  d.Get("crypted").(bool),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Setting the password of %s on guest %d failed: %v", username, vmr.VmId(), err)
+	}
+	return nil
+}
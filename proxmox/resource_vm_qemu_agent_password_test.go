@@ -0,0 +1,16 @@
+package proxmox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAgentPasswordHash(t *testing.T) {
+	hash := agentPasswordHash("hello")
+	if hash != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("expected the sha256 of `hello`, got `%s`", hash)
+	}
+	if strings.Contains(hash, "hello") || agentPasswordHash("hello!") == hash {
+		t.Errorf("expected a hash not revealing the password")
+	}
+}
@@ -0,0 +1,394 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// attribute => the parameter of the VM config, the ones which can still be changed once the VM is
+// a template
+var vmQemuTemplateParameters = map[string]string{
+	"name":   "name",
+	"desc":   "description",
+	"memory": "memory",
+	"cores":  "cores",
+}
+
+// disk sizes in GiB or TiB, e.g. 10G
+var rxQemuTemplateDiskSize = regexp.MustCompile(`^[0-9]+[GT]$`)
+
+func resourceVmQemuTemplate() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Builds a VM, typically from a cloud image, and converts it to a template for proxmox_vm_qemu to clone.",
+
+		Create:   resourceVmQemuTemplateCreate,
+		Read:     resourceVmQemuTemplateRead,
+		Update:   resourceVmQemuTemplateUpdate,
+		Delete:   resourceVmQemuTemplateDelete,
+		Timeouts: resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"target_node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the template is built on",
+			},
+			"vmid": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the template, the next free id by default",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the template, which clones refer to",
+			},
+			"desc": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the template",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The pool the template is added to",
+			},
+			"memory": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     512,
+				Description: "The memory of the VM in MB, clones start with it",
+			},
+			"cores": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "The number of CPU cores of the VM, clones start with it",
+			},
+			"cpu": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "host",
+				Description: "The type of CPU to emulate",
+			},
+			"bios": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "seabios",
+				ValidateFunc: validation.StringInSlice([]string{"seabios", "ovmf"}, false),
+				Description:  "The BIOS of the VM, `seabios` or `ovmf` for UEFI. An ovmf VM gets an EFI disk on `disk_storage`",
+			},
+			"qemu_os": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "l26",
+				Description: "The type of OS in the VM",
+			},
+			"agent": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      0,
+				ValidateFunc: validation.IntInSlice([]int{0, 1}),
+				Description:  "Set to 1 when the image runs the QEMU guest agent",
+			},
+			"scsihw": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "virtio-scsi-pci",
+				Description: "The SCSI controller of the disk",
+			},
+			"disk_storage": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The storage the disk of the VM is created on",
+			},
+			"import_from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"import_from", "disk_size"},
+				Description:  "The volume the disk is imported from, e.g. a cloud image in the import content of a storage like `local:import/debian-12-genericcloud-amd64.qcow2`",
+			},
+			"disk_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{"import_from", "disk_size"},
+				ValidateFunc: validation.StringMatch(rxQemuTemplateDiskSize, "must be a size like 10G or 1T"),
+				Description:  "The size of the disk, e.g. `10G` or `1T`. An imported disk is grown to it",
+			},
+			"cloudinit_storage": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The storage a cloud-init drive is created on, clones get their own drive from it",
+			},
+			"bridge": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "vmbr0",
+				Description: "The bridge the network device of the VM is attached to",
+			},
+			"network_model": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "virtio",
+				Description: "The model of the network device of the VM",
+			},
+			"vlan": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+				Description:  "The VLAN tag of the network device of the VM",
+			},
+			"serial_console": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Add a serial port and use it as the display, which many cloud images expect",
+			},
+		},
+	}
+}
+
+// The parameters creating the VM the template is made of.
+func vmQemuTemplateParams(d *schema.ResourceData, vmid int) map[string]interface{} {
+	storage := d.Get("disk_storage").(string)
+	params := map[string]interface{}{
+		"vmid":   vmid,
+		"cpu":    d.Get("cpu").(string),
+		"bios":   d.Get("bios").(string),
+		"ostype": d.Get("qemu_os").(string),
+		"agent":  d.Get("agent").(int),
+		"scsihw": d.Get("scsihw").(string),
+		"boot":   "order=scsi0",
+		"scsi0":  storage + ":0,import-from=" + d.Get("import_from").(string),
+		"net0":   d.Get("network_model").(string) + ",bridge=" + d.Get("bridge").(string),
+		"memory": d.Get("memory").(int),
+		"cores":  d.Get("cores").(int),
+		"name":   d.Get("name").(string),
+	}
+	if d.Get("import_from").(string) == "" {
+		// a new disk is given in GiB
+		size := d.Get("disk_size").(string)
+		gib, _ := strconv.Atoi(size[:len(size)-1])
+		if strings.HasSuffix(size, "T") {
+			gib *= 1024
+		}
+		params["scsi0"] = fmt.Sprintf("%s:%d", storage, gib)
+	}
+	if desc := d.Get("desc").(string); desc != "" {
+		params["description"] = desc
+	}
+	if pool := d.Get("pool").(string); pool != "" {
+		params["pool"] = pool
+	}
+	if vlan := d.Get("vlan").(int); vlan > 0 {
+		params["net0"] = fmt.Sprintf("%s,tag=%d", params["net0"], vlan)
+	}
+	if cloudinit := d.Get("cloudinit_storage").(string); cloudinit != "" {
+		params["ide2"] = cloudinit + ":cloudinit"
+	}
+	if d.Get("bios").(string) == "ovmf" {
+		params["efidisk0"] = efiDiskParam(map[string]interface{}{
+			"storage":           storage,
+			"efitype":           "4m",
+			"pre_enrolled_keys": false,
+		})
+	}
+	if d.Get("serial_console").(bool) {
+		params["serial0"] = "socket"
+		params["vga"] = "serial0"
+	}
+	return params
+}
+
+// A new disk is created with its size, only an imported one is grown.
+func vmQemuTemplateResize(d *schema.ResourceData) string {
+	size := d.Get("disk_size").(string)
+	if d.Get("import_from").(string) == "" {
+		return ""
+	}
+	return size
+}
+
+func resourceVmQemuTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("target_node").(string)
+	vmid := d.Get("vmid").(int)
+	if vmid == 0 {
+		if vmid, err = nextVmId(pconf); err != nil {
+			return err
+		}
+	}
+	id := strconv.Itoa(vmid)
+
+	logger, _ := CreateSubLogger("resource_vm_qemu_template_create")
+	logger.Info().Str("node", node).Int("vmid", vmid).Msg("Building template")
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "qemu"), vmQemuTemplateParams(d, vmid)); err != nil {
+		return fmt.Errorf("Creating the VM %d of the template failed: %v", vmid, err)
+	}
+	// the VM exists from here on, a failure taints the resource instead of leaking the VM
+	d.SetId(resourceId(node, "qemu", vmid))
+
+	if size := vmQemuTemplateResize(d); size != "" {
+		upid, err := apiPut(pconf.Session, apiPath("nodes", node, "qemu", id, "resize"), map[string]interface{}{
+			"disk": "scsi0",
+			"size": size,
+		})
+		if err == nil {
+			_, err = apiWaitForTask(pconf.Session, client, upid)
+		}
+		if err != nil {
+			return fmt.Errorf("Growing the disk of the template %d to %s failed: %v", vmid, size, err)
+		}
+	}
+
+	upid, err := apiPost(pconf.Session, apiPath("nodes", node, "qemu", id, "template"), map[string]interface{}{})
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Converting the VM %d to a template failed: %v", vmid, err)
+	}
+	return _resourceVmQemuTemplateRead(d, meta)
+}
+
+func resourceVmQemuTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceVmQemuTemplateRead(d, meta)
+}
+
+func _resourceVmQemuTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, _, vmid, err := parseResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_vm_qemu_template_read")
+	logger.Info().Str("node", node).Int("vmid", vmid).Msg("Reading template")
+
+	config, err := apiGetMap(pconf.Session, apiPath("nodes", node, "qemu", strconv.Itoa(vmid), "config"))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if !apiBool(config["template"]) {
+		return fmt.Errorf("VM %d is not a template", vmid)
+	}
+
+	d.Set("target_node", node)
+	d.Set("vmid", vmid)
+	d.Set("name", apiString(config["name"]))
+	d.Set("desc", apiString(config["description"]))
+	// proxmox leaves defaults out of the config
+	d.Set("memory", 512)
+	if memory, ok := config["memory"]; ok {
+		d.Set("memory", apiInt(memory))
+	}
+	d.Set("cores", 1)
+	if cores, ok := config["cores"]; ok {
+		d.Set("cores", apiInt(cores))
+	}
+	return nil
+}
+
+func resourceVmQemuTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node, _, vmid, err := parseResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	deletes := []string{}
+	for attribute, parameter := range vmQemuTemplateParameters {
+		if !d.HasChange(attribute) {
+			continue
+		}
+		if value := d.Get(attribute); value != "" {
+			params[parameter] = value
+		} else {
+			deletes = append(deletes, parameter)
+		}
+	}
+	if len(deletes) > 0 {
+		params["delete"] = strings.Join(deletes, ",")
+	}
+	if len(params) > 0 {
+		if _, err = apiPut(pconf.Session, apiPath("nodes", node, "qemu", strconv.Itoa(vmid), "config"), params); err != nil {
+			return err
+		}
+	}
+	return _resourceVmQemuTemplateRead(d, meta)
+}
+
+// Proxmox refuses to destroy templates with linked clones, they use the disks of the template.
+func resourceVmQemuTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, _, vmid, err := parseResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "qemu", strconv.Itoa(vmid))+"?purge=1&destroy-unreferenced-disks=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil && !strings.Contains(err.Error(), "does not exist") {
+		return fmt.Errorf("Destroying the template %d failed: %v", vmid, err)
+	}
+	return nil
+}
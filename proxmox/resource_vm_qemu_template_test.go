@@ -0,0 +1,59 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestVmQemuTemplateParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVmQemuTemplate().Schema, map[string]interface{}{
+		"target_node":       "pve1",
+		"name":              "debian-12",
+		"disk_storage":      "local-lvm",
+		"import_from":       "local:import/debian-12-genericcloud-amd64.qcow2",
+		"disk_size":         "10G",
+		"cloudinit_storage": "local-lvm",
+		"bios":              "ovmf",
+		"vlan":              20,
+		"serial_console":    true,
+	})
+	expected := map[string]interface{}{
+		"vmid":     9000,
+		"name":     "debian-12",
+		"cpu":      "host",
+		"bios":     "ovmf",
+		"ostype":   "l26",
+		"agent":    0,
+		"scsihw":   "virtio-scsi-pci",
+		"boot":     "order=scsi0",
+		"scsi0":    "local-lvm:0,import-from=local:import/debian-12-genericcloud-amd64.qcow2",
+		"net0":     "virtio,bridge=vmbr0,tag=20",
+		"memory":   512,
+		"cores":    1,
+		"ide2":     "local-lvm:cloudinit",
+		"efidisk0": "local-lvm:1,efitype=4m,pre-enrolled-keys=0",
+		"serial0":  "socket",
+		"vga":      "serial0",
+	}
+	if params := vmQemuTemplateParams(d, 9000); !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected params `%v`, got `%v`", expected, params)
+	}
+	if size := vmQemuTemplateResize(d); size != "10G" {
+		t.Errorf("expected the imported disk to grow to 10G, got %q", size)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceVmQemuTemplate().Schema, map[string]interface{}{
+		"target_node":  "pve1",
+		"name":         "blank",
+		"disk_storage": "local-lvm",
+		"disk_size":    "1T",
+	})
+	if scsi0 := vmQemuTemplateParams(d, 9001)["scsi0"]; scsi0 != "local-lvm:1024" {
+		t.Errorf("expected a new disk of 1024 GiB, got %v", scsi0)
+	}
+	if size := vmQemuTemplateResize(d); size != "" {
+		t.Errorf("expected a new disk not to be grown, got %q", size)
+	}
+}
@@ -2,10 +2,12 @@ package proxmox
 
 import (
 	"fmt"
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -355,3 +357,80 @@ func TestAccProxmoxVmQemu_UpdateRebootRequired(t *testing.T) {
 		},
 	})
 }
+
+// TestAccProxmoxVmQemu_LockedConfigRetry creates, updates and destroys a vm while a third of the API
+// requests fail with the lock error proxmox sends when another task holds the config of the guest.
+// All of them have to be retried, see retryTransport.
+func TestAccProxmoxVmQemu_LockedConfigRetry(t *testing.T) {
+	resourceName := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	resourcePath := fmt.Sprintf("proxmox_vm_qemu.%s", resourceName)
+	os.Setenv(faultInjectionEnv, "lock=0.3")
+	os.Setenv(faultInjectionSeedEnv, "42")
+	defer os.Unsetenv(faultInjectionEnv)
+	defer os.Unsetenv(faultInjectionSeedEnv)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProxmoxProviderFactory(),
+
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleQemuBasic(resourceName, testAccProxmoxTargetNode),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourcePath, "name", resourceName),
+				),
+			},
+			{
+				Config: strings.Replace(testAccExampleQemuBasic(resourceName, testAccProxmoxTargetNode),
+					"name = \""+resourceName+"\"", "name = \""+resourceName+"-renamed\"", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourcePath, "name", resourceName+"-renamed"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseEfiDisk(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output map[string]interface{}
+	}{
+		{name: "secure boot", input: "local-lvm:vm-100-disk-1,efitype=4m,pre-enrolled-keys=1,size=4M",
+			output: map[string]interface{}{"storage": "local-lvm", "efitype": "4m", "pre_enrolled_keys": true}},
+		{name: "before efitype", input: "local:100/vm-100-disk-0.qcow2,size=128K",
+			output: map[string]interface{}{"storage": "local", "efitype": "2m", "pre_enrolled_keys": false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			result := parseEfiDisk(test.input)
+			if !reflect.DeepEqual(result, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+
+	param := efiDiskParam(map[string]interface{}{"storage": "local-lvm", "efitype": "4m", "pre_enrolled_keys": true})
+	if param != "local-lvm:1,efitype=4m,pre-enrolled-keys=1" {
+		t.Errorf("expected `local-lvm:1,efitype=4m,pre-enrolled-keys=1`, got `%s`", param)
+	}
+}
+
+func TestRestoreQemuParams(t *testing.T) {
+	vmr := pxapi.NewVmRef(200)
+	vmr.SetNode("pve1")
+	vmr.SetPool("tenant")
+
+	expected := map[string]interface{}{"vmid": 200, "archive": "pbs:backup/vm/100/2024-01-01T00:00:00Z", "storage": "local-lvm", "bwlimit": 10240, "pool": "tenant"}
+	if params := restoreQemuParams(vmr, "pbs:backup/vm/100/2024-01-01T00:00:00Z", "local-lvm", 10240); !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, params)
+	}
+
+	vmr = pxapi.NewVmRef(200)
+	expected = map[string]interface{}{"vmid": 200, "archive": "local:backup/vzdump-qemu-100-2024_01_01-00_00_00.vma.zst"}
+	if params := restoreQemuParams(vmr, "local:backup/vzdump-qemu-100-2024_01_01-00_00_00.vma.zst", "", 0); !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, params)
+	}
+}
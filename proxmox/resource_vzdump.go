@@ -0,0 +1,217 @@
+package proxmox
+
+import (
+	"fmt"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceVzdump() *schema.Resource {
+	*pxapi.Debug = true
+
+	return &schema.Resource{
+		Description: "Backs up a VM or container once when it is created, e.g. before a change with `replace_triggered_by`.",
+
+		Create:   resourceVzdumpCreate,
+		Read:     resourceVzdumpRead,
+		Update:   resourceVzdumpUpdate,
+		Delete:   resourceVzdumpDelete,
+		Timeouts: resourceTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"vmid": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(100),
+				Description:  "The id of the guest to back up",
+			},
+			"storage": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The storage the backup is written to",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "snapshot",
+				ValidateFunc: validation.StringInSlice([]string{"snapshot", "suspend", "stop"}, false),
+				Description:  "How a running guest is backed up: snapshot, suspend or stop",
+			},
+			"compress": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "zstd",
+				ValidateFunc: validation.StringInSlice([]string{"0", "gzip", "lzo", "zstd"}, false),
+				Description:  "The compression of the backup: zstd, gzip, lzo or 0 for none",
+			},
+			"notes": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The notes of the backup, variables like `{{guestname}}` are replaced",
+			},
+			"protected": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Protect the backup from pruning and deletion",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the backup when the resource is destroyed, e.g. replaced by a new backup",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Changing a value backs up the guest again",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node the guest was backed up on",
+			},
+			"volid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The volume id of the backup, e.g. `pbs:backup/vm/100/2024-01-01T00:00:00Z`, which a restore refers to",
+			},
+		},
+	}
+}
+
+// The backups of a guest on a storage by their volume id.
+func vzdumpBackups(session *pxapi.Session, node string, storage string, vmid int) (map[string]bool, error) {
+	data, err := apiGetWithParams(session, apiPath("nodes", node, "storage", storage, "content"), map[string]interface{}{
+		"content": "backup",
+		"vmid":    vmid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	volids := map[string]bool{}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok {
+			volids[apiString(entry["volid"])] = true
+		}
+	}
+	return volids, nil
+}
+
+// The backup in after which is not in before, the task does not return the volume id.
+func vzdumpNewBackup(before map[string]bool, after map[string]bool) (string, error) {
+	created := []string{}
+	for volid := range after {
+		if !before[volid] {
+			created = append(created, volid)
+		}
+	}
+	if len(created) != 1 {
+		return "", fmt.Errorf("Expected one new backup on the storage, found %d: %v", len(created), created)
+	}
+	return created[0], nil
+}
+
+func resourceVzdumpCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	vmr, err := apiGuestVmRef(pconf.Session, d.Get("vmid").(int))
+	if err != nil {
+		return err
+	}
+	storage := d.Get("storage").(string)
+
+	before, err := vzdumpBackups(pconf.Session, vmr.Node(), storage, vmr.VmId())
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"vmid":     vmr.VmId(),
+		"storage":  storage,
+		"mode":     d.Get("mode").(string),
+		"compress": d.Get("compress").(string),
+	}
+	if notes := d.Get("notes").(string); notes != "" {
+		params["notes-template"] = notes
+	}
+	if d.Get("protected").(bool) {
+		params["protected"] = true
+	}
+
+	logger, _ := CreateSubLogger("resource_vzdump_create")
+	logger.Info().Int("vmid", vmr.VmId()).Str("storage", storage).Msg("Backing up guest")
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", vmr.Node(), "vzdump"), params); err != nil {
+		return fmt.Errorf("Backing up guest %d failed: %v", vmr.VmId(), err)
+	}
+
+	after, err := vzdumpBackups(pconf.Session, vmr.Node(), storage, vmr.VmId())
+	if err != nil {
+		return err
+	}
+	volid, err := vzdumpNewBackup(before, after)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d/%s", vmr.Node(), vmr.VmId(), volid))
+	d.Set("node", vmr.Node())
+	d.Set("volid", volid)
+	logger.Info().Int("vmid", vmr.VmId()).Str("volid", volid).Msg("Backed up guest")
+	return nil
+}
+
+// nothing to read, a backup pruned by the retention of the storage is not taken again
+func resourceVzdumpRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// only delete_on_destroy can change, which is kept in the state alone
+func resourceVzdumpUpdate(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// The backup is kept unless delete_on_destroy is set.
+func resourceVzdumpDelete(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("delete_on_destroy").(bool) {
+		d.SetId("")
+		return nil
+	}
+
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	node := d.Get("node").(string)
+	volid := d.Get("volid").(string)
+	volume, err := apiGetVolume(pconf.Session, node, volid, "backup")
+	if err != nil {
+		return err
+	}
+	if volume == nil {
+		return nil
+	}
+
+	logger, _ := CreateSubLogger("resource_vzdump_delete")
+	logger.Info().Int("vmid", d.Get("vmid").(int)).Str("volid", volid).Msg("Deleting backup of guest")
+	return apiDeleteVolume(pconf.Session, pconf.Client, node, volid)
+}
@@ -0,0 +1,16 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestVzdumpNewBackup(t *testing.T) {
+	before := map[string]bool{"pbs:backup/vm/100/2024-01-01T00:00:00Z": true}
+	after := map[string]bool{"pbs:backup/vm/100/2024-01-01T00:00:00Z": true, "pbs:backup/vm/100/2024-02-01T00:00:00Z": true}
+	if volid, err := vzdumpNewBackup(before, after); err != nil || volid != "pbs:backup/vm/100/2024-02-01T00:00:00Z" {
+		t.Errorf("expected the new backup, got `%s` `%v`", volid, err)
+	}
+	if _, err := vzdumpNewBackup(before, before); err == nil {
+		t.Errorf("expected an error without a new backup")
+	}
+}
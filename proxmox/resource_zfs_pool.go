@@ -0,0 +1,255 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// raid level => the least number of disks Proxmox creates a pool of the level with
+var zfsPoolRaidLevels = map[string]int{
+	"single": 1,
+	"mirror": 2,
+	"raid10": 4,
+	"raidz":  3,
+	"raidz2": 4,
+	"raidz3": 5,
+}
+
+var zfsPoolCompressions = []string{"on", "off", "gzip", "lz4", "lzjb", "zle", "zstd"}
+
+func resourceZfsPool() *schema.Resource {
+	*pxapi.Debug = true
+
+	raidLevels := []string{}
+	for level := range zfsPoolRaidLevels {
+		raidLevels = append(raidLevels, level)
+	}
+
+	return &schema.Resource{
+		Description: "Manages a ZFS pool on disks of a node.",
+
+		Create:        resourceZfsPoolCreate,
+		Read:          resourceZfsPoolRead,
+		Delete:        resourceZfsPoolDelete,
+		CustomizeDiff: resourceZfsPoolCustomizeDiff,
+		Timeouts:      resourceTimeouts(),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"node": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node the disks of the pool are in",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(rxDiskStorageName, "must start with a letter and only contain letters, digits, '-', '_' and '.'"),
+				Description:  "The name of the pool, also the id of the storage when `add_storage` is set",
+			},
+			"devices": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(rxBlockDevice, "must be the path of a block device like /dev/sdb"),
+				},
+				Description: "The paths of the disks of the pool, e.g. `/dev/sdb`",
+			},
+			"raid_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "single",
+				ValidateFunc: validation.StringInSlice(raidLevels, false),
+				Description:  "The raid level of the pool: single, mirror, raid10, raidz, raidz2 or raidz3",
+			},
+			"ashift": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      12,
+				ValidateFunc: validation.IntBetween(9, 16),
+				Description:  "The base 2 logarithm of the sector size of the pool",
+			},
+			"compression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "on",
+				ValidateFunc: validation.StringInSlice(zfsPoolCompressions, false),
+				Description:  "The compression algorithm of the pool: on, off, gzip, lz4, lzjb, zle or zstd",
+			},
+			"add_storage": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether a zfspool storage of the pool is added to the cluster",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the pool in bytes",
+			},
+			"health": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The health of the pool, e.g. `ONLINE` or `DEGRADED`",
+			},
+		},
+	}
+}
+
+// The names of the pools and volume groups created on disks, they are the ids of their storages
+// as well.
+var rxDiskStorageName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-_.]*$`)
+
+func zfsPoolCheckDevices(raidLevel string, devices int) error {
+	least, ok := zfsPoolRaidLevels[raidLevel]
+	if !ok {
+		return fmt.Errorf("Unknown raid level %s", raidLevel)
+	}
+	if devices < least {
+		return fmt.Errorf("A ZFS pool of raid level %s needs at least %d devices, got %d", raidLevel, least, devices)
+	}
+	if raidLevel == "raid10" && devices%2 != 0 {
+		return fmt.Errorf("A ZFS pool of raid level raid10 needs an even number of devices, got %d", devices)
+	}
+	return nil
+}
+
+func resourceZfsPoolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("raid_level") || !d.NewValueKnown("devices") {
+		return nil
+	}
+	return zfsPoolCheckDevices(d.Get("raid_level").(string), len(d.Get("devices").([]interface{})))
+}
+
+// The pool called name on node as listed by /nodes/{node}/disks/zfs, nil when there is none.
+func zfsPoolFind(session *pxapi.Session, node string, name string) (map[string]interface{}, error) {
+	data, err := apiGet(session, apiPath("nodes", node, "disks", "zfs"))
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data.([]interface{})
+	for _, item := range list {
+		if pool, ok := item.(map[string]interface{}); ok && apiString(pool["name"]) == name {
+			return pool, nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceZfsPoolCreate(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	node := d.Get("node").(string)
+	name := d.Get("name").(string)
+	devices := schemaStringList(d.Get("devices"))
+	raidLevel := d.Get("raid_level").(string)
+	if err = zfsPoolCheckDevices(raidLevel, len(devices)); err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"name":        name,
+		"devices":     strings.Join(devices, ","),
+		"raidlevel":   raidLevel,
+		"ashift":      d.Get("ashift").(int),
+		"compression": d.Get("compression").(string),
+		"add_storage": d.Get("add_storage").(bool),
+	}
+
+	logger, _ := CreateSubLogger("resource_zfs_pool_create")
+	logger.Info().Str("node", node).Msgf("Creating ZFS pool %s on %s", name, strings.Join(devices, ", "))
+
+	if _, err = apiPostTask(pconf.Session, client, apiPath("nodes", node, "disks", "zfs"), params); err != nil {
+		return fmt.Errorf("Creating the ZFS pool %s on %s failed: %v", name, node, err)
+	}
+	d.SetId(nodeResourceId(node, name))
+	return _resourceZfsPoolRead(d, meta)
+}
+
+func resourceZfsPoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+	return _resourceZfsPoolRead(d, meta)
+}
+
+// The devices, raid level and options of a pool only apply when creating it, they are kept as
+// configured.
+func _resourceZfsPoolRead(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		d.SetId("")
+		return fmt.Errorf("Unexpected error when trying to read and parse resource id: %v", err)
+	}
+
+	logger, _ := CreateSubLogger("resource_zfs_pool_read")
+	logger.Info().Str("node", node).Msgf("Reading ZFS pool %s", name)
+
+	pool, err := zfsPoolFind(pconf.Session, node, name)
+	if err != nil {
+		return err
+	}
+	if pool == nil {
+		d.SetId("")
+		return nil
+	}
+	d.Set("node", node)
+	d.Set("name", name)
+	d.Set("size", apiInt(pool["size"]))
+	d.Set("health", apiString(pool["health"]))
+	return nil
+}
+
+// The disks of the pool are wiped, so they can be used again, and the storage of the pool is
+// removed with it.
+func resourceZfsPoolDelete(d *schema.ResourceData, meta interface{}) error {
+	pconf := meta.(*providerConfiguration)
+	lock := pmParallelBegin(pconf)
+	defer lock.unlock()
+
+	client, err := resourceTaskClient(d, pconf, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	node, name, err := parseNodeResourceId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	logger, _ := CreateSubLogger("resource_zfs_pool_delete")
+	logger.Info().Str("node", node).Msgf("Destroying ZFS pool %s", name)
+
+	upid, err := apiDelete(pconf.Session, apiPath("nodes", node, "disks", "zfs", name)+"?cleanup-config=1&cleanup-disks=1")
+	if err == nil {
+		_, err = apiWaitForTask(pconf.Session, client, upid)
+	}
+	if err != nil {
+		return fmt.Errorf("Destroying the ZFS pool %s on %s failed: %v", name, node, err)
+	}
+	return nil
+}
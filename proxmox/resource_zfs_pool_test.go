@@ -0,0 +1,29 @@
+package proxmox
+
+import "testing"
+
+func TestZfsPoolCheckDevices(t *testing.T) {
+	tests := []struct {
+		raidLevel string
+		devices   int
+		valid     bool
+	}{
+		{"single", 1, true},
+		{"single", 2, true},
+		{"mirror", 1, false},
+		{"mirror", 2, true},
+		{"raid10", 4, true},
+		{"raid10", 5, false},
+		{"raid10", 6, true},
+		{"raidz", 2, false},
+		{"raidz", 3, true},
+		{"raidz2", 3, false},
+		{"raidz3", 5, true},
+		{"raid5", 5, false},
+	}
+	for _, test := range tests {
+		if err := zfsPoolCheckDevices(test.raidLevel, test.devices); (err == nil) != test.valid {
+			t.Errorf("%s %d: expected valid %v, got `%v`", test.raidLevel, test.devices, test.valid, err)
+		}
+	}
+}
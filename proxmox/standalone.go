@@ -0,0 +1,36 @@
+package proxmox
+
+import (
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/rs/zerolog"
+)
+
+// A node which is not part of a cluster, e.g. of a homelab, still has the cluster wide
+// configuration of the options, the firewall and SDN, which applies to the node alone. It has no
+// HA manager though, and no other node to move guests to.
+
+// Whether the entries of /cluster/status are the ones of a standalone node, the status of a
+// cluster has an entry of type cluster besides the entries of its nodes.
+func standaloneNode(entries interface{}) bool {
+	list, _ := entries.([]interface{})
+	for _, item := range list {
+		if entry, ok := item.(map[string]interface{}); ok && apiString(entry["type"]) == "cluster" {
+			return false
+		}
+	}
+	return true
+}
+
+func apiStandalone(session *pxapi.Session) (bool, error) {
+	entries, err := apiGet(session, "/cluster/status")
+	if err != nil {
+		return false, err
+	}
+	return standaloneNode(entries), nil
+}
+
+// HA resources of a standalone node are only kept in the state, so modules written for a cluster
+// apply to a standalone node as well.
+func haStandaloneWarning(logger zerolog.Logger, object string) {
+	logger.Warn().Msgf("The node is not part of a cluster and has no HA manager, %s is only kept in the state", object)
+}
@@ -0,0 +1,28 @@
+package proxmox
+
+import (
+	"testing"
+)
+
+func TestStandaloneNode(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    interface{}
+		standalone bool
+	}{
+		{name: "standalone", entries: []interface{}{
+			map[string]interface{}{"type": "node", "name": "pve", "online": float64(1), "local": float64(1)},
+		}, standalone: true},
+		{name: "cluster", entries: []interface{}{
+			map[string]interface{}{"type": "cluster", "name": "prod", "nodes": float64(2), "quorate": float64(1)},
+			map[string]interface{}{"type": "node", "name": "pve1"},
+			map[string]interface{}{"type": "node", "name": "pve2"},
+		}, standalone: false},
+	}
+
+	for _, test := range tests {
+		if standalone := standaloneNode(test.entries); standalone != test.standalone {
+			t.Errorf("%s: expected standalone %v, got %v", test.name, test.standalone, standalone)
+		}
+	}
+}
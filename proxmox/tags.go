@@ -0,0 +1,50 @@
+package proxmox
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Proxmox accepts tags separated by ;, commas or spaces and may return them in another order,
+// newer versions sort them. Every object type with tags reads and writes them through these
+// helpers, so they are compared the same way everywhere.
+
+const tagSeparators = ";, "
+
+// the tags of a tag string in their order, without duplicates
+func parseTags(tags string) []string {
+	parsed := []string{}
+	for _, tag := range apiStringList(tags, tagSeparators) {
+		if !stringInList(tag, parsed) {
+			parsed = append(parsed, tag)
+		}
+	}
+	return parsed
+}
+
+// the tag string sent to proxmox
+func formatTags(tags []string) string {
+	return strings.Join(tags, ";")
+}
+
+// the tags of a tag string sorted, so equal tags have equal strings
+func normalizeTags(tags string) string {
+	parsed := parseTags(tags)
+	sort.Strings(parsed)
+	return formatTags(parsed)
+}
+
+// The tags attribute of an object type, differences in the separators and the order of the
+// tags are no change.
+func tagsSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return normalizeTags(old) == normalizeTags(new)
+		},
+		Description: description,
+	}
+}
@@ -0,0 +1,39 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       string
+		parsed     []string
+		normalized string
+	}{
+		{name: "empty", tags: "", parsed: []string{}, normalized: ""},
+		{name: "semicolons", tags: "web;prod", parsed: []string{"web", "prod"}, normalized: "prod;web"},
+		{name: "mixed separators", tags: "web, prod db", parsed: []string{"web", "prod", "db"}, normalized: "db;prod;web"},
+		{name: "duplicates", tags: "web;prod;web", parsed: []string{"web", "prod"}, normalized: "prod;web"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if parsed := parseTags(test.tags); !reflect.DeepEqual(parsed, test.parsed) {
+				t.Errorf("%s: expected `%v`, got `%v`", test.name, test.parsed, parsed)
+			}
+			if normalized := normalizeTags(test.tags); normalized != test.normalized {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.normalized, normalized)
+			}
+		})
+	}
+
+	suppress := tagsSchema("").DiffSuppressFunc
+	if !suppress("tags", "prod;web", "web,prod", nil) {
+		t.Errorf("expected tags in another order and with other separators to be no change")
+	}
+	if suppress("tags", "prod;web", "web", nil) {
+		t.Errorf("expected a removed tag to be a change")
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
@@ -41,6 +45,35 @@ var rootLogger zerolog.Logger
 // IMPORTANT:  this variable is set by the ConfigureLogger function.  Be sure that it has run.
 var logLevels map[string]string
 
+// Several provider instances (aliases) are configured concurrently within the same plugin process.
+// loggerMutex guards the globals above and below, so the instances share the open log files
+// instead of racing on them. The root logger and the log levels are not kept per instance, the
+// sub loggers are created without knowing the instance they log for. The instance configured last
+// sets them for all instances, the logging section of the provider docs tells so.
+var loggerMutex sync.RWMutex
+
+// log files opened by ConfigureLogger, by path
+var logFiles = map[string]*os.File{}
+
+// stdout and stderr can only be redirected once per process
+var logOutputRedirected bool
+
+// all provider instances of one terraform run use the same timestamp for per-run log files
+var logRunStarted = time.Now()
+
+// Returns the path of the log file to write to. When perRun is set, the start time of this run
+// and the process id are inserted in front of the extension, e.g. terraform-plugin-proxmox.log
+// becomes terraform-plugin-proxmox-20211016T101500-4242.log. Every terraform run then writes
+// to its own file instead of growing a single one forever.
+func logFilePath(logPath string, perRun bool) string {
+	if !perRun {
+		return logPath
+	}
+	ext := filepath.Ext(logPath)
+	base := strings.TrimSuffix(logPath, ext)
+	return fmt.Sprintf("%s-%s-%d%s", base, logRunStarted.Format("20060102T150405"), os.Getpid(), ext)
+}
+
 // Configure the debug logger for this provider.  The goal here is to enable selective amounts
 // of output for targetted debugging without overwhelming with data from sources the user/developer
 // doesn't care about.
@@ -65,6 +98,8 @@ var logLevels map[string]string
 //   these will be mapped out to the zerolog levels.  See the levelStringToZerologLevel function.
 //
 // logs will be written out to the logPath specified. An existing file at that path will be appended to.
+// Use logFilePath to get a separate file per run. It is safe to call this function from several
+// provider instances at once: a file is only opened once and stdout/stderr are only redirected once.
 // note that there are some information (like our redirection of the built-in log library) which will not
 // follow the zerolog pattern and thus could mess with parsing.  This is annoying but something to fix in
 // a future verison.
@@ -77,13 +112,26 @@ func ConfigureLogger(enableOutput bool, logPath string, inputLogLevels map[strin
 		return
 	}
 
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+
 	// update the global logLevels
 	// I don't love globals, but feels like the right use here.
 	logLevels = inputLogLevels
 
 	// Create the log file if doesn't exist. And append to it if it already exists.
-	// TODO log to stderr so at least terraform's TF_LOG can capture an issue if this file isn't created
-	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	// If another provider instance already uses this file, share its handle.
+	f, ok := logFiles[logPath]
+	if !ok {
+		var err error
+		f, err = os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			// log to stderr so at least terraform's TF_LOG can capture the issue
+			fmt.Fprintf(os.Stderr, "Unable to open log file %s, logging to stderr instead: %v\n", logPath, err)
+			f = os.Stderr
+		}
+		logFiles[logPath] = f
+	}
 
 	// using a multi-writer here so we can easily add additional log destination (like a json file)
 	// for now though using just the console writer because it makes pretty logs
@@ -118,8 +166,26 @@ func ConfigureLogger(enableOutput bool, logPath string, inputLogLevels map[strin
 	// note there is no initialization here. we WANT this to be set to the global logger
 	rootLogger = zerolog.New(multi).With().Timestamp().Caller().Logger().Level(rootLevel)
 
-	// mirror Stdout to the debug log file as well
-	// useful as we can debug the communication to/from the plugin and terraform
+	// look to see if we should capture all logs going through the native log library
+	// this is mostly useful in this particular case to see logs from the proxmox api library.
+	// just the presense of the _capturelog key (no matter the level set) is indication we should capture it
+	_, ok = logLevels["_capturelog"]
+	if ok {
+		rootLogger.Info().Msg("Enabling the capture of log-library logs as ithe _capturelog flag was detected")
+		log.SetOutput(f) // so we capture logs from any other dependencies not using logrus
+	}
+
+	if !logOutputRedirected && f != os.Stderr {
+		logOutputRedirected = true
+		redirectOutput(f)
+	}
+
+	rootLogger.Info().Msgf("Logging Started. Root Logger Set to level %v", rootLevel)
+}
+
+// mirror Stdout to the debug log file as well
+// useful as we can debug the communication to/from the plugin and terraform
+func redirectOutput(f *os.File) {
 	origStdout := os.Stdout
 	origStderr := os.Stderr
 	mwriter := io.MultiWriter(f, origStdout)
@@ -133,15 +199,6 @@ func ConfigureLogger(enableOutput bool, logPath string, inputLogLevels map[strin
 	os.Stdout = writer
 	os.Stderr = writerStderr
 
-	// look to see if we should capture all logs going through the native log library
-	// this is mostly useful in this particular case to see logs from the proxmox api library.
-	// just the presense of the _capturelog key (no matter the level set) is indication we should capture it
-	_, ok = logLevels["_capturelog"]
-	if ok {
-		rootLogger.Info().Msg("Enabling the capture of log-library logs as ithe _capturelog flag was detected")
-		log.SetOutput(f) // so we capture logs from any other dependencies not using logrus
-	}
-
 	//create channel to control exit | will block until all copies are finished
 	communicateLogExit := make(chan bool)
 
@@ -170,8 +227,6 @@ func ConfigureLogger(enableOutput bool, logPath string, inputLogLevels map[strin
 	//	// close file after all writes have finished
 	//	_ = f.Close()
 	//}
-
-	rootLogger.Info().Msgf("Logging Started. Root Logger Set to level %v", rootLevel)
 }
 
 // Create a sublogger from the rootLogger
@@ -180,6 +235,8 @@ func ConfigureLogger(enableOutput bool, logPath string, inputLogLevels map[strin
 // The loggerName string is used to set the name of the logger in message outputs (as a key-val pair) but
 // also as a way to know what we should set the logging level for this sublogger to (info/trace/warn/etc)
 func CreateSubLogger(loggerName string) (zerolog.Logger, error) {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
 
 	// look to see if there is a default level we should be using
 	defaultLevelString, ok := logLevels["_default"]
@@ -326,3 +383,85 @@ func adaptDeviceToConf(
 
 	return conf
 }
+
+// Converts a schema.TypeSet (or TypeList) of strings into a []string
+func schemaStringList(value interface{}) []string {
+	var items []interface{}
+	switch v := value.(type) {
+	case *schema.Set:
+		items = v.List()
+	case []interface{}:
+		items = v
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		list = append(list, item.(string))
+	}
+	return list
+}
+
+func stringInList(value string, list []string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the parameters of the attributes which had a value and were removed from the config.
+// Proxmox keeps a parameter which is left out of an update, so these have to be sent as delete.
+func clearedParameters(d *schema.ResourceData, parameters map[string]string) []string {
+	cleared := []string{}
+	for attribute, parameter := range parameters {
+		if !d.HasChange(attribute) {
+			continue
+		}
+		prev, next := d.GetChange(attribute)
+		switch next := next.(type) {
+		case string:
+			if next == "" && prev.(string) != "" {
+				cleared = append(cleared, parameter)
+			}
+		case int:
+			if next == 0 && prev.(int) != 0 {
+				cleared = append(cleared, parameter)
+			}
+		}
+	}
+	sort.Strings(cleared)
+	return cleared
+}
+
+// Marks the part of a guest description which is written by the provider itself,
+// everything after it is replaced on every create and update of the guest.
+const runInfoMarker = "<!-- terraform-run -->"
+
+// Appends the workspace and the CI run url to the description (notes) of a guest, so it can be
+// traced back to the pipeline which last created or changed it.
+func descriptionWithRunInfo(description string, workspace string, runURL string) string {
+	description = descriptionWithoutRunInfo(description)
+	if workspace == "" && runURL == "" {
+		return description
+	}
+
+	runInfo := "Managed by Terraform"
+	if workspace != "" {
+		runInfo += fmt.Sprintf(" workspace `%s`", workspace)
+	}
+	if runURL != "" {
+		runInfo += fmt.Sprintf(", last run: [%s](%s)", runURL, runURL)
+	}
+	if description == "" {
+		return runInfoMarker + "\n" + runInfo
+	}
+	return description + "\n\n" + runInfoMarker + "\n" + runInfo
+}
+
+// the description as configured, without what descriptionWithRunInfo added
+func descriptionWithoutRunInfo(description string) string {
+	if i := strings.Index(description, runInfoMarker); i >= 0 {
+		return strings.TrimRight(description[:i], "\n")
+	}
+	return description
+}
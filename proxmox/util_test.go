@@ -0,0 +1,123 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSchemaStringList(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  interface{}
+		output []string
+	}{
+		{name: "nil", input: nil, output: []string{}},
+		{name: "list", input: []interface{}{"b", "a"}, output: []string{"b", "a"}},
+		{name: "set", input: schema.NewSet(schema.HashString, []interface{}{"a", "b"}), output: []string{"a", "b"}},
+		{name: "empty set", input: schema.NewSet(schema.HashString, nil), output: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			result := schemaStringList(test.input)
+			// set iteration order is not defined
+			if _, isSet := test.input.(*schema.Set); isSet {
+				sort.Strings(result)
+			}
+			if !reflect.DeepEqual(result, test.output) {
+				t.Errorf("%s: expected `%+v`, got `%+v`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestLogFilePath(t *testing.T) {
+	suffix := fmt.Sprintf("-%s-%d", logRunStarted.Format("20060102T150405"), os.Getpid())
+	tests := []struct {
+		name   string
+		path   string
+		perRun bool
+		output string
+	}{
+		{name: "single file", path: "terraform-plugin-proxmox.log", perRun: false, output: "terraform-plugin-proxmox.log"},
+		{name: "per run", path: "terraform-plugin-proxmox.log", perRun: true, output: "terraform-plugin-proxmox" + suffix + ".log"},
+		{name: "per run in directory", path: "/var/log/tf/proxmox.log", perRun: true, output: "/var/log/tf/proxmox" + suffix + ".log"},
+		{name: "per run without extension", path: "proxmox", perRun: true, output: "proxmox" + suffix},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			if result := logFilePath(test.path, test.perRun); result != test.output {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.output, result)
+			}
+		})
+	}
+}
+
+func TestDescriptionWithRunInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		workspace   string
+		runURL      string
+		output      string
+	}{
+		{name: "disabled", description: "web server", output: "web server"},
+		{name: "empty description", workspace: "prod", output: runInfoMarker + "\nManaged by Terraform workspace `prod`"},
+		{name: "workspace and url", description: "web server", workspace: "prod", runURL: "https://ci.example.com/run/42",
+			output: "web server\n\n" + runInfoMarker + "\nManaged by Terraform workspace `prod`, last run: [https://ci.example.com/run/42](https://ci.example.com/run/42)"},
+		{name: "replaces previous run", description: "web server\n\n" + runInfoMarker + "\nManaged by Terraform workspace `old`", workspace: "prod",
+			output: "web server\n\n" + runInfoMarker + "\nManaged by Terraform workspace `prod`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(*testing.T) {
+			result := descriptionWithRunInfo(test.description, test.workspace, test.runURL)
+			if result != test.output {
+				t.Errorf("%s: expected `%s`, got `%s`", test.name, test.output, result)
+			}
+			if stripped := descriptionWithoutRunInfo(result); stripped != descriptionWithoutRunInfo(test.description) {
+				t.Errorf("%s: expected `%s` after stripping, got `%s`", test.name, descriptionWithoutRunInfo(test.description), stripped)
+			}
+		})
+	}
+}
+
+func TestClearedParameters(t *testing.T) {
+	resource := &schema.Resource{Schema: map[string]*schema.Schema{
+		"tags":      {Type: schema.TypeString, Optional: true},
+		"args":      {Type: schema.TypeString, Optional: true},
+		"ciuser":    {Type: schema.TypeString, Optional: true},
+		"vlan":      {Type: schema.TypeInt, Optional: true},
+		"ipconfig0": {Type: schema.TypeString, Optional: true},
+	}}
+	state := &terraform.InstanceState{ID: "1", Attributes: map[string]string{
+		"tags":      "web",
+		"args":      "-no-reboot",
+		"ciuser":    "admin",
+		"vlan":      "10",
+		"ipconfig0": "ip=dhcp",
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"args": "-no-reboot", "ciuser": "root", "ipconfig0": "ip=dhcp"})
+	diff, err := schema.InternalMap(resource.Schema).Diff(context.Background(), state, config, nil, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+	d, err := schema.InternalMap(resource.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("unexpected error `%+v`", err)
+	}
+
+	result := clearedParameters(d, map[string]string{"tags": "tags", "args": "args", "ciuser": "ciuser", "vlan": "tag", "ipconfig0": "ipconfig0"})
+	expected := []string{"tag", "tags"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected `%v`, got `%v`", expected, result)
+	}
+}
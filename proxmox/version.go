@@ -0,0 +1,49 @@
+package proxmox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pxapi "github.com/Telmate/proxmox-api-go/proxmox"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// The major versions of Proxmox VE the provider is tested against. The API of a new major
+// version may rename or drop attributes, which the provider would silently lose.
+var pveTestedMajorVersions = []int{6, 7, 8}
+
+// The major version of a version reported by /version, e.g. 8 of 8.1.4.
+func pveMajorVersion(version string) (int, error) {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("Unexpected Proxmox VE version %s", version)
+	}
+	return major, nil
+}
+
+// Checks version against the tested versions, a version which is not tested is returned as a
+// warning or, with strict, as an error.
+func checkPveVersion(version string, strict bool) (diags diag.Diagnostics) {
+	major, err := pveMajorVersion(version)
+	if err == nil {
+		for _, tested := range pveTestedMajorVersions {
+			if major == tested {
+				return nil
+			}
+		}
+		err = fmt.Errorf("Proxmox VE %s is not tested with this version of the provider, attributes changed by the new major version may be lost", version)
+	}
+	if strict {
+		return diag.FromErr(fmt.Errorf("%v, unset pm_strict_version_check to proceed anyway", err))
+	}
+	return diag.Diagnostics{{Severity: diag.Warning, Summary: err.Error()}}
+}
+
+func pveVersion(session *pxapi.Session) (string, error) {
+	data, err := apiGetMap(session, "/version")
+	if err != nil {
+		return "", err
+	}
+	return apiString(data["version"]), nil
+}
@@ -0,0 +1,36 @@
+package proxmox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestCheckPveVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		strict   bool
+		severity diag.Severity
+		ok       bool
+	}{
+		{name: "tested", version: "8.1.4", ok: true},
+		{name: "tested strict", version: "7.4-3", strict: true, ok: true},
+		{name: "untested", version: "9.0.1", severity: diag.Warning},
+		{name: "untested strict", version: "9.0.1", strict: true, severity: diag.Error},
+		{name: "invalid", version: "unknown", severity: diag.Warning},
+	}
+
+	for _, test := range tests {
+		diags := checkPveVersion(test.version, test.strict)
+		if test.ok {
+			if len(diags) != 0 {
+				t.Errorf("%s: expected no diagnostics, got `%v`", test.name, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || diags[0].Severity != test.severity {
+			t.Errorf("%s: expected one diagnostic of severity %v, got `%v`", test.name, test.severity, diags)
+		}
+	}
+}